@@ -0,0 +1,38 @@
+package main
+
+import (
+	"flag"
+	"log"
+	"net/http"
+
+	"github.com/ArtemSind/food_tinder/internal/infrastructure/external/foodji/fake"
+)
+
+func main() {
+	addr := flag.String("addr", ":8081", "address to listen on")
+	fixturePath := flag.String("fixture", "", "JSON fixture with the machines to serve (defaults to the built-in fixture)")
+	latency := flag.Duration("latency", 0, "delay added to every response")
+	errorRate := flag.Float64("error-rate", 0, "share of requests, between 0 and 1, that fail")
+	errorStatus := flag.Int("error-status", http.StatusServiceUnavailable, "status of failed requests")
+	malformed := flag.Bool("malformed", false, "serve truncated JSON payloads")
+	flag.Parse()
+
+	fixture := fake.DefaultFixture()
+	if *fixturePath != "" {
+		var err error
+		fixture, err = fake.LoadFixture(*fixturePath)
+		if err != nil {
+			log.Fatalf("Failed to load fixture: %v", err)
+		}
+	}
+
+	server := fake.NewServer(fixture)
+	server.SetLatency(*latency)
+	server.SetErrorRate(*errorRate, *errorStatus)
+	server.SetMalformed(*malformed)
+
+	log.Printf("Fake Foodji serving %d machines on %s", len(fixture.Machines), *addr)
+	if err := http.ListenAndServe(*addr, server); err != nil {
+		log.Fatalf("Server error: %v", err)
+	}
+}
@@ -7,11 +7,14 @@ import (
 	"net/http"
 	"os"
 	"os/signal"
+	"strconv"
 	"syscall"
 	"time"
 
 	"github.com/ArtemSind/food_tinder/internal/application"
+	"github.com/ArtemSind/food_tinder/internal/infrastructure/external/catalog"
 	"github.com/ArtemSind/food_tinder/internal/infrastructure/external/foodji"
+	"github.com/ArtemSind/food_tinder/internal/infrastructure/external/webhook"
 	httpRouter "github.com/ArtemSind/food_tinder/internal/infrastructure/http"
 	"github.com/ArtemSind/food_tinder/internal/infrastructure/persistence"
 	"github.com/joho/godotenv"
@@ -65,23 +68,71 @@ func main() {
 
 	// Create http clients
 	foodjiClient := foodji.NewClient()
+	webhookClient := webhook.NewClient()
+
+	// Products come from Foodji and the catalog files listed in CATALOG_FILES
+	var productSource catalog.Source = catalog.NewFoodjiSource(foodjiClient, persistence.DefaultMachineID)
+	if fileSources := catalog.FileSourcesFromEnv(); len(fileSources) > 0 {
+		sources := []catalog.Source{productSource}
+		for _, fileSource := range fileSources {
+			fileSource.Start()
+			closers = append(closers, fileSource.Close)
+			sources = append(sources, fileSource)
+		}
+		productSource = catalog.NewCompositeSource(sources...)
+	}
 
 	// Create repositories
 	sessionRepo := persistence.NewSessionRepository(db)
 	voteRepo := persistence.NewVoteRepository(db)
-	productRepo := persistence.NewProductRepository(redisClient, foodjiClient)
+	outboxRepo := persistence.NewOutboxRepository(db)
+	webhookRepo := persistence.NewWebhookRepository(db)
+	impressionRepo := persistence.NewImpressionRepository(db)
+	experimentRepo := persistence.NewExperimentRepository(db)
+	productHistoryRepo := persistence.NewProductHistoryRepository(db)
+	productRepo := persistence.NewProductRepository(redisClient, productSource, outboxRepo, productHistoryRepo)
+	similarityCache := persistence.NewSimilarityCache(redisClient)
+	productOverrideRepo := persistence.NewProductOverrideRepository(db)
+	productAliasRepo := persistence.NewProductAliasRepository(db)
 
 	closers = append(closers, func() error {
 		productRepo.Close()
 		return nil
 	})
 
+	// Admin overrides and aliases are applied to the catalog for every service that shows products
+	curatedProducts := application.NewCuratedProductRepository(productRepo, productOverrideRepo, productAliasRepo)
+
+	// Sold-out products stay in the deck only if DECK_HIDE_SOLD_OUT is false
+	var availability application.ProductAvailability = productRepo
+	if hide, err := strconv.ParseBool(os.Getenv("DECK_HIDE_SOLD_OUT")); err == nil && !hide {
+		availability = nil
+	}
+
 	// Create services
-	sessionService := application.NewSessionService(sessionRepo)
-	voteService := application.NewVoteService(voteRepo, productRepo)
+	sessionService := application.NewSessionService(sessionRepo, experimentRepo)
+	voteService := application.NewVoteService(voteRepo, curatedProducts)
+	webhookService := application.NewWebhookService(webhookRepo)
+	productService := application.NewProductService(productRepo, productOverrideRepo)
+	recommendationService := application.NewRecommendationService(voteRepo, curatedProducts, similarityCache, sessionRepo, productService)
+	deckService := application.NewDeckService(sessionRepo, voteRepo, curatedProducts, impressionRepo, recommendationService, availability, productService)
+	experimentService := application.NewExperimentService(experimentRepo)
+	impressionService := application.NewImpressionService(sessionRepo, curatedProducts, impressionRepo)
+	catalogService := application.NewCatalogService(productRepo, productHistoryRepo)
+	productOverrideService := application.NewProductOverrideService(productOverrideRepo, productRepo)
+	productAliasService := application.NewProductAliasService(productAliasRepo, productRepo)
+
+	// Keep the recommendation model up to date with new votes
+	recommendationService.Start()
+	closers = append(closers, recommendationService.Close)
+
+	// Start delivering outbox events to webhooks
+	webhookDispatcher := application.NewWebhookDispatcher(outboxRepo, webhookRepo, webhookClient)
+	webhookDispatcher.Start()
+	closers = append(closers, webhookDispatcher.Close)
 
 	// Create router
-	router := httpRouter.NewRouter(sessionService, voteService)
+	router := httpRouter.NewRouter(sessionService, voteService, webhookService, recommendationService, deckService, experimentService, impressionService, catalogService, productService, productOverrideService, productAliasService)
 
 	// Start server
 	port := os.Getenv("PORT")
@@ -0,0 +1,94 @@
+package application
+
+import (
+	"context"
+
+	"github.com/ArtemSind/food_tinder/internal/domain"
+	"github.com/google/uuid"
+)
+
+type CatalogRepository interface {
+	GetCatalogValidation(ctx context.Context) (*domain.CatalogValidation, error)
+	TriggerRefresh() bool
+	ForceRefresh() bool
+	GetRefreshStatus(ctx context.Context) (*domain.RefreshStatus, error)
+	ListSnapshots(ctx context.Context) ([]*domain.CatalogSnapshot, error)
+	RollbackToSnapshot(ctx context.Context, version int64) (*domain.CatalogSnapshot, error)
+	UnpinSnapshot(ctx context.Context) error
+	GetProductCacheStats() *domain.ProductCacheStats
+}
+
+type ProductHistoryRepository interface {
+	GetProductHistory(ctx context.Context, productID uuid.UUID) (*domain.CatalogProduct, []*domain.ProductEvent, error)
+	GetPriceHistory(ctx context.Context, productID uuid.UUID) ([]*domain.PricePoint, error)
+}
+
+// CatalogService reports on the product catalog imported from Foodji
+type CatalogService struct {
+	repo    CatalogRepository
+	history ProductHistoryRepository
+}
+
+func NewCatalogService(repo CatalogRepository, history ProductHistoryRepository) *CatalogService {
+	return &CatalogService{repo: repo, history: history}
+}
+
+func (s *CatalogService) GetValidation(ctx context.Context) (*domain.CatalogValidation, error) {
+	return s.repo.GetCatalogValidation(ctx)
+}
+
+// RefreshCatalog starts a refresh in the background unless one is already running,
+// and returns whether it started together with the current status. A forced refresh
+// replaces the catalog even if the shrink guard would refuse it.
+func (s *CatalogService) RefreshCatalog(ctx context.Context, force bool) (bool, *domain.RefreshStatus, error) {
+	var started bool
+	if force {
+		started = s.repo.ForceRefresh()
+	} else {
+		started = s.repo.TriggerRefresh()
+	}
+
+	status, err := s.repo.GetRefreshStatus(ctx)
+	if err != nil {
+		return false, nil, err
+	}
+	// The background refresh may not have recorded itself yet
+	if started {
+		status.Running = true
+	}
+	return started, status, nil
+}
+
+func (s *CatalogService) GetRefreshStatus(ctx context.Context) (*domain.RefreshStatus, error) {
+	return s.repo.GetRefreshStatus(ctx)
+}
+
+func (s *CatalogService) ListSnapshots(ctx context.Context) ([]*domain.CatalogSnapshot, error) {
+	return s.repo.ListSnapshots(ctx)
+}
+
+// RollbackCatalog serves products from an earlier snapshot until it is unpinned or a refresh is forced
+func (s *CatalogService) RollbackCatalog(ctx context.Context, version int64) (*domain.CatalogSnapshot, error) {
+	return s.repo.RollbackToSnapshot(ctx, version)
+}
+
+// UnpinCatalog lets refreshes replace the snapshot the catalog was rolled back to
+func (s *CatalogService) UnpinCatalog(ctx context.Context) error {
+	return s.repo.UnpinSnapshot(ctx)
+}
+
+// GetProductCacheStats returns how many product lookups this replica served from memory
+func (s *CatalogService) GetProductCacheStats() *domain.ProductCacheStats {
+	return s.repo.GetProductCacheStats()
+}
+
+// GetProductHistory returns a product as last seen in the catalog, including discontinued
+// products, and when it was added, changed and removed
+func (s *CatalogService) GetProductHistory(ctx context.Context, productID uuid.UUID) (*domain.CatalogProduct, []*domain.ProductEvent, error) {
+	return s.history.GetProductHistory(ctx, productID)
+}
+
+// GetPriceHistory returns the prices of a product, oldest first, with the votes cast at each price
+func (s *CatalogService) GetPriceHistory(ctx context.Context, productID uuid.UUID) ([]*domain.PricePoint, error) {
+	return s.history.GetPriceHistory(ctx, productID)
+}
@@ -0,0 +1,121 @@
+package application_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/ArtemSind/food_tinder/internal/application"
+	"github.com/ArtemSind/food_tinder/internal/domain"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+// Mock CatalogRepository
+type MockCatalogRepository struct {
+	mock.Mock
+}
+
+func (m *MockCatalogRepository) GetCatalogValidation(ctx context.Context) (*domain.CatalogValidation, error) {
+	args := m.Called(ctx)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*domain.CatalogValidation), args.Error(1)
+}
+
+func (m *MockCatalogRepository) TriggerRefresh() bool {
+	return m.Called().Bool(0)
+}
+
+func (m *MockCatalogRepository) ForceRefresh() bool {
+	return m.Called().Bool(0)
+}
+
+func (m *MockCatalogRepository) GetProductCacheStats() *domain.ProductCacheStats {
+	args := m.Called()
+	return args.Get(0).(*domain.ProductCacheStats)
+}
+
+func (m *MockCatalogRepository) GetRefreshStatus(ctx context.Context) (*domain.RefreshStatus, error) {
+	args := m.Called(ctx)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*domain.RefreshStatus), args.Error(1)
+}
+
+func (m *MockCatalogRepository) ListSnapshots(ctx context.Context) ([]*domain.CatalogSnapshot, error) {
+	args := m.Called(ctx)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*domain.CatalogSnapshot), args.Error(1)
+}
+
+func (m *MockCatalogRepository) UnpinSnapshot(ctx context.Context) error {
+	return m.Called(ctx).Error(0)
+}
+
+func (m *MockCatalogRepository) RollbackToSnapshot(ctx context.Context, version int64) (*domain.CatalogSnapshot, error) {
+	args := m.Called(ctx, version)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*domain.CatalogSnapshot), args.Error(1)
+}
+
+func TestCatalogService_RefreshCatalog(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("reports a started refresh as running", func(t *testing.T) {
+		// Arrange
+		mockRepo := new(MockCatalogRepository)
+		service := application.NewCatalogService(mockRepo, nil)
+		mockRepo.On("TriggerRefresh").Return(true).Once()
+		mockRepo.On("GetRefreshStatus", ctx).Return(&domain.RefreshStatus{}, nil).Once()
+
+		// Act
+		started, status, err := service.RefreshCatalog(ctx, false)
+
+		// Assert
+		require.NoError(t, err)
+		assert.True(t, started)
+		assert.True(t, status.Running)
+		mockRepo.AssertExpectations(t)
+	})
+
+	t.Run("does not start a second refresh", func(t *testing.T) {
+		// Arrange
+		mockRepo := new(MockCatalogRepository)
+		service := application.NewCatalogService(mockRepo, nil)
+		mockRepo.On("TriggerRefresh").Return(false).Once()
+		mockRepo.On("GetRefreshStatus", ctx).Return(&domain.RefreshStatus{Running: true}, nil).Once()
+
+		// Act
+		started, status, err := service.RefreshCatalog(ctx, false)
+
+		// Assert
+		require.NoError(t, err)
+		assert.False(t, started)
+		assert.True(t, status.Running)
+		mockRepo.AssertExpectations(t)
+	})
+
+	t.Run("forces a refresh", func(t *testing.T) {
+		// Arrange
+		mockRepo := new(MockCatalogRepository)
+		service := application.NewCatalogService(mockRepo, nil)
+		mockRepo.On("ForceRefresh").Return(true).Once()
+		mockRepo.On("GetRefreshStatus", ctx).Return(&domain.RefreshStatus{}, nil).Once()
+
+		// Act
+		started, _, err := service.RefreshCatalog(ctx, true)
+
+		// Assert
+		require.NoError(t, err)
+		assert.True(t, started)
+		mockRepo.AssertNotCalled(t, "TriggerRefresh")
+		mockRepo.AssertExpectations(t)
+	})
+}
@@ -0,0 +1,225 @@
+package application
+
+import (
+	"context"
+	"errors"
+	"math/rand"
+	"sync"
+	"time"
+
+	"github.com/ArtemSind/food_tinder/internal/domain"
+	"github.com/google/uuid"
+)
+
+const (
+	// DefaultDeckSize is the number of products returned when no limit is requested
+	DefaultDeckSize = 20
+	// MaxDeckSize caps the number of products returned at once
+	MaxDeckSize = 100
+)
+
+type ImpressionRepository interface {
+	CreateBatch(ctx context.Context, impressions []*domain.Impression) error
+}
+
+// ProductPins is implemented by product repositories that let admins pin products to the front of the deck
+type ProductPins interface {
+	PinnedProducts(ctx context.Context) ([]uuid.UUID, error)
+}
+
+// ProductAvailability reports the products that have no units left
+type ProductAvailability interface {
+	SoldOutProducts(ctx context.Context) ([]uuid.UUID, error)
+}
+
+// DietaryFilter finds the catalog products that dietary preferences rule out
+type DietaryFilter interface {
+	ExcludedProducts(ctx context.Context, preferences domain.DietaryPreferences) (map[uuid.UUID]bool, error)
+}
+
+type Recommender interface {
+	RecommendFor(sessionVotes []*domain.Vote, candidates []uuid.UUID) ([]*domain.Recommendation, error)
+}
+
+// DeckService orders the products a session has not rated yet. Sold-out products are left
+// out of the deck if it has a product availability, and products ruled out by the session's
+// dietary preferences if it has a dietary filter.
+type DeckService struct {
+	sessionRepo    SessionRepository
+	voteRepo       VoteRepository
+	productRepo    ProductRepository
+	impressionRepo ImpressionRepository
+	recommender    Recommender
+	availability   ProductAvailability
+	dietary        DietaryFilter
+
+	mu  sync.Mutex
+	rng *rand.Rand
+}
+
+func NewDeckService(
+	sessionRepo SessionRepository,
+	voteRepo VoteRepository,
+	productRepo ProductRepository,
+	impressionRepo ImpressionRepository,
+	recommender Recommender,
+	availability ProductAvailability,
+	dietary DietaryFilter,
+) *DeckService {
+	return &DeckService{
+		sessionRepo:    sessionRepo,
+		voteRepo:       voteRepo,
+		productRepo:    productRepo,
+		impressionRepo: impressionRepo,
+		recommender:    recommender,
+		availability:   availability,
+		dietary:        dietary,
+		rng:            rand.New(rand.NewSource(time.Now().UnixNano())),
+	}
+}
+
+// GetDeck returns the next products to show, ordered by the session's strategy,
+// and records an impression for each of them
+func (s *DeckService) GetDeck(ctx context.Context, sessionID uuid.UUID, limit int) (*domain.Deck, error) {
+	if limit <= 0 {
+		limit = DefaultDeckSize
+	}
+	if limit > MaxDeckSize {
+		limit = MaxDeckSize
+	}
+
+	session, err := s.sessionRepo.GetByID(ctx, sessionID)
+	if err != nil {
+		return nil, err
+	}
+
+	products, err := s.productRepo.ListProducts(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	votes, err := s.voteRepo.GetBySessionID(ctx, sessionID)
+	if err != nil {
+		return nil, err
+	}
+
+	// Products that changed since the session rated them come back to be rated again
+	rated := make(map[uuid.UUID]bool, len(votes))
+	for _, vote := range votes {
+		if !vote.Outdated {
+			rated[vote.ProductID] = true
+		}
+	}
+
+	soldOut, err := s.soldOutProducts(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var unsafe map[uuid.UUID]bool
+	if s.dietary != nil {
+		unsafe, err = s.dietary.ExcludedProducts(ctx, session.Dietary)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	unrated := make([]uuid.UUID, 0, len(products))
+	for _, id := range products {
+		if !rated[id] && !soldOut[id] && !unsafe[id] {
+			unrated = append(unrated, id)
+		}
+	}
+
+	ordered, err := s.order(ctx, session.DeckStrategy, unrated, votes)
+	if err != nil {
+		return nil, err
+	}
+	if pins, ok := s.productRepo.(ProductPins); ok {
+		pinned, err := pins.PinnedProducts(ctx)
+		if err != nil {
+			return nil, err
+		}
+		ordered = domain.PinFirst(ordered, pinned)
+	}
+	if len(ordered) > limit {
+		ordered = ordered[:limit]
+	}
+
+	deck := &domain.Deck{
+		SessionID: sessionID,
+		Strategy:  session.DeckStrategy,
+		Products:  ordered,
+	}
+
+	if err := s.impressionRepo.CreateBatch(ctx, domain.NewDeckImpressions(deck)); err != nil {
+		return nil, err
+	}
+
+	return deck, nil
+}
+
+// soldOutProducts returns the products to leave out of the deck because they have no units left
+func (s *DeckService) soldOutProducts(ctx context.Context) (map[uuid.UUID]bool, error) {
+	if s.availability == nil {
+		return nil, nil
+	}
+
+	ids, err := s.availability.SoldOutProducts(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	soldOut := make(map[uuid.UUID]bool, len(ids))
+	for _, id := range ids {
+		soldOut[id] = true
+	}
+	return soldOut, nil
+}
+
+func (s *DeckService) order(ctx context.Context, strategy domain.DeckStrategy, products []uuid.UUID, votes []*domain.Vote) ([]uuid.UUID, error) {
+	if strategy == domain.DeckRandom {
+		s.mu.Lock()
+		defer s.mu.Unlock()
+		return domain.OrderRandom(products, s.rng), nil
+	}
+
+	scores, err := s.scoresByProduct(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	switch strategy {
+	case domain.DeckPopularity:
+		return domain.OrderByPopularity(products, scores), nil
+	case domain.DeckBandit:
+		s.mu.Lock()
+		defer s.mu.Unlock()
+		return domain.OrderByThompsonSampling(products, scores, s.rng), nil
+	case domain.DeckRecommendation:
+		// Until the model is computed the deck degrades to popularity order
+		var recommendations []*domain.Recommendation
+		if s.recommender != nil {
+			recommendations, err = s.recommender.RecommendFor(votes, products)
+			if err != nil && !errors.Is(err, domain.ErrModelNotReady) {
+				return nil, err
+			}
+		}
+		return domain.OrderByRecommendations(products, recommendations, scores), nil
+	default:
+		return nil, domain.ErrInvalidDeckStrategy
+	}
+}
+
+func (s *DeckService) scoresByProduct(ctx context.Context) (map[uuid.UUID]*domain.ProductScore, error) {
+	scores, err := s.voteRepo.GetAggregatedScores(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	byProduct := make(map[uuid.UUID]*domain.ProductScore, len(scores))
+	for _, score := range scores {
+		byProduct[score.ProductID] = score
+	}
+	return byProduct, nil
+}
@@ -0,0 +1,201 @@
+package application_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/ArtemSind/food_tinder/internal/application"
+	"github.com/ArtemSind/food_tinder/internal/domain"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+// Mock ImpressionRepository
+type MockImpressionRepository struct {
+	mock.Mock
+}
+
+func (m *MockImpressionRepository) CreateBatch(ctx context.Context, impressions []*domain.Impression) error {
+	args := m.Called(ctx, impressions)
+	return args.Error(0)
+}
+
+// FakeProductAvailability reports a fixed set of sold-out products
+type FakeProductAvailability struct {
+	soldOut []uuid.UUID
+}
+
+func (a *FakeProductAvailability) SoldOutProducts(ctx context.Context) ([]uuid.UUID, error) {
+	return a.soldOut, nil
+}
+
+// FakeDietaryFilter rules out a fixed set of products for any preferences
+type FakeDietaryFilter struct {
+	excluded []uuid.UUID
+}
+
+func (f *FakeDietaryFilter) ExcludedProducts(ctx context.Context, preferences domain.DietaryPreferences) (map[uuid.UUID]bool, error) {
+	if preferences.IsEmpty() {
+		return nil, nil
+	}
+	excluded := make(map[uuid.UUID]bool, len(f.excluded))
+	for _, id := range f.excluded {
+		excluded[id] = true
+	}
+	return excluded, nil
+}
+
+func TestDeckService_GetDeck(t *testing.T) {
+	ctx := context.Background()
+	rated, best, worst := uuid.New(), uuid.New(), uuid.New()
+	catalog := &FakeProductRepository{products: []uuid.UUID{rated, worst, best}}
+
+	t.Run("orders unrated products by popularity and records impressions", func(t *testing.T) {
+		// Arrange
+		mockSessions := new(MockSessionRepository)
+		mockVotes := new(MockVoteRepository)
+		mockImpressions := new(MockImpressionRepository)
+		service := application.NewDeckService(mockSessions, mockVotes, catalog, mockImpressions, nil, nil, nil)
+
+		session := &domain.Session{ID: uuid.New(), DeckStrategy: domain.DeckPopularity}
+		vote, _ := domain.NewVote(session.ID, rated, 5)
+
+		mockSessions.On("GetByID", ctx, session.ID).Return(session, nil).Once()
+		mockVotes.On("GetBySessionID", ctx, session.ID).Return([]*domain.Vote{vote}, nil).Once()
+		mockVotes.On("GetAggregatedScores", ctx).Return([]*domain.ProductScore{
+			{ProductID: rated, AvgScore: 5, VoteCount: 1},
+			{ProductID: best, AvgScore: 4.5, VoteCount: 2},
+			{ProductID: worst, AvgScore: 1.5, VoteCount: 2},
+		}, nil).Once()
+		mockImpressions.On("CreateBatch", ctx, mock.MatchedBy(func(impressions []*domain.Impression) bool {
+			return len(impressions) == 2 &&
+				impressions[0].ProductID == best &&
+				impressions[0].Strategy == domain.DeckPopularity &&
+				impressions[1].Position == 1
+		})).Return(nil).Once()
+
+		// Act
+		deck, err := service.GetDeck(ctx, session.ID, 0)
+
+		// Assert
+		require.NoError(t, err)
+		assert.Equal(t, domain.DeckPopularity, deck.Strategy)
+		assert.Equal(t, []uuid.UUID{best, worst}, deck.Products)
+
+		mockSessions.AssertExpectations(t)
+		mockVotes.AssertExpectations(t)
+		mockImpressions.AssertExpectations(t)
+	})
+
+	t.Run("products that changed since they were rated come back", func(t *testing.T) {
+		// Arrange
+		mockSessions := new(MockSessionRepository)
+		mockVotes := new(MockVoteRepository)
+		mockImpressions := new(MockImpressionRepository)
+		service := application.NewDeckService(mockSessions, mockVotes, catalog, mockImpressions, nil, nil, nil)
+
+		session := &domain.Session{ID: uuid.New(), DeckStrategy: domain.DeckPopularity}
+		current, _ := domain.NewVote(session.ID, rated, 5)
+		outdated, _ := domain.NewVote(session.ID, best, 2)
+		outdated.Outdated = true
+
+		mockSessions.On("GetByID", ctx, session.ID).Return(session, nil).Once()
+		mockVotes.On("GetBySessionID", ctx, session.ID).Return([]*domain.Vote{current, outdated}, nil).Once()
+		mockVotes.On("GetAggregatedScores", ctx).Return([]*domain.ProductScore{
+			{ProductID: best, AvgScore: 4.5, VoteCount: 2},
+			{ProductID: worst, AvgScore: 1.5, VoteCount: 2},
+		}, nil).Once()
+		mockImpressions.On("CreateBatch", ctx, mock.Anything).Return(nil).Once()
+
+		// Act
+		deck, err := service.GetDeck(ctx, session.ID, 0)
+
+		// Assert
+		require.NoError(t, err)
+		assert.Equal(t, []uuid.UUID{best, worst}, deck.Products)
+	})
+
+	t.Run("sold-out products are left out", func(t *testing.T) {
+		// Arrange
+		mockSessions := new(MockSessionRepository)
+		mockVotes := new(MockVoteRepository)
+		mockImpressions := new(MockImpressionRepository)
+		availability := &FakeProductAvailability{soldOut: []uuid.UUID{best}}
+		service := application.NewDeckService(mockSessions, mockVotes, catalog, mockImpressions, nil, availability, nil)
+
+		session := &domain.Session{ID: uuid.New(), DeckStrategy: domain.DeckRandom}
+		mockSessions.On("GetByID", ctx, session.ID).Return(session, nil).Once()
+		mockVotes.On("GetBySessionID", ctx, session.ID).Return([]*domain.Vote{}, nil).Once()
+		mockImpressions.On("CreateBatch", ctx, mock.Anything).Return(nil).Once()
+
+		// Act
+		deck, err := service.GetDeck(ctx, session.ID, 0)
+
+		// Assert
+		require.NoError(t, err)
+		assert.ElementsMatch(t, []uuid.UUID{rated, worst}, deck.Products)
+	})
+
+	t.Run("products ruled out by dietary preferences are left out", func(t *testing.T) {
+		// Arrange
+		mockSessions := new(MockSessionRepository)
+		mockVotes := new(MockVoteRepository)
+		mockImpressions := new(MockImpressionRepository)
+		dietary := &FakeDietaryFilter{excluded: []uuid.UUID{worst}}
+		service := application.NewDeckService(mockSessions, mockVotes, catalog, mockImpressions, nil, nil, dietary)
+
+		session := &domain.Session{
+			ID:           uuid.New(),
+			DeckStrategy: domain.DeckRandom,
+			Dietary:      domain.DietaryPreferences{ExcludedAllergens: []string{"nuts"}},
+		}
+		mockSessions.On("GetByID", ctx, session.ID).Return(session, nil).Once()
+		mockVotes.On("GetBySessionID", ctx, session.ID).Return([]*domain.Vote{}, nil).Once()
+		mockImpressions.On("CreateBatch", ctx, mock.Anything).Return(nil).Once()
+
+		// Act
+		deck, err := service.GetDeck(ctx, session.ID, 0)
+
+		// Assert
+		require.NoError(t, err)
+		assert.ElementsMatch(t, []uuid.UUID{rated, best}, deck.Products)
+	})
+
+	t.Run("random strategy does not need aggregates", func(t *testing.T) {
+		// Arrange
+		mockSessions := new(MockSessionRepository)
+		mockVotes := new(MockVoteRepository)
+		mockImpressions := new(MockImpressionRepository)
+		service := application.NewDeckService(mockSessions, mockVotes, catalog, mockImpressions, nil, nil, nil)
+
+		session := &domain.Session{ID: uuid.New(), DeckStrategy: domain.DeckRandom}
+		mockSessions.On("GetByID", ctx, session.ID).Return(session, nil).Once()
+		mockVotes.On("GetBySessionID", ctx, session.ID).Return([]*domain.Vote{}, nil).Once()
+		mockImpressions.On("CreateBatch", ctx, mock.Anything).Return(nil).Once()
+
+		// Act
+		deck, err := service.GetDeck(ctx, session.ID, 2)
+
+		// Assert
+		require.NoError(t, err)
+		assert.Len(t, deck.Products, 2)
+		mockVotes.AssertNotCalled(t, "GetAggregatedScores", mock.Anything)
+	})
+
+	t.Run("unknown session", func(t *testing.T) {
+		// Arrange
+		mockSessions := new(MockSessionRepository)
+		service := application.NewDeckService(mockSessions, new(MockVoteRepository), catalog, new(MockImpressionRepository), nil, nil, nil)
+		sessionID := uuid.New()
+		mockSessions.On("GetByID", ctx, sessionID).Return(nil, domain.ErrSessionNotFound).Once()
+
+		// Act
+		deck, err := service.GetDeck(ctx, sessionID, 0)
+
+		// Assert
+		assert.Equal(t, domain.ErrSessionNotFound, err)
+		assert.Nil(t, deck)
+	})
+}
@@ -0,0 +1,81 @@
+package application
+
+import (
+	"context"
+
+	"github.com/ArtemSind/food_tinder/internal/domain"
+	"github.com/google/uuid"
+)
+
+type ExperimentRepository interface {
+	Create(ctx context.Context, experiment *domain.Experiment) error
+	GetByID(ctx context.Context, id uuid.UUID) (*domain.Experiment, error)
+	GetRunning(ctx context.Context) (*domain.Experiment, error)
+	List(ctx context.Context) ([]*domain.Experiment, error)
+	Update(ctx context.Context, experiment *domain.Experiment) error
+	GetVariantStats(ctx context.Context, experimentID uuid.UUID) ([]*domain.VariantStats, error)
+}
+
+type ExperimentService struct {
+	repo ExperimentRepository
+}
+
+func NewExperimentService(repo ExperimentRepository) *ExperimentService {
+	return &ExperimentService{repo: repo}
+}
+
+// CreateExperiment starts a new experiment; only one experiment can run at a time
+func (s *ExperimentService) CreateExperiment(ctx context.Context, name, description string, variants []domain.Variant) (*domain.Experiment, error) {
+	experiment, err := domain.NewExperiment(name, description, variants)
+	if err != nil {
+		return nil, err
+	}
+
+	running, err := s.repo.GetRunning(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if running != nil {
+		return nil, domain.ErrExperimentAlreadyRunning
+	}
+
+	if err := s.repo.Create(ctx, experiment); err != nil {
+		return nil, err
+	}
+	return experiment, nil
+}
+
+func (s *ExperimentService) ListExperiments(ctx context.Context) ([]*domain.Experiment, error) {
+	return s.repo.List(ctx)
+}
+
+func (s *ExperimentService) StopExperiment(ctx context.Context, id uuid.UUID) (*domain.Experiment, error) {
+	experiment, err := s.repo.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if experiment.Status == domain.ExperimentStopped {
+		return experiment, nil
+	}
+
+	experiment.Stop()
+	if err := s.repo.Update(ctx, experiment); err != nil {
+		return nil, err
+	}
+	return experiment, nil
+}
+
+// GetResults reports votes per impression and average score per variant
+func (s *ExperimentService) GetResults(ctx context.Context, id uuid.UUID) (*domain.Experiment, []*domain.VariantResult, error) {
+	experiment, err := s.repo.GetByID(ctx, id)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	stats, err := s.repo.GetVariantStats(ctx, id)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return experiment, experiment.Analyze(stats), nil
+}
@@ -0,0 +1,139 @@
+package application_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/ArtemSind/food_tinder/internal/application"
+	"github.com/ArtemSind/food_tinder/internal/domain"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+// Mock ExperimentRepository
+type MockExperimentRepository struct {
+	mock.Mock
+}
+
+func (m *MockExperimentRepository) Create(ctx context.Context, experiment *domain.Experiment) error {
+	args := m.Called(ctx, experiment)
+	return args.Error(0)
+}
+
+func (m *MockExperimentRepository) GetByID(ctx context.Context, id uuid.UUID) (*domain.Experiment, error) {
+	args := m.Called(ctx, id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*domain.Experiment), args.Error(1)
+}
+
+func (m *MockExperimentRepository) GetRunning(ctx context.Context) (*domain.Experiment, error) {
+	args := m.Called(ctx)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*domain.Experiment), args.Error(1)
+}
+
+func (m *MockExperimentRepository) List(ctx context.Context) ([]*domain.Experiment, error) {
+	args := m.Called(ctx)
+	return args.Get(0).([]*domain.Experiment), args.Error(1)
+}
+
+func (m *MockExperimentRepository) Update(ctx context.Context, experiment *domain.Experiment) error {
+	args := m.Called(ctx, experiment)
+	return args.Error(0)
+}
+
+func (m *MockExperimentRepository) GetVariantStats(ctx context.Context, experimentID uuid.UUID) ([]*domain.VariantStats, error) {
+	args := m.Called(ctx, experimentID)
+	return args.Get(0).([]*domain.VariantStats), args.Error(1)
+}
+
+var testVariants = []domain.Variant{
+	{Name: "control", Strategy: domain.DeckRandom, Weight: 1},
+	{Name: "treatment", Strategy: domain.DeckBandit, Weight: 1},
+}
+
+func TestExperimentService_CreateExperiment(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("creates experiment", func(t *testing.T) {
+		// Arrange
+		mockRepo := new(MockExperimentRepository)
+		service := application.NewExperimentService(mockRepo)
+		mockRepo.On("GetRunning", ctx).Return(nil, nil).Once()
+		mockRepo.On("Create", ctx, mock.AnythingOfType("*domain.Experiment")).Return(nil).Once()
+
+		// Act
+		experiment, err := service.CreateExperiment(ctx, "bandit vs random", "", testVariants)
+
+		// Assert
+		require.NoError(t, err)
+		assert.Equal(t, domain.ExperimentRunning, experiment.Status)
+		mockRepo.AssertExpectations(t)
+	})
+
+	t.Run("rejects a second running experiment", func(t *testing.T) {
+		// Arrange
+		mockRepo := new(MockExperimentRepository)
+		service := application.NewExperimentService(mockRepo)
+		running, _ := domain.NewExperiment("running", "", testVariants)
+		mockRepo.On("GetRunning", ctx).Return(running, nil).Once()
+
+		// Act
+		experiment, err := service.CreateExperiment(ctx, "another", "", testVariants)
+
+		// Assert
+		assert.Equal(t, domain.ErrExperimentAlreadyRunning, err)
+		assert.Nil(t, experiment)
+		mockRepo.AssertNotCalled(t, "Create", mock.Anything, mock.Anything)
+	})
+}
+
+func TestExperimentService_StopExperiment(t *testing.T) {
+	// Arrange
+	ctx := context.Background()
+	mockRepo := new(MockExperimentRepository)
+	service := application.NewExperimentService(mockRepo)
+	experiment, _ := domain.NewExperiment("running", "", testVariants)
+
+	mockRepo.On("GetByID", ctx, experiment.ID).Return(experiment, nil).Once()
+	mockRepo.On("Update", ctx, experiment).Return(nil).Once()
+
+	// Act
+	stopped, err := service.StopExperiment(ctx, experiment.ID)
+
+	// Assert
+	require.NoError(t, err)
+	assert.Equal(t, domain.ExperimentStopped, stopped.Status)
+	assert.NotNil(t, stopped.StoppedAt)
+	mockRepo.AssertExpectations(t)
+}
+
+func TestExperimentService_GetResults(t *testing.T) {
+	// Arrange
+	ctx := context.Background()
+	mockRepo := new(MockExperimentRepository)
+	service := application.NewExperimentService(mockRepo)
+	experiment, _ := domain.NewExperiment("running", "", testVariants)
+
+	mockRepo.On("GetByID", ctx, experiment.ID).Return(experiment, nil).Once()
+	mockRepo.On("GetVariantStats", ctx, experiment.ID).Return([]*domain.VariantStats{
+		{Variant: "control", Sessions: 1, Impressions: 4, Votes: 2, ScoreSum: 6, ScoreSquaredSum: 18},
+	}, nil).Once()
+
+	// Act
+	_, results, err := service.GetResults(ctx, experiment.ID)
+
+	// Assert
+	require.NoError(t, err)
+	require.Len(t, results, 2)
+	assert.InDelta(t, 0.5, results[0].VotesPerImpression, 1e-9)
+	assert.InDelta(t, 3.0, results[0].AvgScore, 1e-9)
+	assert.Zero(t, results[1].Votes)
+	mockRepo.AssertExpectations(t)
+}
@@ -0,0 +1,53 @@
+package application
+
+import (
+	"context"
+
+	"github.com/ArtemSind/food_tinder/internal/domain"
+	"github.com/google/uuid"
+)
+
+// ImpressionService records what clients showed to a session and what it skipped
+type ImpressionService struct {
+	sessionRepo    SessionRepository
+	productRepo    ProductRepository
+	impressionRepo ImpressionRepository
+}
+
+func NewImpressionService(sessionRepo SessionRepository, productRepo ProductRepository, impressionRepo ImpressionRepository) *ImpressionService {
+	return &ImpressionService{
+		sessionRepo:    sessionRepo,
+		productRepo:    productRepo,
+		impressionRepo: impressionRepo,
+	}
+}
+
+// RecordImpression stores an impression or skip; it is attributed to the session's current deck strategy
+func (s *ImpressionService) RecordImpression(ctx context.Context, sessionID, productID uuid.UUID, action string, position int) (*domain.Impression, error) {
+	parsed, err := domain.ParseImpressionAction(action)
+	if err != nil {
+		return nil, err
+	}
+
+	session, err := s.sessionRepo.GetByID(ctx, sessionID)
+	if err != nil {
+		return nil, err
+	}
+
+	if resolver, ok := s.productRepo.(ProductAliasResolver); ok {
+		productID, err = resolver.ResolveProductID(ctx, productID)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if _, err := s.productRepo.GetProduct(ctx, productID); err != nil {
+		return nil, err
+	}
+
+	impression := domain.NewImpression(session, productID, position, parsed)
+	if err := s.impressionRepo.CreateBatch(ctx, []*domain.Impression{impression}); err != nil {
+		return nil, err
+	}
+	return impression, nil
+}
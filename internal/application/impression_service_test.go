@@ -0,0 +1,74 @@
+package application_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/ArtemSind/food_tinder/internal/application"
+	"github.com/ArtemSind/food_tinder/internal/domain"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestImpressionService_RecordImpression(t *testing.T) {
+	ctx := context.Background()
+	productID := uuid.New()
+	catalog := &FakeProductRepository{products: []uuid.UUID{productID}}
+
+	t.Run("records a skip with the session strategy", func(t *testing.T) {
+		// Arrange
+		mockSessions := new(MockSessionRepository)
+		mockImpressions := new(MockImpressionRepository)
+		service := application.NewImpressionService(mockSessions, catalog, mockImpressions)
+
+		session := &domain.Session{ID: uuid.New(), DeckStrategy: domain.DeckBandit}
+		mockSessions.On("GetByID", ctx, session.ID).Return(session, nil).Once()
+		mockImpressions.On("CreateBatch", ctx, mock.MatchedBy(func(impressions []*domain.Impression) bool {
+			return len(impressions) == 1 &&
+				impressions[0].Action == domain.ActionSkip &&
+				impressions[0].Strategy == domain.DeckBandit &&
+				impressions[0].Position == 4
+		})).Return(nil).Once()
+
+		// Act
+		impression, err := service.RecordImpression(ctx, session.ID, productID, "skip", 4)
+
+		// Assert
+		require.NoError(t, err)
+		assert.Equal(t, productID, impression.ProductID)
+		mockSessions.AssertExpectations(t)
+		mockImpressions.AssertExpectations(t)
+	})
+
+	t.Run("unknown product", func(t *testing.T) {
+		// Arrange
+		mockSessions := new(MockSessionRepository)
+		mockImpressions := new(MockImpressionRepository)
+		service := application.NewImpressionService(mockSessions, catalog, mockImpressions)
+
+		session := &domain.Session{ID: uuid.New(), DeckStrategy: domain.DeckRandom}
+		mockSessions.On("GetByID", ctx, session.ID).Return(session, nil).Once()
+
+		// Act
+		impression, err := service.RecordImpression(ctx, session.ID, uuid.New(), "", 0)
+
+		// Assert
+		assert.ErrorIs(t, err, domain.ErrProductNotFound)
+		assert.Nil(t, impression)
+		mockImpressions.AssertNotCalled(t, "CreateBatch", mock.Anything, mock.Anything)
+	})
+
+	t.Run("invalid action", func(t *testing.T) {
+		// Arrange
+		service := application.NewImpressionService(new(MockSessionRepository), catalog, new(MockImpressionRepository))
+
+		// Act
+		impression, err := service.RecordImpression(ctx, uuid.New(), productID, "like", 0)
+
+		// Assert
+		assert.Equal(t, domain.ErrInvalidImpressionAction, err)
+		assert.Nil(t, impression)
+	})
+}
@@ -0,0 +1,44 @@
+package application
+
+import (
+	"context"
+
+	"github.com/ArtemSind/food_tinder/internal/domain"
+	"github.com/google/uuid"
+)
+
+type ProductAliasRepository interface {
+	CreateAlias(ctx context.Context, alias *domain.ProductAlias) error
+	ListAliases(ctx context.Context) ([]*domain.ProductAlias, error)
+}
+
+// ProductAliasService merges the ratings of products Foodji re-issued under a new ID
+type ProductAliasService struct {
+	repo    ProductAliasRepository
+	curated CuratedProductInvalidator
+}
+
+// NewProductAliasService creates an alias service. The invalidator is optional; when set, every
+// new alias drops the cached curated products.
+func NewProductAliasService(repo ProductAliasRepository, curated CuratedProductInvalidator) *ProductAliasService {
+	return &ProductAliasService{repo: repo, curated: curated}
+}
+
+// CreateAlias declares aliasID an alias of productID and moves its votes and impressions
+func (s *ProductAliasService) CreateAlias(ctx context.Context, aliasID, productID uuid.UUID) (*domain.ProductAlias, error) {
+	alias, err := domain.NewProductAlias(aliasID, productID)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.repo.CreateAlias(ctx, alias); err != nil {
+		return nil, err
+	}
+	if s.curated != nil {
+		s.curated.InvalidateCuratedProducts(ctx)
+	}
+	return alias, nil
+}
+
+func (s *ProductAliasService) ListAliases(ctx context.Context) ([]*domain.ProductAlias, error) {
+	return s.repo.ListAliases(ctx)
+}
@@ -0,0 +1,138 @@
+package application_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/ArtemSind/food_tinder/internal/application"
+	"github.com/ArtemSind/food_tinder/internal/domain"
+	"github.com/ArtemSind/food_tinder/internal/infrastructure/external/foodji"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+// FakeAliasRepository keeps aliases in memory
+type FakeAliasRepository struct {
+	aliases []*domain.ProductAlias
+}
+
+func (r *FakeAliasRepository) CreateAlias(ctx context.Context, alias *domain.ProductAlias) error {
+	for _, existing := range r.aliases {
+		if existing.AliasID == alias.AliasID {
+			return domain.ErrAlreadyAliased
+		}
+	}
+	r.aliases = append(r.aliases, alias)
+	return nil
+}
+
+func (r *FakeAliasRepository) ListAliases(ctx context.Context) ([]*domain.ProductAlias, error) {
+	return r.aliases, nil
+}
+
+func (r *FakeAliasRepository) ResolveAlias(ctx context.Context, id uuid.UUID) (uuid.UUID, error) {
+	for _, alias := range r.aliases {
+		if alias.AliasID == id {
+			return alias.ProductID, nil
+		}
+	}
+	return id, nil
+}
+
+func TestProductAliasService_CreateAlias(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("successful alias", func(t *testing.T) {
+		// Arrange
+		repo := &FakeAliasRepository{}
+		service := application.NewProductAliasService(repo, nil)
+		oldID, newID := uuid.New(), uuid.New()
+
+		// Act
+		alias, err := service.CreateAlias(ctx, oldID, newID)
+
+		// Assert
+		require.NoError(t, err)
+		assert.Equal(t, oldID, alias.AliasID)
+		assert.Equal(t, newID, alias.ProductID)
+		assert.Len(t, repo.aliases, 1)
+	})
+
+	t.Run("a new alias drops cached curated products", func(t *testing.T) {
+		// Arrange
+		repo := &FakeAliasRepository{}
+		cache := &FakeCachingProductRepository{curated: map[uuid.UUID]*foodji.Product{uuid.New(): {}}}
+		service := application.NewProductAliasService(repo, cache)
+
+		// Act
+		_, err := service.CreateAlias(ctx, uuid.New(), uuid.New())
+
+		// Assert
+		require.NoError(t, err)
+		assert.Empty(t, cache.curated)
+	})
+
+	t.Run("product cannot be its own alias", func(t *testing.T) {
+		// Arrange
+		repo := &FakeAliasRepository{}
+		service := application.NewProductAliasService(repo, nil)
+		id := uuid.New()
+
+		// Act
+		alias, err := service.CreateAlias(ctx, id, id)
+
+		// Assert
+		assert.ErrorIs(t, err, domain.ErrSelfAlias)
+		assert.Nil(t, alias)
+		assert.Empty(t, repo.aliases)
+	})
+}
+
+func TestCuratedProductRepository_Aliases(t *testing.T) {
+	ctx := context.Background()
+	oldID, newID, other := uuid.New(), uuid.New(), uuid.New()
+	catalog := &FakeProductRepository{products: []uuid.UUID{oldID, newID, other}}
+	aliases := &FakeAliasRepository{}
+	_, err := application.NewProductAliasService(aliases, nil).CreateAlias(ctx, oldID, newID)
+	require.NoError(t, err)
+	repo := application.NewCuratedProductRepository(catalog, &FakeOverrideRepository{}, aliases)
+
+	t.Run("aliases are left out of the catalog", func(t *testing.T) {
+		// Act
+		ids, err := repo.ListProducts(ctx)
+
+		// Assert
+		require.NoError(t, err)
+		assert.Equal(t, []uuid.UUID{newID, other}, ids)
+	})
+
+	t.Run("an alias resolves to its product", func(t *testing.T) {
+		// Act
+		product, err := repo.GetProduct(ctx, oldID)
+
+		// Assert
+		require.NoError(t, err)
+		assert.Equal(t, newID, product.ID)
+	})
+
+	t.Run("votes on an alias are recorded on its product", func(t *testing.T) {
+		// Arrange
+		mockVoteRepo := new(MockVoteRepository)
+		service := application.NewVoteService(mockVoteRepo, repo)
+		sessionID := uuid.New()
+		mockVoteRepo.On("GetBySessionID", ctx, sessionID).Return([]*domain.Vote{}, nil).Once()
+		mockVoteRepo.On("Create", ctx, mock.MatchedBy(func(vote *domain.Vote) bool {
+			return vote.ProductID == newID
+		})).Return(nil).Once()
+
+		// Act
+		vote, err := service.CreateOrUpdateVote(ctx, sessionID, oldID, 4)
+
+		// Assert
+		require.NoError(t, err)
+		assert.Equal(t, newID, vote.ProductID)
+		mockVoteRepo.AssertExpectations(t)
+	})
+}
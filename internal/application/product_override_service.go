@@ -0,0 +1,225 @@
+package application
+
+import (
+	"context"
+	"errors"
+
+	"github.com/ArtemSind/food_tinder/internal/domain"
+	"github.com/ArtemSind/food_tinder/internal/infrastructure/external/foodji"
+	"github.com/google/uuid"
+)
+
+type ProductOverrideRepository interface {
+	SaveOverride(ctx context.Context, override *domain.ProductOverride) error
+	GetOverride(ctx context.Context, productID uuid.UUID) (*domain.ProductOverride, error)
+	ListOverrides(ctx context.Context) ([]*domain.ProductOverride, error)
+	DeleteOverride(ctx context.Context, productID uuid.UUID) error
+}
+
+// CuratedProductCache is implemented by product repositories that cache curated products until
+// the catalog, an override or an alias changes
+type CuratedProductCache interface {
+	GetCuratedProduct(ctx context.Context, id uuid.UUID, curate func(ctx context.Context, id uuid.UUID) (*foodji.Product, error)) (*foodji.Product, error)
+}
+
+// CuratedProductInvalidator drops the curated products every replica cached
+type CuratedProductInvalidator interface {
+	InvalidateCuratedProducts(ctx context.Context)
+}
+
+// ProductOverrideService lets admins hide, pin and correct catalog products
+type ProductOverrideService struct {
+	repo    ProductOverrideRepository
+	curated CuratedProductInvalidator
+}
+
+// NewProductOverrideService creates an override service. The invalidator is optional; when set,
+// every override change drops the cached curated products.
+func NewProductOverrideService(repo ProductOverrideRepository, curated CuratedProductInvalidator) *ProductOverrideService {
+	return &ProductOverrideService{repo: repo, curated: curated}
+}
+
+// SetOverride creates or replaces the override of a product. Products do not have to be
+// in the catalog, so a product can be hidden before it is imported.
+func (s *ProductOverrideService) SetOverride(ctx context.Context, productID uuid.UUID, hidden, pinned bool, name, category, imageURL *string) (*domain.ProductOverride, error) {
+	override, err := domain.NewProductOverride(productID, hidden, pinned, name, category, imageURL)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.repo.SaveOverride(ctx, override); err != nil {
+		return nil, err
+	}
+	s.invalidate(ctx)
+	return override, nil
+}
+
+func (s *ProductOverrideService) GetOverride(ctx context.Context, productID uuid.UUID) (*domain.ProductOverride, error) {
+	return s.repo.GetOverride(ctx, productID)
+}
+
+func (s *ProductOverrideService) ListOverrides(ctx context.Context) ([]*domain.ProductOverride, error) {
+	return s.repo.ListOverrides(ctx)
+}
+
+func (s *ProductOverrideService) DeleteOverride(ctx context.Context, productID uuid.UUID) error {
+	if err := s.repo.DeleteOverride(ctx, productID); err != nil {
+		return err
+	}
+	s.invalidate(ctx)
+	return nil
+}
+
+func (s *ProductOverrideService) invalidate(ctx context.Context) {
+	if s.curated != nil {
+		s.curated.InvalidateCuratedProducts(ctx)
+	}
+}
+
+type ProductOverrideReader interface {
+	GetOverride(ctx context.Context, productID uuid.UUID) (*domain.ProductOverride, error)
+	ListOverrides(ctx context.Context) ([]*domain.ProductOverride, error)
+}
+
+type ProductAliasReader interface {
+	ResolveAlias(ctx context.Context, id uuid.UUID) (uuid.UUID, error)
+	ListAliases(ctx context.Context) ([]*domain.ProductAlias, error)
+}
+
+// ProductAliasResolver is implemented by product repositories that know which product an
+// alias stands for, so votes and impressions of an alias are recorded on its product
+type ProductAliasResolver interface {
+	ResolveProductID(ctx context.Context, id uuid.UUID) (uuid.UUID, error)
+}
+
+// CuratedProductRepository merges admin overrides over the products of the catalog.
+// Hidden products are left out of ListProducts but can still be fetched, so existing
+// votes on them keep working. Aliases resolve to the product they stand for and are
+// left out of ListProducts. Curated products are cached if the product repository
+// implements CuratedProductCache.
+type CuratedProductRepository struct {
+	products  ProductRepository
+	overrides ProductOverrideReader
+	aliases   ProductAliasReader
+}
+
+func NewCuratedProductRepository(products ProductRepository, overrides ProductOverrideReader, aliases ProductAliasReader) *CuratedProductRepository {
+	return &CuratedProductRepository{products: products, overrides: overrides, aliases: aliases}
+}
+
+// GetProduct returns the product an ID or alias stands for, with its override applied
+func (r *CuratedProductRepository) GetProduct(ctx context.Context, id uuid.UUID) (*foodji.Product, error) {
+	if cache, ok := r.products.(CuratedProductCache); ok {
+		return cache.GetCuratedProduct(ctx, id, r.curateProduct)
+	}
+	return r.curateProduct(ctx, id)
+}
+
+func (r *CuratedProductRepository) curateProduct(ctx context.Context, id uuid.UUID) (*foodji.Product, error) {
+	id, err := r.ResolveProductID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	product, err := r.products.GetProduct(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	override, err := r.overrides.GetOverride(ctx, id)
+	if err != nil {
+		if errors.Is(err, domain.ErrOverrideNotFound) {
+			return product, nil
+		}
+		return nil, err
+	}
+
+	curated := applyOverride(*product, override)
+	return &curated, nil
+}
+
+// ListProducts returns the catalog without hidden products and aliases, pinned products first
+func (r *CuratedProductRepository) ListProducts(ctx context.Context) ([]uuid.UUID, error) {
+	ids, err := r.products.ListProducts(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	overrides, err := r.overrides.ListOverrides(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	hidden := make(map[uuid.UUID]bool)
+	for _, override := range overrides {
+		if override.Hidden {
+			hidden[override.ProductID] = true
+		}
+	}
+	if r.aliases != nil {
+		aliases, err := r.aliases.ListAliases(ctx)
+		if err != nil {
+			return nil, err
+		}
+		for _, alias := range aliases {
+			hidden[alias.AliasID] = true
+		}
+	}
+
+	visible := make([]uuid.UUID, 0, len(ids))
+	for _, id := range ids {
+		if !hidden[id] {
+			visible = append(visible, id)
+		}
+	}
+	return domain.PinFirst(visible, pinnedProducts(overrides)), nil
+}
+
+// ResolveProductID returns the product an alias stands for, or the ID itself if it is no alias
+func (r *CuratedProductRepository) ResolveProductID(ctx context.Context, id uuid.UUID) (uuid.UUID, error) {
+	if r.aliases == nil {
+		return id, nil
+	}
+	return r.aliases.ResolveAlias(ctx, id)
+}
+
+// PinnedProducts returns the pinned products in the order their overrides were created
+func (r *CuratedProductRepository) PinnedProducts(ctx context.Context) ([]uuid.UUID, error) {
+	overrides, err := r.overrides.ListOverrides(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return pinnedProducts(overrides), nil
+}
+
+func pinnedProducts(overrides []*domain.ProductOverride) []uuid.UUID {
+	var pinned []uuid.UUID
+	for _, override := range overrides {
+		if override.Pinned {
+			pinned = append(pinned, override.ProductID)
+		}
+	}
+	return pinned
+}
+
+// applyOverride returns the product with the overridden fields replaced
+func applyOverride(product foodji.Product, override *domain.ProductOverride) foodji.Product {
+	if override.Name != nil {
+		product.Name = *override.Name
+	}
+	if override.Category != nil {
+		product.Category = *override.Category
+	}
+	if override.ImageURL != nil {
+		product.ImageURL = *override.ImageURL
+	}
+	return product
+}
+
+// overridesByProduct indexes overrides by product ID
+func overridesByProduct(overrides []*domain.ProductOverride) map[uuid.UUID]*domain.ProductOverride {
+	byProduct := make(map[uuid.UUID]*domain.ProductOverride, len(overrides))
+	for _, override := range overrides {
+		byProduct[override.ProductID] = override
+	}
+	return byProduct
+}
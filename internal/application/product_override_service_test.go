@@ -0,0 +1,262 @@
+package application_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/ArtemSind/food_tinder/internal/application"
+	"github.com/ArtemSind/food_tinder/internal/domain"
+	"github.com/ArtemSind/food_tinder/internal/infrastructure/external/foodji"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+// FakeOverrideRepository keeps overrides in memory, in the order they were saved
+type FakeOverrideRepository struct {
+	overrides []*domain.ProductOverride
+}
+
+func (r *FakeOverrideRepository) SaveOverride(ctx context.Context, override *domain.ProductOverride) error {
+	for i, existing := range r.overrides {
+		if existing.ProductID == override.ProductID {
+			override.CreatedAt = existing.CreatedAt
+			r.overrides[i] = override
+			return nil
+		}
+	}
+	r.overrides = append(r.overrides, override)
+	return nil
+}
+
+func (r *FakeOverrideRepository) GetOverride(ctx context.Context, productID uuid.UUID) (*domain.ProductOverride, error) {
+	for _, override := range r.overrides {
+		if override.ProductID == productID {
+			return override, nil
+		}
+	}
+	return nil, domain.ErrOverrideNotFound
+}
+
+func (r *FakeOverrideRepository) ListOverrides(ctx context.Context) ([]*domain.ProductOverride, error) {
+	return r.overrides, nil
+}
+
+func (r *FakeOverrideRepository) DeleteOverride(ctx context.Context, productID uuid.UUID) error {
+	for i, override := range r.overrides {
+		if override.ProductID == productID {
+			r.overrides = append(r.overrides[:i], r.overrides[i+1:]...)
+			return nil
+		}
+	}
+	return domain.ErrOverrideNotFound
+}
+
+// FakeCachingProductRepository caches curated products in memory until they are invalidated
+type FakeCachingProductRepository struct {
+	FakeProductRepository
+	curated map[uuid.UUID]*foodji.Product
+}
+
+func (r *FakeCachingProductRepository) GetCuratedProduct(ctx context.Context, id uuid.UUID, curate func(ctx context.Context, id uuid.UUID) (*foodji.Product, error)) (*foodji.Product, error) {
+	if product, ok := r.curated[id]; ok {
+		return product, nil
+	}
+	product, err := curate(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	r.curated[id] = product
+	return product, nil
+}
+
+func (r *FakeCachingProductRepository) InvalidateCuratedProducts(ctx context.Context) {
+	r.curated = make(map[uuid.UUID]*foodji.Product)
+}
+
+func stringPtr(value string) *string {
+	return &value
+}
+
+func TestProductOverrideService_SetOverride(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("replaces an existing override", func(t *testing.T) {
+		// Arrange
+		repo := &FakeOverrideRepository{}
+		service := application.NewProductOverrideService(repo, nil)
+		productID := uuid.New()
+
+		first, err := service.SetOverride(ctx, productID, true, false, nil, nil, nil)
+		require.NoError(t, err)
+
+		// Act
+		second, err := service.SetOverride(ctx, productID, false, false, stringPtr(" Greek Salad "), nil, nil)
+
+		// Assert
+		require.NoError(t, err)
+		assert.Equal(t, first.CreatedAt, second.CreatedAt)
+		assert.False(t, second.Hidden)
+		assert.Equal(t, "Greek Salad", *second.Name)
+
+		overrides, err := service.ListOverrides(ctx)
+		require.NoError(t, err)
+		assert.Len(t, overrides, 1)
+	})
+
+	t.Run("invalid override is not saved", func(t *testing.T) {
+		// Arrange
+		repo := &FakeOverrideRepository{}
+		service := application.NewProductOverrideService(repo, nil)
+
+		// Act
+		override, err := service.SetOverride(ctx, uuid.New(), true, true, nil, nil, nil)
+
+		// Assert
+		assert.ErrorIs(t, err, domain.ErrHiddenPinnedOverride)
+		assert.Nil(t, override)
+		assert.Empty(t, repo.overrides)
+	})
+}
+
+func TestCuratedProductRepository(t *testing.T) {
+	ctx := context.Background()
+	plain, hidden, pinned, renamed := uuid.New(), uuid.New(), uuid.New(), uuid.New()
+	catalog := &FakeProductRepository{products: []uuid.UUID{plain, hidden, pinned, renamed}}
+
+	newRepository := func() *application.CuratedProductRepository {
+		overrides := &FakeOverrideRepository{}
+		service := application.NewProductOverrideService(overrides, nil)
+		_, err := service.SetOverride(ctx, hidden, true, false, nil, nil, nil)
+		require.NoError(t, err)
+		_, err = service.SetOverride(ctx, pinned, false, true, nil, nil, nil)
+		require.NoError(t, err)
+		_, err = service.SetOverride(ctx, renamed, false, false, stringPtr("Greek Salad"), stringPtr("Salads"), stringPtr("https://cdn.example.com/salad.png"))
+		require.NoError(t, err)
+		return application.NewCuratedProductRepository(catalog, overrides, nil)
+	}
+
+	t.Run("lists visible products with pinned ones first", func(t *testing.T) {
+		// Arrange
+		repo := newRepository()
+
+		// Act
+		ids, err := repo.ListProducts(ctx)
+
+		// Assert
+		require.NoError(t, err)
+		assert.Equal(t, []uuid.UUID{pinned, plain, renamed}, ids)
+	})
+
+	t.Run("merges overridden fields", func(t *testing.T) {
+		// Arrange
+		repo := newRepository()
+
+		// Act
+		product, err := repo.GetProduct(ctx, renamed)
+
+		// Assert
+		require.NoError(t, err)
+		assert.Equal(t, "Greek Salad", product.Name)
+		assert.Equal(t, "Salads", product.Category)
+		assert.Equal(t, "https://cdn.example.com/salad.png", product.ImageURL)
+	})
+
+	t.Run("hidden products can still be fetched", func(t *testing.T) {
+		// Arrange
+		repo := newRepository()
+
+		// Act
+		product, err := repo.GetProduct(ctx, hidden)
+
+		// Assert
+		require.NoError(t, err)
+		assert.Equal(t, hidden, product.ID)
+	})
+
+	t.Run("curated products are cached until an override changes", func(t *testing.T) {
+		// Arrange
+		cache := &FakeCachingProductRepository{FakeProductRepository: *catalog, curated: make(map[uuid.UUID]*foodji.Product)}
+		overrides := &FakeOverrideRepository{}
+		service := application.NewProductOverrideService(overrides, cache)
+		repo := application.NewCuratedProductRepository(cache, overrides, nil)
+		_, err := repo.GetProduct(ctx, plain)
+		require.NoError(t, err)
+
+		// Act
+		overrides.overrides = append(overrides.overrides, &domain.ProductOverride{ProductID: plain, Name: stringPtr("Written around the service")})
+		cached, err := repo.GetProduct(ctx, plain)
+		require.NoError(t, err)
+		_, err = service.SetOverride(ctx, plain, false, false, stringPtr("Greek Salad"), nil, nil)
+		require.NoError(t, err)
+		product, err := repo.GetProduct(ctx, plain)
+
+		// Assert
+		require.NoError(t, err)
+		assert.Empty(t, cached.Name, "the cached product is served until an override changes")
+		assert.Equal(t, "Greek Salad", product.Name)
+	})
+
+	t.Run("deck puts pinned products first whatever the strategy", func(t *testing.T) {
+		// Arrange
+		mockSessions := new(MockSessionRepository)
+		mockVotes := new(MockVoteRepository)
+		mockImpressions := new(MockImpressionRepository)
+		service := application.NewDeckService(mockSessions, mockVotes, newRepository(), mockImpressions, nil, nil, nil)
+
+		session := &domain.Session{ID: uuid.New(), DeckStrategy: domain.DeckPopularity}
+		mockSessions.On("GetByID", ctx, session.ID).Return(session, nil).Once()
+		mockVotes.On("GetBySessionID", ctx, session.ID).Return([]*domain.Vote{}, nil).Once()
+		mockVotes.On("GetAggregatedScores", ctx).Return([]*domain.ProductScore{
+			{ProductID: renamed, AvgScore: 4.5, VoteCount: 2},
+			{ProductID: plain, AvgScore: 3, VoteCount: 2},
+			{ProductID: pinned, AvgScore: 1, VoteCount: 2},
+		}, nil).Once()
+		mockImpressions.On("CreateBatch", ctx, mock.Anything).Return(nil).Once()
+
+		// Act
+		deck, err := service.GetDeck(ctx, session.ID, 0)
+
+		// Assert
+		require.NoError(t, err)
+		assert.Equal(t, []uuid.UUID{pinned, renamed, plain}, deck.Products)
+	})
+}
+
+func TestProductService_SearchAppliesOverrides(t *testing.T) {
+	// Arrange
+	ctx := context.Background()
+	soup, salad := uuid.New(), uuid.New()
+	mockRepo := new(MockProductCatalogRepository)
+	mockRepo.On("GetCatalogVersion", ctx).Return(int64(1), nil)
+	mockRepo.On("GetCatalog", ctx).Return(int64(1), []foodji.Product{
+		{ID: soup, Name: "Tomato Soup", Category: "Soups"},
+		{ID: salad, Name: "TEST PRODUCT 2", Category: "Test"},
+	}, nil).Once()
+
+	overrides := &FakeOverrideRepository{}
+	overrideService := application.NewProductOverrideService(overrides, nil)
+	_, err := overrideService.SetOverride(ctx, salad, false, false, stringPtr("Greek Salad"), stringPtr("Salads"), nil)
+	require.NoError(t, err)
+	service := application.NewProductService(mockRepo, overrides)
+
+	// Act
+	products, categories, err := service.SearchProducts(ctx, domain.ProductQuery{Text: "salad"})
+
+	// Assert
+	require.NoError(t, err)
+	require.Len(t, products, 1)
+	assert.Equal(t, salad, products[0].ID)
+	assert.Equal(t, []domain.CategoryFacet{{Category: "Salads", Count: 1}}, categories)
+
+	// Act
+	_, err = overrideService.SetOverride(ctx, soup, true, false, nil, nil, nil)
+	require.NoError(t, err)
+	products, _, err = service.SearchProducts(ctx, domain.ProductQuery{})
+
+	// Assert
+	require.NoError(t, err)
+	require.Len(t, products, 1, "hidden products are left out right away")
+	assert.Equal(t, salad, products[0].ID)
+}
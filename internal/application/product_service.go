@@ -0,0 +1,308 @@
+package application
+
+import (
+	"context"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/ArtemSind/food_tinder/internal/domain"
+	"github.com/ArtemSind/food_tinder/internal/infrastructure/external/foodji"
+	"github.com/google/uuid"
+)
+
+type ProductCatalogRepository interface {
+	// GetCatalogVersion returns the version of the catalog products are served from, or 0 if there is none
+	GetCatalogVersion(ctx context.Context) (int64, error)
+	GetCatalog(ctx context.Context) (int64, []foodji.Product, error)
+}
+
+// ProductStockRevision is implemented by catalog repositories that update stock without a new
+// catalog version
+type ProductStockRevision interface {
+	GetStockRevision(ctx context.Context) (int64, error)
+}
+
+// ProductService searches the catalog through an in-memory index. The index is rebuilt
+// whenever the catalog version changes, which covers refreshes and rollbacks on any replica,
+// and whenever the stock changes if the repository reports stock revisions.
+// Product overrides are merged over the index on every search, so they apply right away.
+type ProductService struct {
+	repo      ProductCatalogRepository
+	overrides ProductOverrideReader
+
+	mu    sync.Mutex
+	index *productIndex
+}
+
+// NewProductService creates a product service. The override reader is optional.
+func NewProductService(repo ProductCatalogRepository, overrides ProductOverrideReader) *ProductService {
+	return &ProductService{repo: repo, overrides: overrides}
+}
+
+// SearchProducts returns the products matching the query, best name matches first, together
+// with the number of matching products per category. Names and categories are in the query's locale.
+func (s *ProductService) SearchProducts(ctx context.Context, query domain.ProductQuery) ([]foodji.Product, []domain.CategoryFacet, error) {
+	index, err := s.currentIndex(ctx)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var overrides []*domain.ProductOverride
+	if s.overrides != nil {
+		overrides, err = s.overrides.ListOverrides(ctx)
+		if err != nil {
+			return nil, nil, err
+		}
+	}
+
+	products, facets := index.search(query, overridesByProduct(overrides))
+	return products, facets, nil
+}
+
+// GetProducts returns the products with the IDs, in the order of the IDs and in the locale, with
+// overrides applied. Hidden products and products the catalog does not have are left out.
+func (s *ProductService) GetProducts(ctx context.Context, ids []uuid.UUID, locale domain.Locale) ([]foodji.Product, error) {
+	index, err := s.currentIndex(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var overrides []*domain.ProductOverride
+	if s.overrides != nil {
+		overrides, err = s.overrides.ListOverrides(ctx)
+		if err != nil {
+			return nil, err
+		}
+	}
+	byProduct := overridesByProduct(overrides)
+
+	localized := index.forLocale(locale)
+	products := make([]foodji.Product, 0, len(ids))
+	for _, id := range ids {
+		position, ok := index.positions[id]
+		if !ok {
+			continue
+		}
+		product := localized[position].product
+		if override := byProduct[id]; override != nil {
+			if override.Hidden {
+				continue
+			}
+			product = applyOverride(product, override)
+		}
+		products = append(products, product)
+	}
+	return products, nil
+}
+
+// ExcludedProducts returns the catalog products that dietary preferences rule out
+func (s *ProductService) ExcludedProducts(ctx context.Context, preferences domain.DietaryPreferences) (map[uuid.UUID]bool, error) {
+	if preferences.IsEmpty() {
+		return nil, nil
+	}
+
+	index, err := s.currentIndex(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	excluded := make(map[uuid.UUID]bool)
+	for _, indexed := range index.products {
+		if !preferences.Allows(indexed.product.Allergens, indexed.product.Diets) {
+			excluded[indexed.product.ID] = true
+		}
+	}
+	return excluded, nil
+}
+
+// currentIndex returns the index of the current catalog, rebuilding it if the catalog or its
+// stock changed
+func (s *ProductService) currentIndex(ctx context.Context) (*productIndex, error) {
+	version, err := s.repo.GetCatalogVersion(ctx)
+	if err != nil {
+		return nil, err
+	}
+	var stockRevision int64
+	if revisions, ok := s.repo.(ProductStockRevision); ok {
+		stockRevision, err = revisions.GetStockRevision(ctx)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.index != nil && s.index.version == version && s.index.stockRevision == stockRevision {
+		return s.index, nil
+	}
+
+	// The revision is read before the catalog, so stock written in between rebuilds it again
+	version, products, err := s.repo.GetCatalog(ctx)
+	if err != nil {
+		return nil, err
+	}
+	s.index = newProductIndex(version, products)
+	s.index.stockRevision = stockRevision
+	return s.index, nil
+}
+
+// indexedProduct is a product with its fields prepared for matching
+type indexedProduct struct {
+	product   foodji.Product
+	terms     []string
+	category  string
+	allergens map[string]bool
+}
+
+// productIndex holds the products of one catalog version
+type productIndex struct {
+	version       int64
+	stockRevision int64
+	products      []indexedProduct
+	// positions maps each product ID to its position in products and in each localized list
+	positions map[uuid.UUID]int
+	// localized holds the products with their texts in each locale but the default one
+	localized map[domain.Locale][]indexedProduct
+}
+
+func newProductIndex(version int64, products []foodji.Product) *productIndex {
+	index := &productIndex{
+		version:   version,
+		products:  make([]indexedProduct, len(products)),
+		positions: make(map[uuid.UUID]int, len(products)),
+		localized: make(map[domain.Locale][]indexedProduct),
+	}
+	for i, product := range products {
+		index.products[i] = newIndexedProduct(product)
+		index.positions[product.ID] = i
+	}
+	for _, locale := range domain.Locales {
+		if locale == domain.DefaultLocale {
+			continue
+		}
+		localized := make([]indexedProduct, len(products))
+		for i, product := range products {
+			localized[i] = newIndexedProduct(product.Localized(string(locale)))
+			// Names in the default language still match, so a product is found by either name
+			localized[i].terms = append(localized[i].terms, domain.SearchTerms(product.Name)...)
+		}
+		index.localized[locale] = localized
+	}
+	return index
+}
+
+// forLocale returns the products with their texts in the locale
+func (idx *productIndex) forLocale(locale domain.Locale) []indexedProduct {
+	if localized, ok := idx.localized[locale]; ok {
+		return localized
+	}
+	return idx.products
+}
+
+func newIndexedProduct(product foodji.Product) indexedProduct {
+	allergens := make(map[string]bool, len(product.Allergens))
+	for _, allergen := range product.Allergens {
+		allergens[strings.ToLower(allergen)] = true
+	}
+	return indexedProduct{
+		product:   product,
+		terms:     domain.SearchTerms(product.Name),
+		category:  strings.ToLower(product.Category),
+		allergens: allergens,
+	}
+}
+
+type productMatch struct {
+	product foodji.Product
+	score   float64
+}
+
+// search returns the products matching the query in its locale, with overrides applied and hidden
+// products left out
+func (idx *productIndex) search(query domain.ProductQuery, overrides map[uuid.UUID]*domain.ProductOverride) ([]foodji.Product, []domain.CategoryFacet) {
+	terms := domain.SearchTerms(query.Text)
+	category := strings.ToLower(query.Category)
+	excluded := make([]string, 0, len(query.ExcludeAllergens))
+	for _, allergen := range query.ExcludeAllergens {
+		if allergen = strings.ToLower(strings.TrimSpace(allergen)); allergen != "" {
+			excluded = append(excluded, allergen)
+		}
+	}
+
+	var matches []productMatch
+	counts := make(map[string]int)
+	for _, indexed := range idx.forLocale(query.Locale) {
+		if override := overrides[indexed.product.ID]; override != nil {
+			if override.Hidden {
+				continue
+			}
+			indexed = newIndexedProduct(applyOverride(indexed.product, override))
+		}
+		if !indexed.matchesFilters(query, excluded) {
+			continue
+		}
+		score, ok := domain.MatchTerms(terms, indexed.terms)
+		if !ok {
+			continue
+		}
+
+		// Facets ignore the category filter, so they show what choosing another category would return
+		if indexed.product.Category != "" {
+			counts[indexed.product.Category]++
+		}
+		if category != "" && indexed.category != category {
+			continue
+		}
+		matches = append(matches, productMatch{product: indexed.product, score: score})
+	}
+
+	sort.Slice(matches, func(i, j int) bool {
+		a, b := matches[i], matches[j]
+		if a.score != b.score {
+			return a.score > b.score
+		}
+		if a.product.Name != b.product.Name {
+			return a.product.Name < b.product.Name
+		}
+		return a.product.ID.String() < b.product.ID.String()
+	})
+
+	products := make([]foodji.Product, len(matches))
+	for i, match := range matches {
+		products[i] = match.product
+	}
+
+	facets := make([]domain.CategoryFacet, 0, len(counts))
+	for name, count := range counts {
+		facets = append(facets, domain.CategoryFacet{Category: name, Count: count})
+	}
+	sort.Slice(facets, func(i, j int) bool {
+		if facets[i].Count != facets[j].Count {
+			return facets[i].Count > facets[j].Count
+		}
+		return facets[i].Category < facets[j].Category
+	})
+	return products, facets
+}
+
+// matchesFilters applies the price, allergen and machine filters of a query
+func (p *indexedProduct) matchesFilters(query domain.ProductQuery, excludedAllergens []string) bool {
+	if query.MaxPrice != nil && p.product.Price > *query.MaxPrice {
+		return false
+	}
+	if query.MachineID != "" && p.product.MachineID != query.MachineID {
+		return false
+	}
+	// Products whose allergens are unknown may contain the excluded ones
+	if len(excludedAllergens) > 0 && p.product.Allergens == nil {
+		return false
+	}
+	for _, allergen := range excludedAllergens {
+		if p.allergens[allergen] {
+			return false
+		}
+	}
+	return true
+}
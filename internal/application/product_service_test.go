@@ -0,0 +1,328 @@
+package application_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/ArtemSind/food_tinder/internal/application"
+	"github.com/ArtemSind/food_tinder/internal/domain"
+	"github.com/ArtemSind/food_tinder/internal/infrastructure/external/foodji"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+// Mock ProductCatalogRepository
+type MockProductCatalogRepository struct {
+	mock.Mock
+}
+
+func (m *MockProductCatalogRepository) GetCatalogVersion(ctx context.Context) (int64, error) {
+	args := m.Called(ctx)
+	return args.Get(0).(int64), args.Error(1)
+}
+
+func (m *MockProductCatalogRepository) GetCatalog(ctx context.Context) (int64, []foodji.Product, error) {
+	args := m.Called(ctx)
+	if args.Get(1) == nil {
+		return args.Get(0).(int64), nil, args.Error(2)
+	}
+	return args.Get(0).(int64), args.Get(1).([]foodji.Product), args.Error(2)
+}
+
+// MockStockRevisionCatalogRepository is a catalog repository that reports stock revisions
+type MockStockRevisionCatalogRepository struct {
+	MockProductCatalogRepository
+}
+
+func (m *MockStockRevisionCatalogRepository) GetStockRevision(ctx context.Context) (int64, error) {
+	args := m.Called(ctx)
+	return args.Get(0).(int64), args.Error(1)
+}
+
+func searchCatalog() []foodji.Product {
+	return []foodji.Product{
+		{ID: uuid.New(), Name: "Greek Salad", Category: "Salads", Price: 7.5, Allergens: []string{"milk"}, MachineID: "m1"},
+		{ID: uuid.New(), Name: "Chicken Caesar Salad", Category: "Salads", Price: 8.9, Allergens: []string{"egg", "fish"}, MachineID: "m1"},
+		{ID: uuid.New(), Name: "Fruit Salad", Category: "Desserts", Price: 4.2, Allergens: []string{}, MachineID: "m2"},
+		{ID: uuid.New(), Name: "Chocolate Brownie", Category: "Desserts", Price: 3.8, Allergens: []string{"Gluten", "milk"}, MachineID: "m1"},
+		{ID: uuid.New(), Name: "Lentil Curry", Category: "Hot Meals", Price: 9.4, Origin: "canteen"},
+	}
+}
+
+func productNames(products []foodji.Product) []string {
+	names := make([]string, len(products))
+	for i, product := range products {
+		names[i] = product.Name
+	}
+	return names
+}
+
+func TestProductService_SearchProducts(t *testing.T) {
+	ctx := context.Background()
+	maxPrice := 8.0
+
+	tests := []struct {
+		name       string
+		query      domain.ProductQuery
+		expected   []string
+		categories []domain.CategoryFacet
+	}{
+		{
+			name:     "no query returns every product by name",
+			query:    domain.ProductQuery{},
+			expected: []string{"Chicken Caesar Salad", "Chocolate Brownie", "Fruit Salad", "Greek Salad", "Lentil Curry"},
+			categories: []domain.CategoryFacet{
+				{Category: "Desserts", Count: 2}, {Category: "Salads", Count: 2}, {Category: "Hot Meals", Count: 1},
+			},
+		},
+		{
+			name:       "fuzzy name match",
+			query:      domain.ProductQuery{Text: "salda"},
+			expected:   []string{"Chicken Caesar Salad", "Fruit Salad", "Greek Salad"},
+			categories: []domain.CategoryFacet{{Category: "Salads", Count: 2}, {Category: "Desserts", Count: 1}},
+		},
+		{
+			name:       "category filter keeps facets of other categories",
+			query:      domain.ProductQuery{Text: "salad", Category: "salads"},
+			expected:   []string{"Chicken Caesar Salad", "Greek Salad"},
+			categories: []domain.CategoryFacet{{Category: "Salads", Count: 2}, {Category: "Desserts", Count: 1}},
+		},
+		{
+			name:       "max price",
+			query:      domain.ProductQuery{MaxPrice: &maxPrice},
+			expected:   []string{"Chocolate Brownie", "Fruit Salad", "Greek Salad"},
+			categories: []domain.CategoryFacet{{Category: "Desserts", Count: 2}, {Category: "Salads", Count: 1}},
+		},
+		{
+			name:       "excluded allergens ignore case and rule out unknown allergens",
+			query:      domain.ProductQuery{ExcludeAllergens: []string{"gluten", " Fish"}},
+			expected:   []string{"Fruit Salad", "Greek Salad"},
+			categories: []domain.CategoryFacet{{Category: "Desserts", Count: 1}, {Category: "Salads", Count: 1}},
+		},
+		{
+			name:       "machine",
+			query:      domain.ProductQuery{MachineID: "m2"},
+			expected:   []string{"Fruit Salad"},
+			categories: []domain.CategoryFacet{{Category: "Desserts", Count: 1}},
+		},
+		{
+			name:       "no match",
+			query:      domain.ProductQuery{Text: "pizza"},
+			expected:   []string{},
+			categories: []domain.CategoryFacet{},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Arrange
+			mockRepo := new(MockProductCatalogRepository)
+			mockRepo.On("GetCatalogVersion", ctx).Return(int64(3), nil)
+			mockRepo.On("GetCatalog", ctx).Return(int64(3), searchCatalog(), nil).Once()
+			service := application.NewProductService(mockRepo, nil)
+
+			// Act
+			products, categories, err := service.SearchProducts(ctx, tt.query)
+
+			// Assert
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected, productNames(products))
+			assert.Equal(t, tt.categories, categories)
+			mockRepo.AssertExpectations(t)
+		})
+	}
+
+	t.Run("ranks closer name matches first", func(t *testing.T) {
+		// Arrange
+		mockRepo := new(MockProductCatalogRepository)
+		mockRepo.On("GetCatalogVersion", ctx).Return(int64(1), nil)
+		mockRepo.On("GetCatalog", ctx).Return(int64(1), []foodji.Product{
+			{ID: uuid.New(), Name: "Brownie Bites"},
+			{ID: uuid.New(), Name: "Brown Bread"},
+		}, nil).Once()
+		service := application.NewProductService(mockRepo, nil)
+
+		// Act
+		products, _, err := service.SearchProducts(ctx, domain.ProductQuery{Text: "brown"})
+
+		// Assert
+		require.NoError(t, err)
+		assert.Equal(t, []string{"Brown Bread", "Brownie Bites"}, productNames(products))
+	})
+
+	t.Run("serves names and categories in the query's locale", func(t *testing.T) {
+		// Arrange
+		mockRepo := new(MockProductCatalogRepository)
+		mockRepo.On("GetCatalogVersion", ctx).Return(int64(1), nil)
+		mockRepo.On("GetCatalog", ctx).Return(int64(1), []foodji.Product{
+			{ID: uuid.New(), Name: "Greek Salad", Category: "Salads", Translations: map[string]foodji.ProductText{
+				"de": {Name: "Griechischer Salat", Category: "Salate"},
+			}},
+			{ID: uuid.New(), Name: "Fruit Salad", Category: "Desserts", Translations: map[string]foodji.ProductText{
+				"de": {Name: "Obstsalat"},
+			}},
+			{ID: uuid.New(), Name: "Tomato Soup", Category: "Soups"},
+		}, nil).Once()
+		service := application.NewProductService(mockRepo, nil)
+
+		// Act
+		german, germanCategories, err := service.SearchProducts(ctx, domain.ProductQuery{Text: "salat", Locale: domain.LocaleGerman})
+		require.NoError(t, err)
+		byEnglishName, _, err := service.SearchProducts(ctx, domain.ProductQuery{Text: "greek", Locale: domain.LocaleGerman})
+		require.NoError(t, err)
+		english, _, err := service.SearchProducts(ctx, domain.ProductQuery{Text: "salad"})
+		require.NoError(t, err)
+
+		// Assert
+		assert.Equal(t, []string{"Griechischer Salat", "Obstsalat"}, productNames(german))
+		assert.Equal(t, []domain.CategoryFacet{{Category: "Desserts", Count: 1}, {Category: "Salate", Count: 1}}, germanCategories)
+		assert.Equal(t, []string{"Griechischer Salat"}, productNames(byEnglishName), "names in the default language still match")
+		assert.Equal(t, []string{"Fruit Salad", "Greek Salad"}, productNames(english))
+		mockRepo.AssertExpectations(t)
+	})
+
+	t.Run("rebuilds the index when the catalog version changes", func(t *testing.T) {
+		// Arrange
+		mockRepo := new(MockProductCatalogRepository)
+		mockRepo.On("GetCatalogVersion", ctx).Return(int64(1), nil).Twice()
+		mockRepo.On("GetCatalog", ctx).Return(int64(1), searchCatalog(), nil).Once()
+		service := application.NewProductService(mockRepo, nil)
+
+		_, _, err := service.SearchProducts(ctx, domain.ProductQuery{})
+		require.NoError(t, err)
+		_, _, err = service.SearchProducts(ctx, domain.ProductQuery{})
+		require.NoError(t, err)
+
+		mockRepo.On("GetCatalogVersion", ctx).Return(int64(2), nil).Once()
+		mockRepo.On("GetCatalog", ctx).Return(int64(2), []foodji.Product{{ID: uuid.New(), Name: "Tomato Soup"}}, nil).Once()
+
+		// Act
+		products, _, err := service.SearchProducts(ctx, domain.ProductQuery{})
+
+		// Assert
+		require.NoError(t, err)
+		assert.Equal(t, []string{"Tomato Soup"}, productNames(products))
+		mockRepo.AssertExpectations(t)
+	})
+
+	t.Run("rebuilds the index when the stock changes", func(t *testing.T) {
+		// Arrange
+		salad := uuid.New()
+		mockRepo := new(MockStockRevisionCatalogRepository)
+		mockRepo.On("GetCatalogVersion", ctx).Return(int64(1), nil)
+		mockRepo.On("GetStockRevision", ctx).Return(int64(4), nil).Once()
+		mockRepo.On("GetCatalog", ctx).Return(int64(1), []foodji.Product{
+			{ID: salad, Name: "Greek Salad", Slots: []foodji.Slot{{Number: 1, Quantity: 2}}},
+		}, nil).Once()
+		service := application.NewProductService(mockRepo, nil)
+
+		_, _, err := service.SearchProducts(ctx, domain.ProductQuery{})
+		require.NoError(t, err)
+
+		mockRepo.On("GetStockRevision", ctx).Return(int64(5), nil).Once()
+		mockRepo.On("GetCatalog", ctx).Return(int64(1), []foodji.Product{
+			{ID: salad, Name: "Greek Salad", Slots: []foodji.Slot{{Number: 1, Quantity: 0}}},
+		}, nil).Once()
+
+		// Act
+		products, _, err := service.SearchProducts(ctx, domain.ProductQuery{})
+
+		// Assert
+		require.NoError(t, err)
+		require.Len(t, products, 1)
+		assert.True(t, products[0].SoldOut())
+		mockRepo.AssertExpectations(t)
+	})
+
+	t.Run("repository error", func(t *testing.T) {
+		// Arrange
+		mockRepo := new(MockProductCatalogRepository)
+		mockRepo.On("GetCatalogVersion", ctx).Return(int64(0), errors.New("redis down"))
+		service := application.NewProductService(mockRepo, nil)
+
+		// Act
+		products, categories, err := service.SearchProducts(ctx, domain.ProductQuery{})
+
+		// Assert
+		assert.Error(t, err)
+		assert.Nil(t, products)
+		assert.Nil(t, categories)
+	})
+}
+
+func TestProductService_GetProducts(t *testing.T) {
+	// Arrange
+	ctx := context.Background()
+	salad, soup, brownie := uuid.New(), uuid.New(), uuid.New()
+	mockRepo := new(MockProductCatalogRepository)
+	mockRepo.On("GetCatalogVersion", ctx).Return(int64(1), nil)
+	mockRepo.On("GetCatalog", ctx).Return(int64(1), []foodji.Product{
+		{ID: salad, Name: "Greek Salad", Translations: map[string]foodji.ProductText{"de": {Name: "Griechischer Salat"}}},
+		{ID: soup, Name: "Tomato Soup"},
+		{ID: brownie, Name: "Chocolate Brownie"},
+	}, nil).Once()
+
+	overrides := &FakeOverrideRepository{}
+	overrideService := application.NewProductOverrideService(overrides, nil)
+	_, err := overrideService.SetOverride(ctx, brownie, true, false, nil, nil, nil)
+	require.NoError(t, err)
+	_, err = overrideService.SetOverride(ctx, soup, false, false, stringPtr("Gazpacho"), nil, nil)
+	require.NoError(t, err)
+	service := application.NewProductService(mockRepo, overrides)
+
+	// Act
+	products, err := service.GetProducts(ctx, []uuid.UUID{soup, uuid.New(), brownie, salad}, domain.LocaleGerman)
+
+	// Assert
+	require.NoError(t, err)
+	assert.Equal(t, []string{"Gazpacho", "Griechischer Salat"}, productNames(products),
+		"products keep the order of the IDs, with overrides and in the locale; hidden and unknown products are left out")
+	mockRepo.AssertExpectations(t)
+}
+
+func TestProductService_ExcludedProducts(t *testing.T) {
+	ctx := context.Background()
+	catalog := []foodji.Product{
+		{ID: uuid.New(), Name: "Greek Salad", Allergens: []string{"milk"}, Diets: []string{"vegetarian"}},
+		{ID: uuid.New(), Name: "Lentil Curry", Allergens: []string{}, Diets: []string{"vegan"}},
+		{ID: uuid.New(), Name: "Chocolate Brownie", Allergens: []string{"Nuts"}, Diets: []string{"vegetarian"}},
+		{ID: uuid.New(), Name: "Spaghetti Bolognese", Allergens: []string{"gluten"}},
+		{ID: uuid.New(), Name: "Fruit Salad", Diets: []string{"vegan"}},
+	}
+
+	t.Run("rules out unsafe products", func(t *testing.T) {
+		// Arrange
+		mockRepo := new(MockProductCatalogRepository)
+		mockRepo.On("GetCatalogVersion", ctx).Return(int64(1), nil).Once()
+		mockRepo.On("GetCatalog", ctx).Return(int64(1), catalog, nil).Once()
+		service := application.NewProductService(mockRepo, nil)
+		preferences, err := domain.NewDietaryPreferences([]string{"nuts"}, []string{"vegetarian"})
+		require.NoError(t, err)
+
+		// Act
+		excluded, err := service.ExcludedProducts(ctx, preferences)
+
+		// Assert
+		require.NoError(t, err)
+		assert.Equal(t, map[uuid.UUID]bool{catalog[2].ID: true, catalog[3].ID: true, catalog[4].ID: true}, excluded,
+			"products with unknown allergens are unsafe")
+		mockRepo.AssertExpectations(t)
+	})
+
+	t.Run("no preferences do not load the catalog", func(t *testing.T) {
+		// Arrange
+		mockRepo := new(MockProductCatalogRepository)
+		service := application.NewProductService(mockRepo, nil)
+
+		// Act
+		excluded, err := service.ExcludedProducts(ctx, domain.DietaryPreferences{})
+
+		// Assert
+		require.NoError(t, err)
+		assert.Empty(t, excluded)
+		mockRepo.AssertNotCalled(t, "GetCatalogVersion", mock.Anything)
+	})
+}
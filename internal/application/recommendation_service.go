@@ -0,0 +1,336 @@
+package application
+
+import (
+	"context"
+	"errors"
+	"log"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/ArtemSind/food_tinder/internal/domain"
+	"github.com/ArtemSind/food_tinder/internal/infrastructure/external/foodji"
+	"github.com/google/uuid"
+)
+
+const (
+	// RecommendationRefreshInterval is how often the similarity model is recomputed from votes
+	RecommendationRefreshInterval = 15 * time.Minute
+	// RecommendationRefreshTimeout is the timeout for a single recomputation
+	RecommendationRefreshTimeout = time.Minute
+	// DefaultRecommendationLimit is the number of recommendations returned when none is requested
+	DefaultRecommendationLimit = 10
+	// DefaultSimilarProductsLimit is the number of similar products returned when none is requested
+	DefaultSimilarProductsLimit = 10
+)
+
+type RatingRepository interface {
+	GetAll(ctx context.Context) ([]*domain.Vote, error)
+	GetBySessionID(ctx context.Context, sessionID uuid.UUID) ([]*domain.Vote, error)
+}
+
+type SimilarityCache interface {
+	// Store caches the neighbours of the products and drops the lists of any other product
+	Store(ctx context.Context, model *domain.SimilarityModel, products []uuid.UUID) error
+	Get(ctx context.Context, productID uuid.UUID) ([]domain.SimilarProduct, bool, error)
+}
+
+// RecommendationService recommends products using item-item collaborative filtering
+type RecommendationService struct {
+	ratings      RatingRepository
+	productRepo  ProductRepository
+	similarities SimilarityCache
+	sessions     SessionRepository
+	dietary      DietaryFilter
+
+	mu    sync.RWMutex
+	model *domain.SimilarityModel
+
+	cancel context.CancelFunc
+	done   chan struct{}
+}
+
+// NewRecommendationService creates a recommendation service.
+// The similarity cache is optional; when set, every recomputation is shared through it.
+// The session repository and dietary filter are optional; when both are set, products ruled
+// out by a session's dietary preferences are not recommended to it.
+func NewRecommendationService(ratings RatingRepository, productRepo ProductRepository, similarities SimilarityCache, sessions SessionRepository, dietary DietaryFilter) *RecommendationService {
+	return &RecommendationService{
+		ratings:      ratings,
+		productRepo:  productRepo,
+		similarities: similarities,
+		sessions:     sessions,
+		dietary:      dietary,
+	}
+}
+
+// Start computes the model and keeps recomputing it in a goroutine until Close is called
+func (s *RecommendationService) Start() {
+	ctx, cancel := context.WithCancel(context.Background())
+	s.cancel = cancel
+	s.done = make(chan struct{})
+
+	go s.run(ctx)
+}
+
+// Close stops the periodic recomputation
+func (s *RecommendationService) Close() error {
+	if s.cancel != nil {
+		log.Println("Shutting down recommendation service")
+		s.cancel()
+		<-s.done
+	}
+	return nil
+}
+
+func (s *RecommendationService) run(ctx context.Context) {
+	defer close(s.done)
+
+	ticker := time.NewTicker(RecommendationRefreshInterval)
+	defer ticker.Stop()
+
+	for {
+		refreshCtx, cancel := context.WithTimeout(ctx, RecommendationRefreshTimeout)
+		if err := s.Refresh(refreshCtx); err != nil {
+			log.Printf("Error computing recommendation model: %v", err)
+		}
+		cancel()
+
+		select {
+		case <-ticker.C:
+		case <-ctx.Done():
+			return
+		}
+	}
+}
+
+// Refresh recomputes the similarity model from all votes and caches the neighbours of the
+// catalog's products, dropping the lists of products that left it
+func (s *RecommendationService) Refresh(ctx context.Context) error {
+	votes, err := s.ratings.GetAll(ctx)
+	if err != nil {
+		return err
+	}
+
+	model := domain.ComputeItemSimilarities(votes, domain.MinCoRatings)
+
+	s.mu.Lock()
+	s.model = model
+	s.mu.Unlock()
+
+	if s.similarities != nil {
+		catalog, err := s.productRepo.ListProducts(ctx)
+		if err != nil {
+			return err
+		}
+		if err := s.similarities.Store(ctx, model, catalog); err != nil {
+			return err
+		}
+	}
+
+	log.Printf("Recommendation model computed from %d votes", len(votes))
+	return nil
+}
+
+func (s *RecommendationService) currentModel() (*domain.SimilarityModel, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	if s.model == nil {
+		return nil, domain.ErrModelNotReady
+	}
+	return s.model, nil
+}
+
+// GetRecommendations returns catalog products the session has not rated, ranked by predicted
+// score, each with the name of the liked product it is recommended for in the locale
+func (s *RecommendationService) GetRecommendations(ctx context.Context, sessionID uuid.UUID, limit int, locale domain.Locale) ([]*domain.Recommendation, error) {
+	model, err := s.currentModel()
+	if err != nil {
+		return nil, err
+	}
+
+	if limit <= 0 {
+		limit = DefaultRecommendationLimit
+	}
+
+	sessionVotes, err := s.ratings.GetBySessionID(ctx, sessionID)
+	if err != nil {
+		return nil, err
+	}
+
+	candidates, err := s.productRepo.ListProducts(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	candidates, err = s.safeCandidates(ctx, sessionID, candidates)
+	if err != nil {
+		return nil, err
+	}
+
+	recommendations := model.Recommend(sessionVotes, candidates, limit)
+	if err := s.nameLikedProducts(ctx, recommendations, locale); err != nil {
+		return nil, err
+	}
+	return recommendations, nil
+}
+
+// nameLikedProducts sets the name of the liked product of every recommendation, looking each
+// product up once; products that left the catalog stay unnamed
+func (s *RecommendationService) nameLikedProducts(ctx context.Context, recommendations []*domain.Recommendation, locale domain.Locale) error {
+	names := make(map[uuid.UUID]string)
+	for _, recommendation := range recommendations {
+		name, ok := names[recommendation.BecauseOf]
+		if !ok {
+			product, err := s.productRepo.GetProduct(ctx, recommendation.BecauseOf)
+			switch {
+			case errors.Is(err, domain.ErrProductNotFound):
+			case err != nil:
+				return err
+			default:
+				name = product.Localized(string(locale)).Name
+			}
+			names[recommendation.BecauseOf] = name
+		}
+		recommendation.BecauseOfName = name
+	}
+	return nil
+}
+
+// safeCandidates leaves out the candidates the session's dietary preferences rule out
+func (s *RecommendationService) safeCandidates(ctx context.Context, sessionID uuid.UUID, candidates []uuid.UUID) ([]uuid.UUID, error) {
+	if s.sessions == nil || s.dietary == nil {
+		return candidates, nil
+	}
+
+	session, err := s.sessions.GetByID(ctx, sessionID)
+	if err != nil {
+		return nil, err
+	}
+	unsafe, err := s.dietary.ExcludedProducts(ctx, session.Dietary)
+	if err != nil || len(unsafe) == 0 {
+		return candidates, err
+	}
+
+	safe := make([]uuid.UUID, 0, len(candidates))
+	for _, id := range candidates {
+		if !unsafe[id] {
+			safe = append(safe, id)
+		}
+	}
+	return safe, nil
+}
+
+// RecommendFor ranks the candidates for a session whose votes are already loaded
+func (s *RecommendationService) RecommendFor(sessionVotes []*domain.Vote, candidates []uuid.UUID) ([]*domain.Recommendation, error) {
+	model, err := s.currentModel()
+	if err != nil {
+		return nil, err
+	}
+	return model.Recommend(sessionVotes, candidates, 0), nil
+}
+
+// GetSimilarProducts returns the catalog products most often rated like the given one; like the
+// deck, it leaves out products that left the catalog, are hidden or are aliases. An alias is
+// answered for the product it stands for. Lists cached by any replica are preferred; the local
+// model is used on a cache miss. Products too few sessions rated alike are made up for with the
+// products whose category, diets and allergens are most alike.
+func (s *RecommendationService) GetSimilarProducts(ctx context.Context, productID uuid.UUID, limit int) ([]domain.SimilarProduct, error) {
+	product, err := s.productRepo.GetProduct(ctx, productID)
+	if err != nil {
+		return nil, err
+	}
+	productID = product.ID
+
+	if limit <= 0 {
+		limit = DefaultSimilarProductsLimit
+	}
+
+	var neighbours []domain.SimilarProduct
+	found := false
+	if s.similarities != nil {
+		var err error
+		neighbours, found, err = s.similarities.Get(ctx, productID)
+		if err != nil {
+			log.Printf("Failed to read similar products from cache: %v", err)
+		}
+	}
+
+	if !found {
+		model, err := s.currentModel()
+		if err != nil {
+			return nil, err
+		}
+		neighbours = model.Neighbours(productID)
+	}
+
+	ids, err := s.productRepo.ListProducts(ctx)
+	if err != nil {
+		return nil, err
+	}
+	inCatalog := make(map[uuid.UUID]bool, len(ids))
+	for _, id := range ids {
+		inCatalog[id] = true
+	}
+
+	similar := make([]domain.SimilarProduct, 0, limit)
+	included := map[uuid.UUID]bool{productID: true}
+	for _, neighbour := range neighbours {
+		if neighbour.Similarity <= 0 {
+			break
+		}
+		if !inCatalog[neighbour.ProductID] || included[neighbour.ProductID] {
+			continue
+		}
+		similar = append(similar, neighbour)
+		included[neighbour.ProductID] = true
+		if len(similar) == limit {
+			return similar, nil
+		}
+	}
+
+	alike, err := s.alikeProducts(ctx, product, ids, included)
+	if err != nil {
+		return nil, err
+	}
+	if missing := limit - len(similar); len(alike) > missing {
+		alike = alike[:missing]
+	}
+	return append(similar, alike...), nil
+}
+
+// alikeProducts ranks the catalog products not yet included by how alike their category, diets
+// and allergens are to the product's, leaving out products with nothing in common
+func (s *RecommendationService) alikeProducts(ctx context.Context, product *foodji.Product, ids []uuid.UUID, included map[uuid.UUID]bool) ([]domain.SimilarProduct, error) {
+	attributes := productAttributes(product)
+
+	var alike []domain.SimilarProduct
+	for _, id := range ids {
+		if included[id] {
+			continue
+		}
+		candidate, err := s.productRepo.GetProduct(ctx, id)
+		if errors.Is(err, domain.ErrProductNotFound) {
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		if similarity := domain.AttributeSimilarity(attributes, productAttributes(candidate)); similarity > 0 {
+			alike = append(alike, domain.SimilarProduct{ProductID: id, Similarity: similarity})
+		}
+	}
+
+	sort.Slice(alike, func(i, j int) bool {
+		if alike[i].Similarity != alike[j].Similarity {
+			return alike[i].Similarity > alike[j].Similarity
+		}
+		return alike[i].ProductID.String() < alike[j].ProductID.String()
+	})
+	return alike, nil
+}
+
+func productAttributes(product *foodji.Product) domain.ProductAttributes {
+	return domain.ProductAttributes{Category: product.Category, Allergens: product.Allergens, Diets: product.Diets}
+}
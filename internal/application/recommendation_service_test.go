@@ -0,0 +1,305 @@
+package application_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/ArtemSind/food_tinder/internal/application"
+	"github.com/ArtemSind/food_tinder/internal/domain"
+	"github.com/ArtemSind/food_tinder/internal/infrastructure/external/foodji"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+// Mock RatingRepository
+type MockRatingRepository struct {
+	mock.Mock
+}
+
+func (m *MockRatingRepository) GetAll(ctx context.Context) ([]*domain.Vote, error) {
+	args := m.Called(ctx)
+	return args.Get(0).([]*domain.Vote), args.Error(1)
+}
+
+func (m *MockRatingRepository) GetBySessionID(ctx context.Context, sessionID uuid.UUID) ([]*domain.Vote, error) {
+	args := m.Called(ctx, sessionID)
+	return args.Get(0).([]*domain.Vote), args.Error(1)
+}
+
+// FakeProductRepository serves a fixed catalog
+type FakeProductRepository struct {
+	products []uuid.UUID
+	// details holds the fields of the products that have more than an ID
+	details map[uuid.UUID]foodji.Product
+}
+
+func (r *FakeProductRepository) GetProduct(ctx context.Context, id uuid.UUID) (*foodji.Product, error) {
+	for _, productID := range r.products {
+		if productID == id {
+			product, ok := r.details[id]
+			if !ok {
+				product = foodji.Product{ID: id}
+			}
+			return &product, nil
+		}
+	}
+	return nil, domain.ErrProductNotFound
+}
+
+func (r *FakeProductRepository) ListProducts(ctx context.Context) ([]uuid.UUID, error) {
+	return r.products, nil
+}
+
+// Mock SimilarityCache
+type MockSimilarityCache struct {
+	mock.Mock
+}
+
+func (m *MockSimilarityCache) Store(ctx context.Context, model *domain.SimilarityModel, products []uuid.UUID) error {
+	args := m.Called(ctx, model, products)
+	return args.Error(0)
+}
+
+func (m *MockSimilarityCache) Get(ctx context.Context, productID uuid.UUID) ([]domain.SimilarProduct, bool, error) {
+	args := m.Called(ctx, productID)
+	return args.Get(0).([]domain.SimilarProduct), args.Bool(1), args.Error(2)
+}
+
+func TestRecommendationService_GetRecommendations(t *testing.T) {
+	ctx := context.Background()
+	pizza, pasta, salad := uuid.New(), uuid.New(), uuid.New()
+	s1, s2, s3 := uuid.New(), uuid.New(), uuid.New()
+
+	newVote := func(sessionID, productID uuid.UUID, score int) *domain.Vote {
+		vote, _ := domain.NewVote(sessionID, productID, score)
+		return vote
+	}
+	allVotes := []*domain.Vote{
+		newVote(s1, pizza, 5), newVote(s1, pasta, 5), newVote(s1, salad, 1),
+		newVote(s2, pizza, 4), newVote(s2, pasta, 5), newVote(s2, salad, 2),
+		newVote(s3, pizza, 1), newVote(s3, pasta, 2), newVote(s3, salad, 5),
+	}
+
+	t.Run("model not ready before first refresh", func(t *testing.T) {
+		// Arrange
+		service := application.NewRecommendationService(new(MockRatingRepository), &FakeProductRepository{}, nil, nil, nil)
+
+		// Act
+		recommendations, err := service.GetRecommendations(ctx, uuid.New(), 5, domain.LocaleEnglish)
+
+		// Assert
+		assert.Equal(t, domain.ErrModelNotReady, err)
+		assert.Nil(t, recommendations)
+	})
+
+	t.Run("recommends unrated products after refresh", func(t *testing.T) {
+		// Arrange
+		mockRatings := new(MockRatingRepository)
+		catalog := &FakeProductRepository{
+			products: []uuid.UUID{pizza, pasta, salad},
+			details: map[uuid.UUID]foodji.Product{pasta: {ID: pasta, Name: "Pasta Pesto", Translations: map[string]foodji.ProductText{
+				"de": {Name: "Nudeln mit Pesto"},
+			}}},
+		}
+		service := application.NewRecommendationService(mockRatings, catalog, nil, nil, nil)
+		sessionID := uuid.New()
+
+		mockRatings.On("GetAll", ctx).Return(allVotes, nil).Once()
+		mockRatings.On("GetBySessionID", ctx, sessionID).Return([]*domain.Vote{newVote(sessionID, pasta, 5)}, nil).Once()
+		require.NoError(t, service.Refresh(ctx))
+
+		// Act
+		recommendations, err := service.GetRecommendations(ctx, sessionID, 0, domain.LocaleGerman)
+
+		// Assert
+		require.NoError(t, err)
+		require.Len(t, recommendations, 1)
+		assert.Equal(t, pizza, recommendations[0].ProductID)
+		assert.Equal(t, pasta, recommendations[0].BecauseOf)
+		assert.Equal(t, "Nudeln mit Pesto", recommendations[0].BecauseOfName, "the liked product is named in the locale")
+
+		mockRatings.AssertExpectations(t)
+	})
+
+	t.Run("leaves out products ruled out by dietary preferences", func(t *testing.T) {
+		// Arrange
+		mockRatings := new(MockRatingRepository)
+		mockSessions := new(MockSessionRepository)
+		catalog := &FakeProductRepository{products: []uuid.UUID{pizza, pasta, salad}}
+		dietary := &FakeDietaryFilter{excluded: []uuid.UUID{pizza}}
+		service := application.NewRecommendationService(mockRatings, catalog, nil, mockSessions, dietary)
+		session := &domain.Session{ID: uuid.New(), Dietary: domain.DietaryPreferences{ExcludedAllergens: []string{"gluten"}}}
+
+		mockRatings.On("GetAll", ctx).Return(allVotes, nil).Once()
+		mockRatings.On("GetBySessionID", ctx, session.ID).Return([]*domain.Vote{newVote(session.ID, pasta, 5)}, nil).Once()
+		mockSessions.On("GetByID", ctx, session.ID).Return(session, nil).Once()
+		require.NoError(t, service.Refresh(ctx))
+
+		// Act
+		recommendations, err := service.GetRecommendations(ctx, session.ID, 0, domain.LocaleEnglish)
+
+		// Assert
+		require.NoError(t, err)
+		for _, recommendation := range recommendations {
+			assert.NotEqual(t, pizza, recommendation.ProductID)
+		}
+		mockSessions.AssertExpectations(t)
+	})
+
+	t.Run("handles repository errors", func(t *testing.T) {
+		// Arrange
+		mockRatings := new(MockRatingRepository)
+		service := application.NewRecommendationService(mockRatings, &FakeProductRepository{}, nil, nil, nil)
+		mockRatings.On("GetAll", ctx).Return([]*domain.Vote{}, assert.AnError).Once()
+
+		// Act
+		err := service.Refresh(ctx)
+
+		// Assert
+		assert.Error(t, err)
+		mockRatings.AssertExpectations(t)
+	})
+}
+
+func TestRecommendationService_GetSimilarProducts(t *testing.T) {
+	ctx := context.Background()
+	pizza, pasta, salad := uuid.New(), uuid.New(), uuid.New()
+	catalog := &FakeProductRepository{products: []uuid.UUID{pizza, pasta, salad}}
+
+	t.Run("serves cached list and drops dissimilar products", func(t *testing.T) {
+		// Arrange
+		mockCache := new(MockSimilarityCache)
+		service := application.NewRecommendationService(new(MockRatingRepository), catalog, mockCache, nil, nil)
+		mockCache.On("Get", ctx, pizza).Return([]domain.SimilarProduct{
+			{ProductID: pasta, Similarity: 0.9, CoRatings: 3},
+			{ProductID: salad, Similarity: -0.7, CoRatings: 3},
+		}, true, nil).Once()
+
+		// Act
+		similar, err := service.GetSimilarProducts(ctx, pizza, 0)
+
+		// Assert
+		require.NoError(t, err)
+		require.Len(t, similar, 1)
+		assert.Equal(t, pasta, similar[0].ProductID)
+		mockCache.AssertExpectations(t)
+	})
+
+	t.Run("falls back to local model on cache miss", func(t *testing.T) {
+		// Arrange
+		mockRatings := new(MockRatingRepository)
+		mockCache := new(MockSimilarityCache)
+		service := application.NewRecommendationService(mockRatings, catalog, mockCache, nil, nil)
+
+		newVote := func(sessionID, productID uuid.UUID, score int) *domain.Vote {
+			vote, _ := domain.NewVote(sessionID, productID, score)
+			return vote
+		}
+		s1, s2 := uuid.New(), uuid.New()
+		mockRatings.On("GetAll", ctx).Return([]*domain.Vote{
+			newVote(s1, pizza, 5), newVote(s1, pasta, 5), newVote(s1, salad, 1),
+			newVote(s2, pizza, 5), newVote(s2, pasta, 4), newVote(s2, salad, 1),
+		}, nil).Once()
+		mockCache.On("Store", ctx, mock.AnythingOfType("*domain.SimilarityModel"), catalog.products).Return(nil).Once()
+		mockCache.On("Get", ctx, pizza).Return([]domain.SimilarProduct{}, false, nil).Once()
+		require.NoError(t, service.Refresh(ctx))
+
+		// Act
+		similar, err := service.GetSimilarProducts(ctx, pizza, 5)
+
+		// Assert
+		require.NoError(t, err)
+		require.NotEmpty(t, similar)
+		assert.Equal(t, pasta, similar[0].ProductID)
+		mockCache.AssertExpectations(t)
+	})
+
+	t.Run("leaves out products that are not in the curated catalog", func(t *testing.T) {
+		// Arrange
+		hidden := uuid.New()
+		mockCache := new(MockSimilarityCache)
+		service := application.NewRecommendationService(new(MockRatingRepository), catalog, mockCache, nil, nil)
+		mockCache.On("Get", ctx, pizza).Return([]domain.SimilarProduct{
+			{ProductID: hidden, Similarity: 0.95, CoRatings: 4},
+			{ProductID: pasta, Similarity: 0.9, CoRatings: 3},
+		}, true, nil).Once()
+
+		// Act
+		similar, err := service.GetSimilarProducts(ctx, pizza, 0)
+
+		// Assert
+		require.NoError(t, err)
+		require.Len(t, similar, 1)
+		assert.Equal(t, pasta, similar[0].ProductID)
+		mockCache.AssertExpectations(t)
+	})
+
+	t.Run("fills up with products of alike category, diets and allergens", func(t *testing.T) {
+		// Arrange
+		lasagne, curry, brownie := uuid.New(), uuid.New(), uuid.New()
+		attributed := &FakeProductRepository{
+			products: []uuid.UUID{pizza, pasta, lasagne, curry, brownie},
+			details: map[uuid.UUID]foodji.Product{
+				pizza:   {ID: pizza, Category: "Hot Meals", Allergens: []string{"gluten", "milk"}, Diets: []string{"vegetarian"}},
+				lasagne: {ID: lasagne, Category: "hot meals", Allergens: []string{"gluten", "milk"}, Diets: []string{"vegetarian"}},
+				curry:   {ID: curry, Category: "Hot Meals", Allergens: []string{}, Diets: []string{"vegan"}},
+				brownie: {ID: brownie, Category: "Desserts", Allergens: []string{"nuts"}},
+			},
+		}
+		mockCache := new(MockSimilarityCache)
+		service := application.NewRecommendationService(new(MockRatingRepository), attributed, mockCache, nil, nil)
+		mockCache.On("Get", ctx, pizza).Return([]domain.SimilarProduct{
+			{ProductID: pasta, Similarity: 0.9, CoRatings: 3},
+		}, true, nil).Once()
+
+		// Act
+		similar, err := service.GetSimilarProducts(ctx, pizza, 5)
+
+		// Assert
+		require.NoError(t, err)
+		require.Len(t, similar, 3, "products with nothing in common are left out")
+		assert.Equal(t, pasta, similar[0].ProductID, "rated neighbours come first")
+		assert.Equal(t, lasagne, similar[1].ProductID)
+		assert.Equal(t, 1.0, similar[1].Similarity)
+		assert.Zero(t, similar[1].CoRatings)
+		assert.Equal(t, curry, similar[2].ProductID)
+		mockCache.AssertExpectations(t)
+	})
+
+	t.Run("an alias is answered for its product", func(t *testing.T) {
+		// Arrange
+		oldPizza := uuid.New()
+		aliases := &FakeAliasRepository{}
+		_, err := application.NewProductAliasService(aliases, nil).CreateAlias(ctx, oldPizza, pizza)
+		require.NoError(t, err)
+		curated := application.NewCuratedProductRepository(catalog, &FakeOverrideRepository{}, aliases)
+		mockCache := new(MockSimilarityCache)
+		service := application.NewRecommendationService(new(MockRatingRepository), curated, mockCache, nil, nil)
+		mockCache.On("Get", ctx, pizza).Return([]domain.SimilarProduct{
+			{ProductID: pasta, Similarity: 0.9, CoRatings: 3},
+		}, true, nil).Once()
+
+		// Act
+		similar, err := service.GetSimilarProducts(ctx, oldPizza, 0)
+
+		// Assert
+		require.NoError(t, err)
+		require.Len(t, similar, 1)
+		assert.Equal(t, pasta, similar[0].ProductID)
+		mockCache.AssertExpectations(t)
+	})
+
+	t.Run("unknown product", func(t *testing.T) {
+		// Arrange
+		service := application.NewRecommendationService(new(MockRatingRepository), catalog, nil, nil, nil)
+
+		// Act
+		similar, err := service.GetSimilarProducts(ctx, uuid.New(), 5)
+
+		// Assert
+		assert.Equal(t, domain.ErrProductNotFound, err)
+		assert.Nil(t, similar)
+	})
+}
@@ -10,18 +10,39 @@ import (
 type SessionRepository interface {
 	Create(ctx context.Context, session *domain.Session) error
 	GetByID(ctx context.Context, id uuid.UUID) (*domain.Session, error)
+	UpdateDeckStrategy(ctx context.Context, id uuid.UUID, strategy domain.DeckStrategy) error
+	UpdateDietaryPreferences(ctx context.Context, id uuid.UUID, preferences domain.DietaryPreferences) error
+	UpdateLocale(ctx context.Context, id uuid.UUID, locale domain.Locale) error
+}
+
+type RunningExperimentRepository interface {
+	GetRunning(ctx context.Context) (*domain.Experiment, error)
 }
 
 type SessionService struct {
-	repo SessionRepository
+	repo        SessionRepository
+	experiments RunningExperimentRepository
 }
 
-func NewSessionService(repo SessionRepository) *SessionService {
-	return &SessionService{repo: repo}
+// NewSessionService creates a session service.
+// The experiment repository is optional; when set, new sessions join the running experiment.
+func NewSessionService(repo SessionRepository, experiments RunningExperimentRepository) *SessionService {
+	return &SessionService{repo: repo, experiments: experiments}
 }
 
 func (s *SessionService) CreateSession(ctx context.Context) (*domain.Session, error) {
 	session := domain.NewSession()
+
+	if s.experiments != nil {
+		experiment, err := s.experiments.GetRunning(ctx)
+		if err != nil {
+			return nil, err
+		}
+		if experiment != nil {
+			session.AssignExperiment(experiment)
+		}
+	}
+
 	if err := s.repo.Create(ctx, session); err != nil {
 		return nil, err
 	}
@@ -31,3 +52,40 @@ func (s *SessionService) CreateSession(ctx context.Context) (*domain.Session, er
 func (s *SessionService) GetSession(ctx context.Context, id uuid.UUID) (*domain.Session, error) {
 	return s.repo.GetByID(ctx, id)
 }
+
+func (s *SessionService) SetDeckStrategy(ctx context.Context, id uuid.UUID, strategy string) (*domain.Session, error) {
+	deckStrategy, err := domain.ParseDeckStrategy(strategy)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.repo.UpdateDeckStrategy(ctx, id, deckStrategy); err != nil {
+		return nil, err
+	}
+	return s.repo.GetByID(ctx, id)
+}
+
+// SetDietaryPreferences replaces the allergens and diets that rule products out of the session's
+// deck and recommendations
+func (s *SessionService) SetDietaryPreferences(ctx context.Context, id uuid.UUID, excludedAllergens, diets []string) (*domain.Session, error) {
+	preferences, err := domain.NewDietaryPreferences(excludedAllergens, diets)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.repo.UpdateDietaryPreferences(ctx, id, preferences); err != nil {
+		return nil, err
+	}
+	return s.repo.GetByID(ctx, id)
+}
+
+// SetLocale chooses the language of the session's product texts and messages. An empty locale
+// goes back to following the language of each request.
+func (s *SessionService) SetLocale(ctx context.Context, id uuid.UUID, locale string) (*domain.Session, error) {
+	sessionLocale, err := domain.ParseLocale(locale)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.repo.UpdateLocale(ctx, id, sessionLocale); err != nil {
+		return nil, err
+	}
+	return s.repo.GetByID(ctx, id)
+}
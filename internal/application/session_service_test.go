@@ -30,10 +30,25 @@ func (m *MockSessionRepository) GetByID(ctx context.Context, id uuid.UUID) (*dom
 	return args.Get(0).(*domain.Session), args.Error(1)
 }
 
+func (m *MockSessionRepository) UpdateDeckStrategy(ctx context.Context, id uuid.UUID, strategy domain.DeckStrategy) error {
+	args := m.Called(ctx, id, strategy)
+	return args.Error(0)
+}
+
+func (m *MockSessionRepository) UpdateDietaryPreferences(ctx context.Context, id uuid.UUID, preferences domain.DietaryPreferences) error {
+	args := m.Called(ctx, id, preferences)
+	return args.Error(0)
+}
+
+func (m *MockSessionRepository) UpdateLocale(ctx context.Context, id uuid.UUID, locale domain.Locale) error {
+	args := m.Called(ctx, id, locale)
+	return args.Error(0)
+}
+
 func TestSessionService_CreateSession(t *testing.T) {
 	// Arrange
 	mockRepo := new(MockSessionRepository)
-	service := application.NewSessionService(mockRepo)
+	service := application.NewSessionService(mockRepo, nil)
 	ctx := context.Background()
 
 	t.Run("creates session successfully", func(t *testing.T) {
@@ -67,10 +82,39 @@ func TestSessionService_CreateSession(t *testing.T) {
 	})
 }
 
+func TestSessionService_CreateSession_WithRunningExperiment(t *testing.T) {
+	// Arrange
+	ctx := context.Background()
+	mockRepo := new(MockSessionRepository)
+	mockExperiments := new(MockExperimentRepository)
+	service := application.NewSessionService(mockRepo, mockExperiments)
+
+	experiment, err := domain.NewExperiment("bandit vs popularity", "", []domain.Variant{
+		{Name: "control", Strategy: domain.DeckPopularity, Weight: 1},
+		{Name: "treatment", Strategy: domain.DeckBandit, Weight: 1},
+	})
+	require.NoError(t, err)
+
+	mockExperiments.On("GetRunning", ctx).Return(experiment, nil).Once()
+	mockRepo.On("Create", ctx, mock.MatchedBy(func(session *domain.Session) bool {
+		return session.Experiment != nil && session.Experiment.ExperimentID == experiment.ID
+	})).Return(nil).Once()
+
+	// Act
+	session, err := service.CreateSession(ctx)
+
+	// Assert
+	require.NoError(t, err)
+	assert.Equal(t, experiment.Assign(session.ID).Strategy, session.DeckStrategy)
+
+	mockRepo.AssertExpectations(t)
+	mockExperiments.AssertExpectations(t)
+}
+
 func TestSessionService_GetSession(t *testing.T) {
 	// Arrange
 	mockRepo := new(MockSessionRepository)
-	service := application.NewSessionService(mockRepo)
+	service := application.NewSessionService(mockRepo, nil)
 	ctx := context.Background()
 	sessionID := uuid.New()
 
@@ -105,3 +149,103 @@ func TestSessionService_GetSession(t *testing.T) {
 		mockRepo.AssertExpectations(t)
 	})
 }
+
+func TestSessionService_SetDeckStrategy(t *testing.T) {
+	// Arrange
+	mockRepo := new(MockSessionRepository)
+	service := application.NewSessionService(mockRepo, nil)
+	ctx := context.Background()
+	sessionID := uuid.New()
+
+	t.Run("updates strategy", func(t *testing.T) {
+		// Arrange
+		updated := &domain.Session{ID: sessionID, DeckStrategy: domain.DeckBandit}
+		mockRepo.On("UpdateDeckStrategy", ctx, sessionID, domain.DeckBandit).Return(nil).Once()
+		mockRepo.On("GetByID", ctx, sessionID).Return(updated, nil).Once()
+
+		// Act
+		session, err := service.SetDeckStrategy(ctx, sessionID, "bandit")
+
+		// Assert
+		require.NoError(t, err)
+		assert.Equal(t, domain.DeckBandit, session.DeckStrategy)
+
+		mockRepo.AssertExpectations(t)
+	})
+
+	t.Run("rejects unknown strategy", func(t *testing.T) {
+		// Act
+		session, err := service.SetDeckStrategy(ctx, sessionID, "alphabetical")
+
+		// Assert
+		assert.Equal(t, domain.ErrInvalidDeckStrategy, err)
+		assert.Nil(t, session)
+	})
+}
+
+func TestSessionService_SetDietaryPreferences(t *testing.T) {
+	// Arrange
+	mockRepo := new(MockSessionRepository)
+	service := application.NewSessionService(mockRepo, nil)
+	ctx := context.Background()
+	sessionID := uuid.New()
+
+	t.Run("stores normalized preferences", func(t *testing.T) {
+		// Arrange
+		preferences := domain.DietaryPreferences{ExcludedAllergens: []string{"nuts", "milk"}, Diets: []string{"vegetarian"}}
+		updated := &domain.Session{ID: sessionID, DeckStrategy: domain.DeckRandom, Dietary: preferences}
+		mockRepo.On("UpdateDietaryPreferences", ctx, sessionID, preferences).Return(nil).Once()
+		mockRepo.On("GetByID", ctx, sessionID).Return(updated, nil).Once()
+
+		// Act
+		session, err := service.SetDietaryPreferences(ctx, sessionID, []string{" Nuts", "milk", "NUTS"}, []string{"Vegetarian"})
+
+		// Assert
+		require.NoError(t, err)
+		assert.Equal(t, preferences, session.Dietary)
+
+		mockRepo.AssertExpectations(t)
+	})
+
+	t.Run("rejects blank entries", func(t *testing.T) {
+		// Act
+		session, err := service.SetDietaryPreferences(ctx, sessionID, []string{"nuts", " "}, nil)
+
+		// Assert
+		assert.ErrorIs(t, err, domain.ErrInvalidDietaryPreferences)
+		assert.Nil(t, session)
+	})
+}
+
+func TestSessionService_SetLocale(t *testing.T) {
+	// Arrange
+	mockRepo := new(MockSessionRepository)
+	service := application.NewSessionService(mockRepo, nil)
+	ctx := context.Background()
+	sessionID := uuid.New()
+
+	t.Run("stores the language of a regional tag", func(t *testing.T) {
+		// Arrange
+		updated := &domain.Session{ID: sessionID, DeckStrategy: domain.DeckRandom, Locale: domain.LocaleGerman}
+		mockRepo.On("UpdateLocale", ctx, sessionID, domain.LocaleGerman).Return(nil).Once()
+		mockRepo.On("GetByID", ctx, sessionID).Return(updated, nil).Once()
+
+		// Act
+		session, err := service.SetLocale(ctx, sessionID, "de-CH")
+
+		// Assert
+		require.NoError(t, err)
+		assert.Equal(t, domain.LocaleGerman, session.Locale)
+
+		mockRepo.AssertExpectations(t)
+	})
+
+	t.Run("rejects unsupported locales", func(t *testing.T) {
+		// Act
+		session, err := service.SetLocale(ctx, sessionID, "fr")
+
+		// Assert
+		assert.ErrorIs(t, err, domain.ErrInvalidLocale)
+		assert.Nil(t, session)
+	})
+}
@@ -13,6 +13,7 @@ type VoteRepository interface {
 	Update(ctx context.Context, vote *domain.Vote) error
 	GetBySessionID(ctx context.Context, sessionID uuid.UUID) ([]*domain.Vote, error)
 	GetAggregatedScores(ctx context.Context) ([]*domain.ProductScore, error)
+	GetUnvotedViews(ctx context.Context) ([]*domain.ProductViews, error)
 }
 
 type ProductRepository interface {
@@ -33,10 +34,12 @@ func NewVoteService(voteRepo VoteRepository, productRepo ProductRepository) *Vot
 }
 
 func (s *VoteService) CreateOrUpdateVote(ctx context.Context, sessionID, productID uuid.UUID, score int) (*domain.Vote, error) {
-
-	if _, err := s.productRepo.GetProduct(ctx, productID); err != nil {
+	product, err := s.productRepo.GetProduct(ctx, productID)
+	if err != nil {
 		return nil, err
 	}
+	// A vote on an alias is recorded on the product it stands for
+	productID = product.ID
 
 	vote, err := domain.NewVote(sessionID, productID, score)
 	if err != nil {
@@ -60,6 +63,7 @@ func (s *VoteService) CreateOrUpdateVote(ctx context.Context, sessionID, product
 
 	if existingVote != nil {
 		existingVote.Score = score
+		existingVote.UpdatedAt = vote.UpdatedAt
 		if err := s.voteRepo.Update(ctx, existingVote); err != nil {
 			return nil, err
 		}
@@ -77,6 +81,19 @@ func (s *VoteService) GetVotesBySession(ctx context.Context, sessionID uuid.UUID
 	return s.voteRepo.GetBySessionID(ctx, sessionID)
 }
 
-func (s *VoteService) GetAggregatedScores(ctx context.Context) ([]*domain.ProductScore, error) {
-	return s.voteRepo.GetAggregatedScores(ctx)
+// GetAggregatedScores returns the aggregated scores of all products in the order of the ranking
+func (s *VoteService) GetAggregatedScores(ctx context.Context, ranking domain.ScoreRanking) ([]*domain.ProductScore, error) {
+	scores, err := s.voteRepo.GetAggregatedScores(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if ranking == domain.RankByValue {
+		return domain.OrderByValue(scores), nil
+	}
+	return scores, nil
+}
+
+// GetUnvotedViews returns how often each product that was never voted on was shown and skipped
+func (s *VoteService) GetUnvotedViews(ctx context.Context) ([]*domain.ProductViews, error) {
+	return s.voteRepo.GetUnvotedViews(ctx)
 }
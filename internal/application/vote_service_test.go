@@ -3,6 +3,7 @@ package application_test
 import (
 	"context"
 	"testing"
+	"time"
 
 	"github.com/ArtemSind/food_tinder/internal/application"
 	"github.com/ArtemSind/food_tinder/internal/domain"
@@ -38,12 +39,17 @@ func (m *MockVoteRepository) GetAggregatedScores(ctx context.Context) ([]*domain
 	return args.Get(0).([]*domain.ProductScore), args.Error(1)
 }
 
+func (m *MockVoteRepository) GetUnvotedViews(ctx context.Context) ([]*domain.ProductViews, error) {
+	args := m.Called(ctx)
+	return args.Get(0).([]*domain.ProductViews), args.Error(1)
+}
+
 type MockProductRepository struct {
 	mock.Mock
 }
 
 func (r *MockProductRepository) GetProduct(ctx context.Context, id uuid.UUID) (*foodji.Product, error) {
-	return nil, nil
+	return &foodji.Product{ID: id}, nil
 }
 
 func (r *MockProductRepository) ListProducts(ctx context.Context) ([]uuid.UUID, error) {
@@ -81,6 +87,9 @@ func TestVoteService_CreateOrUpdateVote(t *testing.T) {
 	t.Run("update existing vote", func(t *testing.T) {
 		// Arrange
 		existingVote, _ := domain.NewVote(sessionID, productID, 3)
+		existingVote.CreatedAt = existingVote.CreatedAt.Add(-time.Hour)
+		existingVote.UpdatedAt = existingVote.CreatedAt
+		createdAt := existingVote.CreatedAt
 		mockVoteRepo.On("GetBySessionID", ctx, sessionID).Return([]*domain.Vote{existingVote}, nil).Once()
 		mockVoteRepo.On("Update", ctx, mock.AnythingOfType("*domain.Vote")).Return(nil).Once()
 
@@ -95,6 +104,8 @@ func TestVoteService_CreateOrUpdateVote(t *testing.T) {
 		assert.Equal(t, sessionID, vote.SessionID)
 		assert.Equal(t, productID, vote.ProductID)
 		assert.Equal(t, newScore, vote.Score)
+		assert.Equal(t, createdAt, vote.CreatedAt)
+		assert.True(t, vote.UpdatedAt.After(createdAt), "the vote should record when it was rescored")
 
 		mockVoteRepo.AssertExpectations(t)
 	})
@@ -192,7 +203,7 @@ func TestVoteService_GetAggregatedScores(t *testing.T) {
 		mockVoteRepo.On("GetAggregatedScores", ctx).Return(expectedScores, nil).Once()
 
 		// Act
-		scores, err := service.GetAggregatedScores(ctx)
+		scores, err := service.GetAggregatedScores(ctx, domain.RankByScore)
 
 		// Assert
 		require.NoError(t, err)
@@ -202,12 +213,31 @@ func TestVoteService_GetAggregatedScores(t *testing.T) {
 		mockVoteRepo.AssertExpectations(t)
 	})
 
+	t.Run("ranks by value", func(t *testing.T) {
+		// Arrange
+		cheap, pricey := 4.0, 10.0
+		best := &domain.ProductScore{ProductID: uuid.New(), AvgScore: 5, VoteCount: 4, Price: &pricey}
+		value := &domain.ProductScore{ProductID: uuid.New(), AvgScore: 4, VoteCount: 3, Price: &cheap}
+		unpriced := &domain.ProductScore{ProductID: uuid.New(), AvgScore: 3, VoteCount: 8}
+
+		mockVoteRepo.On("GetAggregatedScores", ctx).Return([]*domain.ProductScore{best, unpriced, value}, nil).Once()
+
+		// Act
+		scores, err := service.GetAggregatedScores(ctx, domain.RankByValue)
+
+		// Assert
+		require.NoError(t, err)
+		assert.Equal(t, []*domain.ProductScore{value, best, unpriced}, scores)
+
+		mockVoteRepo.AssertExpectations(t)
+	})
+
 	t.Run("handles repository errors", func(t *testing.T) {
 		// Arrange
 		mockVoteRepo.On("GetAggregatedScores", ctx).Return([]*domain.ProductScore{}, assert.AnError).Once()
 
 		// Act
-		scores, err := service.GetAggregatedScores(ctx)
+		scores, err := service.GetAggregatedScores(ctx, domain.RankByScore)
 
 		// Assert
 		assert.Error(t, err)
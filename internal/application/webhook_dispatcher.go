@@ -0,0 +1,139 @@
+package application
+
+import (
+	"context"
+	"log"
+	"time"
+
+	"github.com/ArtemSind/food_tinder/internal/domain"
+)
+
+const (
+	// DispatchInterval is how often the outbox and the delivery queue are polled
+	DispatchInterval = 5 * time.Second
+	// DispatchBatchSize is the maximum number of events or deliveries handled per poll
+	DispatchBatchSize = 50
+	// DeliveryLease is how long a claimed delivery is hidden from other replicas
+	DeliveryLease = time.Minute
+)
+
+type OutboxRepository interface {
+	FetchPending(ctx context.Context, limit int) ([]*domain.OutboxEvent, error)
+}
+
+type WebhookSender interface {
+	Send(ctx context.Context, delivery *domain.WebhookDelivery) error
+}
+
+// WebhookDispatcher fans outbox events out to subscriptions and delivers them with retries
+type WebhookDispatcher struct {
+	outbox   OutboxRepository
+	webhooks WebhookRepository
+	sender   WebhookSender
+	cancel   context.CancelFunc
+	done     chan struct{}
+}
+
+func NewWebhookDispatcher(outbox OutboxRepository, webhooks WebhookRepository, sender WebhookSender) *WebhookDispatcher {
+	return &WebhookDispatcher{
+		outbox:   outbox,
+		webhooks: webhooks,
+		sender:   sender,
+	}
+}
+
+// Start runs the dispatch loop in a goroutine until Close is called
+func (d *WebhookDispatcher) Start() {
+	ctx, cancel := context.WithCancel(context.Background())
+	d.cancel = cancel
+	d.done = make(chan struct{})
+
+	go d.run(ctx)
+}
+
+// Close stops the dispatch loop and waits for the current poll to finish
+func (d *WebhookDispatcher) Close() error {
+	if d.cancel != nil {
+		log.Println("Shutting down webhook dispatcher")
+		d.cancel()
+		<-d.done
+	}
+	return nil
+}
+
+func (d *WebhookDispatcher) run(ctx context.Context) {
+	defer close(d.done)
+
+	ticker := time.NewTicker(DispatchInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			if err := d.DispatchPending(ctx); err != nil {
+				log.Printf("Error dispatching outbox events: %v", err)
+			}
+			if err := d.DeliverDue(ctx); err != nil {
+				log.Printf("Error delivering webhooks: %v", err)
+			}
+		case <-ctx.Done():
+			return
+		}
+	}
+}
+
+// DispatchPending creates a delivery per matching subscription for every pending outbox event
+func (d *WebhookDispatcher) DispatchPending(ctx context.Context) error {
+	events, err := d.outbox.FetchPending(ctx, DispatchBatchSize)
+	if err != nil {
+		return err
+	}
+	if len(events) == 0 {
+		return nil
+	}
+
+	subscriptions, err := d.webhooks.ListSubscriptions(ctx)
+	if err != nil {
+		return err
+	}
+
+	for _, event := range events {
+		var deliveries []*domain.WebhookDelivery
+		for _, subscription := range subscriptions {
+			if subscription.Subscribes(event.EventType) {
+				deliveries = append(deliveries, domain.NewWebhookDelivery(subscription, event))
+			}
+		}
+
+		if err := d.webhooks.EnqueueDeliveries(ctx, event.ID, deliveries); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// DeliverDue sends every delivery whose next attempt is due and records the outcome
+func (d *WebhookDispatcher) DeliverDue(ctx context.Context) error {
+	deliveries, err := d.webhooks.ClaimDueDeliveries(ctx, DispatchBatchSize, DeliveryLease)
+	if err != nil {
+		return err
+	}
+
+	for _, delivery := range deliveries {
+		if err := d.sender.Send(ctx, delivery); err != nil {
+			delivery.MarkFailed(err, time.Now())
+			if delivery.Status == domain.DeliveryDead {
+				log.Printf("Webhook delivery %s dead-lettered after %d attempts: %v", delivery.ID, delivery.Attempts, err)
+			}
+		} else {
+			delivery.MarkDelivered()
+		}
+
+		if err := d.webhooks.SaveDeliveryResult(ctx, delivery); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
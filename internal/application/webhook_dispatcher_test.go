@@ -0,0 +1,177 @@
+package application_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/ArtemSind/food_tinder/internal/application"
+	"github.com/ArtemSind/food_tinder/internal/domain"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+// Mock OutboxRepository
+type MockOutboxRepository struct {
+	mock.Mock
+}
+
+func (m *MockOutboxRepository) FetchPending(ctx context.Context, limit int) ([]*domain.OutboxEvent, error) {
+	args := m.Called(ctx, limit)
+	return args.Get(0).([]*domain.OutboxEvent), args.Error(1)
+}
+
+// Mock WebhookRepository
+type MockWebhookRepository struct {
+	mock.Mock
+}
+
+func (m *MockWebhookRepository) CreateSubscription(ctx context.Context, subscription *domain.WebhookSubscription) error {
+	args := m.Called(ctx, subscription)
+	return args.Error(0)
+}
+
+func (m *MockWebhookRepository) ListSubscriptions(ctx context.Context) ([]*domain.WebhookSubscription, error) {
+	args := m.Called(ctx)
+	return args.Get(0).([]*domain.WebhookSubscription), args.Error(1)
+}
+
+func (m *MockWebhookRepository) DeleteSubscription(ctx context.Context, id uuid.UUID) error {
+	args := m.Called(ctx, id)
+	return args.Error(0)
+}
+
+func (m *MockWebhookRepository) EnqueueDeliveries(ctx context.Context, eventID uuid.UUID, deliveries []*domain.WebhookDelivery) error {
+	args := m.Called(ctx, eventID, deliveries)
+	return args.Error(0)
+}
+
+func (m *MockWebhookRepository) ClaimDueDeliveries(ctx context.Context, limit int, lease time.Duration) ([]*domain.WebhookDelivery, error) {
+	args := m.Called(ctx, limit, lease)
+	return args.Get(0).([]*domain.WebhookDelivery), args.Error(1)
+}
+
+func (m *MockWebhookRepository) SaveDeliveryResult(ctx context.Context, delivery *domain.WebhookDelivery) error {
+	args := m.Called(ctx, delivery)
+	return args.Error(0)
+}
+
+func (m *MockWebhookRepository) ListDeadLetters(ctx context.Context, limit int) ([]*domain.WebhookDelivery, error) {
+	args := m.Called(ctx, limit)
+	return args.Get(0).([]*domain.WebhookDelivery), args.Error(1)
+}
+
+func (m *MockWebhookRepository) RequeueDelivery(ctx context.Context, id uuid.UUID) error {
+	args := m.Called(ctx, id)
+	return args.Error(0)
+}
+
+// Mock WebhookSender
+type MockWebhookSender struct {
+	mock.Mock
+}
+
+func (m *MockWebhookSender) Send(ctx context.Context, delivery *domain.WebhookDelivery) error {
+	args := m.Called(ctx, delivery)
+	return args.Error(0)
+}
+
+func TestWebhookDispatcher_DispatchPending(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("creates deliveries for matching subscriptions only", func(t *testing.T) {
+		// Arrange
+		mockOutbox := new(MockOutboxRepository)
+		mockWebhooks := new(MockWebhookRepository)
+		dispatcher := application.NewWebhookDispatcher(mockOutbox, mockWebhooks, new(MockWebhookSender))
+
+		voteHook, _ := domain.NewWebhookSubscription("https://example.com/votes", "", []string{domain.EventVoteCreated})
+		catalogHook, _ := domain.NewWebhookSubscription("https://example.com/catalog", "", []string{domain.EventCatalogUpdated})
+		event, _ := domain.NewOutboxEvent(domain.EventVoteCreated, map[string]int{"score": 4})
+
+		mockOutbox.On("FetchPending", ctx, application.DispatchBatchSize).Return([]*domain.OutboxEvent{event}, nil).Once()
+		mockWebhooks.On("ListSubscriptions", ctx).Return([]*domain.WebhookSubscription{voteHook, catalogHook}, nil).Once()
+		mockWebhooks.On("EnqueueDeliveries", ctx, event.ID, mock.MatchedBy(func(deliveries []*domain.WebhookDelivery) bool {
+			return len(deliveries) == 1 && deliveries[0].SubscriptionID == voteHook.ID
+		})).Return(nil).Once()
+
+		// Act
+		err := dispatcher.DispatchPending(ctx)
+
+		// Assert
+		require.NoError(t, err)
+		mockOutbox.AssertExpectations(t)
+		mockWebhooks.AssertExpectations(t)
+	})
+
+	t.Run("does nothing without pending events", func(t *testing.T) {
+		// Arrange
+		mockOutbox := new(MockOutboxRepository)
+		mockWebhooks := new(MockWebhookRepository)
+		dispatcher := application.NewWebhookDispatcher(mockOutbox, mockWebhooks, new(MockWebhookSender))
+
+		mockOutbox.On("FetchPending", ctx, application.DispatchBatchSize).Return([]*domain.OutboxEvent{}, nil).Once()
+
+		// Act
+		err := dispatcher.DispatchPending(ctx)
+
+		// Assert
+		require.NoError(t, err)
+		mockWebhooks.AssertNotCalled(t, "ListSubscriptions", mock.Anything)
+	})
+}
+
+func TestWebhookDispatcher_DeliverDue(t *testing.T) {
+	ctx := context.Background()
+	subscription, _ := domain.NewWebhookSubscription("https://example.com", "", []string{domain.EventVoteCreated})
+	event, _ := domain.NewOutboxEvent(domain.EventVoteCreated, map[string]int{"score": 4})
+
+	t.Run("marks successful delivery as delivered", func(t *testing.T) {
+		// Arrange
+		mockWebhooks := new(MockWebhookRepository)
+		mockSender := new(MockWebhookSender)
+		dispatcher := application.NewWebhookDispatcher(new(MockOutboxRepository), mockWebhooks, mockSender)
+		delivery := domain.NewWebhookDelivery(subscription, event)
+
+		mockWebhooks.On("ClaimDueDeliveries", ctx, application.DispatchBatchSize, application.DeliveryLease).
+			Return([]*domain.WebhookDelivery{delivery}, nil).Once()
+		mockSender.On("Send", ctx, delivery).Return(nil).Once()
+		mockWebhooks.On("SaveDeliveryResult", ctx, delivery).Return(nil).Once()
+
+		// Act
+		err := dispatcher.DeliverDue(ctx)
+
+		// Assert
+		require.NoError(t, err)
+		assert.Equal(t, domain.DeliveryDelivered, delivery.Status)
+		assert.Equal(t, 1, delivery.Attempts)
+		mockWebhooks.AssertExpectations(t)
+		mockSender.AssertExpectations(t)
+	})
+
+	t.Run("schedules retry on failure", func(t *testing.T) {
+		// Arrange
+		mockWebhooks := new(MockWebhookRepository)
+		mockSender := new(MockWebhookSender)
+		dispatcher := application.NewWebhookDispatcher(new(MockOutboxRepository), mockWebhooks, mockSender)
+		delivery := domain.NewWebhookDelivery(subscription, event)
+
+		mockWebhooks.On("ClaimDueDeliveries", ctx, application.DispatchBatchSize, application.DeliveryLease).
+			Return([]*domain.WebhookDelivery{delivery}, nil).Once()
+		mockSender.On("Send", ctx, delivery).Return(assert.AnError).Once()
+		mockWebhooks.On("SaveDeliveryResult", ctx, delivery).Return(nil).Once()
+
+		// Act
+		err := dispatcher.DeliverDue(ctx)
+
+		// Assert
+		require.NoError(t, err)
+		assert.Equal(t, domain.DeliveryPending, delivery.Status)
+		assert.Equal(t, 1, delivery.Attempts)
+		assert.Equal(t, assert.AnError.Error(), delivery.LastError)
+		assert.True(t, delivery.NextAttemptAt.After(time.Now()))
+		mockWebhooks.AssertExpectations(t)
+	})
+}
@@ -0,0 +1,58 @@
+package application
+
+import (
+	"context"
+	"time"
+
+	"github.com/ArtemSind/food_tinder/internal/domain"
+	"github.com/google/uuid"
+)
+
+// DeadLetterLimit is the maximum number of dead-lettered deliveries returned at once
+const DeadLetterLimit = 100
+
+type WebhookRepository interface {
+	CreateSubscription(ctx context.Context, subscription *domain.WebhookSubscription) error
+	ListSubscriptions(ctx context.Context) ([]*domain.WebhookSubscription, error)
+	DeleteSubscription(ctx context.Context, id uuid.UUID) error
+	EnqueueDeliveries(ctx context.Context, eventID uuid.UUID, deliveries []*domain.WebhookDelivery) error
+	ClaimDueDeliveries(ctx context.Context, limit int, lease time.Duration) ([]*domain.WebhookDelivery, error)
+	SaveDeliveryResult(ctx context.Context, delivery *domain.WebhookDelivery) error
+	ListDeadLetters(ctx context.Context, limit int) ([]*domain.WebhookDelivery, error)
+	RequeueDelivery(ctx context.Context, id uuid.UUID) error
+}
+
+type WebhookService struct {
+	repo WebhookRepository
+}
+
+func NewWebhookService(repo WebhookRepository) *WebhookService {
+	return &WebhookService{repo: repo}
+}
+
+func (s *WebhookService) CreateSubscription(ctx context.Context, url, secret string, eventTypes []string) (*domain.WebhookSubscription, error) {
+	subscription, err := domain.NewWebhookSubscription(url, secret, eventTypes)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.repo.CreateSubscription(ctx, subscription); err != nil {
+		return nil, err
+	}
+	return subscription, nil
+}
+
+func (s *WebhookService) ListSubscriptions(ctx context.Context) ([]*domain.WebhookSubscription, error) {
+	return s.repo.ListSubscriptions(ctx)
+}
+
+func (s *WebhookService) DeleteSubscription(ctx context.Context, id uuid.UUID) error {
+	return s.repo.DeleteSubscription(ctx, id)
+}
+
+func (s *WebhookService) ListDeadLetters(ctx context.Context) ([]*domain.WebhookDelivery, error) {
+	return s.repo.ListDeadLetters(ctx, DeadLetterLimit)
+}
+
+func (s *WebhookService) RetryDelivery(ctx context.Context, id uuid.UUID) error {
+	return s.repo.RequeueDelivery(ctx, id)
+}
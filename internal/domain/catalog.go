@@ -0,0 +1,166 @@
+package domain
+
+import (
+	"errors"
+	"time"
+)
+
+const (
+	// DefaultMaxCatalogShrink is the largest share of the catalog a single refresh may remove
+	DefaultMaxCatalogShrink = 0.5
+	// MaxRecordedRejections caps the rejected products kept as examples in a validation report
+	MaxRecordedRejections = 20
+	// DefaultSnapshotRetention is how many earlier catalog snapshots are kept for rollback
+	DefaultSnapshotRetention = 5
+)
+
+var (
+	ErrCatalogShrink       = errors.New("refusing to replace catalog: new product list shrinks too much")
+	ErrNoCatalogValidation = errors.New("no catalog payload has been validated yet")
+	ErrSnapshotNotFound    = errors.New("catalog snapshot not found")
+)
+
+// ProductRejection is a product from Foodji that failed validation
+type ProductRejection struct {
+	Index     int    `json:"index"`
+	ProductID string `json:"product_id,omitempty"`
+	Reason    string `json:"reason"`
+}
+
+// CatalogValidation reports how the last Foodji payload fared against validation
+type CatalogValidation struct {
+	CheckedAt time.Time `json:"checked_at"`
+	Accepted  int       `json:"accepted"`
+	Rejected  int       `json:"rejected"`
+	// Rejections holds up to MaxRecordedRejections examples
+	Rejections []ProductRejection `json:"rejections,omitempty"`
+	// MissingFields lists the product fields no product in the payload had, which decode as zero values
+	MissingFields []string `json:"missing_fields,omitempty"`
+	// Refused is set when the payload did not replace the catalog
+	Refused       bool   `json:"refused"`
+	RefusedReason string `json:"refused_reason,omitempty"`
+}
+
+// NewCatalogValidation creates a validation report, keeping a sample of the rejections
+func NewCatalogValidation(accepted int, rejections []ProductRejection) *CatalogValidation {
+	validation := &CatalogValidation{
+		CheckedAt: time.Now(),
+		Accepted:  accepted,
+		Rejected:  len(rejections),
+	}
+	if len(rejections) > MaxRecordedRejections {
+		rejections = rejections[:MaxRecordedRejections]
+	}
+	validation.Rejections = rejections
+	return validation
+}
+
+// Refuse marks the payload as not applied
+func (v *CatalogValidation) Refuse(reason error) {
+	v.Refused = true
+	v.RefusedReason = reason.Error()
+}
+
+// ShrinksTooMuch reports whether replacing a catalog of current products with next products
+// would remove more than maxShrink of it
+func ShrinksTooMuch(current, next int, maxShrink float64) bool {
+	if current == 0 || next >= current {
+		return false
+	}
+	return float64(current-next)/float64(current) > maxShrink
+}
+
+// RefreshTrigger is what started a catalog refresh
+type RefreshTrigger string
+
+const (
+	RefreshScheduled  RefreshTrigger = "scheduled"
+	RefreshManual     RefreshTrigger = "manual"
+	RefreshEmptyCache RefreshTrigger = "empty_cache"
+	// RefreshSourceChanged is a refresh started by a product source that noticed a change
+	RefreshSourceChanged RefreshTrigger = "source_changed"
+	// RefreshForced is a manual refresh that replaces the catalog even if it shrinks too much
+	RefreshForced RefreshTrigger = "forced"
+)
+
+// RefreshOutcome is how a catalog refresh ended
+type RefreshOutcome string
+
+const (
+	RefreshSucceeded   RefreshOutcome = "succeeded"
+	RefreshNotModified RefreshOutcome = "not_modified"
+	RefreshFailed      RefreshOutcome = "failed"
+	// RefreshPinned is a refresh that kept a snapshot an admin rolled back to
+	RefreshPinned RefreshOutcome = "pinned"
+)
+
+var (
+	ErrRefreshInProgress = errors.New("a catalog refresh is already running")
+	ErrStaleRefresh      = errors.New("catalog was written by a newer refresh")
+)
+
+// RefreshRun describes a finished catalog refresh
+type RefreshRun struct {
+	Trigger      RefreshTrigger `json:"trigger"`
+	StartedAt    time.Time      `json:"started_at"`
+	FinishedAt   time.Time      `json:"finished_at"`
+	Duration     time.Duration  `json:"duration"`
+	Outcome      RefreshOutcome `json:"outcome"`
+	ProductCount int            `json:"product_count"`
+	Error        string         `json:"error,omitempty"`
+}
+
+// NewRefreshRun records the end of a refresh that started at startedAt
+func NewRefreshRun(trigger RefreshTrigger, startedAt time.Time, outcome RefreshOutcome, productCount int, err error) *RefreshRun {
+	finishedAt := time.Now()
+	run := &RefreshRun{
+		Trigger:      trigger,
+		StartedAt:    startedAt,
+		FinishedAt:   finishedAt,
+		Duration:     finishedAt.Sub(startedAt),
+		Outcome:      outcome,
+		ProductCount: productCount,
+	}
+	if err != nil {
+		run.Outcome = RefreshFailed
+		run.Error = err.Error()
+	}
+	return run
+}
+
+// RefreshStatus reports whether a refresh is running and how the last one went
+type RefreshStatus struct {
+	Running      bool        `json:"running"`
+	RunningSince *time.Time  `json:"running_since,omitempty"`
+	LastRun      *RefreshRun `json:"last_run,omitempty"`
+}
+
+// ProductCacheStats counts the product lookups a replica served from memory
+type ProductCacheStats struct {
+	Enabled  bool
+	Capacity int
+	Entries  int
+	Hits     int64
+	Misses   int64
+}
+
+// HitRatio is the share of lookups served from memory, or 0 if there were none
+func (s *ProductCacheStats) HitRatio() float64 {
+	if lookups := s.Hits + s.Misses; lookups > 0 {
+		return float64(s.Hits) / float64(lookups)
+	}
+	return 0
+}
+
+// CatalogSnapshot is a version of the catalog written by a refresh
+type CatalogSnapshot struct {
+	Version      int64
+	CreatedAt    time.Time
+	ProductCount int
+	// StockUpdatedAt is when the stock of the snapshot was last polled; nil if it was not
+	StockUpdatedAt *time.Time
+	// Current is set for the snapshot products are served from
+	Current bool
+	// Pinned is set for the snapshot a rollback switched to; refreshes keep it until it is unpinned
+	Pinned bool
+}
@@ -0,0 +1,52 @@
+package domain_test
+
+import (
+	"testing"
+
+	"github.com/ArtemSind/food_tinder/internal/domain"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestShrinksTooMuch(t *testing.T) {
+	tests := []struct {
+		name      string
+		current   int
+		next      int
+		maxShrink float64
+		want      bool
+	}{
+		{name: "empty cache", current: 0, next: 0, maxShrink: 0.5, want: false},
+		{name: "growing", current: 10, next: 12, maxShrink: 0, want: false},
+		{name: "within threshold", current: 10, next: 5, maxShrink: 0.5, want: false},
+		{name: "beyond threshold", current: 10, next: 4, maxShrink: 0.5, want: true},
+		{name: "emptied", current: 10, next: 0, maxShrink: 0.5, want: true},
+		{name: "guard disabled", current: 10, next: 0, maxShrink: 1, want: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, domain.ShrinksTooMuch(tt.current, tt.next, tt.maxShrink))
+		})
+	}
+}
+
+func TestNewCatalogValidation(t *testing.T) {
+	// Arrange
+	rejections := make([]domain.ProductRejection, domain.MaxRecordedRejections+5)
+
+	// Act
+	validation := domain.NewCatalogValidation(7, rejections)
+	validation.Refuse(domain.ErrCatalogShrink)
+
+	// Assert
+	assert.Equal(t, 7, validation.Accepted)
+	assert.Equal(t, domain.MaxRecordedRejections+5, validation.Rejected)
+	assert.Len(t, validation.Rejections, domain.MaxRecordedRejections)
+	assert.True(t, validation.Refused)
+	assert.Equal(t, domain.ErrCatalogShrink.Error(), validation.RefusedReason)
+}
+
+func TestProductCacheStats_HitRatio(t *testing.T) {
+	assert.Equal(t, 0.75, (&domain.ProductCacheStats{Hits: 3, Misses: 1}).HitRatio())
+	assert.Equal(t, 0.0, (&domain.ProductCacheStats{}).HitRatio(), "no lookups have no hit ratio")
+}
@@ -0,0 +1,159 @@
+package domain
+
+import (
+	"errors"
+	"math"
+	"math/rand"
+	"sort"
+
+	"github.com/google/uuid"
+)
+
+// DeckStrategy decides the order in which products are shown to a session
+type DeckStrategy string
+
+const (
+	// DeckRandom shuffles the products
+	DeckRandom DeckStrategy = "random"
+	// DeckPopularity shows the best rated products first
+	DeckPopularity DeckStrategy = "popularity"
+	// DeckBandit orders products by Thompson sampling over their scores,
+	// so rarely rated products are still shown often enough to learn about them
+	DeckBandit DeckStrategy = "bandit"
+	// DeckRecommendation shows the products predicted to be liked by the session first,
+	// followed by the rest in order of popularity
+	DeckRecommendation DeckStrategy = "recommendation"
+
+	// DefaultDeckStrategy is used by sessions that did not choose a strategy
+	DefaultDeckStrategy = DeckRandom
+)
+
+var ErrInvalidDeckStrategy = errors.New("unknown deck strategy")
+
+// DeckStrategies lists every supported strategy
+var DeckStrategies = []DeckStrategy{DeckRandom, DeckPopularity, DeckBandit, DeckRecommendation}
+
+// ParseDeckStrategy validates a strategy name
+func ParseDeckStrategy(name string) (DeckStrategy, error) {
+	for _, strategy := range DeckStrategies {
+		if string(strategy) == name {
+			return strategy, nil
+		}
+	}
+	return "", ErrInvalidDeckStrategy
+}
+
+// Deck is an ordered list of products to show to a session
+type Deck struct {
+	SessionID uuid.UUID    `json:"session_id"`
+	Strategy  DeckStrategy `json:"strategy"`
+	Products  []uuid.UUID  `json:"products"`
+}
+
+// OrderRandom returns the products in random order
+func OrderRandom(products []uuid.UUID, rng *rand.Rand) []uuid.UUID {
+	ordered := append([]uuid.UUID(nil), products...)
+	rng.Shuffle(len(ordered), func(i, j int) {
+		ordered[i], ordered[j] = ordered[j], ordered[i]
+	})
+	return ordered
+}
+
+// OrderByPopularity returns the products by average score, then vote count.
+// Unrated products, including ones that were only shown, keep their relative order at the end.
+func OrderByPopularity(products []uuid.UUID, scores map[uuid.UUID]*ProductScore) []uuid.UUID {
+	ordered := append([]uuid.UUID(nil), products...)
+	sort.SliceStable(ordered, func(i, j int) bool {
+		a, b := scores[ordered[i]], scores[ordered[j]]
+		switch {
+		case a == nil || a.VoteCount == 0:
+			return false
+		case b == nil || b.VoteCount == 0:
+			return true
+		case a.AvgScore != b.AvgScore:
+			return a.AvgScore > b.AvgScore
+		default:
+			return a.VoteCount > b.VoteCount
+		}
+	})
+	return ordered
+}
+
+// OrderByRecommendations puts the recommended products first, in recommendation order,
+// followed by the remaining products in order of popularity
+func OrderByRecommendations(products []uuid.UUID, recommendations []*Recommendation, scores map[uuid.UUID]*ProductScore) []uuid.UUID {
+	candidates := make(map[uuid.UUID]bool, len(products))
+	for _, id := range products {
+		candidates[id] = true
+	}
+
+	ordered := make([]uuid.UUID, 0, len(products))
+	recommended := make(map[uuid.UUID]bool, len(recommendations))
+	for _, recommendation := range recommendations {
+		if candidates[recommendation.ProductID] && !recommended[recommendation.ProductID] {
+			ordered = append(ordered, recommendation.ProductID)
+			recommended[recommendation.ProductID] = true
+		}
+	}
+
+	rest := make([]uuid.UUID, 0, len(products)-len(ordered))
+	for _, id := range products {
+		if !recommended[id] {
+			rest = append(rest, id)
+		}
+	}
+
+	return append(ordered, OrderByPopularity(rest, scores)...)
+}
+
+// OrderByThompsonSampling draws a plausible "like rate" for every product from a Beta posterior
+// over its scores and orders by the draws. A score of 1..5 counts as (score-1)/4 of a success,
+// so well-rated products win most draws while uncertain new products regularly come first.
+func OrderByThompsonSampling(products []uuid.UUID, scores map[uuid.UUID]*ProductScore, rng *rand.Rand) []uuid.UUID {
+	draws := make(map[uuid.UUID]float64, len(products))
+	for _, id := range products {
+		alpha, beta := 1.0, 1.0
+		if score := scores[id]; score != nil && score.VoteCount > 0 {
+			successes := float64(score.VoteCount) * (score.AvgScore - 1) / 4
+			alpha += successes
+			beta += float64(score.VoteCount) - successes
+		}
+		draws[id] = sampleBeta(rng, alpha, beta)
+	}
+
+	ordered := append([]uuid.UUID(nil), products...)
+	sort.SliceStable(ordered, func(i, j int) bool {
+		return draws[ordered[i]] > draws[ordered[j]]
+	})
+	return ordered
+}
+
+// sampleBeta draws from Beta(alpha, beta) using two Gamma draws
+func sampleBeta(rng *rand.Rand, alpha, beta float64) float64 {
+	x := sampleGamma(rng, alpha)
+	y := sampleGamma(rng, beta)
+	return x / (x + y)
+}
+
+// sampleGamma draws from Gamma(shape, 1) with the Marsaglia-Tsang method
+func sampleGamma(rng *rand.Rand, shape float64) float64 {
+	if shape < 1 {
+		// Boost the shape and correct, see Marsaglia & Tsang (2000)
+		return sampleGamma(rng, shape+1) * math.Pow(rng.Float64(), 1/shape)
+	}
+
+	d := shape - 1.0/3
+	c := 1 / math.Sqrt(9*d)
+	for {
+		x := rng.NormFloat64()
+		v := 1 + c*x
+		if v <= 0 {
+			continue
+		}
+		v = v * v * v
+		u := rng.Float64()
+		if math.Log(u) < 0.5*x*x+d-d*v+d*math.Log(v) {
+			return d * v
+		}
+	}
+}
@@ -0,0 +1,80 @@
+package domain_test
+
+import (
+	"math/rand"
+	"testing"
+
+	"github.com/ArtemSind/food_tinder/internal/domain"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseDeckStrategy(t *testing.T) {
+	strategy, err := domain.ParseDeckStrategy("bandit")
+	require.NoError(t, err)
+	assert.Equal(t, domain.DeckBandit, strategy)
+
+	_, err = domain.ParseDeckStrategy("alphabetical")
+	assert.Equal(t, domain.ErrInvalidDeckStrategy, err)
+}
+
+func TestOrderByPopularity(t *testing.T) {
+	// Arrange
+	best, good, unrated, shown := uuid.New(), uuid.New(), uuid.New(), uuid.New()
+	scores := map[uuid.UUID]*domain.ProductScore{
+		best:  {ProductID: best, AvgScore: 4.8, VoteCount: 3},
+		good:  {ProductID: good, AvgScore: 3.5, VoteCount: 10},
+		shown: {ProductID: shown, Views: 12, Skips: 9},
+	}
+
+	// Act
+	ordered := domain.OrderByPopularity([]uuid.UUID{unrated, shown, good, best}, scores)
+
+	// Assert
+	assert.Equal(t, []uuid.UUID{best, good, unrated, shown}, ordered)
+}
+
+func TestOrderByThompsonSampling(t *testing.T) {
+	// Arrange
+	favourite, disliked, unknown := uuid.New(), uuid.New(), uuid.New()
+	scores := map[uuid.UUID]*domain.ProductScore{
+		favourite: {ProductID: favourite, AvgScore: 4.9, VoteCount: 50},
+		disliked:  {ProductID: disliked, AvgScore: 1.2, VoteCount: 50},
+	}
+	rng := rand.New(rand.NewSource(42))
+	firstPlaces := map[uuid.UUID]int{}
+
+	// Act
+	for range 1000 {
+		ordered := domain.OrderByThompsonSampling([]uuid.UUID{disliked, unknown, favourite}, scores, rng)
+		require.Len(t, ordered, 3)
+		firstPlaces[ordered[0]]++
+	}
+
+	// Assert
+	assert.Greater(t, firstPlaces[favourite], firstPlaces[unknown], "favourites should usually lead")
+	assert.Greater(t, firstPlaces[unknown], 0, "unrated products must still be explored")
+	assert.Less(t, firstPlaces[disliked], 10, "well-known bad products should rarely lead")
+}
+
+func TestNewDeckImpressions(t *testing.T) {
+	// Arrange
+	deck := &domain.Deck{
+		SessionID: uuid.New(),
+		Strategy:  domain.DeckBandit,
+		Products:  []uuid.UUID{uuid.New(), uuid.New()},
+	}
+
+	// Act
+	impressions := domain.NewDeckImpressions(deck)
+
+	// Assert
+	require.Len(t, impressions, 2)
+	for i, impression := range impressions {
+		assert.Equal(t, deck.SessionID, impression.SessionID)
+		assert.Equal(t, deck.Products[i], impression.ProductID)
+		assert.Equal(t, domain.DeckBandit, impression.Strategy)
+		assert.Equal(t, i, impression.Position)
+	}
+}
@@ -0,0 +1,114 @@
+package domain
+
+import (
+	"errors"
+	"strings"
+)
+
+var ErrInvalidDietaryPreferences = errors.New("invalid dietary preferences")
+
+// MaxDietaryPreferences caps the allergens and diets a session can declare
+const MaxDietaryPreferences = 32
+
+// impliedDiets lists the diets a product labelled with a diet also meets
+var impliedDiets = map[string][]string{
+	"vegan": {"vegetarian"},
+}
+
+// DietaryPreferences are the exclusions a session declared. Allergens and diets are lower case.
+type DietaryPreferences struct {
+	// ExcludedAllergens rules out products containing any of these allergens
+	ExcludedAllergens []string `json:"exclude_allergens"`
+	// Diets rules out products that do not meet every one of these diets, such as vegetarian
+	Diets []string `json:"diets"`
+}
+
+// NewDietaryPreferences normalizes the declared allergens and diets to lower case without duplicates
+func NewDietaryPreferences(excludedAllergens, diets []string) (DietaryPreferences, error) {
+	allergens, err := normalizeLabels(excludedAllergens)
+	if err != nil {
+		return DietaryPreferences{}, err
+	}
+	normalizedDiets, err := normalizeLabels(diets)
+	if err != nil {
+		return DietaryPreferences{}, err
+	}
+	return DietaryPreferences{ExcludedAllergens: allergens, Diets: normalizedDiets}, nil
+}
+
+func normalizeLabels(labels []string) ([]string, error) {
+	if len(labels) > MaxDietaryPreferences {
+		return nil, ErrInvalidDietaryPreferences
+	}
+
+	normalized := make([]string, 0, len(labels))
+	seen := make(map[string]bool, len(labels))
+	for _, label := range labels {
+		label = strings.ToLower(strings.TrimSpace(label))
+		if label == "" {
+			return nil, ErrInvalidDietaryPreferences
+		}
+		if !seen[label] {
+			seen[label] = true
+			normalized = append(normalized, label)
+		}
+	}
+	return normalized, nil
+}
+
+// IsEmpty reports whether the preferences rule out no product
+func (p DietaryPreferences) IsEmpty() bool {
+	return len(p.ExcludedAllergens) == 0 && len(p.Diets) == 0
+}
+
+// Allows reports whether a product with these allergens and diet labels is safe for the
+// session. Labels are compared case-insensitively. Nil allergens mean the source does not report
+// them, so a product with unknown allergens is unsafe once the session excludes any allergen.
+func (p DietaryPreferences) Allows(allergens, diets []string) bool {
+	if allergens == nil && len(p.ExcludedAllergens) > 0 {
+		return false
+	}
+	for _, allergen := range allergens {
+		for _, excluded := range p.ExcludedAllergens {
+			if strings.EqualFold(allergen, excluded) {
+				return false
+			}
+		}
+	}
+
+	if len(p.Diets) == 0 {
+		return true
+	}
+	met := make(map[string]bool, len(diets))
+	for _, diet := range diets {
+		diet = strings.ToLower(diet)
+		met[diet] = true
+		for _, implied := range impliedDiets[diet] {
+			met[implied] = true
+		}
+	}
+	for _, diet := range p.Diets {
+		if !met[diet] {
+			return false
+		}
+	}
+	return true
+}
+
+// AllergensUnknownWarning is the warning for products whose source does not report allergens
+const AllergensUnknownWarning = "Allergens unknown"
+
+// AllergenWarnings returns a warning for every allergen a product contains, or a single warning
+// if its allergens are unknown
+func AllergenWarnings(allergens []string) []string {
+	if allergens == nil {
+		return []string{AllergensUnknownWarning}
+	}
+	warnings := make([]string, 0, len(allergens))
+	for _, allergen := range allergens {
+		if allergen = strings.TrimSpace(allergen); allergen != "" {
+			warnings = append(warnings, "Contains "+strings.ToLower(allergen))
+		}
+	}
+	return warnings
+}
@@ -0,0 +1,89 @@
+package domain_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/ArtemSind/food_tinder/internal/domain"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewDietaryPreferences(t *testing.T) {
+	t.Run("normalizes labels", func(t *testing.T) {
+		// Act
+		preferences, err := domain.NewDietaryPreferences([]string{" Nuts ", "milk", "nuts"}, []string{"Vegan"})
+
+		// Assert
+		require.NoError(t, err)
+		assert.Equal(t, []string{"nuts", "milk"}, preferences.ExcludedAllergens)
+		assert.Equal(t, []string{"vegan"}, preferences.Diets)
+		assert.False(t, preferences.IsEmpty())
+	})
+
+	t.Run("no preferences", func(t *testing.T) {
+		// Act
+		preferences, err := domain.NewDietaryPreferences(nil, nil)
+
+		// Assert
+		require.NoError(t, err)
+		assert.True(t, preferences.IsEmpty())
+		assert.NotNil(t, preferences.ExcludedAllergens)
+	})
+
+	t.Run("rejects blank and too many labels", func(t *testing.T) {
+		// Act
+		_, blankErr := domain.NewDietaryPreferences([]string{""}, nil)
+		_, tooManyErr := domain.NewDietaryPreferences(nil, strings.Split(strings.Repeat("x,", domain.MaxDietaryPreferences+1), ","))
+
+		// Assert
+		assert.ErrorIs(t, blankErr, domain.ErrInvalidDietaryPreferences)
+		assert.ErrorIs(t, tooManyErr, domain.ErrInvalidDietaryPreferences)
+	})
+}
+
+func TestDietaryPreferences_Allows(t *testing.T) {
+	tests := []struct {
+		name      string
+		excluded  []string
+		diets     []string
+		allergens []string
+		labels    []string
+		want      bool
+	}{
+		{name: "no preferences", allergens: []string{"nuts"}, want: true},
+		{name: "excluded allergen", excluded: []string{"nuts"}, allergens: []string{"milk", "Nuts"}, want: false},
+		{name: "other allergens", excluded: []string{"nuts"}, allergens: []string{"milk"}, want: true},
+		{name: "no allergens", excluded: []string{"nuts"}, allergens: []string{}, want: true},
+		{name: "unknown allergens", excluded: []string{"nuts"}, want: false},
+		{name: "unknown allergens without exclusions", diets: []string{"vegan"}, labels: []string{"vegan"}, want: true},
+		{name: "diet met", diets: []string{"vegetarian"}, labels: []string{"Vegetarian"}, want: true},
+		{name: "diet not labelled", diets: []string{"vegetarian"}, want: false},
+		{name: "vegan is vegetarian", diets: []string{"vegetarian"}, labels: []string{"vegan"}, want: true},
+		{name: "every diet has to be met", diets: []string{"vegan", "gluten-free"}, labels: []string{"vegan"}, want: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Arrange
+			preferences, err := domain.NewDietaryPreferences(tt.excluded, tt.diets)
+			require.NoError(t, err)
+
+			// Act
+			allowed := preferences.Allows(tt.allergens, tt.labels)
+
+			// Assert
+			assert.Equal(t, tt.want, allowed)
+		})
+	}
+}
+
+func TestAllergenWarnings(t *testing.T) {
+	// Act
+	warnings := domain.AllergenWarnings([]string{"Gluten", " ", "nuts"})
+
+	// Assert
+	assert.Equal(t, []string{"Contains gluten", "Contains nuts"}, warnings)
+	assert.Equal(t, []string{}, domain.AllergenWarnings([]string{}), "products without allergens have an empty list")
+	assert.Equal(t, []string{"Allergens unknown"}, domain.AllergenWarnings(nil))
+}
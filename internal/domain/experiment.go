@@ -0,0 +1,218 @@
+package domain
+
+import (
+	"errors"
+	"hash/fnv"
+	"math"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// ExperimentStatus is the lifecycle state of an experiment
+type ExperimentStatus string
+
+const (
+	ExperimentRunning ExperimentStatus = "running"
+	ExperimentStopped ExperimentStatus = "stopped"
+)
+
+// ConfidenceZ is the z-score of the 95% confidence intervals reported by experiments
+const ConfidenceZ = 1.96
+
+var (
+	ErrExperimentNotFound       = errors.New("experiment not found")
+	ErrExperimentAlreadyRunning = errors.New("another experiment is already running")
+	ErrExperimentNameRequired   = errors.New("experiment name is required")
+	ErrTooFewVariants           = errors.New("an experiment needs at least two variants")
+	ErrInvalidVariantWeight     = errors.New("variant weight must be positive")
+	ErrDuplicateVariant         = errors.New("variant names must be unique")
+	ErrVariantNameRequired      = errors.New("variant name is required")
+)
+
+// Variant is one arm of an experiment; Weight is its relative share of traffic
+type Variant struct {
+	Name     string       `json:"name"`
+	Strategy DeckStrategy `json:"strategy"`
+	Weight   int          `json:"weight"`
+}
+
+// Experiment compares deck strategies by splitting new sessions between variants
+type Experiment struct {
+	ID          uuid.UUID        `json:"id"`
+	Name        string           `json:"name"`
+	Description string           `json:"description"`
+	Status      ExperimentStatus `json:"status"`
+	Variants    []Variant        `json:"variants"`
+	CreatedAt   time.Time        `json:"created_at"`
+	StoppedAt   *time.Time       `json:"stopped_at,omitempty"`
+}
+
+// ExperimentAssignment records which variant a session was put in
+type ExperimentAssignment struct {
+	ExperimentID uuid.UUID `json:"experiment_id"`
+	Variant      string    `json:"variant"`
+}
+
+// NewExperiment validates the variants and creates a running experiment
+func NewExperiment(name, description string, variants []Variant) (*Experiment, error) {
+	if name == "" {
+		return nil, ErrExperimentNameRequired
+	}
+	if len(variants) < 2 {
+		return nil, ErrTooFewVariants
+	}
+
+	seen := make(map[string]bool, len(variants))
+	for _, variant := range variants {
+		if variant.Name == "" {
+			return nil, ErrVariantNameRequired
+		}
+		if seen[variant.Name] {
+			return nil, ErrDuplicateVariant
+		}
+		seen[variant.Name] = true
+
+		if variant.Weight <= 0 {
+			return nil, ErrInvalidVariantWeight
+		}
+		if _, err := ParseDeckStrategy(string(variant.Strategy)); err != nil {
+			return nil, err
+		}
+	}
+
+	return &Experiment{
+		ID:          uuid.New(),
+		Name:        name,
+		Description: description,
+		Status:      ExperimentRunning,
+		Variants:    variants,
+		CreatedAt:   time.Now(),
+	}, nil
+}
+
+// Assign deterministically picks a variant for a session: hashing the experiment and
+// session IDs together gives the same answer on every replica and on every retry
+func (e *Experiment) Assign(sessionID uuid.UUID) Variant {
+	totalWeight := 0
+	for _, variant := range e.Variants {
+		totalWeight += variant.Weight
+	}
+
+	h := fnv.New64a()
+	h.Write(e.ID[:])
+	h.Write(sessionID[:])
+	bucket := int(h.Sum64() % uint64(totalWeight))
+
+	for _, variant := range e.Variants {
+		if bucket < variant.Weight {
+			return variant
+		}
+		bucket -= variant.Weight
+	}
+	return e.Variants[len(e.Variants)-1]
+}
+
+// Stop ends the experiment; new sessions are no longer assigned to it
+func (e *Experiment) Stop() {
+	now := time.Now()
+	e.Status = ExperimentStopped
+	e.StoppedAt = &now
+}
+
+// AssignExperiment puts the session in a variant of the experiment and adopts its deck strategy
+func (s *Session) AssignExperiment(experiment *Experiment) {
+	variant := experiment.Assign(s.ID)
+	s.DeckStrategy = variant.Strategy
+	s.Experiment = &ExperimentAssignment{
+		ExperimentID: experiment.ID,
+		Variant:      variant.Name,
+	}
+}
+
+// VariantStats are the raw totals of the sessions assigned to a variant
+type VariantStats struct {
+	Variant         string
+	Sessions        int
+	Impressions     int
+	Votes           int
+	ScoreSum        int
+	ScoreSquaredSum int
+}
+
+// Interval is a confidence interval
+type Interval struct {
+	Low  float64 `json:"low"`
+	High float64 `json:"high"`
+}
+
+// VariantResult summarises a variant with 95% confidence intervals
+type VariantResult struct {
+	Variant              string   `json:"variant"`
+	Strategy             string   `json:"strategy"`
+	Sessions             int      `json:"sessions"`
+	Impressions          int      `json:"impressions"`
+	Votes                int      `json:"votes"`
+	VotesPerImpression   float64  `json:"votes_per_impression"`
+	VotesPerImpressionCI Interval `json:"votes_per_impression_ci"`
+	AvgScore             float64  `json:"avg_score"`
+	AvgScoreCI           Interval `json:"avg_score_ci"`
+}
+
+// Analyze computes the per-variant metrics. Sessions are counted in the variant they were
+// assigned to even if they later switched strategy (intention-to-treat).
+func (e *Experiment) Analyze(stats []*VariantStats) []*VariantResult {
+	byVariant := make(map[string]*VariantStats, len(stats))
+	for _, s := range stats {
+		byVariant[s.Variant] = s
+	}
+
+	results := make([]*VariantResult, 0, len(e.Variants))
+	for _, variant := range e.Variants {
+		s := byVariant[variant.Name]
+		if s == nil {
+			s = &VariantStats{Variant: variant.Name}
+		}
+
+		result := &VariantResult{
+			Variant:     variant.Name,
+			Strategy:    string(variant.Strategy),
+			Sessions:    s.Sessions,
+			Impressions: s.Impressions,
+			Votes:       s.Votes,
+		}
+
+		if s.Impressions > 0 {
+			// Votes can exceed impressions when clients vote on products they found elsewhere
+			successes := math.Min(float64(s.Votes), float64(s.Impressions))
+			result.VotesPerImpression = float64(s.Votes) / float64(s.Impressions)
+			result.VotesPerImpressionCI = wilsonInterval(successes, float64(s.Impressions))
+		}
+
+		if s.Votes > 0 {
+			n := float64(s.Votes)
+			mean := float64(s.ScoreSum) / n
+			result.AvgScore = mean
+			result.AvgScoreCI = Interval{Low: mean, High: mean}
+			if s.Votes > 1 {
+				variance := (float64(s.ScoreSquaredSum) - n*mean*mean) / (n - 1)
+				margin := ConfidenceZ * math.Sqrt(math.Max(variance, 0)/n)
+				result.AvgScoreCI = Interval{Low: mean - margin, High: mean + margin}
+			}
+		}
+
+		results = append(results, result)
+	}
+
+	return results
+}
+
+// wilsonInterval is the Wilson score interval of a proportion, well behaved for small samples
+func wilsonInterval(successes, trials float64) Interval {
+	p := successes / trials
+	z2 := ConfidenceZ * ConfidenceZ
+	denominator := 1 + z2/trials
+	centre := (p + z2/(2*trials)) / denominator
+	margin := ConfidenceZ * math.Sqrt(p*(1-p)/trials+z2/(4*trials*trials)) / denominator
+	return Interval{Low: centre - margin, High: centre + margin}
+}
@@ -0,0 +1,155 @@
+package domain_test
+
+import (
+	"testing"
+
+	"github.com/ArtemSind/food_tinder/internal/domain"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewExperiment(t *testing.T) {
+	valid := []domain.Variant{
+		{Name: "control", Strategy: domain.DeckRandom, Weight: 1},
+		{Name: "treatment", Strategy: domain.DeckBandit, Weight: 1},
+	}
+
+	tests := []struct {
+		name     string
+		expName  string
+		variants []domain.Variant
+		wantErr  error
+	}{
+		{name: "valid", expName: "bandit vs random", variants: valid},
+		{name: "missing name", variants: valid, wantErr: domain.ErrExperimentNameRequired},
+		{name: "single variant", expName: "x", variants: valid[:1], wantErr: domain.ErrTooFewVariants},
+		{
+			name:    "duplicate variant",
+			expName: "x",
+			variants: []domain.Variant{
+				{Name: "a", Strategy: domain.DeckRandom, Weight: 1},
+				{Name: "a", Strategy: domain.DeckBandit, Weight: 1},
+			},
+			wantErr: domain.ErrDuplicateVariant,
+		},
+		{
+			name:    "unnamed variant",
+			expName: "x",
+			variants: []domain.Variant{
+				{Name: "", Strategy: domain.DeckRandom, Weight: 1},
+				{Name: "b", Strategy: domain.DeckBandit, Weight: 1},
+			},
+			wantErr: domain.ErrVariantNameRequired,
+		},
+		{
+			name:    "zero weight",
+			expName: "x",
+			variants: []domain.Variant{
+				{Name: "a", Strategy: domain.DeckRandom, Weight: 0},
+				{Name: "b", Strategy: domain.DeckBandit, Weight: 1},
+			},
+			wantErr: domain.ErrInvalidVariantWeight,
+		},
+		{
+			name:    "unknown strategy",
+			expName: "x",
+			variants: []domain.Variant{
+				{Name: "a", Strategy: "newest", Weight: 1},
+				{Name: "b", Strategy: domain.DeckBandit, Weight: 1},
+			},
+			wantErr: domain.ErrInvalidDeckStrategy,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Act
+			experiment, err := domain.NewExperiment(tt.expName, "", tt.variants)
+
+			// Assert
+			if tt.wantErr != nil {
+				assert.Equal(t, tt.wantErr, err)
+				assert.Nil(t, experiment)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, domain.ExperimentRunning, experiment.Status)
+		})
+	}
+}
+
+func TestExperiment_Assign(t *testing.T) {
+	// Arrange
+	experiment, err := domain.NewExperiment("split", "", []domain.Variant{
+		{Name: "small", Strategy: domain.DeckRandom, Weight: 1},
+		{Name: "large", Strategy: domain.DeckPopularity, Weight: 3},
+	})
+	require.NoError(t, err)
+
+	t.Run("is deterministic", func(t *testing.T) {
+		sessionID := uuid.New()
+		assert.Equal(t, experiment.Assign(sessionID), experiment.Assign(sessionID))
+	})
+
+	t.Run("follows the weights", func(t *testing.T) {
+		// Act
+		counts := map[string]int{}
+		for i := 0; i < 4000; i++ {
+			counts[experiment.Assign(uuid.New()).Name]++
+		}
+
+		// Assert
+		assert.InDelta(t, 1000, counts["small"], 150)
+		assert.InDelta(t, 3000, counts["large"], 150)
+	})
+
+	t.Run("session adopts the variant strategy", func(t *testing.T) {
+		// Act
+		session := domain.NewSession()
+		session.AssignExperiment(experiment)
+
+		// Assert
+		require.NotNil(t, session.Experiment)
+		assert.Equal(t, experiment.ID, session.Experiment.ExperimentID)
+		variant := experiment.Assign(session.ID)
+		assert.Equal(t, variant.Name, session.Experiment.Variant)
+		assert.Equal(t, variant.Strategy, session.DeckStrategy)
+	})
+}
+
+func TestExperiment_Analyze(t *testing.T) {
+	// Arrange
+	experiment, err := domain.NewExperiment("split", "", []domain.Variant{
+		{Name: "control", Strategy: domain.DeckRandom, Weight: 1},
+		{Name: "treatment", Strategy: domain.DeckBandit, Weight: 1},
+	})
+	require.NoError(t, err)
+
+	stats := []*domain.VariantStats{
+		// Scores 3, 4 and 5
+		{Variant: "treatment", Sessions: 2, Impressions: 10, Votes: 3, ScoreSum: 12, ScoreSquaredSum: 50},
+	}
+
+	// Act
+	results := experiment.Analyze(stats)
+
+	// Assert
+	require.Len(t, results, 2)
+
+	control := results[0]
+	assert.Equal(t, "control", control.Variant)
+	assert.Zero(t, control.Sessions)
+	assert.Zero(t, control.VotesPerImpression)
+
+	treatment := results[1]
+	assert.Equal(t, "bandit", treatment.Strategy)
+	assert.InDelta(t, 0.3, treatment.VotesPerImpression, 1e-9)
+	assert.Less(t, treatment.VotesPerImpressionCI.Low, 0.3)
+	assert.Greater(t, treatment.VotesPerImpressionCI.High, 0.3)
+	assert.GreaterOrEqual(t, treatment.VotesPerImpressionCI.Low, 0.0)
+	assert.InDelta(t, 4.0, treatment.AvgScore, 1e-9)
+	// Sample standard deviation is 1, so the margin is 1.96/sqrt(3)
+	assert.InDelta(t, 4-1.96/1.7320508, treatment.AvgScoreCI.Low, 1e-6)
+	assert.InDelta(t, 4+1.96/1.7320508, treatment.AvgScoreCI.High, 1e-6)
+}
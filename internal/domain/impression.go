@@ -0,0 +1,74 @@
+package domain
+
+import (
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// ImpressionAction is what happened when a product was in front of a session
+type ImpressionAction string
+
+const (
+	// ActionImpression means the product was shown
+	ActionImpression ImpressionAction = "impression"
+	// ActionSkip means the session explicitly passed over the product without voting
+	ActionSkip ImpressionAction = "skip"
+)
+
+var ErrInvalidImpressionAction = errors.New("impression action must be impression or skip")
+
+// ParseImpressionAction validates an action name; an empty name is a plain impression
+func ParseImpressionAction(name string) (ImpressionAction, error) {
+	switch ImpressionAction(name) {
+	case "", ActionImpression:
+		return ActionImpression, nil
+	case ActionSkip:
+		return ActionSkip, nil
+	default:
+		return "", ErrInvalidImpressionAction
+	}
+}
+
+// Impression records that a product was shown to, or skipped by, a session
+type Impression struct {
+	ID        uuid.UUID        `json:"id"`
+	SessionID uuid.UUID        `json:"session_id"`
+	ProductID uuid.UUID        `json:"product_id"`
+	Strategy  DeckStrategy     `json:"strategy"`
+	Position  int              `json:"position"`
+	Action    ImpressionAction `json:"action"`
+	CreatedAt time.Time        `json:"created_at"`
+}
+
+// NewImpression creates an impression reported by a client for a session's product
+func NewImpression(session *Session, productID uuid.UUID, position int, action ImpressionAction) *Impression {
+	return &Impression{
+		ID:        uuid.New(),
+		SessionID: session.ID,
+		ProductID: productID,
+		Strategy:  session.DeckStrategy,
+		Position:  position,
+		Action:    action,
+		CreatedAt: time.Now(),
+	}
+}
+
+// NewDeckImpressions creates one impression per product in the deck, in deck order
+func NewDeckImpressions(deck *Deck) []*Impression {
+	now := time.Now()
+	impressions := make([]*Impression, len(deck.Products))
+	for i, productID := range deck.Products {
+		impressions[i] = &Impression{
+			ID:        uuid.New(),
+			SessionID: deck.SessionID,
+			ProductID: productID,
+			Strategy:  deck.Strategy,
+			Position:  i,
+			Action:    ActionImpression,
+			CreatedAt: now,
+		}
+	}
+	return impressions
+}
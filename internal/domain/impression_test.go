@@ -0,0 +1,88 @@
+package domain_test
+
+import (
+	"testing"
+
+	"github.com/ArtemSind/food_tinder/internal/domain"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseImpressionAction(t *testing.T) {
+	tests := []struct {
+		name    string
+		input   string
+		want    domain.ImpressionAction
+		wantErr error
+	}{
+		{name: "default", input: "", want: domain.ActionImpression},
+		{name: "impression", input: "impression", want: domain.ActionImpression},
+		{name: "skip", input: "skip", want: domain.ActionSkip},
+		{name: "unknown", input: "like", wantErr: domain.ErrInvalidImpressionAction},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Act
+			action, err := domain.ParseImpressionAction(tt.input)
+
+			// Assert
+			assert.Equal(t, tt.wantErr, err)
+			assert.Equal(t, tt.want, action)
+		})
+	}
+}
+
+func TestProductScore_Rates(t *testing.T) {
+	tests := []struct {
+		name           string
+		score          domain.ProductScore
+		wantConversion float64
+		wantSkipRate   float64
+	}{
+		{name: "never shown", score: domain.ProductScore{VoteCount: 3}},
+		{name: "passed over", score: domain.ProductScore{Views: 10, VoteCount: 1, Skips: 8}, wantConversion: 0.1, wantSkipRate: 0.8},
+		{name: "votes from elsewhere are capped", score: domain.ProductScore{Views: 2, VoteCount: 5}, wantConversion: 1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Assert
+			assert.InDelta(t, tt.wantConversion, tt.score.ConversionRate(), 1e-9)
+			assert.InDelta(t, tt.wantSkipRate, tt.score.SkipRate(), 1e-9)
+		})
+	}
+}
+
+func TestProductScore_CurrentVersion(t *testing.T) {
+	// Arrange
+	score := domain.ProductScore{Versions: []domain.ProductVersionScore{
+		{Version: "old", AvgScore: 4.5, VoteCount: 8},
+		{Version: "new", AvgScore: 2, VoteCount: 2, Current: true},
+	}}
+
+	// Act
+	current := score.CurrentVersion()
+
+	// Assert
+	require.NotNil(t, current)
+	assert.Equal(t, "new", current.Version)
+	assert.Nil(t, (&domain.ProductScore{}).CurrentVersion())
+}
+
+func TestNewImpression(t *testing.T) {
+	// Arrange
+	session := &domain.Session{ID: uuid.New(), DeckStrategy: domain.DeckBandit}
+	productID := uuid.New()
+
+	// Act
+	impression := domain.NewImpression(session, productID, 3, domain.ActionSkip)
+
+	// Assert
+	assert.Equal(t, session.ID, impression.SessionID)
+	assert.Equal(t, productID, impression.ProductID)
+	assert.Equal(t, domain.DeckBandit, impression.Strategy)
+	assert.Equal(t, 3, impression.Position)
+	assert.Equal(t, domain.ActionSkip, impression.Action)
+}
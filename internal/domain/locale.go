@@ -0,0 +1,41 @@
+package domain
+
+import (
+	"errors"
+	"strings"
+)
+
+// Locale is a language the API serves product texts and messages in
+type Locale string
+
+const (
+	LocaleEnglish Locale = "en"
+	LocaleGerman  Locale = "de"
+
+	// DefaultLocale is used when neither the session nor the request asks for a language.
+	// Catalog texts without a translation are in this language.
+	DefaultLocale = LocaleEnglish
+)
+
+var ErrInvalidLocale = errors.New("unsupported locale")
+
+// Locales lists every supported locale
+var Locales = []Locale{LocaleEnglish, LocaleGerman}
+
+// ParseLocale validates a language tag such as de or de-CH; regional variants map to their
+// language. An empty tag is no locale.
+func ParseLocale(tag string) (Locale, error) {
+	tag = strings.ToLower(strings.TrimSpace(tag))
+	if tag == "" {
+		return "", nil
+	}
+	if i := strings.IndexAny(tag, "-_"); i > 0 {
+		tag = tag[:i]
+	}
+	for _, locale := range Locales {
+		if string(locale) == tag {
+			return locale, nil
+		}
+	}
+	return "", ErrInvalidLocale
+}
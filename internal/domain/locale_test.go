@@ -0,0 +1,39 @@
+package domain_test
+
+import (
+	"testing"
+
+	"github.com/ArtemSind/food_tinder/internal/domain"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseLocale(t *testing.T) {
+	tests := []struct {
+		name    string
+		input   string
+		want    domain.Locale
+		wantErr bool
+	}{
+		{name: "empty is no locale", input: "", want: ""},
+		{name: "english", input: "en", want: domain.LocaleEnglish},
+		{name: "regional variant", input: "de-CH", want: domain.LocaleGerman},
+		{name: "underscore and case", input: " DE_at ", want: domain.LocaleGerman},
+		{name: "unsupported", input: "fr", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Act
+			locale, err := domain.ParseLocale(tt.input)
+
+			// Assert
+			if tt.wantErr {
+				assert.ErrorIs(t, err, domain.ErrInvalidLocale)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, locale)
+		})
+	}
+}
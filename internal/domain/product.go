@@ -0,0 +1,105 @@
+package domain
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"sort"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+var (
+	ErrProductNotFound = errors.New("product not found")
+)
+
+// ProductEventType is how a product changed between two catalog refreshes
+type ProductEventType string
+
+const (
+	ProductAdded   ProductEventType = "added"
+	ProductRemoved ProductEventType = "removed"
+	ProductChanged ProductEventType = "changed"
+)
+
+// CatalogProduct is a product as it was last seen in the catalog
+type CatalogProduct struct {
+	ID          uuid.UUID
+	ContentHash string
+	// Data is the product as received from Foodji
+	Data        json.RawMessage
+	FirstSeenAt time.Time
+	LastSeenAt  time.Time
+	// DiscontinuedAt is set once the product is no longer in the catalog
+	DiscontinuedAt *time.Time
+	// Price is the price of the product when it is recorded; nil if the source has none
+	Price *float64
+}
+
+// NewCatalogProduct creates a catalog product whose content hash is taken over its content,
+// the fields that make up the product, while data is the product as it is stored
+func NewCatalogProduct(id uuid.UUID, content, data json.RawMessage) *CatalogProduct {
+	sum := sha256.Sum256(content)
+	return &CatalogProduct{
+		ID:          id,
+		ContentHash: hex.EncodeToString(sum[:]),
+		Data:        data,
+	}
+}
+
+// Discontinued reports whether the product has left the catalog
+func (p *CatalogProduct) Discontinued() bool {
+	return p.DiscontinuedAt != nil
+}
+
+// ProductEvent records a product being added to, removed from or changed in the catalog
+type ProductEvent struct {
+	ID          uuid.UUID
+	ProductID   uuid.UUID
+	Type        ProductEventType
+	ContentHash string
+	CreatedAt   time.Time
+}
+
+func newProductEvent(productID uuid.UUID, eventType ProductEventType, contentHash string, at time.Time) *ProductEvent {
+	return &ProductEvent{
+		ID:          uuid.New(),
+		ProductID:   productID,
+		Type:        eventType,
+		ContentHash: contentHash,
+		CreatedAt:   at,
+	}
+}
+
+// DiffCatalog compares the content hashes of the products that were in the catalog with the
+// products in it now. Added and changed products come first in catalog order, followed by
+// the removed ones ordered by ID.
+func DiffCatalog(previous map[uuid.UUID]string, current []*CatalogProduct, at time.Time) []*ProductEvent {
+	var events []*ProductEvent
+	seen := make(map[uuid.UUID]bool, len(current))
+	for _, product := range current {
+		seen[product.ID] = true
+		hash, known := previous[product.ID]
+		switch {
+		case !known:
+			events = append(events, newProductEvent(product.ID, ProductAdded, product.ContentHash, at))
+		case hash != product.ContentHash:
+			events = append(events, newProductEvent(product.ID, ProductChanged, product.ContentHash, at))
+		}
+	}
+
+	var removed []uuid.UUID
+	for id := range previous {
+		if !seen[id] {
+			removed = append(removed, id)
+		}
+	}
+	sort.Slice(removed, func(i, j int) bool { return removed[i].String() < removed[j].String() })
+	for _, id := range removed {
+		events = append(events, newProductEvent(id, ProductRemoved, previous[id], at))
+	}
+
+	return events
+}
@@ -0,0 +1,39 @@
+package domain
+
+import (
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+var (
+	ErrSelfAlias      = errors.New("a product cannot be an alias of itself")
+	ErrAliasCycle     = errors.New("the product is already an alias of the aliased product")
+	ErrAlreadyAliased = errors.New("the product is already an alias of another product")
+)
+
+// ProductAlias records that Foodji re-issued a product under a new ID. Votes and impressions
+// of the alias were moved to the product, and later ones are recorded on the product too.
+type ProductAlias struct {
+	AliasID   uuid.UUID `json:"alias_id"`
+	ProductID uuid.UUID `json:"product_id"`
+	// MovedVotes counts votes moved from the alias to the product
+	MovedVotes int `json:"moved_votes"`
+	// MergedVotes counts sessions that had rated both; their latest vote was kept on the product
+	MergedVotes      int       `json:"merged_votes"`
+	MovedImpressions int       `json:"moved_impressions"`
+	CreatedAt        time.Time `json:"created_at"`
+}
+
+// NewProductAlias declares aliasID an alias of productID
+func NewProductAlias(aliasID, productID uuid.UUID) (*ProductAlias, error) {
+	if aliasID == productID {
+		return nil, ErrSelfAlias
+	}
+	return &ProductAlias{
+		AliasID:   aliasID,
+		ProductID: productID,
+		CreatedAt: time.Now(),
+	}, nil
+}
@@ -0,0 +1,102 @@
+package domain
+
+import (
+	"errors"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+var (
+	ErrOverrideNotFound     = errors.New("product override not found")
+	ErrEmptyOverride        = errors.New("override must hide, pin or change the product")
+	ErrHiddenPinnedOverride = errors.New("a product cannot be both hidden and pinned")
+	ErrInvalidOverrideField = errors.New("overridden name and category must not be blank")
+	ErrInvalidOverrideImage = errors.New("overridden image must be an absolute http or https URL")
+)
+
+// ProductOverride is an admin correction merged over the catalog data of a product.
+// Nil fields keep the catalog value.
+type ProductOverride struct {
+	ProductID uuid.UUID `json:"product_id"`
+	// Hidden keeps the product out of the deck, recommendations and search
+	Hidden bool `json:"hidden"`
+	// Pinned puts the product at the front of the deck
+	Pinned    bool      `json:"pinned"`
+	Name      *string   `json:"name,omitempty"`
+	Category  *string   `json:"category,omitempty"`
+	ImageURL  *string   `json:"image_url,omitempty"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// NewProductOverride validates and creates an override for a product
+func NewProductOverride(productID uuid.UUID, hidden, pinned bool, name, category, imageURL *string) (*ProductOverride, error) {
+	if hidden && pinned {
+		return nil, ErrHiddenPinnedOverride
+	}
+	if !hidden && !pinned && name == nil && category == nil && imageURL == nil {
+		return nil, ErrEmptyOverride
+	}
+
+	name, category = trimmed(name), trimmed(category)
+	if (name != nil && *name == "") || (category != nil && *category == "") {
+		return nil, ErrInvalidOverrideField
+	}
+	if imageURL != nil {
+		image, err := url.Parse(*imageURL)
+		if err != nil || !image.IsAbs() || (image.Scheme != "http" && image.Scheme != "https") || image.Host == "" {
+			return nil, ErrInvalidOverrideImage
+		}
+	}
+
+	now := time.Now()
+	return &ProductOverride{
+		ProductID: productID,
+		Hidden:    hidden,
+		Pinned:    pinned,
+		Name:      name,
+		Category:  category,
+		ImageURL:  imageURL,
+		CreatedAt: now,
+		UpdatedAt: now,
+	}, nil
+}
+
+func trimmed(value *string) *string {
+	if value == nil {
+		return nil
+	}
+	result := strings.TrimSpace(*value)
+	return &result
+}
+
+// PinFirst moves the pinned products in ordered to the front, in the order of pinned,
+// and keeps the order of the other products
+func PinFirst(ordered []uuid.UUID, pinned []uuid.UUID) []uuid.UUID {
+	if len(pinned) == 0 {
+		return ordered
+	}
+
+	present := make(map[uuid.UUID]bool, len(ordered))
+	for _, id := range ordered {
+		present[id] = true
+	}
+
+	result := make([]uuid.UUID, 0, len(ordered))
+	isPinned := make(map[uuid.UUID]bool, len(pinned))
+	for _, id := range pinned {
+		if present[id] && !isPinned[id] {
+			isPinned[id] = true
+			result = append(result, id)
+		}
+	}
+	for _, id := range ordered {
+		if !isPinned[id] {
+			result = append(result, id)
+		}
+	}
+	return result
+}
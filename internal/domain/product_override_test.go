@@ -0,0 +1,63 @@
+package domain_test
+
+import (
+	"testing"
+
+	"github.com/ArtemSind/food_tinder/internal/domain"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewProductOverride(t *testing.T) {
+	name, blank := "Greek Salad", "  "
+	image, relative := "https://cdn.example.com/salad.png", "/salad.png"
+
+	tests := []struct {
+		name     string
+		hidden   bool
+		pinned   bool
+		field    *string
+		imageURL *string
+		err      error
+	}{
+		{name: "hidden", hidden: true},
+		{name: "pinned with a new name", pinned: true, field: &name},
+		{name: "image", imageURL: &image},
+		{name: "nothing to override", err: domain.ErrEmptyOverride},
+		{name: "hidden and pinned", hidden: true, pinned: true, err: domain.ErrHiddenPinnedOverride},
+		{name: "blank name", field: &blank, err: domain.ErrInvalidOverrideField},
+		{name: "relative image", imageURL: &relative, err: domain.ErrInvalidOverrideImage},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Act
+			override, err := domain.NewProductOverride(uuid.New(), tt.hidden, tt.pinned, tt.field, nil, tt.imageURL)
+
+			// Assert
+			if tt.err != nil {
+				assert.ErrorIs(t, err, tt.err)
+				assert.Nil(t, override)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.hidden, override.Hidden)
+			assert.Equal(t, tt.pinned, override.Pinned)
+			assert.False(t, override.CreatedAt.IsZero())
+		})
+	}
+}
+
+func TestPinFirst(t *testing.T) {
+	// Arrange
+	a, b, c, d := uuid.New(), uuid.New(), uuid.New(), uuid.New()
+	missing := uuid.New()
+
+	// Act
+	ordered := domain.PinFirst([]uuid.UUID{a, b, c, d}, []uuid.UUID{d, missing, b})
+
+	// Assert
+	assert.Equal(t, []uuid.UUID{d, b, a, c}, ordered)
+	assert.Equal(t, []uuid.UUID{a, b}, domain.PinFirst([]uuid.UUID{a, b}, nil))
+}
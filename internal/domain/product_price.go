@@ -0,0 +1,87 @@
+package domain
+
+import (
+	"errors"
+	"sort"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// PricePoint is a price of a product, from the refresh that first saw it to the last refresh
+// before the price changed, together with the votes cast or changed at that price
+type PricePoint struct {
+	ProductID uuid.UUID
+	Price     float64
+	// Version is the content hash of the product when the price was first seen
+	Version    string
+	RecordedAt time.Time
+	// LastRecordedAt is the last refresh that saw the price
+	LastRecordedAt time.Time
+	AvgScore       float64
+	VoteCount      int
+}
+
+// ScoreRanking decides the order of aggregated product scores
+type ScoreRanking string
+
+const (
+	// RankByScore shows the best rated products first
+	RankByScore ScoreRanking = "score"
+	// RankByValue shows the products with the most score per unit of price first
+	RankByValue ScoreRanking = "value"
+
+	// DefaultScoreRanking is used when no ranking is requested
+	DefaultScoreRanking = RankByScore
+
+	// ValuePriorVotes is how many votes of ValuePriorScore every product starts with when ranked
+	// by value, so a few votes cannot make a cheap product the best value
+	ValuePriorVotes = 5
+	// ValuePriorScore is the middle of the score range, what a product is assumed to score until voted on
+	ValuePriorScore = 3.0
+)
+
+var ErrInvalidScoreRanking = errors.New("unknown score ranking")
+
+// ParseScoreRanking validates a ranking name; an empty name is the default ranking
+func ParseScoreRanking(name string) (ScoreRanking, error) {
+	switch ScoreRanking(name) {
+	case "":
+		return DefaultScoreRanking, nil
+	case RankByScore, RankByValue:
+		return ScoreRanking(name), nil
+	}
+	return "", ErrInvalidScoreRanking
+}
+
+// Value relates the score of a product to its current price as score points per unit of price.
+// The score is a Bayesian average that weighs the product's votes against ValuePriorVotes votes
+// of ValuePriorScore, so it only approaches the average score as votes add up. Products without
+// votes or a known price have no value.
+func (s *ProductScore) Value() (float64, bool) {
+	if s.VoteCount == 0 || s.Price == nil || *s.Price <= 0 {
+		return 0, false
+	}
+	score := (s.AvgScore*float64(s.VoteCount) + ValuePriorScore*ValuePriorVotes) / float64(s.VoteCount+ValuePriorVotes)
+	return score / *s.Price, true
+}
+
+// OrderByValue orders scores by value, best first. Products with more votes win ties, and
+// products without a value come last in their original order.
+func OrderByValue(scores []*ProductScore) []*ProductScore {
+	ordered := make([]*ProductScore, len(scores))
+	copy(ordered, scores)
+
+	sort.SliceStable(ordered, func(i, j int) bool {
+		a, aOK := ordered[i].Value()
+		b, bOK := ordered[j].Value()
+		if aOK != bOK {
+			return aOK
+		}
+		if !aOK || a == b {
+			return aOK && ordered[i].VoteCount > ordered[j].VoteCount
+		}
+		return a > b
+	})
+	return ordered
+}
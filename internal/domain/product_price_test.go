@@ -0,0 +1,90 @@
+package domain_test
+
+import (
+	"testing"
+
+	"github.com/ArtemSind/food_tinder/internal/domain"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseScoreRanking(t *testing.T) {
+	tests := []struct {
+		name    string
+		input   string
+		want    domain.ScoreRanking
+		wantErr bool
+	}{
+		{name: "default", input: "", want: domain.RankByScore},
+		{name: "score", input: "score", want: domain.RankByScore},
+		{name: "value", input: "value", want: domain.RankByValue},
+		{name: "unknown", input: "price", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Act
+			ranking, err := domain.ParseScoreRanking(tt.input)
+
+			// Assert
+			if tt.wantErr {
+				assert.ErrorIs(t, err, domain.ErrInvalidScoreRanking)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, ranking)
+		})
+	}
+}
+
+func TestProductScore_Value(t *testing.T) {
+	// Arrange
+	price, free := 4.0, 0.0
+	score := &domain.ProductScore{AvgScore: 5, VoteCount: 5, Price: &price}
+
+	// Act
+	value, ok := score.Value()
+
+	// Assert
+	assert.True(t, ok)
+	assert.InDelta(t, 1.0, value, 1e-9, "five votes of 5 weigh as much as the prior votes of 3")
+
+	_, ok = (&domain.ProductScore{AvgScore: 3, VoteCount: 2}).Value()
+	assert.False(t, ok, "a product without a price has no value")
+	_, ok = (&domain.ProductScore{AvgScore: 3, VoteCount: 2, Price: &free}).Value()
+	assert.False(t, ok, "a free product has no value")
+	_, ok = (&domain.ProductScore{Price: &price}).Value()
+	assert.False(t, ok, "a product without votes has no value")
+}
+
+func TestOrderByValue(t *testing.T) {
+	// Arrange
+	cheap, pricey := 2.0, 8.0
+	bargain := &domain.ProductScore{ProductID: uuid.New(), AvgScore: 3, VoteCount: 1, Price: &cheap}
+	popular := &domain.ProductScore{ProductID: uuid.New(), AvgScore: 3, VoteCount: 5, Price: &cheap}
+	premium := &domain.ProductScore{ProductID: uuid.New(), AvgScore: 5, VoteCount: 9, Price: &pricey}
+	unrated := &domain.ProductScore{ProductID: uuid.New(), Price: &cheap}
+	unpriced := &domain.ProductScore{ProductID: uuid.New(), AvgScore: 4, VoteCount: 3}
+	scores := []*domain.ProductScore{premium, unrated, bargain, unpriced, popular}
+
+	// Act
+	ordered := domain.OrderByValue(scores)
+
+	// Assert
+	assert.Equal(t, []*domain.ProductScore{popular, bargain, premium, unrated, unpriced}, ordered)
+	assert.Equal(t, premium, scores[0], "the input is not reordered")
+}
+
+func TestOrderByValue_FewVotes(t *testing.T) {
+	// Arrange
+	cheap, fair := 2.0, 2.5
+	lucky := &domain.ProductScore{ProductID: uuid.New(), AvgScore: 5, VoteCount: 1, Price: &cheap}
+	proven := &domain.ProductScore{ProductID: uuid.New(), AvgScore: 4.8, VoteCount: 40, Price: &fair}
+
+	// Act
+	ordered := domain.OrderByValue([]*domain.ProductScore{lucky, proven})
+
+	// Assert
+	assert.Equal(t, []*domain.ProductScore{proven, lucky}, ordered, "a single top vote does not outrank many good ones")
+}
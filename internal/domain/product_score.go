@@ -9,4 +9,71 @@ type ProductScore struct {
 	ProductID uuid.UUID `json:"product_id"`
 	AvgScore  float64   `json:"avg_score"`
 	VoteCount int       `json:"vote_count"`
+	// Views is the number of distinct sessions the product was shown to
+	Views int `json:"views"`
+	// Skips is the number of distinct sessions that explicitly skipped the product
+	Skips int `json:"skips"`
+	// Product is the product as last seen in the catalog; nil if it was never recorded
+	Product *CatalogProduct `json:"-"`
+	// Versions splits the votes by the product version they were cast on, oldest first
+	Versions []ProductVersionScore `json:"versions,omitempty"`
+	// Price is the last recorded price of the product; nil if none was recorded
+	Price *float64 `json:"price,omitempty"`
+}
+
+// ProductViews counts how often a product that was never voted on was shown and skipped
+type ProductViews struct {
+	ProductID uuid.UUID `json:"product_id"`
+	// Views is the number of distinct sessions the product was shown to
+	Views int `json:"views"`
+	// Skips is the number of distinct sessions that explicitly skipped the product
+	Skips int `json:"skips"`
+}
+
+// ProductVersionScore aggregates the votes cast on one version of a product
+type ProductVersionScore struct {
+	// Version is the content hash of the product; empty for votes from before products were tracked
+	Version   string  `json:"version"`
+	AvgScore  float64 `json:"avg_score"`
+	VoteCount int     `json:"vote_count"`
+	// Current is set for the version the catalog holds now
+	Current bool `json:"current"`
+}
+
+// CurrentVersion returns the votes on the current product version, or nil if it was not rated yet
+func (s *ProductScore) CurrentVersion() *ProductVersionScore {
+	for i := range s.Versions {
+		if s.Versions[i].Current {
+			return &s.Versions[i]
+		}
+	}
+	return nil
+}
+
+// Discontinued reports whether the product has left the catalog
+func (s *ProductScore) Discontinued() bool {
+	return s.Product != nil && s.Product.Discontinued()
+}
+
+// ConversionRate is the share of sessions that voted on the product after seeing it.
+// Votes from sessions that found the product elsewhere are capped so the rate stays within [0, 1].
+func (s *ProductScore) ConversionRate() float64 {
+	if s.Views == 0 {
+		return 0
+	}
+	if s.VoteCount >= s.Views {
+		return 1
+	}
+	return float64(s.VoteCount) / float64(s.Views)
+}
+
+// SkipRate is the share of sessions that explicitly skipped the product after seeing it
+func (s *ProductScore) SkipRate() float64 {
+	if s.Views == 0 {
+		return 0
+	}
+	if s.Skips >= s.Views {
+		return 1
+	}
+	return float64(s.Skips) / float64(s.Views)
 }
@@ -0,0 +1,123 @@
+package domain
+
+import (
+	"strings"
+	"unicode"
+)
+
+// ProductQuery filters the products of the catalog. Zero fields do not filter.
+type ProductQuery struct {
+	// Text is matched against product names, tolerating typos and partial words
+	Text     string
+	Category string
+	MaxPrice *float64
+	// ExcludeAllergens drops products containing any of these allergens
+	ExcludeAllergens []string
+	MachineID        string
+	// Locale is the language of the returned names and categories; empty is the default locale
+	Locale Locale
+}
+
+// CategoryFacet counts the products of a category that match a query, ignoring its category filter
+type CategoryFacet struct {
+	Category string `json:"category"`
+	Count    int    `json:"count"`
+}
+
+// SearchTerms splits text into lower-case words for matching
+func SearchTerms(text string) []string {
+	return strings.FieldsFunc(strings.ToLower(text), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsNumber(r)
+	})
+}
+
+// MatchTerms scores how well the query terms match the name terms, from 0 to 1.
+// Every query term has to match a name term exactly, as a prefix, as a substring or
+// within a few typos, otherwise the name does not match.
+func MatchTerms(query, name []string) (float64, bool) {
+	if len(query) == 0 {
+		return 1, true
+	}
+
+	total := 0.0
+	for _, term := range query {
+		best := 0.0
+		for _, word := range name {
+			if score := matchTerm(term, word); score > best {
+				best = score
+			}
+		}
+		if best == 0 {
+			return 0, false
+		}
+		total += best
+	}
+	return total / float64(len(query)), true
+}
+
+// matchTerm scores a single query term against a word of a name, or returns 0
+func matchTerm(term, word string) float64 {
+	switch {
+	case term == word:
+		return 1
+	case strings.HasPrefix(word, term):
+		return 0.9
+	case strings.Contains(word, term):
+		return 0.7
+	}
+
+	allowed := allowedTypos(term)
+	if allowed == 0 {
+		return 0
+	}
+	distance := editDistance([]rune(term), []rune(word))
+	// A partially typed word is compared against the start of the name word
+	if runes := []rune(word); len(runes) > len([]rune(term)) {
+		if prefix := editDistance([]rune(term), runes[:len([]rune(term))]); prefix < distance {
+			distance = prefix
+		}
+	}
+	if distance > allowed {
+		return 0
+	}
+	return 0.6 - 0.1*float64(distance-1)
+}
+
+// allowedTypos is the edit distance tolerated for a query term; short terms have to match exactly
+func allowedTypos(term string) int {
+	switch length := len([]rune(term)); {
+	case length < 4:
+		return 0
+	case length < 8:
+		return 1
+	default:
+		return 2
+	}
+}
+
+// editDistance is the number of insertions, deletions, substitutions and swaps of adjacent
+// letters that turn a into b (optimal string alignment distance)
+func editDistance(a, b []rune) int {
+	rows := make([][]int, len(a)+1)
+	for i := range rows {
+		rows[i] = make([]int, len(b)+1)
+		rows[i][0] = i
+	}
+	for j := range rows[0] {
+		rows[0][j] = j
+	}
+
+	for i := 1; i <= len(a); i++ {
+		for j := 1; j <= len(b); j++ {
+			cost := 1
+			if a[i-1] == b[j-1] {
+				cost = 0
+			}
+			rows[i][j] = min(rows[i-1][j]+1, rows[i][j-1]+1, rows[i-1][j-1]+cost)
+			if i > 1 && j > 1 && a[i-1] == b[j-2] && a[i-2] == b[j-1] {
+				rows[i][j] = min(rows[i][j], rows[i-2][j-2]+1)
+			}
+		}
+	}
+	return rows[len(a)][len(b)]
+}
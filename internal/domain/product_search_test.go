@@ -0,0 +1,67 @@
+package domain_test
+
+import (
+	"testing"
+
+	"github.com/ArtemSind/food_tinder/internal/domain"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSearchTerms(t *testing.T) {
+	// Act
+	terms := domain.SearchTerms("Chicken Caesar-Salad (large)")
+
+	// Assert
+	assert.Equal(t, []string{"chicken", "caesar", "salad", "large"}, terms)
+}
+
+func TestMatchTerms(t *testing.T) {
+	name := domain.SearchTerms("Chocolate Brownie")
+
+	tests := []struct {
+		name    string
+		query   string
+		matches bool
+	}{
+		{name: "exact word", query: "brownie", matches: true},
+		{name: "prefix", query: "choc", matches: true},
+		{name: "substring", query: "rown", matches: true},
+		{name: "typo", query: "choclate", matches: true},
+		{name: "swapped letters", query: "borwnie", matches: true},
+		{name: "typo in partial word", query: "browm", matches: true},
+		{name: "every term has to match", query: "chocolate cake", matches: false},
+		{name: "short terms have to match exactly", query: "cho", matches: true},
+		{name: "short term with a typo", query: "chx", matches: false},
+		{name: "unrelated", query: "lentil", matches: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Act
+			_, matches := domain.MatchTerms(domain.SearchTerms(tt.query), name)
+
+			// Assert
+			assert.Equal(t, tt.matches, matches)
+		})
+	}
+
+	t.Run("closer matches score higher", func(t *testing.T) {
+		// Act
+		exact, _ := domain.MatchTerms([]string{"brownie"}, name)
+		prefix, _ := domain.MatchTerms([]string{"brown"}, name)
+		typo, _ := domain.MatchTerms([]string{"browny"}, name)
+
+		// Assert
+		assert.Greater(t, exact, prefix)
+		assert.Greater(t, prefix, typo)
+	})
+
+	t.Run("empty query matches everything", func(t *testing.T) {
+		// Act
+		score, matches := domain.MatchTerms(nil, name)
+
+		// Assert
+		assert.True(t, matches)
+		assert.Equal(t, 1.0, score)
+	})
+}
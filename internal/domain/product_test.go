@@ -0,0 +1,73 @@
+package domain_test
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/ArtemSind/food_tinder/internal/domain"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewCatalogProduct(t *testing.T) {
+	// Arrange
+	id := uuid.New()
+
+	data := json.RawMessage(`{"id":"` + id.String() + `","name":"Soup","price":4.5}`)
+
+	// Act
+	product := domain.NewCatalogProduct(id, json.RawMessage(`{"name":"Soup"}`), data)
+	repriced := domain.NewCatalogProduct(id, json.RawMessage(`{"name":"Soup"}`),
+		json.RawMessage(`{"id":"`+id.String()+`","name":"Soup","price":5}`))
+	changed := domain.NewCatalogProduct(id, json.RawMessage(`{"name":"Salad"}`), data)
+
+	// Assert
+	assert.Len(t, product.ContentHash, 64)
+	assert.Equal(t, data, product.Data)
+	assert.Equal(t, product.ContentHash, repriced.ContentHash, "only the content is hashed")
+	assert.NotEqual(t, product.ContentHash, changed.ContentHash)
+	assert.False(t, product.Discontinued())
+}
+
+func TestDiffCatalog(t *testing.T) {
+	// Arrange
+	kept := domain.NewCatalogProduct(uuid.New(), json.RawMessage(`{"name":"Soup"}`), json.RawMessage(`{"name":"Soup"}`))
+	changed := domain.NewCatalogProduct(uuid.New(), json.RawMessage(`{"name":"Salad"}`), json.RawMessage(`{"name":"Salad"}`))
+	added := domain.NewCatalogProduct(uuid.New(), json.RawMessage(`{"name":"Wrap"}`), json.RawMessage(`{"name":"Wrap"}`))
+	removed := uuid.New()
+	at := time.Now()
+
+	previous := map[uuid.UUID]string{
+		kept.ID:    kept.ContentHash,
+		changed.ID: "old-hash",
+		removed:    "removed-hash",
+	}
+
+	// Act
+	events := domain.DiffCatalog(previous, []*domain.CatalogProduct{kept, changed, added}, at)
+
+	// Assert
+	require.Len(t, events, 3)
+	assert.Equal(t, changed.ID, events[0].ProductID)
+	assert.Equal(t, domain.ProductChanged, events[0].Type)
+	assert.Equal(t, changed.ContentHash, events[0].ContentHash)
+	assert.Equal(t, added.ID, events[1].ProductID)
+	assert.Equal(t, domain.ProductAdded, events[1].Type)
+	assert.Equal(t, removed, events[2].ProductID)
+	assert.Equal(t, domain.ProductRemoved, events[2].Type)
+	assert.Equal(t, "removed-hash", events[2].ContentHash)
+	assert.Equal(t, at, events[2].CreatedAt)
+}
+
+func TestDiffCatalog_Unchanged(t *testing.T) {
+	// Arrange
+	product := domain.NewCatalogProduct(uuid.New(), json.RawMessage(`{}`), json.RawMessage(`{}`))
+
+	// Act
+	events := domain.DiffCatalog(map[uuid.UUID]string{product.ID: product.ContentHash}, []*domain.CatalogProduct{product}, time.Now())
+
+	// Assert
+	assert.Empty(t, events)
+}
@@ -0,0 +1,238 @@
+package domain
+
+import (
+	"errors"
+	"math"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+const (
+	// MinCoRatings is the number of sessions that must have rated both products
+	// before their similarity is trusted
+	MinCoRatings = 2
+	// LikedScore is the lowest score that counts as liking a product
+	LikedScore = 4
+)
+
+var ErrModelNotReady = errors.New("recommendation model has not been computed yet")
+
+// SimilarProduct is a neighbour of a product in the item-item similarity model, or a product
+// with alike catalog fields, which has no co-ratings
+type SimilarProduct struct {
+	ProductID  uuid.UUID `json:"product_id"`
+	Similarity float64   `json:"similarity"`
+	CoRatings  int       `json:"co_ratings"`
+}
+
+// SimilarityModel holds item-item similarities computed from the session×product rating matrix
+type SimilarityModel struct {
+	neighbours map[uuid.UUID][]SimilarProduct
+	ComputedAt time.Time
+}
+
+// Recommendation is a product predicted to be liked by a session
+type Recommendation struct {
+	ProductID      uuid.UUID `json:"product_id"`
+	PredictedScore float64   `json:"predicted_score"`
+	// BecauseOf is the liked product contributing most to the prediction
+	BecauseOf uuid.UUID `json:"because_of"`
+	// BecauseOfName is the name of BecauseOf in the requested locale; empty if it left the catalog
+	BecauseOfName string `json:"because_of_name,omitempty"`
+}
+
+// ComputeItemSimilarities builds an item-item model using adjusted cosine similarity:
+// each rating is centred on the mean of its session so that generous and harsh raters compare fairly.
+func ComputeItemSimilarities(votes []*Vote, minCoRatings int) *SimilarityModel {
+	// Centre ratings on each session's mean
+	sessionTotals := make(map[uuid.UUID][2]int)
+	for _, v := range votes {
+		t := sessionTotals[v.SessionID]
+		sessionTotals[v.SessionID] = [2]int{t[0] + v.Score, t[1] + 1}
+	}
+
+	centred := make(map[uuid.UUID]map[uuid.UUID]float64)
+	for _, v := range votes {
+		t := sessionTotals[v.SessionID]
+		if centred[v.SessionID] == nil {
+			centred[v.SessionID] = make(map[uuid.UUID]float64)
+		}
+		centred[v.SessionID][v.ProductID] = float64(v.Score) - float64(t[0])/float64(t[1])
+	}
+
+	type pairKey struct{ a, b uuid.UUID }
+	type pairStats struct {
+		dot, normA, normB float64
+		count             int
+	}
+	pairs := make(map[pairKey]*pairStats)
+
+	for _, ratings := range centred {
+		products := make([]uuid.UUID, 0, len(ratings))
+		for id := range ratings {
+			products = append(products, id)
+		}
+		for i := range products {
+			for j := i + 1; j < len(products); j++ {
+				a, b := products[i], products[j]
+				if a.String() > b.String() {
+					a, b = b, a
+				}
+				key := pairKey{a, b}
+				stats := pairs[key]
+				if stats == nil {
+					stats = &pairStats{}
+					pairs[key] = stats
+				}
+				ra, rb := ratings[a], ratings[b]
+				stats.dot += ra * rb
+				stats.normA += ra * ra
+				stats.normB += rb * rb
+				stats.count++
+			}
+		}
+	}
+
+	model := &SimilarityModel{
+		neighbours: make(map[uuid.UUID][]SimilarProduct),
+		ComputedAt: time.Now(),
+	}
+	for key, stats := range pairs {
+		if stats.count < minCoRatings || stats.normA == 0 || stats.normB == 0 {
+			continue
+		}
+		similarity := stats.dot / (math.Sqrt(stats.normA) * math.Sqrt(stats.normB))
+		model.neighbours[key.a] = append(model.neighbours[key.a], SimilarProduct{ProductID: key.b, Similarity: similarity, CoRatings: stats.count})
+		model.neighbours[key.b] = append(model.neighbours[key.b], SimilarProduct{ProductID: key.a, Similarity: similarity, CoRatings: stats.count})
+	}
+
+	for id := range model.neighbours {
+		sortSimilarProducts(model.neighbours[id])
+	}
+
+	return model
+}
+
+// Neighbours returns the products most similar to the given one, best first
+func (m *SimilarityModel) Neighbours(productID uuid.UUID) []SimilarProduct {
+	return m.neighbours[productID]
+}
+
+// Products returns every product that has at least one neighbour
+func (m *SimilarityModel) Products() []uuid.UUID {
+	products := make([]uuid.UUID, 0, len(m.neighbours))
+	for id := range m.neighbours {
+		products = append(products, id)
+	}
+	return products
+}
+
+// Recommend predicts scores for products the session has not rated, using the weighted
+// average of the session's ratings of positively similar products
+func (m *SimilarityModel) Recommend(sessionVotes []*Vote, candidates []uuid.UUID, limit int) []*Recommendation {
+	rated := make(map[uuid.UUID]int, len(sessionVotes))
+	for _, v := range sessionVotes {
+		rated[v.ProductID] = v.Score
+	}
+
+	var recommendations []*Recommendation
+	for _, candidate := range candidates {
+		if _, ok := rated[candidate]; ok {
+			continue
+		}
+
+		var weighted, weights, bestContribution float64
+		var becauseOf uuid.UUID
+		for _, neighbour := range m.neighbours[candidate] {
+			score, ok := rated[neighbour.ProductID]
+			if !ok || neighbour.Similarity <= 0 {
+				continue
+			}
+			weighted += neighbour.Similarity * float64(score)
+			weights += neighbour.Similarity
+			if score >= LikedScore && neighbour.Similarity*float64(score) > bestContribution {
+				bestContribution = neighbour.Similarity * float64(score)
+				becauseOf = neighbour.ProductID
+			}
+		}
+
+		// Only recommend products that are close to something the session liked
+		if weights == 0 || becauseOf == uuid.Nil {
+			continue
+		}
+
+		recommendations = append(recommendations, &Recommendation{
+			ProductID:      candidate,
+			PredictedScore: weighted / weights,
+			BecauseOf:      becauseOf,
+		})
+	}
+
+	sort.SliceStable(recommendations, func(i, j int) bool {
+		return recommendations[i].PredictedScore > recommendations[j].PredictedScore
+	})
+	if limit > 0 && len(recommendations) > limit {
+		recommendations = recommendations[:limit]
+	}
+
+	return recommendations
+}
+
+// ProductAttributes are the catalog fields products are compared on when too few sessions rated them
+type ProductAttributes struct {
+	Category  string
+	Allergens []string
+	Diets     []string
+}
+
+// AttributeSimilarity scores how alike two products are by their catalog fields, from 0 for
+// nothing in common to 1 for the same category, diets and allergens. The category weighs most,
+// then the share of diets and of allergens they have in common. Labels ignore case.
+func AttributeSimilarity(a, b ProductAttributes) float64 {
+	similarity := 0.0
+	if a.Category != "" && strings.EqualFold(a.Category, b.Category) {
+		similarity += 0.5
+	}
+	similarity += 0.3 * labelOverlap(a.Diets, b.Diets)
+	similarity += 0.2 * labelOverlap(a.Allergens, b.Allergens)
+	return similarity
+}
+
+// labelOverlap is the Jaccard index of two label sets; 0 if both are empty
+func labelOverlap(a, b []string) float64 {
+	set := make(map[string]bool, len(a))
+	for _, label := range a {
+		set[strings.ToLower(label)] = true
+	}
+	shared := 0
+	union := len(set)
+	seen := make(map[string]bool, len(b))
+	for _, label := range b {
+		label = strings.ToLower(label)
+		if seen[label] {
+			continue
+		}
+		seen[label] = true
+		if set[label] {
+			shared++
+		} else {
+			union++
+		}
+	}
+	if union == 0 {
+		return 0
+	}
+	return float64(shared) / float64(union)
+}
+
+func sortSimilarProducts(products []SimilarProduct) {
+	sort.Slice(products, func(i, j int) bool {
+		if products[i].Similarity != products[j].Similarity {
+			return products[i].Similarity > products[j].Similarity
+		}
+		return products[i].CoRatings > products[j].CoRatings
+	})
+}
@@ -0,0 +1,114 @@
+package domain_test
+
+import (
+	"testing"
+
+	"github.com/ArtemSind/food_tinder/internal/domain"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func mustVote(t *testing.T, sessionID, productID uuid.UUID, score int) *domain.Vote {
+	t.Helper()
+	vote, err := domain.NewVote(sessionID, productID, score)
+	require.NoError(t, err)
+	return vote
+}
+
+func TestComputeItemSimilarities(t *testing.T) {
+	// Arrange
+	pizza, pasta, salad := uuid.New(), uuid.New(), uuid.New()
+	s1, s2, s3 := uuid.New(), uuid.New(), uuid.New()
+
+	// Pizza and pasta are liked by the same people, salad by the others
+	votes := []*domain.Vote{
+		mustVote(t, s1, pizza, 5), mustVote(t, s1, pasta, 5), mustVote(t, s1, salad, 1),
+		mustVote(t, s2, pizza, 4), mustVote(t, s2, pasta, 5), mustVote(t, s2, salad, 2),
+		mustVote(t, s3, pizza, 1), mustVote(t, s3, pasta, 2), mustVote(t, s3, salad, 5),
+	}
+
+	// Act
+	model := domain.ComputeItemSimilarities(votes, domain.MinCoRatings)
+
+	// Assert
+	neighbours := model.Neighbours(pizza)
+	require.Len(t, neighbours, 2)
+	assert.Equal(t, pasta, neighbours[0].ProductID)
+	assert.Greater(t, neighbours[0].Similarity, 0.5)
+	assert.Equal(t, 3, neighbours[0].CoRatings)
+	assert.Equal(t, salad, neighbours[1].ProductID)
+	assert.Less(t, neighbours[1].Similarity, 0.0)
+}
+
+func TestComputeItemSimilarities_MinCoRatings(t *testing.T) {
+	// Arrange
+	pizza, pasta := uuid.New(), uuid.New()
+	session := uuid.New()
+	votes := []*domain.Vote{mustVote(t, session, pizza, 5), mustVote(t, session, pasta, 1)}
+
+	// Act
+	model := domain.ComputeItemSimilarities(votes, domain.MinCoRatings)
+
+	// Assert
+	assert.Empty(t, model.Neighbours(pizza))
+}
+
+func TestSimilarityModel_Recommend(t *testing.T) {
+	// Arrange
+	pizza, pasta, salad := uuid.New(), uuid.New(), uuid.New()
+	s1, s2, s3 := uuid.New(), uuid.New(), uuid.New()
+	model := domain.ComputeItemSimilarities([]*domain.Vote{
+		mustVote(t, s1, pizza, 5), mustVote(t, s1, pasta, 5), mustVote(t, s1, salad, 1),
+		mustVote(t, s2, pizza, 4), mustVote(t, s2, pasta, 5), mustVote(t, s2, salad, 2),
+		mustVote(t, s3, pizza, 1), mustVote(t, s3, pasta, 2), mustVote(t, s3, salad, 5),
+	}, domain.MinCoRatings)
+
+	newSession := uuid.New()
+	sessionVotes := []*domain.Vote{mustVote(t, newSession, pizza, 5)}
+
+	t.Run("recommends products similar to liked ones", func(t *testing.T) {
+		// Act
+		recommendations := model.Recommend(sessionVotes, []uuid.UUID{pizza, pasta, salad}, 10)
+
+		// Assert
+		require.Len(t, recommendations, 1)
+		assert.Equal(t, pasta, recommendations[0].ProductID)
+		assert.Equal(t, pizza, recommendations[0].BecauseOf)
+		assert.InDelta(t, 5.0, recommendations[0].PredictedScore, 0.001)
+	})
+
+	t.Run("skips candidates outside the catalog", func(t *testing.T) {
+		// Act
+		recommendations := model.Recommend(sessionVotes, []uuid.UUID{salad}, 10)
+
+		// Assert
+		assert.Empty(t, recommendations)
+	})
+}
+
+func TestAttributeSimilarity(t *testing.T) {
+	salad := domain.ProductAttributes{Category: "Salads", Allergens: []string{"milk"}, Diets: []string{"vegetarian"}}
+
+	tests := []struct {
+		name  string
+		other domain.ProductAttributes
+		want  float64
+	}{
+		{name: "same fields ignoring case", other: domain.ProductAttributes{Category: "salads", Allergens: []string{"Milk"}, Diets: []string{"Vegetarian"}}, want: 1},
+		{name: "same category only", other: domain.ProductAttributes{Category: "Salads"}, want: 0.5},
+		{name: "shared diet", other: domain.ProductAttributes{Category: "Soups", Diets: []string{"vegetarian", "vegan"}}, want: 0.15},
+		{name: "nothing in common", other: domain.ProductAttributes{Category: "Desserts", Allergens: []string{"nuts"}}, want: 0},
+		{name: "no category is no match", other: domain.ProductAttributes{}, want: 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Act
+			similarity := domain.AttributeSimilarity(salad, tt.other)
+
+			// Assert
+			assert.InDelta(t, tt.want, similarity, 1e-9)
+		})
+	}
+}
@@ -1,19 +1,30 @@
 package domain
 
 import (
+	"errors"
 	"time"
 
 	"github.com/google/uuid"
 )
 
+var ErrSessionNotFound = errors.New("session not found")
+
 type Session struct {
-	ID        uuid.UUID `json:"id"`
+	ID           uuid.UUID    `json:"id"`
+	DeckStrategy DeckStrategy `json:"deck_strategy"`
+	// Experiment is set when the session was assigned to an experiment variant on creation
+	Experiment *ExperimentAssignment `json:"experiment,omitempty"`
+	// Dietary rules products out of the session's deck and recommendations
+	Dietary DietaryPreferences `json:"dietary_preferences"`
+	// Locale is the language the session chose for product texts and messages; empty follows the request
+	Locale    Locale    `json:"locale,omitempty"`
 	CreatedAt time.Time `json:"created_at"`
 }
 
 func NewSession() *Session {
 	return &Session{
-		ID:        uuid.New(),
-		CreatedAt: time.Now(),
+		ID:           uuid.New(),
+		DeckStrategy: DefaultDeckStrategy,
+		CreatedAt:    time.Now(),
 	}
 }
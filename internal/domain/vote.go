@@ -17,6 +17,12 @@ type Vote struct {
 	ProductID uuid.UUID `json:"product_id"`
 	Score     int       `json:"score"`
 	CreatedAt time.Time `json:"created_at"`
+	// UpdatedAt is when the score last changed
+	UpdatedAt time.Time `json:"updated_at"`
+	// ProductVersion is the content hash of the product when it was rated; empty if unknown
+	ProductVersion string `json:"product_version,omitempty"`
+	// Outdated is set when the product changed since it was rated, so the session is asked again
+	Outdated bool `json:"outdated,omitempty"`
 }
 
 func NewVote(sessionID, productID uuid.UUID, score int) (*Vote, error) {
@@ -24,11 +30,13 @@ func NewVote(sessionID, productID uuid.UUID, score int) (*Vote, error) {
 		return nil, ErrInvalidScore
 	}
 
+	now := time.Now()
 	return &Vote{
 		ID:        uuid.New(),
 		SessionID: sessionID,
 		ProductID: productID,
 		Score:     score,
-		CreatedAt: time.Now(),
+		CreatedAt: now,
+		UpdatedAt: now,
 	}, nil
 }
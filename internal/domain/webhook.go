@@ -0,0 +1,224 @@
+package domain
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"net/url"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Event types published through the outbox
+const (
+	EventVoteCreated    = "vote.created"
+	EventVoteUpdated    = "vote.updated"
+	EventVoteDeleted    = "vote.deleted"
+	EventCatalogUpdated = "catalog.updated"
+)
+
+// KnownEventTypes lists every event type a webhook can subscribe to
+var KnownEventTypes = []string{
+	EventVoteCreated,
+	EventVoteUpdated,
+	EventVoteDeleted,
+	EventCatalogUpdated,
+}
+
+const (
+	// WebhookMaxAttempts is the number of delivery attempts before a delivery is dead-lettered
+	WebhookMaxAttempts = 8
+	// WebhookBaseBackoff is the delay before the first retry
+	WebhookBaseBackoff = 30 * time.Second
+	// WebhookMaxBackoff caps the delay between two retries
+	WebhookMaxBackoff = 6 * time.Hour
+)
+
+var (
+	ErrInvalidWebhookURL = errors.New("webhook URL must be an absolute http or https URL")
+	ErrInvalidEventType  = errors.New("unknown event type")
+	ErrNoEventTypes      = errors.New("at least one event type is required")
+	ErrWebhookNotFound   = errors.New("webhook not found")
+	ErrDeliveryNotFound  = errors.New("webhook delivery not found")
+)
+
+// WebhookSubscription is an outgoing webhook registered by an internal tool
+type WebhookSubscription struct {
+	ID         uuid.UUID `json:"id"`
+	URL        string    `json:"url"`
+	Secret     string    `json:"-"`
+	EventTypes []string  `json:"event_types"`
+	Active     bool      `json:"active"`
+	CreatedAt  time.Time `json:"created_at"`
+}
+
+// NewWebhookSubscription validates the target and event types and creates a subscription.
+// A random secret is generated when none is given.
+func NewWebhookSubscription(rawURL, secret string, eventTypes []string) (*WebhookSubscription, error) {
+	target, err := url.Parse(rawURL)
+	if err != nil || !target.IsAbs() || (target.Scheme != "http" && target.Scheme != "https") || target.Host == "" {
+		return nil, ErrInvalidWebhookURL
+	}
+
+	if len(eventTypes) == 0 {
+		return nil, ErrNoEventTypes
+	}
+	for _, eventType := range eventTypes {
+		if !isKnownEventType(eventType) {
+			return nil, ErrInvalidEventType
+		}
+	}
+
+	if secret == "" {
+		secret, err = generateSecret()
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return &WebhookSubscription{
+		ID:         uuid.New(),
+		URL:        target.String(),
+		Secret:     secret,
+		EventTypes: eventTypes,
+		Active:     true,
+		CreatedAt:  time.Now(),
+	}, nil
+}
+
+// Subscribes reports whether the subscription wants events of the given type
+func (s *WebhookSubscription) Subscribes(eventType string) bool {
+	if !s.Active {
+		return false
+	}
+	for _, t := range s.EventTypes {
+		if t == eventType {
+			return true
+		}
+	}
+	return false
+}
+
+// OutboxEvent is an event recorded alongside the write that caused it
+type OutboxEvent struct {
+	ID        uuid.UUID       `json:"id"`
+	EventType string          `json:"event_type"`
+	Payload   json.RawMessage `json:"payload"`
+	CreatedAt time.Time       `json:"created_at"`
+}
+
+// NewOutboxEvent creates an outbox event with the payload encoded as JSON
+func NewOutboxEvent(eventType string, payload any) (*OutboxEvent, error) {
+	data, err := json.Marshal(payload)
+	if err != nil {
+		return nil, err
+	}
+	return &OutboxEvent{
+		ID:        uuid.New(),
+		EventType: eventType,
+		Payload:   data,
+		CreatedAt: time.Now(),
+	}, nil
+}
+
+// CatalogUpdatedPayload is the payload of a catalog.updated event
+type CatalogUpdatedPayload struct {
+	ProductCount int       `json:"product_count"`
+	UpdatedAt    time.Time `json:"updated_at"`
+}
+
+// DeliveryStatus is the state of a single webhook delivery
+type DeliveryStatus string
+
+const (
+	DeliveryPending   DeliveryStatus = "pending"
+	DeliveryDelivered DeliveryStatus = "delivered"
+	DeliveryDead      DeliveryStatus = "dead"
+)
+
+// WebhookDelivery is one event to be sent to one subscription
+type WebhookDelivery struct {
+	ID             uuid.UUID       `json:"id"`
+	SubscriptionID uuid.UUID       `json:"subscription_id"`
+	EventID        uuid.UUID       `json:"event_id"`
+	EventType      string          `json:"event_type"`
+	Payload        json.RawMessage `json:"payload"`
+	Status         DeliveryStatus  `json:"status"`
+	Attempts       int             `json:"attempts"`
+	NextAttemptAt  time.Time       `json:"next_attempt_at"`
+	LastError      string          `json:"last_error,omitempty"`
+	CreatedAt      time.Time       `json:"created_at"`
+
+	// URL and Secret are copied from the subscription when the delivery is claimed
+	URL    string `json:"-"`
+	Secret string `json:"-"`
+}
+
+// NewWebhookDelivery creates a pending delivery of the event to the subscription
+func NewWebhookDelivery(subscription *WebhookSubscription, event *OutboxEvent) *WebhookDelivery {
+	now := time.Now()
+	return &WebhookDelivery{
+		ID:             uuid.New(),
+		SubscriptionID: subscription.ID,
+		EventID:        event.ID,
+		EventType:      event.EventType,
+		Payload:        event.Payload,
+		Status:         DeliveryPending,
+		NextAttemptAt:  now,
+		CreatedAt:      now,
+	}
+}
+
+// MarkDelivered records a successful attempt
+func (d *WebhookDelivery) MarkDelivered() {
+	d.Attempts++
+	d.Status = DeliveryDelivered
+	d.LastError = ""
+}
+
+// MarkFailed records a failed attempt and schedules the next one,
+// moving the delivery to the dead-letter list once attempts are exhausted
+func (d *WebhookDelivery) MarkFailed(err error, now time.Time) {
+	d.Attempts++
+	d.LastError = err.Error()
+	if d.Attempts >= WebhookMaxAttempts {
+		d.Status = DeliveryDead
+		return
+	}
+	d.Status = DeliveryPending
+	d.NextAttemptAt = now.Add(WebhookBackoff(d.Attempts))
+}
+
+// WebhookBackoff returns the delay before the retry following the given attempt
+func WebhookBackoff(attempt int) time.Duration {
+	if attempt < 1 {
+		attempt = 1
+	}
+	backoff := WebhookBaseBackoff
+	for i := 1; i < attempt; i++ {
+		backoff *= 2
+		if backoff >= WebhookMaxBackoff {
+			return WebhookMaxBackoff
+		}
+	}
+	return backoff
+}
+
+func isKnownEventType(eventType string) bool {
+	for _, known := range KnownEventTypes {
+		if known == eventType {
+			return true
+		}
+	}
+	return false
+}
+
+func generateSecret() (string, error) {
+	buf := make([]byte, 32)
+	if _, err := rand.Read(buf); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(buf), nil
+}
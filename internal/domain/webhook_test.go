@@ -0,0 +1,105 @@
+package domain_test
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/ArtemSind/food_tinder/internal/domain"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewWebhookSubscription(t *testing.T) {
+	t.Run("valid subscription", func(t *testing.T) {
+		// Act
+		subscription, err := domain.NewWebhookSubscription("https://example.com/hook", "s3cret", []string{domain.EventVoteCreated})
+
+		// Assert
+		require.NoError(t, err)
+		assert.Equal(t, "https://example.com/hook", subscription.URL)
+		assert.Equal(t, "s3cret", subscription.Secret)
+		assert.True(t, subscription.Active)
+		assert.True(t, subscription.Subscribes(domain.EventVoteCreated))
+		assert.False(t, subscription.Subscribes(domain.EventCatalogUpdated))
+	})
+
+	t.Run("generates secret when empty", func(t *testing.T) {
+		// Act
+		subscription, err := domain.NewWebhookSubscription("http://localhost:9000", "", []string{domain.EventCatalogUpdated})
+
+		// Assert
+		require.NoError(t, err)
+		assert.Len(t, subscription.Secret, 64)
+	})
+
+	t.Run("invalid URL", func(t *testing.T) {
+		// Act
+		subscription, err := domain.NewWebhookSubscription("ftp://example.com", "", []string{domain.EventVoteCreated})
+
+		// Assert
+		assert.Equal(t, domain.ErrInvalidWebhookURL, err)
+		assert.Nil(t, subscription)
+	})
+
+	t.Run("unknown event type", func(t *testing.T) {
+		// Act
+		subscription, err := domain.NewWebhookSubscription("https://example.com", "", []string{"vote.archived"})
+
+		// Assert
+		assert.Equal(t, domain.ErrInvalidEventType, err)
+		assert.Nil(t, subscription)
+	})
+
+	t.Run("no event types", func(t *testing.T) {
+		// Act
+		subscription, err := domain.NewWebhookSubscription("https://example.com", "", nil)
+
+		// Assert
+		assert.Equal(t, domain.ErrNoEventTypes, err)
+		assert.Nil(t, subscription)
+	})
+}
+
+func TestWebhookDelivery_MarkFailed(t *testing.T) {
+	subscription, _ := domain.NewWebhookSubscription("https://example.com", "", []string{domain.EventVoteCreated})
+	event, _ := domain.NewOutboxEvent(domain.EventVoteCreated, map[string]int{"score": 5})
+	now := time.Now()
+
+	t.Run("schedules retry with exponential backoff", func(t *testing.T) {
+		// Arrange
+		delivery := domain.NewWebhookDelivery(subscription, event)
+
+		// Act
+		delivery.MarkFailed(errors.New("boom"), now)
+		first := delivery.NextAttemptAt
+		delivery.MarkFailed(errors.New("boom"), now)
+
+		// Assert
+		assert.Equal(t, domain.DeliveryPending, delivery.Status)
+		assert.Equal(t, 2, delivery.Attempts)
+		assert.Equal(t, "boom", delivery.LastError)
+		assert.Equal(t, now.Add(domain.WebhookBaseBackoff), first)
+		assert.Equal(t, now.Add(2*domain.WebhookBaseBackoff), delivery.NextAttemptAt)
+	})
+
+	t.Run("dead-letters after max attempts", func(t *testing.T) {
+		// Arrange
+		delivery := domain.NewWebhookDelivery(subscription, event)
+
+		// Act
+		for range domain.WebhookMaxAttempts {
+			delivery.MarkFailed(errors.New("boom"), now)
+		}
+
+		// Assert
+		assert.Equal(t, domain.DeliveryDead, delivery.Status)
+		assert.Equal(t, domain.WebhookMaxAttempts, delivery.Attempts)
+	})
+}
+
+func TestWebhookBackoff(t *testing.T) {
+	assert.Equal(t, domain.WebhookBaseBackoff, domain.WebhookBackoff(1))
+	assert.Equal(t, 4*domain.WebhookBaseBackoff, domain.WebhookBackoff(3))
+	assert.Equal(t, domain.WebhookMaxBackoff, domain.WebhookBackoff(50))
+}
@@ -0,0 +1,138 @@
+package catalog
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+
+	"github.com/ArtemSind/food_tinder/internal/infrastructure/external/foodji"
+)
+
+// CompositeSource merges the products of several sources. A product offered by more than
+// one source is taken from the first of them and rejected for the others.
+type CompositeSource struct {
+	sources []Source
+	changes chan struct{}
+
+	mu sync.Mutex
+	// last holds the latest result of every source, so an unchanged source does not have to be refetched
+	last map[string]*Result
+}
+
+// NewCompositeSource creates a source merging the given sources in order of precedence
+func NewCompositeSource(sources ...Source) *CompositeSource {
+	c := &CompositeSource{
+		sources: sources,
+		changes: make(chan struct{}, 1),
+		last:    make(map[string]*Result),
+	}
+
+	// Forward the changes of the sources that watch themselves
+	for _, source := range sources {
+		if watcher, ok := source.(Watcher); ok {
+			go c.forward(watcher.Changes())
+		}
+	}
+	return c
+}
+
+func (c *CompositeSource) Name() string {
+	names := make([]string, len(c.sources))
+	for i, source := range c.sources {
+		names[i] = source.Name()
+	}
+	return strings.Join(names, "+")
+}
+
+// Fetch fetches every source; it fails if any of them fails, so a partial catalog never
+// replaces a complete one. Its version combines the versions of the sources.
+func (c *CompositeSource) Fetch(ctx context.Context, version string) (*Result, error) {
+	versions := make(map[string]string)
+	if version != "" {
+		// An unreadable version only costs a full fetch
+		_ = json.Unmarshal([]byte(version), &versions)
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	modified := false
+	results := make([]*Result, len(c.sources))
+	nextVersions := make(map[string]string, len(c.sources))
+	for i, source := range c.sources {
+		name := source.Name()
+
+		// Without the last result an unchanged source could not be merged
+		sourceVersion := ""
+		if last, ok := c.last[name]; ok && last.Version == versions[name] {
+			sourceVersion = versions[name]
+		}
+
+		result, err := source.Fetch(ctx, sourceVersion)
+		if errors.Is(err, ErrNotModified) {
+			result = c.last[name]
+		} else if err != nil {
+			return nil, fmt.Errorf("%s: %w", name, err)
+		} else {
+			c.last[name] = result
+			modified = true
+		}
+
+		results[i] = result
+		nextVersions[name] = result.Version
+	}
+	if !modified {
+		return nil, ErrNotModified
+	}
+
+	merged := &Result{}
+	origins := make(map[string]string)
+	for i, result := range results {
+		name := c.sources[i].Name()
+		for _, rejection := range result.Rejected {
+			rejection.Reason = name + ": " + rejection.Reason
+			merged.Rejected = append(merged.Rejected, rejection)
+		}
+		for _, field := range result.MissingFields {
+			merged.MissingFields = append(merged.MissingFields, name+": "+field)
+		}
+		for j, product := range result.Products {
+			id := product.ID.String()
+			if origin, ok := origins[id]; ok {
+				merged.Rejected = append(merged.Rejected, foodji.Rejection{
+					Index:     j,
+					ProductID: id,
+					Reason:    fmt.Sprintf("%s: duplicate of a product from %s", name, origin),
+				})
+				continue
+			}
+			origins[id] = product.Origin
+			merged.Products = append(merged.Products, product)
+		}
+	}
+
+	versionJSON, err := json.Marshal(nextVersions)
+	if err != nil {
+		return nil, err
+	}
+	merged.Version = string(versionJSON)
+	return merged, nil
+}
+
+// Changes receives a value whenever one of the watching sources changed
+func (c *CompositeSource) Changes() <-chan struct{} {
+	return c.changes
+}
+
+// forward passes on the changes of a source until it closes its channel
+func (c *CompositeSource) forward(changes <-chan struct{}) {
+	for range changes {
+		select {
+		case c.changes <- struct{}{}:
+		default:
+		}
+	}
+}
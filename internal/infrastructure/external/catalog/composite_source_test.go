@@ -0,0 +1,119 @@
+package catalog_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/ArtemSind/food_tinder/internal/infrastructure/external/catalog"
+	"github.com/ArtemSind/food_tinder/internal/infrastructure/external/foodji"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// FakeSource serves a fixed product list with a version that changes with Set
+type FakeSource struct {
+	name     string
+	products []foodji.Product
+	version  string
+	err      error
+	fetches  int
+}
+
+func NewFakeSource(name string, ids ...uuid.UUID) *FakeSource {
+	source := &FakeSource{name: name}
+	source.Set(ids...)
+	return source
+}
+
+func (s *FakeSource) Set(ids ...uuid.UUID) {
+	s.products = nil
+	for _, id := range ids {
+		s.products = append(s.products, foodji.Product{ID: id, Origin: s.name})
+	}
+	s.version = uuid.NewString()
+}
+
+func (s *FakeSource) Name() string {
+	return s.name
+}
+
+func (s *FakeSource) Fetch(ctx context.Context, version string) (*catalog.Result, error) {
+	s.fetches++
+	if s.err != nil {
+		return nil, s.err
+	}
+	if version == s.version {
+		return nil, catalog.ErrNotModified
+	}
+	return &catalog.Result{Products: s.products, Version: s.version}, nil
+}
+
+func productIDs(products []foodji.Product) []uuid.UUID {
+	ids := make([]uuid.UUID, len(products))
+	for i, product := range products {
+		ids[i] = product.ID
+	}
+	return ids
+}
+
+func TestCompositeSource_Fetch(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("merges sources and rejects duplicates", func(t *testing.T) {
+		// Arrange
+		shared, soup, salad := uuid.New(), uuid.New(), uuid.New()
+		foodjiSource := NewFakeSource("foodji", shared, soup)
+		canteen := NewFakeSource("canteen", shared, salad)
+		source := catalog.NewCompositeSource(foodjiSource, canteen)
+
+		// Act
+		result, err := source.Fetch(ctx, "")
+
+		// Assert
+		require.NoError(t, err)
+		assert.Equal(t, "foodji+canteen", source.Name())
+		assert.Equal(t, []uuid.UUID{shared, soup, salad}, productIDs(result.Products))
+		assert.Equal(t, "foodji", result.Products[0].Origin)
+		assert.Equal(t, "canteen", result.Products[2].Origin)
+		require.Len(t, result.Rejected, 1)
+		assert.Equal(t, shared.String(), result.Rejected[0].ProductID)
+		assert.Equal(t, "canteen: duplicate of a product from foodji", result.Rejected[0].Reason)
+	})
+
+	t.Run("refetches only changed sources", func(t *testing.T) {
+		// Arrange
+		soup, salad, stew := uuid.New(), uuid.New(), uuid.New()
+		foodjiSource := NewFakeSource("foodji", soup)
+		canteen := NewFakeSource("canteen", salad)
+		source := catalog.NewCompositeSource(foodjiSource, canteen)
+
+		first, err := source.Fetch(ctx, "")
+		require.NoError(t, err)
+
+		// Act
+		_, unchanged := source.Fetch(ctx, first.Version)
+		canteen.Set(salad, stew)
+		changed, err := source.Fetch(ctx, first.Version)
+
+		// Assert
+		assert.ErrorIs(t, unchanged, catalog.ErrNotModified)
+		require.NoError(t, err)
+		assert.Equal(t, []uuid.UUID{soup, salad, stew}, productIDs(changed.Products))
+		assert.NotEqual(t, first.Version, changed.Version)
+	})
+
+	t.Run("fails when a source fails", func(t *testing.T) {
+		// Arrange
+		canteen := NewFakeSource("canteen", uuid.New())
+		canteen.err = errors.New("file not found")
+		source := catalog.NewCompositeSource(NewFakeSource("foodji", uuid.New()), canteen)
+
+		// Act
+		_, err := source.Fetch(ctx, "")
+
+		// Assert
+		assert.ErrorContains(t, err, "canteen: file not found")
+	})
+}
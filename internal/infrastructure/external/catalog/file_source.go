@@ -0,0 +1,229 @@
+package catalog
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/csv"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/ArtemSind/food_tinder/internal/infrastructure/external/foodji"
+)
+
+// FileWatchInterval is how often a file source checks its file for changes
+const FileWatchInterval = 5 * time.Second
+
+// FileSource reads a catalog from a JSON or CSV file, picked by the file extension.
+// A JSON catalog is an array of products; a CSV catalog has a header row naming the
+// product fields, such as id, name, category, price, allergens and diets. Once started, it watches the file and reports changes.
+type FileSource struct {
+	// WatchInterval is how often the started source checks the file for changes
+	WatchInterval time.Duration
+
+	name    string
+	path    string
+	changes chan struct{}
+	cancel  func()
+	wg      sync.WaitGroup
+	once    sync.Once
+}
+
+// NewFileSource creates a source for a catalog file. Its products are tagged with the
+// file name without extension, so canteen.csv yields products from "canteen".
+func NewFileSource(path string) *FileSource {
+	name := strings.TrimSuffix(filepath.Base(path), filepath.Ext(path))
+	return &FileSource{
+		WatchInterval: FileWatchInterval,
+		name:          name,
+		path:          path,
+		changes:       make(chan struct{}, 1),
+	}
+}
+
+// FileSourcesFromEnv creates a file source for every path in the comma-separated CATALOG_FILES
+func FileSourcesFromEnv() []*FileSource {
+	var sources []*FileSource
+	for _, path := range strings.Split(os.Getenv("CATALOG_FILES"), ",") {
+		if path = strings.TrimSpace(path); path != "" {
+			sources = append(sources, NewFileSource(path))
+		}
+	}
+	return sources
+}
+
+func (s *FileSource) Name() string {
+	return s.name
+}
+
+// Fetch reads the file; its version is the hash of the file content
+func (s *FileSource) Fetch(ctx context.Context, version string) (*Result, error) {
+	content, err := os.ReadFile(s.path)
+	if err != nil {
+		return nil, fmt.Errorf("error reading catalog file: %w", err)
+	}
+
+	sum := sha256.Sum256(content)
+	contentVersion := hex.EncodeToString(sum[:])
+	if contentVersion == version {
+		return nil, ErrNotModified
+	}
+
+	var items []json.RawMessage
+	switch strings.ToLower(filepath.Ext(s.path)) {
+	case ".json":
+		if err := json.Unmarshal(content, &items); err != nil {
+			return nil, fmt.Errorf("%w: %s is not a JSON array of products: %v", foodji.ErrInvalidPayload, s.path, err)
+		}
+	case ".csv":
+		items, err = csvProducts(content)
+		if err != nil {
+			return nil, fmt.Errorf("%w: %s: %v", foodji.ErrInvalidPayload, s.path, err)
+		}
+	default:
+		return nil, fmt.Errorf("unsupported catalog file %s, expected .json or .csv", s.path)
+	}
+
+	products, rejected := foodji.DecodeProducts(items)
+	tagOrigin(products, s.name)
+	return &Result{
+		Products: products,
+		Rejected: rejected,
+		Version:  contentVersion,
+	}, nil
+}
+
+// csvProducts converts every CSV row into a JSON product keyed by the header row.
+// Prices are numbers; allergens and diets are separated by semicolons.
+func csvProducts(content []byte) ([]json.RawMessage, error) {
+	reader := csv.NewReader(bytes.NewReader(content))
+	reader.TrimLeadingSpace = true
+
+	header, err := reader.Read()
+	if err == io.EOF {
+		return nil, fmt.Errorf("missing header row")
+	}
+	if err != nil {
+		return nil, err
+	}
+	for i := range header {
+		header[i] = strings.ToLower(strings.TrimSpace(header[i]))
+	}
+
+	var items []json.RawMessage
+	for {
+		record, err := reader.Read()
+		if err == io.EOF {
+			return items, nil
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		fields := make(map[string]any, len(header))
+		for i, value := range record {
+			if i >= len(header) || value == "" {
+				continue
+			}
+			fields[header[i]] = csvValue(header[i], value)
+		}
+		item, err := json.Marshal(fields)
+		if err != nil {
+			return nil, err
+		}
+		items = append(items, item)
+	}
+}
+
+// csvValue converts a CSV cell to the JSON type of its product field; a cell that does not
+// convert is kept as a string, so the product is rejected with the decoding error
+func csvValue(field, value string) any {
+	switch field {
+	case "price":
+		if price, err := strconv.ParseFloat(value, 64); err == nil {
+			return price
+		}
+	case "allergens", "diets":
+		var labels []string
+		for _, label := range strings.Split(value, ";") {
+			if label = strings.TrimSpace(label); label != "" {
+				labels = append(labels, label)
+			}
+		}
+		return labels
+	}
+	return value
+}
+
+// Changes receives a value after the file was modified
+func (s *FileSource) Changes() <-chan struct{} {
+	return s.changes
+}
+
+// Start watches the file for changes until Close is called
+func (s *FileSource) Start() {
+	ctx, cancel := context.WithCancel(context.Background())
+	s.cancel = cancel
+
+	s.wg.Add(1)
+	go s.watch(ctx, s.WatchInterval)
+}
+
+// Close stops watching the file and closes the Changes channel
+func (s *FileSource) Close() error {
+	s.once.Do(func() {
+		if s.cancel != nil {
+			s.cancel()
+			s.wg.Wait()
+		}
+		close(s.changes)
+	})
+	return nil
+}
+
+// watch polls the modification time and size of the file
+func (s *FileSource) watch(ctx context.Context, interval time.Duration) {
+	defer s.wg.Done()
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	last := s.stat()
+	for {
+		select {
+		case <-ticker.C:
+			current := s.stat()
+			if current == last {
+				continue
+			}
+			last = current
+			log.Printf("Catalog file %s changed", s.path)
+
+			// A pending notification already covers this change
+			select {
+			case s.changes <- struct{}{}:
+			default:
+			}
+		case <-ctx.Done():
+			return
+		}
+	}
+}
+
+// stat describes the file version cheaply; a missing file is described as empty
+func (s *FileSource) stat() string {
+	info, err := os.Stat(s.path)
+	if err != nil {
+		return ""
+	}
+	return fmt.Sprintf("%d-%d", info.ModTime().UnixNano(), info.Size())
+}
@@ -0,0 +1,136 @@
+package catalog_test
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/ArtemSind/food_tinder/internal/infrastructure/external/catalog"
+	"github.com/ArtemSind/food_tinder/internal/infrastructure/external/foodji"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func writeFile(t *testing.T, path, content string) {
+	t.Helper()
+	require.NoError(t, os.WriteFile(path, []byte(content), 0o644))
+}
+
+func TestFileSource_Fetch(t *testing.T) {
+	ctx := context.Background()
+	soup, salad := uuid.New(), uuid.New()
+
+	tests := []struct {
+		name    string
+		file    string
+		content string
+	}{
+		{
+			name:    "json",
+			file:    "canteen.json",
+			content: `[{"id":"` + soup.String() + `"},{"id":"` + salad.String() + `"},{"id":"not-a-uuid"}]`,
+		},
+		{
+			name:    "csv",
+			file:    "canteen.csv",
+			content: "ID,Name\n" + soup.String() + ",Soup\n" + salad.String() + ",Salad\nnot-a-uuid,Stew\n",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Arrange
+			path := filepath.Join(t.TempDir(), tt.file)
+			writeFile(t, path, tt.content)
+			source := catalog.NewFileSource(path)
+
+			// Act
+			result, err := source.Fetch(ctx, "")
+
+			// Assert
+			require.NoError(t, err)
+			assert.Equal(t, "canteen", source.Name())
+			require.Len(t, result.Products, 2)
+			assert.Equal(t, soup, result.Products[0].ID)
+			assert.Equal(t, "canteen", result.Products[0].Origin)
+			assert.Equal(t, salad, result.Products[1].ID)
+			require.Len(t, result.Rejected, 1)
+			assert.Equal(t, 2, result.Rejected[0].Index)
+			assert.NotEmpty(t, result.Version)
+
+			_, err = source.Fetch(ctx, result.Version)
+			assert.ErrorIs(t, err, catalog.ErrNotModified)
+		})
+	}
+
+	t.Run("csv product fields", func(t *testing.T) {
+		// Arrange
+		path := filepath.Join(t.TempDir(), "canteen.csv")
+		writeFile(t, path, "id,name,category,price,allergens,diets\n"+
+			soup.String()+",Tomato Soup,Soups,5.20,celery; milk,vegetarian;gluten-free\n"+
+			salad.String()+",Greek Salad,Salads,cheap,,\n")
+
+		// Act
+		result, err := catalog.NewFileSource(path).Fetch(ctx, "")
+
+		// Assert
+		require.NoError(t, err)
+		require.Len(t, result.Products, 1)
+		assert.Equal(t, "Tomato Soup", result.Products[0].Name)
+		assert.Equal(t, "Soups", result.Products[0].Category)
+		assert.Equal(t, 5.2, result.Products[0].Price)
+		assert.Equal(t, []string{"celery", "milk"}, result.Products[0].Allergens)
+		assert.Equal(t, []string{"vegetarian", "gluten-free"}, result.Products[0].Diets)
+		require.Len(t, result.Rejected, 1, "a price that is not a number rejects the product")
+		assert.Equal(t, salad.String(), result.Rejected[0].ProductID)
+	})
+
+	t.Run("invalid file", func(t *testing.T) {
+		// Arrange
+		path := filepath.Join(t.TempDir(), "canteen.json")
+		writeFile(t, path, `{"products":[]}`)
+
+		// Act
+		_, err := catalog.NewFileSource(path).Fetch(ctx, "")
+
+		// Assert
+		assert.ErrorIs(t, err, foodji.ErrInvalidPayload)
+	})
+
+	t.Run("missing file", func(t *testing.T) {
+		// Act
+		_, err := catalog.NewFileSource(filepath.Join(t.TempDir(), "missing.csv")).Fetch(ctx, "")
+
+		// Assert
+		assert.Error(t, err)
+	})
+}
+
+func TestFileSource_Watch(t *testing.T) {
+	// Arrange
+	path := filepath.Join(t.TempDir(), "canteen.csv")
+	writeFile(t, path, "id\n"+uuid.New().String()+"\n")
+
+	source := catalog.NewFileSource(path)
+	source.WatchInterval = 5 * time.Millisecond
+	source.Start()
+	defer source.Close()
+
+	// Act
+	time.Sleep(20 * time.Millisecond)
+	writeFile(t, path, "id\n"+uuid.New().String()+"\n"+uuid.New().String()+"\n")
+
+	// Assert
+	select {
+	case <-source.Changes():
+	case <-time.After(time.Second):
+		t.Fatal("the change was not reported")
+	}
+
+	require.NoError(t, source.Close())
+	_, open := <-source.Changes()
+	assert.False(t, open, "closing the source closes its changes")
+}
@@ -0,0 +1,61 @@
+package catalog
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+
+	"github.com/ArtemSind/food_tinder/internal/infrastructure/external/foodji"
+)
+
+// FoodjiOrigin is the origin of products imported from Foodji
+const FoodjiOrigin = "foodji"
+
+// FoodjiSource imports the products of a Foodji machine. Its versions carry the
+// ETag and Last-Modified of the response, so fetches are conditional requests.
+type FoodjiSource struct {
+	client    *foodji.Client
+	machineID string
+}
+
+func NewFoodjiSource(client *foodji.Client, machineID string) *FoodjiSource {
+	return &FoodjiSource{client: client, machineID: machineID}
+}
+
+func (s *FoodjiSource) Name() string {
+	return FoodjiOrigin
+}
+
+func (s *FoodjiSource) Fetch(ctx context.Context, version string) (*Result, error) {
+	var validators foodji.Validators
+	if version != "" {
+		// An unreadable version only costs a full fetch
+		_ = json.Unmarshal([]byte(version), &validators)
+	}
+
+	machine, err := s.client.FetchMachineProducts(ctx, s.machineID, validators)
+	if errors.Is(err, foodji.ErrNotModified) {
+		return nil, ErrNotModified
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	tagOrigin(machine.Products, s.Name())
+	for i := range machine.Products {
+		machine.Products[i].MachineID = s.machineID
+	}
+	result := &Result{
+		Products:      machine.Products,
+		Rejected:      machine.Rejected,
+		MissingFields: machine.MissingFields,
+	}
+	if !machine.Validators.IsZero() {
+		versionJSON, err := json.Marshal(machine.Validators)
+		if err != nil {
+			return nil, err
+		}
+		result.Version = string(versionJSON)
+	}
+	return result, nil
+}
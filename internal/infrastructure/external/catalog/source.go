@@ -0,0 +1,46 @@
+// Package catalog provides the sources the product catalog is imported from:
+// the Foodji API, catalog files on disk and a composite merging several sources.
+package catalog
+
+import (
+	"context"
+	"errors"
+
+	"github.com/ArtemSind/food_tinder/internal/infrastructure/external/foodji"
+)
+
+// ErrNotModified is returned by a conditional fetch when the products did not change
+var ErrNotModified = errors.New("catalog not modified")
+
+// Source provides the products of a catalog
+type Source interface {
+	// Name identifies the source; its products are tagged with it as their origin
+	Name() string
+	// Fetch returns the products of the source. Passing the version of an earlier result makes
+	// the fetch conditional: it returns ErrNotModified if the products did not change since.
+	Fetch(ctx context.Context, version string) (*Result, error)
+}
+
+// Watcher is implemented by sources that notice changes on their own
+type Watcher interface {
+	// Changes receives a value whenever the products of the source may have changed
+	Changes() <-chan struct{}
+}
+
+// Result holds the products of a source
+type Result struct {
+	Products []foodji.Product
+	// Rejected lists the products that failed validation
+	Rejected []foodji.Rejection
+	// MissingFields lists the product fields the source sent for no product
+	MissingFields []string
+	// Version identifies the result for conditional fetches
+	Version string
+}
+
+// tagOrigin sets the origin of every product
+func tagOrigin(products []foodji.Product, origin string) {
+	for i := range products {
+		products[i].Origin = origin
+	}
+}
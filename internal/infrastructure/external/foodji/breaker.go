@@ -0,0 +1,118 @@
+package foodji
+
+import (
+	"errors"
+	"sync"
+	"time"
+)
+
+const (
+	// DefaultFailureThreshold is the number of consecutive transient failures that opens the breaker
+	DefaultFailureThreshold = 5
+	// DefaultCooldown is how long the breaker stays open before letting a probe request through
+	DefaultCooldown = time.Minute
+)
+
+var ErrCircuitOpen = errors.New("foodji circuit breaker is open")
+
+// BreakerState is the state of a circuit breaker
+type BreakerState string
+
+const (
+	// BreakerClosed lets every request through
+	BreakerClosed BreakerState = "closed"
+	// BreakerOpen rejects requests until the cooldown has passed
+	BreakerOpen BreakerState = "open"
+	// BreakerHalfOpen lets a single probe request through
+	BreakerHalfOpen BreakerState = "half-open"
+)
+
+// CircuitBreaker stops calling Foodji after repeated transient failures
+// and probes it again once the cooldown has passed
+type CircuitBreaker struct {
+	FailureThreshold int
+	Cooldown         time.Duration
+
+	mu       sync.Mutex
+	state    BreakerState
+	failures int
+	openedAt time.Time
+	probing  bool
+	now      func() time.Time
+}
+
+// NewCircuitBreaker creates a closed circuit breaker
+func NewCircuitBreaker(failureThreshold int, cooldown time.Duration) *CircuitBreaker {
+	return &CircuitBreaker{
+		FailureThreshold: failureThreshold,
+		Cooldown:         cooldown,
+		state:            BreakerClosed,
+		now:              time.Now,
+	}
+}
+
+// Allow reports whether a request may be made. Once the cooldown has passed an open
+// breaker becomes half-open and lets exactly one probe through.
+func (b *CircuitBreaker) Allow() error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	switch b.state {
+	case BreakerOpen:
+		if b.now().Sub(b.openedAt) < b.Cooldown {
+			return ErrCircuitOpen
+		}
+		b.state = BreakerHalfOpen
+		b.probing = true
+		return nil
+	case BreakerHalfOpen:
+		if b.probing {
+			// A probe is already in flight
+			return ErrCircuitOpen
+		}
+		b.probing = true
+		return nil
+	default:
+		return nil
+	}
+}
+
+// RecordSuccess closes the breaker
+func (b *CircuitBreaker) RecordSuccess() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.state = BreakerClosed
+	b.failures = 0
+	b.probing = false
+}
+
+// RecordFailure counts a transient failure and opens the breaker at the threshold;
+// a failed probe reopens it immediately
+func (b *CircuitBreaker) RecordFailure() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.failures++
+	b.probing = false
+	if b.state == BreakerHalfOpen || b.failures >= b.FailureThreshold {
+		b.state = BreakerOpen
+		b.openedAt = b.now()
+	}
+}
+
+// RecordAbandoned notes a request the caller gave up on before Foodji answered. It says
+// nothing about Foodji, so the state is kept; a half-open breaker lets the next probe through.
+func (b *CircuitBreaker) RecordAbandoned() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.probing = false
+}
+
+// State returns the current state of the breaker
+func (b *CircuitBreaker) State() BreakerState {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.state
+}
@@ -2,10 +2,14 @@ package foodji
 
 import (
 	"context"
-	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
+	"log"
 	"net/http"
+	"os"
+	"strconv"
+	"strings"
 	"time"
 )
 
@@ -20,20 +24,125 @@ const (
 type Client struct {
 	BaseURL    string
 	HTTPClient *http.Client
+	Retry      RetryPolicy
+	Breaker    *CircuitBreaker
+	Validation ValidationMode
 }
 
-// NewClient creates a new Foodji API client
+// NewClient creates a new Foodji API client. FOODJI_BASE_URL, FOODJI_MAX_ATTEMPTS,
+// FOODJI_BREAKER_THRESHOLD, FOODJI_BREAKER_COOLDOWN and FOODJI_VALIDATION override the defaults.
 func NewClient() *Client {
+	retry := DefaultRetryPolicy()
+	retry.MaxAttempts = envInt("FOODJI_MAX_ATTEMPTS", retry.MaxAttempts)
+
+	baseURL := os.Getenv("FOODJI_BASE_URL")
+	if baseURL == "" {
+		baseURL = DefaultBaseURL
+	}
+
+	validation := ValidationLenient
+	if ValidationMode(os.Getenv("FOODJI_VALIDATION")) == ValidationStrict {
+		validation = ValidationStrict
+	}
+
 	return &Client{
-		BaseURL: DefaultBaseURL,
+		BaseURL: baseURL,
 		HTTPClient: &http.Client{
 			Timeout: DefaultTimeout,
 		},
+		Retry: retry,
+		Breaker: NewCircuitBreaker(
+			envInt("FOODJI_BREAKER_THRESHOLD", DefaultFailureThreshold),
+			envDuration("FOODJI_BREAKER_COOLDOWN", DefaultCooldown),
+		),
+		Validation: validation,
 	}
 }
 
-// GetMachine fetches machine data by ID
+// Validators are the HTTP cache validators of a previous response
+type Validators struct {
+	ETag         string `json:"etag,omitempty"`
+	LastModified string `json:"last_modified,omitempty"`
+}
+
+// IsZero reports whether no validators are known
+func (v Validators) IsZero() bool {
+	return v.ETag == "" && v.LastModified == ""
+}
+
+// MachineProducts is a machine's valid products together with the validators of the response,
+// the products that were skipped because they failed validation and the fields no product had
+type MachineProducts struct {
+	Products      []Product
+	Rejected      []Rejection
+	MissingFields []string
+	Validators    Validators
+}
+
+var ErrNotModified = errors.New("foodji machine products not modified")
+
+// GetMachineProducts fetches the products of a machine, retrying transient failures
+// with jittered exponential backoff. While the circuit breaker is open it fails fast with ErrCircuitOpen.
 func (c *Client) GetMachineProducts(ctx context.Context, machineID string) (*[]Product, error) {
+	result, err := c.FetchMachineProducts(ctx, machineID, Validators{})
+	if err != nil {
+		return nil, err
+	}
+	return &result.Products, nil
+}
+
+// FetchMachineProducts is GetMachineProducts as a conditional request: with validators from a
+// previous response it returns ErrNotModified when Foodji answers 304 Not Modified.
+// Invalid products are skipped and reported, or fail the request with a ValidationError in strict mode.
+func (c *Client) FetchMachineProducts(ctx context.Context, machineID string, validators Validators) (*MachineProducts, error) {
+	attempts := c.Retry.MaxAttempts
+	if attempts < 1 {
+		attempts = 1
+	}
+
+	var err error
+	for attempt := 1; attempt <= attempts; attempt++ {
+		if attempt > 1 {
+			delay := c.Retry.Backoff(attempt - 1)
+			log.Printf("Retrying Foodji request in %v (attempt %d/%d): %v", delay, attempt, attempts, err)
+			if sleepErr := sleep(ctx, delay); sleepErr != nil {
+				return nil, err
+			}
+		}
+
+		if c.Breaker != nil {
+			if breakerErr := c.Breaker.Allow(); breakerErr != nil {
+				return nil, breakerErr
+			}
+		}
+
+		var result *MachineProducts
+		result, err = c.fetchMachineProducts(ctx, machineID, validators)
+		transient := err != nil && ctx.Err() == nil && IsTransient(err)
+
+		if c.Breaker != nil {
+			switch {
+			case err != nil && ctx.Err() != nil:
+				// The caller's context ended the request, so it did not reach a verdict on Foodji
+				c.Breaker.RecordAbandoned()
+			case transient:
+				c.Breaker.RecordFailure()
+			default:
+				// Any answer that is not a transient failure means Foodji is reachable
+				c.Breaker.RecordSuccess()
+			}
+		}
+
+		if !transient {
+			return result, err
+		}
+	}
+
+	return nil, err
+}
+
+// fetchMachineProducts makes a single request for the products of a machine
+func (c *Client) fetchMachineProducts(ctx context.Context, machineID string, validators Validators) (*MachineProducts, error) {
 	url := fmt.Sprintf("%s/machines/%s", c.BaseURL, machineID)
 
 	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
@@ -42,6 +151,12 @@ func (c *Client) GetMachineProducts(ctx context.Context, machineID string) (*[]P
 	}
 
 	req.Header.Set("Content-Type", "application/json")
+	if validators.ETag != "" {
+		req.Header.Set("If-None-Match", validators.ETag)
+	}
+	if validators.LastModified != "" {
+		req.Header.Set("If-Modified-Since", validators.LastModified)
+	}
 
 	resp, err := c.HTTPClient.Do(req)
 	if err != nil {
@@ -49,15 +164,61 @@ func (c *Client) GetMachineProducts(ctx context.Context, machineID string) (*[]P
 	}
 	defer resp.Body.Close()
 
+	if resp.StatusCode == http.StatusNotModified {
+		return nil, ErrNotModified
+	}
+
 	if resp.StatusCode != http.StatusOK {
 		body, _ := io.ReadAll(resp.Body)
-		return nil, fmt.Errorf("unexpected status code: %d, body: %s", resp.StatusCode, string(body))
+		return nil, &StatusError{StatusCode: resp.StatusCode, Body: string(body)}
 	}
 
-	var apiResponse Response
-	if err := json.NewDecoder(resp.Body).Decode(&apiResponse); err != nil {
-		return nil, fmt.Errorf("error decoding response: %w", err)
+	// Read the body before decoding so a dropped connection (transient) is not
+	// confused with a malformed payload (permanent)
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("error reading response: %w", err)
 	}
 
-	return &apiResponse.Data.MachineProducts, nil
+	items, err := machineProductItems(body)
+	if err != nil {
+		return nil, err
+	}
+	products, rejected := DecodeProducts(items)
+	if len(rejected) > 0 {
+		if c.Validation == ValidationStrict {
+			return nil, &ValidationError{Rejections: rejected}
+		}
+		log.Printf("Skipped %d invalid Foodji products", len(rejected))
+	}
+	missing := MissingFields(items)
+	if len(missing) > 0 {
+		log.Printf("No Foodji product has %s; Foodji may have renamed or dropped them", strings.Join(missing, ", "))
+	}
+
+	return &MachineProducts{
+		Products:      products,
+		Rejected:      rejected,
+		MissingFields: missing,
+		Validators: Validators{
+			ETag:         resp.Header.Get("ETag"),
+			LastModified: resp.Header.Get("Last-Modified"),
+		},
+	}, nil
+}
+
+func envInt(key string, fallback int) int {
+	value, err := strconv.Atoi(os.Getenv(key))
+	if err != nil || value <= 0 {
+		return fallback
+	}
+	return value
+}
+
+func envDuration(key string, fallback time.Duration) time.Duration {
+	value, err := time.ParseDuration(os.Getenv(key))
+	if err != nil || value <= 0 {
+		return fallback
+	}
+	return value
 }
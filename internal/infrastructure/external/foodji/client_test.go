@@ -0,0 +1,213 @@
+package foodji_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/ArtemSind/food_tinder/internal/infrastructure/external/foodji"
+	"github.com/ArtemSind/food_tinder/internal/infrastructure/external/foodji/fake"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const machineID = "machine"
+
+func newFakeFoodji(t *testing.T) (*fake.Server, *foodji.Client) {
+	t.Helper()
+
+	fakeServer := fake.NewServer(&fake.Fixture{Machines: map[string][]foodji.Product{
+		machineID: {{ID: uuid.New()}, {ID: uuid.New()}},
+	}})
+	server := httptest.NewServer(fakeServer)
+	t.Cleanup(server.Close)
+
+	client := foodji.NewClient()
+	client.BaseURL = server.URL
+	client.Retry = foodji.RetryPolicy{MaxAttempts: 3, BaseDelay: time.Millisecond, MaxDelay: 5 * time.Millisecond}
+	client.Breaker = foodji.NewCircuitBreaker(5, 50*time.Millisecond)
+	return fakeServer, client
+}
+
+func TestClient_GetMachineProducts(t *testing.T) {
+	t.Run("retries transient errors", func(t *testing.T) {
+		// Arrange
+		fakeServer, client := newFakeFoodji(t)
+		fakeServer.FailNext(http.StatusServiceUnavailable, http.StatusBadGateway)
+
+		// Act
+		products, err := client.GetMachineProducts(context.Background(), machineID)
+
+		// Assert
+		require.NoError(t, err)
+		assert.Len(t, *products, 2)
+		assert.Equal(t, 3, fakeServer.Requests())
+		assert.Equal(t, foodji.BreakerClosed, client.Breaker.State())
+	})
+
+	t.Run("does not retry client errors", func(t *testing.T) {
+		// Arrange
+		fakeServer, client := newFakeFoodji(t)
+
+		// Act
+		_, err := client.GetMachineProducts(context.Background(), "unknown-machine")
+
+		// Assert
+		var statusErr *foodji.StatusError
+		require.ErrorAs(t, err, &statusErr)
+		assert.Equal(t, http.StatusNotFound, statusErr.StatusCode)
+		assert.Equal(t, 1, fakeServer.Requests())
+	})
+
+	t.Run("does not retry malformed payloads", func(t *testing.T) {
+		// Arrange
+		fakeServer, client := newFakeFoodji(t)
+		fakeServer.SetMalformed(true)
+
+		// Act
+		_, err := client.GetMachineProducts(context.Background(), machineID)
+
+		// Assert
+		assert.ErrorContains(t, err, "error decoding response")
+		assert.Equal(t, 1, fakeServer.Requests())
+	})
+
+	t.Run("retries timeouts", func(t *testing.T) {
+		// Arrange
+		fakeServer, client := newFakeFoodji(t)
+		client.HTTPClient.Timeout = 20 * time.Millisecond
+		fakeServer.SetLatency(100 * time.Millisecond)
+
+		// Act
+		_, err := client.GetMachineProducts(context.Background(), machineID)
+
+		// Assert
+		assert.True(t, foodji.IsTransient(err))
+		assert.Equal(t, 3, fakeServer.Requests())
+	})
+
+	t.Run("circuit breaker fails fast and recovers", func(t *testing.T) {
+		// Arrange
+		fakeServer, client := newFakeFoodji(t)
+		client.Breaker = foodji.NewCircuitBreaker(2, 50*time.Millisecond)
+		fakeServer.SetErrorRate(1, http.StatusBadGateway)
+
+		// Act: two failed attempts open the breaker, the third is never sent
+		_, err := client.GetMachineProducts(context.Background(), machineID)
+
+		// Assert
+		assert.ErrorIs(t, err, foodji.ErrCircuitOpen)
+		assert.Equal(t, 2, fakeServer.Requests())
+		assert.Equal(t, foodji.BreakerOpen, client.Breaker.State())
+
+		// Act: after the cooldown a probe goes through and closes the breaker
+		fakeServer.SetErrorRate(0, http.StatusBadGateway)
+		time.Sleep(60 * time.Millisecond)
+		products, err := client.GetMachineProducts(context.Background(), machineID)
+
+		// Assert
+		require.NoError(t, err)
+		assert.Len(t, *products, 2)
+		assert.Equal(t, foodji.BreakerClosed, client.Breaker.State())
+	})
+
+	t.Run("a probe cancelled by the caller keeps the breaker half-open", func(t *testing.T) {
+		// Arrange
+		fakeServer, client := newFakeFoodji(t)
+		client.Breaker = foodji.NewCircuitBreaker(1, 10*time.Millisecond)
+		client.Breaker.RecordFailure()
+		time.Sleep(15 * time.Millisecond)
+		fakeServer.SetLatency(100 * time.Millisecond)
+		ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+		defer cancel()
+
+		// Act
+		_, err := client.GetMachineProducts(ctx, machineID)
+
+		// Assert
+		assert.ErrorIs(t, err, context.DeadlineExceeded)
+		assert.Equal(t, foodji.BreakerHalfOpen, client.Breaker.State())
+		assert.NoError(t, client.Breaker.Allow(), "the next request probes again")
+	})
+}
+
+func TestClient_FetchMachineProducts_Conditional(t *testing.T) {
+	// Arrange
+	fakeServer, client := newFakeFoodji(t)
+	ctx := context.Background()
+
+	// Act
+	first, err := client.FetchMachineProducts(ctx, machineID, foodji.Validators{})
+	require.NoError(t, err)
+	_, notModifiedErr := client.FetchMachineProducts(ctx, machineID, first.Validators)
+
+	fakeServer.SetProducts(machineID, []foodji.Product{{ID: uuid.New()}})
+	changed, err := client.FetchMachineProducts(ctx, machineID, first.Validators)
+
+	// Assert
+	assert.Len(t, first.Products, 2)
+	assert.NotEmpty(t, first.Validators.ETag)
+	assert.NotEmpty(t, first.Validators.LastModified)
+	assert.ErrorIs(t, notModifiedErr, foodji.ErrNotModified)
+	require.NoError(t, err)
+	assert.Len(t, changed.Products, 1)
+	assert.NotEqual(t, first.Validators.ETag, changed.Validators.ETag)
+	assert.Equal(t, foodji.BreakerClosed, client.Breaker.State())
+}
+
+func TestRetryPolicy_Backoff(t *testing.T) {
+	// Arrange
+	policy := foodji.RetryPolicy{MaxAttempts: 10, BaseDelay: 100 * time.Millisecond, MaxDelay: time.Second}
+
+	for retry, bound := range map[int]time.Duration{
+		1: 100 * time.Millisecond,
+		3: 400 * time.Millisecond,
+		8: time.Second,
+	} {
+		for range 100 {
+			// Act
+			delay := policy.Backoff(retry)
+
+			// Assert
+			assert.GreaterOrEqual(t, delay, time.Duration(0))
+			assert.LessOrEqual(t, delay, bound)
+		}
+	}
+}
+
+func TestCircuitBreaker_FailedProbeReopens(t *testing.T) {
+	// Arrange
+	breaker := foodji.NewCircuitBreaker(1, 10*time.Millisecond)
+	breaker.RecordFailure()
+	require.ErrorIs(t, breaker.Allow(), foodji.ErrCircuitOpen)
+	time.Sleep(15 * time.Millisecond)
+
+	// Act
+	require.NoError(t, breaker.Allow())
+	probeBlocked := breaker.Allow()
+	breaker.RecordFailure()
+
+	// Assert
+	assert.ErrorIs(t, probeBlocked, foodji.ErrCircuitOpen, "only one probe at a time")
+	assert.Equal(t, foodji.BreakerOpen, breaker.State())
+	assert.ErrorIs(t, breaker.Allow(), foodji.ErrCircuitOpen)
+}
+
+func TestCircuitBreaker_AbandonedProbeStaysHalfOpen(t *testing.T) {
+	// Arrange
+	breaker := foodji.NewCircuitBreaker(1, 10*time.Millisecond)
+	breaker.RecordFailure()
+	time.Sleep(15 * time.Millisecond)
+	require.NoError(t, breaker.Allow())
+
+	// Act
+	breaker.RecordAbandoned()
+
+	// Assert
+	assert.Equal(t, foodji.BreakerHalfOpen, breaker.State())
+	require.NoError(t, breaker.Allow(), "another probe may go through")
+	assert.ErrorIs(t, breaker.Allow(), foodji.ErrCircuitOpen, "only one probe at a time")
+}
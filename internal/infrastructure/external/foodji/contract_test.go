@@ -0,0 +1,259 @@
+package foodji_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/ArtemSind/food_tinder/internal/infrastructure/external/foodji"
+	"github.com/ArtemSind/food_tinder/internal/infrastructure/external/foodji/fake"
+	"github.com/ArtemSind/food_tinder/internal/infrastructure/external/foodji/recorder"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// Contract tests run against recordings of the Foodji API in testdata/contract. Without a
+// recording taken from Foodji itself they fail in CI (CI set) and are skipped elsewhere;
+// recordings of the fake server or any other host prove nothing about Foodji's format.
+// Record with FOODJI_RECORD=1 go test ./internal/infrastructure/external/foodji -run Contract,
+// and again whenever foodji.Product decodes a new field.
+
+const (
+	contractDir       = "testdata/contract"
+	contractMachineID = "4bf115ee-303a-4089-a3ea-f6e7aae0ab94"
+)
+
+// localFields are the product fields the catalog sources set themselves; Foodji does not send them
+var localFields = map[string]bool{"machineId": true, "origin": true}
+
+func recording() bool {
+	return os.Getenv("FOODJI_RECORD") == "1"
+}
+
+// missingRecording fails the test in CI, where the contract must be checked, and skips it
+// on developer machines that cannot reach Foodji
+func missingRecording(t *testing.T, format string, args ...any) {
+	t.Helper()
+
+	if os.Getenv("CI") != "" {
+		t.Fatalf(format, args...)
+	}
+	t.Skipf(format, args...)
+}
+
+func newContractClient(t *testing.T) *foodji.Client {
+	t.Helper()
+
+	mode := recorder.ModeReplay
+	if recording() {
+		mode = recorder.ModeRecord
+	}
+
+	client := foodji.NewClient()
+	client.HTTPClient.Transport = recorder.New(mode, contractDir)
+	client.Retry.MaxAttempts = 1
+	client.Breaker = nil
+	return client
+}
+
+// loadContractRecording returns the recording of the machine, skipping or failing the test
+// unless it was taken from Foodji
+func loadContractRecording(t *testing.T) *recorder.Recording {
+	t.Helper()
+
+	foodjiURL, err := url.Parse(foodji.DefaultBaseURL)
+	require.NoError(t, err)
+
+	recording, err := recorder.Load(filepath.Join(contractDir, "GET_machines_"+contractMachineID+".json"))
+	if errors.Is(err, recorder.ErrNoRecording) {
+		missingRecording(t, "no recording of %s; record one with FOODJI_RECORD=1", foodjiURL.Host)
+	}
+	require.NoError(t, err)
+	if recording.Host != foodjiURL.Host {
+		missingRecording(t, "the recording was taken from %q, not %s; record it again with FOODJI_RECORD=1", recording.Host, foodjiURL.Host)
+	}
+	return recording
+}
+
+func TestContract_MachineProducts(t *testing.T) {
+	// Arrange
+	if !recording() {
+		loadContractRecording(t)
+	}
+	client := newContractClient(t)
+
+	// Act
+	result, err := client.FetchMachineProducts(context.Background(), contractMachineID, foodji.Validators{})
+
+	// Assert
+	require.NoError(t, err)
+	require.NotEmpty(t, result.Products, "the machine should have products")
+	assert.Empty(t, result.Rejected, "every product should be valid")
+
+	seen := map[uuid.UUID]bool{}
+	for _, product := range result.Products {
+		assert.NotEqual(t, uuid.Nil, product.ID)
+		assert.False(t, seen[product.ID], "duplicate product %s", product.ID)
+		seen[product.ID] = true
+	}
+}
+
+// TestContract_MachineProductsShape checks the raw payload, because decoding silently
+// ignores fields that Foodji renamed or dropped. Every field foodji.Product decodes must
+// appear in the recording, so adding a field fails this test until Foodji is recorded again.
+func TestContract_MachineProductsShape(t *testing.T) {
+	// Arrange
+	recording := loadContractRecording(t)
+	assert.Equal(t, 200, recording.Status)
+	assert.Contains(t, recording.Headers["Content-Type"], "application/json")
+
+	// Act
+	var payload struct {
+		Data struct {
+			MachineProducts []json.RawMessage `json:"machineProducts"`
+		} `json:"data"`
+	}
+	err := json.Unmarshal(recording.RawBody(), &payload)
+
+	// Assert
+	require.NoError(t, err)
+	require.NotEmpty(t, payload.Data.MachineProducts, "data.machineProducts should be a non-empty array")
+
+	productType := reflect.TypeOf(foodji.Product{})
+	observed := map[string]bool{}
+	for i, raw := range payload.Data.MachineProducts {
+		var product foodji.Product
+		assert.NoError(t, json.Unmarshal(raw, &product), "product %d should decode", i)
+		assert.NotEqual(t, uuid.Nil, product.ID, "product %d should have a UUID id", i)
+		observeFields(productType, raw, "", observed)
+	}
+
+	for _, field := range jsonFields(productType, "") {
+		if localFields[field] {
+			assert.False(t, observed[field], "Foodji now sends %q, which the sources overwrite", field)
+			continue
+		}
+		assert.True(t, observed[field], "no product in the recording has %q", field)
+	}
+}
+
+// TestFakeServesEveryDecodedField keeps the fake server in step with foodji.Product, so
+// development and tests exercise every field the client decodes
+func TestFakeServesEveryDecodedField(t *testing.T) {
+	// Arrange
+	server := httptest.NewServer(fake.NewServer(fake.DefaultFixture()))
+	defer server.Close()
+
+	// Act
+	resp, err := http.Get(server.URL + "/machines/" + contractMachineID)
+	require.NoError(t, err)
+	defer resp.Body.Close()
+
+	var payload struct {
+		Data struct {
+			MachineProducts []json.RawMessage `json:"machineProducts"`
+		} `json:"data"`
+	}
+	require.NoError(t, json.NewDecoder(resp.Body).Decode(&payload))
+
+	// Assert
+	productType := reflect.TypeOf(foodji.Product{})
+	observed := map[string]bool{}
+	for _, raw := range payload.Data.MachineProducts {
+		observeFields(productType, raw, "", observed)
+	}
+	for _, field := range jsonFields(productType, "") {
+		if !localFields[field] {
+			assert.True(t, observed[field], "no fake product has %q", field)
+		}
+	}
+}
+
+// jsonFields lists the JSON paths of every field a type decodes, descending into nested
+// structs; slice elements are written as [] and map values as {}, e.g. slots[].number
+func jsonFields(t reflect.Type, prefix string) []string {
+	var fields []string
+	switch t.Kind() {
+	case reflect.Pointer:
+		return jsonFields(t.Elem(), prefix)
+	case reflect.Slice:
+		return jsonFields(t.Elem(), prefix+"[]")
+	case reflect.Map:
+		return jsonFields(t.Elem(), prefix+"{}")
+	case reflect.Struct:
+		for i := 0; i < t.NumField(); i++ {
+			name := jsonName(t.Field(i))
+			if name == "" {
+				continue
+			}
+			path := joinPath(prefix, name)
+			fields = append(fields, path)
+			fields = append(fields, jsonFields(t.Field(i).Type, path)...)
+		}
+	}
+	return fields
+}
+
+// observeFields records the JSON paths of the fields of a type that are present in raw
+func observeFields(t reflect.Type, raw json.RawMessage, prefix string, observed map[string]bool) {
+	switch t.Kind() {
+	case reflect.Pointer:
+		observeFields(t.Elem(), raw, prefix, observed)
+	case reflect.Slice:
+		var items []json.RawMessage
+		if json.Unmarshal(raw, &items) == nil {
+			for _, item := range items {
+				observeFields(t.Elem(), item, prefix+"[]", observed)
+			}
+		}
+	case reflect.Map:
+		var values map[string]json.RawMessage
+		if json.Unmarshal(raw, &values) == nil {
+			for _, value := range values {
+				observeFields(t.Elem(), value, prefix+"{}", observed)
+			}
+		}
+	case reflect.Struct:
+		var object map[string]json.RawMessage
+		if json.Unmarshal(raw, &object) != nil {
+			return
+		}
+		for i := 0; i < t.NumField(); i++ {
+			name := jsonName(t.Field(i))
+			value, ok := object[name]
+			if name == "" || !ok {
+				continue
+			}
+			path := joinPath(prefix, name)
+			observed[path] = true
+			observeFields(t.Field(i).Type, value, path, observed)
+		}
+	}
+}
+
+func jsonName(field reflect.StructField) string {
+	name, _, _ := strings.Cut(field.Tag.Get("json"), ",")
+	if name == "-" || !field.IsExported() {
+		return ""
+	}
+	if name == "" {
+		return field.Name
+	}
+	return name
+}
+
+func joinPath(prefix, name string) string {
+	if prefix == "" {
+		return name
+	}
+	return prefix + "." + name
+}
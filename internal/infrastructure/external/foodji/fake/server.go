@@ -0,0 +1,235 @@
+// Package fake serves a Foodji-compatible API from JSON fixtures for offline development and tests.
+// It can inject latency, error responses and malformed payloads.
+package fake
+
+import (
+	"crypto/sha256"
+	"embed"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"math/rand"
+	"net/http"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/ArtemSind/food_tinder/internal/infrastructure/external/foodji"
+)
+
+//go:embed fixtures/default.json
+var fixtures embed.FS
+
+// Fixture lists the products of every machine the fake serves
+type Fixture struct {
+	Machines map[string][]foodji.Product `json:"machines"`
+}
+
+// DefaultFixture returns the built-in fixture, which serves the machine used by the product repository
+func DefaultFixture() *Fixture {
+	data, err := fixtures.ReadFile("fixtures/default.json")
+	if err != nil {
+		panic(fmt.Sprintf("fake foodji: missing default fixture: %v", err))
+	}
+
+	var fixture Fixture
+	if err := json.Unmarshal(data, &fixture); err != nil {
+		panic(fmt.Sprintf("fake foodji: invalid default fixture: %v", err))
+	}
+	return &fixture
+}
+
+// LoadFixture reads a fixture from a JSON file
+func LoadFixture(path string) (*Fixture, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	return ParseFixture(file)
+}
+
+// ParseFixture decodes a fixture from JSON
+func ParseFixture(r io.Reader) (*Fixture, error) {
+	var fixture Fixture
+	if err := json.NewDecoder(r).Decode(&fixture); err != nil {
+		return nil, fmt.Errorf("error decoding fixture: %w", err)
+	}
+	if fixture.Machines == nil {
+		fixture.Machines = map[string][]foodji.Product{}
+	}
+	return &fixture, nil
+}
+
+// Server is an http.Handler that mimics GET /machines/{machineID} of the Foodji API.
+// Responses carry an ETag and Last-Modified and honour conditional requests.
+type Server struct {
+	mu           sync.Mutex
+	machines     map[string][]json.RawMessage
+	lastModified time.Time
+	latency      time.Duration
+	errorRate    float64
+	errorStatus  int
+	failures     []int
+	malformed    bool
+	requests     int
+	rng          *rand.Rand
+}
+
+// NewServer creates a fake serving the machines of the fixture
+func NewServer(fixture *Fixture) *Server {
+	s := &Server{
+		machines:     map[string][]json.RawMessage{},
+		lastModified: time.Now().UTC().Truncate(time.Second),
+		errorStatus:  http.StatusInternalServerError,
+		rng:          rand.New(rand.NewSource(time.Now().UnixNano())),
+	}
+	for machineID, products := range fixture.Machines {
+		s.machines[machineID] = encodeProducts(products)
+	}
+	return s
+}
+
+// SetProducts replaces the products of a machine and bumps its Last-Modified
+func (s *Server) SetProducts(machineID string, products []foodji.Product) {
+	s.SetRawProducts(machineID, encodeProducts(products))
+}
+
+// SetRawProducts replaces the products of a machine with arbitrary JSON, to serve invalid
+// products; nil serves a payload without a machineProducts array
+func (s *Server) SetRawProducts(machineID string, products []json.RawMessage) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.machines[machineID] = products
+	s.lastModified = s.lastModified.Add(time.Second)
+	if now := time.Now().UTC().Truncate(time.Second); now.After(s.lastModified) {
+		s.lastModified = now
+	}
+}
+
+// SetLatency delays every response
+func (s *Server) SetLatency(latency time.Duration) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.latency = latency
+}
+
+// SetErrorRate makes a random share of requests, between 0 and 1, fail with the status
+func (s *Server) SetErrorRate(rate float64, status int) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.errorRate = rate
+	s.errorStatus = status
+}
+
+// FailNext makes the next requests fail with the given statuses, in order
+func (s *Server) FailNext(statuses ...int) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.failures = append(s.failures, statuses...)
+}
+
+// SetMalformed makes successful responses return a truncated JSON payload
+func (s *Server) SetMalformed(malformed bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.malformed = malformed
+}
+
+// Requests returns the number of requests served so far
+func (s *Server) Requests() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.requests
+}
+
+func (s *Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	s.requests++
+	latency := s.latency
+	status := 0
+	if len(s.failures) > 0 {
+		status, s.failures = s.failures[0], s.failures[1:]
+	} else if s.errorRate > 0 && s.rng.Float64() < s.errorRate {
+		status = s.errorStatus
+	}
+	machineID, found := strings.CutPrefix(r.URL.Path, "/machines/")
+	products, known := s.machines[machineID]
+	lastModified := s.lastModified
+	malformed := s.malformed
+	s.mu.Unlock()
+
+	if latency > 0 {
+		select {
+		case <-time.After(latency):
+		case <-r.Context().Done():
+			return
+		}
+	}
+
+	if status != 0 {
+		http.Error(w, http.StatusText(status), status)
+		return
+	}
+	if r.Method != http.MethodGet || !found {
+		http.NotFound(w, r)
+		return
+	}
+	if !known {
+		http.Error(w, `{"error":"machine not found"}`, http.StatusNotFound)
+		return
+	}
+
+	var payload struct {
+		Data struct {
+			MachineProducts []json.RawMessage `json:"machineProducts"`
+		} `json:"data"`
+	}
+	payload.Data.MachineProducts = products
+
+	body, err := json.Marshal(payload)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	sum := sha256.Sum256(body)
+	etag := `"` + hex.EncodeToString(sum[:8]) + `"`
+	w.Header().Set("ETag", etag)
+	w.Header().Set("Last-Modified", lastModified.Format(http.TimeFormat))
+
+	if notModified(r, etag, lastModified) {
+		w.WriteHeader(http.StatusNotModified)
+		return
+	}
+
+	if malformed {
+		body = body[:len(body)/2]
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.Write(body)
+}
+
+// notModified applies If-None-Match, which takes precedence, then If-Modified-Since
+func notModified(r *http.Request, etag string, lastModified time.Time) bool {
+	if match := r.Header.Get("If-None-Match"); match != "" {
+		return match == etag
+	}
+	if since, err := http.ParseTime(r.Header.Get("If-Modified-Since")); err == nil {
+		return !lastModified.After(since)
+	}
+	return false
+}
+
+func encodeProducts(products []foodji.Product) []json.RawMessage {
+	encoded := make([]json.RawMessage, len(products))
+	for i, product := range products {
+		encoded[i], _ = json.Marshal(product)
+	}
+	return encoded
+}
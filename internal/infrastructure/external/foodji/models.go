@@ -12,6 +12,72 @@ type Machine struct {
 
 // Product represents a product in the machine
 type Product struct {
-	ID uuid.UUID `json:"id"`
-	// Including only ID as per the sample, but can be expanded with more fields as needed
+	ID       uuid.UUID `json:"id"`
+	Name     string    `json:"name,omitempty"`
+	Category string    `json:"category,omitempty"`
+	Price    float64   `json:"price,omitempty"`
+	// Allergens are nil if the source does not report them and empty if the product contains none
+	Allergens []string `json:"allergens"`
+	// Diets are the dietary labels the product meets, such as vegan or vegetarian
+	Diets    []string `json:"diets,omitempty"`
+	ImageURL string   `json:"imageUrl,omitempty"`
+
+	// MachineID is the machine the product is sold from; empty for products from other sources
+	MachineID string `json:"machineId,omitempty"`
+	// Origin is the name of the catalog source the product was imported from
+	Origin string `json:"origin,omitempty"`
+	// Slots are the machine slots stocked with the product; empty if the source does not report stock
+	Slots []Slot `json:"slots,omitempty"`
+	// Translations holds the texts of the product in other languages, keyed by language code
+	Translations map[string]ProductText `json:"translations,omitempty"`
+}
+
+// ProductText is the name and category of a product in one language
+type ProductText struct {
+	Name     string `json:"name,omitempty"`
+	Category string `json:"category,omitempty"`
+}
+
+// Localized returns the product with its name and category in the language. Texts without a
+// translation stay in the tenant's default language.
+func (p Product) Localized(language string) Product {
+	text, ok := p.Translations[language]
+	if !ok {
+		return p
+	}
+	if text.Name != "" {
+		p.Name = text.Name
+	}
+	if text.Category != "" {
+		p.Category = text.Category
+	}
+	return p
+}
+
+// Slot is a machine slot and the units of the product left in it
+type Slot struct {
+	Number   int `json:"number"`
+	Quantity int `json:"quantity"`
+}
+
+// Stock returns the units left over all slots, and false if the source does not report stock
+func (p Product) Stock() (int, bool) {
+	if len(p.Slots) == 0 {
+		return 0, false
+	}
+
+	stock := 0
+	for _, slot := range p.Slots {
+		if slot.Quantity > 0 {
+			stock += slot.Quantity
+		}
+	}
+	return stock, true
+}
+
+// SoldOut reports whether the product is known to have no units left. Products with
+// unknown stock are not sold out.
+func (p Product) SoldOut() bool {
+	stock, known := p.Stock()
+	return known && stock == 0
 }
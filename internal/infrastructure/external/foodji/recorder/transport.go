@@ -0,0 +1,178 @@
+// Package recorder provides an http.RoundTripper that records responses to golden files
+// and replays them, so API contract tests run offline against real payloads.
+package recorder
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// Mode selects whether the transport talks to the real API or to the recordings
+type Mode string
+
+const (
+	// ModeReplay serves responses from the golden files and never touches the network
+	ModeReplay Mode = "replay"
+	// ModeRecord forwards requests and overwrites the golden files with the responses
+	ModeRecord Mode = "record"
+)
+
+var ErrNoRecording = errors.New("no recording for request")
+
+// recordedHeaders are the response headers kept in recordings; the rest are volatile
+var recordedHeaders = []string{"Content-Type", "ETag", "Last-Modified"}
+
+// Recording is the golden file format of one request and its response
+type Recording struct {
+	// Host is the server the response was recorded from
+	Host    string            `json:"host,omitempty"`
+	Method  string            `json:"method"`
+	Path    string            `json:"path"`
+	Status  int               `json:"status"`
+	Headers map[string]string `json:"headers,omitempty"`
+	// Body holds JSON responses as JSON so recordings diff well; other bodies go to BodyText
+	Body     json.RawMessage `json:"body,omitempty"`
+	BodyText string          `json:"body_text,omitempty"`
+}
+
+// Transport records or replays HTTP exchanges in Dir, one golden file per method and path
+type Transport struct {
+	Mode Mode
+	Dir  string
+	// Next performs the real requests when recording; defaults to http.DefaultTransport
+	Next http.RoundTripper
+}
+
+// New creates a transport in the given mode
+func New(mode Mode, dir string) *Transport {
+	return &Transport{Mode: mode, Dir: dir}
+}
+
+func (t *Transport) RoundTrip(req *http.Request) (*http.Response, error) {
+	path := t.goldenPath(req)
+
+	if t.Mode != ModeRecord {
+		recording, err := Load(path)
+		if err != nil {
+			return nil, err
+		}
+		return recording.Response(req), nil
+	}
+
+	next := t.Next
+	if next == nil {
+		next = http.DefaultTransport
+	}
+
+	resp, err := next.RoundTrip(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("error reading response to record: %w", err)
+	}
+
+	recording := &Recording{
+		Host:    req.URL.Host,
+		Method:  req.Method,
+		Path:    req.URL.Path,
+		Status:  resp.StatusCode,
+		Headers: map[string]string{},
+	}
+	if json.Valid(body) {
+		recording.Body = body
+	} else {
+		recording.BodyText = string(body)
+	}
+	for _, header := range recordedHeaders {
+		if value := resp.Header.Get(header); value != "" {
+			recording.Headers[header] = value
+		}
+	}
+
+	if err := recording.Save(path); err != nil {
+		return nil, err
+	}
+	return recording.Response(req), nil
+}
+
+// goldenPath names the golden file after the method and path, e.g. GET_machines_<id>.json
+func (t *Transport) goldenPath(req *http.Request) string {
+	name := req.Method + "_" + strings.Trim(strings.ReplaceAll(req.URL.Path, "/", "_"), "_")
+	return filepath.Join(t.Dir, name+".json")
+}
+
+// Load reads a golden file
+func Load(path string) (*Recording, error) {
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, fmt.Errorf("%w: %s", ErrNoRecording, path)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var recording Recording
+	if err := json.Unmarshal(data, &recording); err != nil {
+		return nil, fmt.Errorf("error decoding recording %s: %w", path, err)
+	}
+	return &recording, nil
+}
+
+// Save writes the recording as an indented golden file
+func (r *Recording) Save(path string) error {
+	data, err := json.MarshalIndent(r, "", "  ")
+	if err != nil {
+		return fmt.Errorf("error encoding recording: %w", err)
+	}
+
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return err
+	}
+	return os.WriteFile(path, append(data, '\n'), 0o644)
+}
+
+// RawBody returns the response body; JSON bodies come back compacted
+func (r *Recording) RawBody() []byte {
+	if r.Body == nil {
+		return []byte(r.BodyText)
+	}
+
+	var compact bytes.Buffer
+	if err := json.Compact(&compact, r.Body); err != nil {
+		return r.Body
+	}
+	return compact.Bytes()
+}
+
+// Response builds the recorded response for a request
+func (r *Recording) Response(req *http.Request) *http.Response {
+	body := r.RawBody()
+
+	header := http.Header{}
+	for key, value := range r.Headers {
+		header.Set(key, value)
+	}
+
+	return &http.Response{
+		Status:        fmt.Sprintf("%d %s", r.Status, http.StatusText(r.Status)),
+		StatusCode:    r.Status,
+		Proto:         "HTTP/1.1",
+		ProtoMajor:    1,
+		ProtoMinor:    1,
+		Header:        header,
+		Body:          io.NopCloser(bytes.NewReader(body)),
+		ContentLength: int64(len(body)),
+		Request:       req,
+	}
+}
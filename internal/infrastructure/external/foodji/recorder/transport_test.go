@@ -0,0 +1,74 @@
+package recorder_test
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/ArtemSind/food_tinder/internal/infrastructure/external/foodji/recorder"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTransport_RecordThenReplay(t *testing.T) {
+	tests := []struct {
+		name        string
+		contentType string
+		body        string
+	}{
+		{name: "json body", contentType: "application/json", body: `{"data":{"machineProducts":[]}}`},
+		{name: "text body", contentType: "text/plain", body: "upstream unavailable"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Arrange
+			dir := t.TempDir()
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				w.Header().Set("Content-Type", tt.contentType)
+				w.Header().Set("ETag", `"abc"`)
+				w.Header().Set("Date", "volatile")
+				w.Write([]byte(tt.body))
+			}))
+			defer server.Close()
+
+			recording := &http.Client{Transport: recorder.New(recorder.ModeRecord, dir)}
+			replay := &http.Client{Transport: recorder.New(recorder.ModeReplay, dir)}
+
+			// Act
+			recorded, err := recording.Get(server.URL + "/machines/42")
+			require.NoError(t, err)
+			recordedBody, _ := io.ReadAll(recorded.Body)
+			server.Close()
+
+			replayed, err := replay.Get(server.URL + "/machines/42")
+			require.NoError(t, err)
+			replayedBody, _ := io.ReadAll(replayed.Body)
+
+			// Assert
+			assert.Equal(t, tt.body, string(recordedBody))
+			assert.Equal(t, tt.body, string(replayedBody))
+			assert.Equal(t, http.StatusOK, replayed.StatusCode)
+			assert.Equal(t, `"abc"`, replayed.Header.Get("ETag"))
+			assert.Empty(t, replayed.Header.Get("Date"), "volatile headers are not recorded")
+
+			saved, err := recorder.Load(filepath.Join(dir, "GET_machines_42.json"))
+			require.NoError(t, err)
+			assert.Equal(t, strings.TrimPrefix(server.URL, "http://"), saved.Host)
+		})
+	}
+}
+
+func TestTransport_ReplayWithoutRecording(t *testing.T) {
+	// Arrange
+	client := &http.Client{Transport: recorder.New(recorder.ModeReplay, t.TempDir())}
+
+	// Act
+	_, err := client.Get("http://foodji.invalid/machines/unknown")
+
+	// Assert
+	assert.ErrorIs(t, err, recorder.ErrNoRecording)
+}
@@ -0,0 +1,98 @@
+package foodji
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"math/rand"
+	"net"
+	"net/http"
+	"syscall"
+	"time"
+)
+
+const (
+	// DefaultMaxAttempts is the number of attempts made for a request, including the first one
+	DefaultMaxAttempts = 4
+	// DefaultBaseDelay is the backoff before the first retry
+	DefaultBaseDelay = 500 * time.Millisecond
+	// DefaultMaxDelay caps the backoff between retries
+	DefaultMaxDelay = 10 * time.Second
+)
+
+// RetryPolicy controls how transient failures are retried
+type RetryPolicy struct {
+	MaxAttempts int
+	BaseDelay   time.Duration
+	MaxDelay    time.Duration
+}
+
+// DefaultRetryPolicy returns the retry policy used by NewClient
+func DefaultRetryPolicy() RetryPolicy {
+	return RetryPolicy{
+		MaxAttempts: DefaultMaxAttempts,
+		BaseDelay:   DefaultBaseDelay,
+		MaxDelay:    DefaultMaxDelay,
+	}
+}
+
+// Backoff returns the delay before the given retry (1 for the first retry).
+// It uses full jitter: a random delay up to the exponential bound, so that
+// many clients recovering at once do not retry in lockstep.
+func (p RetryPolicy) Backoff(retry int) time.Duration {
+	bound := p.BaseDelay
+	for i := 1; i < retry && bound < p.MaxDelay; i++ {
+		bound *= 2
+	}
+	if bound > p.MaxDelay {
+		bound = p.MaxDelay
+	}
+	if bound <= 0 {
+		return 0
+	}
+	return time.Duration(rand.Int63n(int64(bound) + 1))
+}
+
+// StatusError is returned when Foodji answers with a non-200 status
+type StatusError struct {
+	StatusCode int
+	Body       string
+}
+
+func (e *StatusError) Error() string {
+	return fmt.Sprintf("unexpected status code: %d, body: %s", e.StatusCode, e.Body)
+}
+
+// IsTransient reports whether a failed request is worth retrying:
+// 5xx and 429 responses, timeouts and dropped connections
+func IsTransient(err error) bool {
+	var statusErr *StatusError
+	if errors.As(err, &statusErr) {
+		return statusErr.StatusCode >= http.StatusInternalServerError ||
+			statusErr.StatusCode == http.StatusTooManyRequests
+	}
+
+	var netErr net.Error
+	if errors.As(err, &netErr) && netErr.Timeout() {
+		return true
+	}
+
+	return errors.Is(err, syscall.ECONNRESET) ||
+		errors.Is(err, syscall.ECONNREFUSED) ||
+		errors.Is(err, io.ErrUnexpectedEOF) ||
+		errors.Is(err, io.EOF)
+}
+
+// sleep waits for the delay unless the context is done first
+func sleep(ctx context.Context, delay time.Duration) error {
+	timer := time.NewTimer(delay)
+	defer timer.Stop()
+
+	select {
+	case <-timer.C:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
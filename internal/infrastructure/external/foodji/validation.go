@@ -0,0 +1,144 @@
+package foodji
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+
+	"github.com/google/uuid"
+)
+
+// ValidationMode decides what happens to a payload with invalid products
+type ValidationMode string
+
+const (
+	// ValidationLenient skips invalid products and reports them
+	ValidationLenient ValidationMode = "lenient"
+	// ValidationStrict rejects the whole payload if any product is invalid
+	ValidationStrict ValidationMode = "strict"
+)
+
+var ErrInvalidPayload = errors.New("invalid foodji payload")
+
+// Rejection describes a product that failed validation
+type Rejection struct {
+	Index     int    `json:"index"`
+	ProductID string `json:"product_id,omitempty"`
+	Reason    string `json:"reason"`
+}
+
+// ValidationError is returned in strict mode when products were rejected
+type ValidationError struct {
+	Rejections []Rejection
+}
+
+func (e *ValidationError) Error() string {
+	return fmt.Sprintf("%v: %d products rejected, first: %s", ErrInvalidPayload, len(e.Rejections), e.Rejections[0].Reason)
+}
+
+func (e *ValidationError) Unwrap() error {
+	return ErrInvalidPayload
+}
+
+// DecodeMachineProducts decodes and validates a machine payload. The machineProducts array
+// must be present; products that cannot be decoded, have no ID or repeat an ID are rejected.
+func DecodeMachineProducts(body []byte) ([]Product, []Rejection, error) {
+	items, err := machineProductItems(body)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	products, rejections := DecodeProducts(items)
+	return products, rejections, nil
+}
+
+// machineProductItems returns the undecoded products of a machine payload
+func machineProductItems(body []byte) ([]json.RawMessage, error) {
+	var payload struct {
+		Data *struct {
+			MachineProducts *[]json.RawMessage `json:"machineProducts"`
+		} `json:"data"`
+	}
+	if err := json.Unmarshal(body, &payload); err != nil {
+		return nil, fmt.Errorf("error decoding response: %w", err)
+	}
+	if payload.Data == nil || payload.Data.MachineProducts == nil {
+		return nil, fmt.Errorf("%w: missing data.machineProducts", ErrInvalidPayload)
+	}
+	return *payload.Data.MachineProducts, nil
+}
+
+// MissingFields lists the fields Product decodes that no product in items has. Decoding
+// leaves a field Foodji renamed or dropped at its zero value without an error, which reads
+// as no allergens, no price or sold out, so a field missing from every product is reported.
+// MachineID and Origin are set by the catalog sources and never expected.
+func MissingFields(items []json.RawMessage) []string {
+	if len(items) == 0 {
+		return nil
+	}
+
+	present := make(map[string]bool)
+	for _, raw := range items {
+		var object map[string]json.RawMessage
+		if json.Unmarshal(raw, &object) != nil {
+			continue
+		}
+		for name := range object {
+			present[name] = true
+		}
+	}
+
+	var missing []string
+	productType := reflect.TypeOf(Product{})
+	for i := 0; i < productType.NumField(); i++ {
+		name, _, _ := strings.Cut(productType.Field(i).Tag.Get("json"), ",")
+		if name == "" || name == "-" || name == "machineId" || name == "origin" {
+			continue
+		}
+		if !present[name] {
+			missing = append(missing, name)
+		}
+	}
+	return missing
+}
+
+// DecodeProducts decodes a list of products, rejecting products that cannot be decoded,
+// have no ID or repeat an ID
+func DecodeProducts(items []json.RawMessage) ([]Product, []Rejection) {
+	products := make([]Product, 0, len(items))
+	var rejections []Rejection
+	seen := make(map[uuid.UUID]bool, len(items))
+
+	for i, raw := range items {
+		var product Product
+		if err := json.Unmarshal(raw, &product); err != nil {
+			rejections = append(rejections, Rejection{Index: i, ProductID: rawProductID(raw), Reason: "malformed product: " + err.Error()})
+			continue
+		}
+
+		switch {
+		case product.ID == uuid.Nil:
+			rejections = append(rejections, Rejection{Index: i, Reason: "missing product id"})
+		case seen[product.ID]:
+			rejections = append(rejections, Rejection{Index: i, ProductID: product.ID.String(), Reason: "duplicate product id"})
+		default:
+			seen[product.ID] = true
+			products = append(products, product)
+		}
+	}
+
+	return products, rejections
+}
+
+// rawProductID extracts the id of a product that failed to decode, for reporting
+func rawProductID(raw json.RawMessage) string {
+	var product struct {
+		ID any `json:"id"`
+	}
+	if err := json.Unmarshal(raw, &product); err != nil || product.ID == nil {
+		return ""
+	}
+	return fmt.Sprint(product.ID)
+}
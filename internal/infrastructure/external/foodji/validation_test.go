@@ -0,0 +1,139 @@
+package foodji_test
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/ArtemSind/food_tinder/internal/infrastructure/external/foodji"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDecodeMachineProducts(t *testing.T) {
+	const valid = `{"id":"0b0ec0d4-4d8c-4b0c-9c25-f3b5f7a1e6a1"}`
+
+	tests := []struct {
+		name         string
+		body         string
+		wantProducts int
+		wantReasons  []string
+		wantErr      error
+	}{
+		{name: "valid", body: `{"data":{"machineProducts":[` + valid + `]}}`, wantProducts: 1},
+		{name: "empty", body: `{"data":{"machineProducts":[]}}`},
+		{
+			name:         "invalid products are skipped",
+			body:         `{"data":{"machineProducts":[` + valid + `,{"id":"not-a-uuid"},{},` + valid + `]}}`,
+			wantProducts: 1,
+			wantReasons:  []string{"malformed product", "missing product id", "duplicate product id"},
+		},
+		{name: "missing array", body: `{"data":{}}`, wantErr: foodji.ErrInvalidPayload},
+		{name: "missing data", body: `{"machineProducts":[]}`, wantErr: foodji.ErrInvalidPayload},
+		{name: "null array", body: `{"data":{"machineProducts":null}}`, wantErr: foodji.ErrInvalidPayload},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Act
+			products, rejections, err := foodji.DecodeMachineProducts([]byte(tt.body))
+
+			// Assert
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+				return
+			}
+			require.NoError(t, err)
+			assert.Len(t, products, tt.wantProducts)
+			require.Len(t, rejections, len(tt.wantReasons))
+			for i, reason := range tt.wantReasons {
+				assert.Contains(t, rejections[i].Reason, reason)
+			}
+		})
+	}
+}
+
+func TestMissingFields(t *testing.T) {
+	tests := []struct {
+		name  string
+		items []string
+		want  []string
+	}{
+		{name: "no products", want: nil},
+		{
+			name:  "every field but the local ones",
+			items: []string{`{"id":"a","name":"Pizza","category":"Mains","price":5,"allergens":[],"diets":[],"imageUrl":"","slots":[],"translations":{}}`},
+			want:  nil,
+		},
+		{
+			name: "a field of any product counts",
+			items: []string{
+				`{"id":"a","name":"Pizza","category":"Mains","allergens":null,"diets":[],"imageUrl":"","slots":[]}`,
+				`{"id":"b","price":5,"translations":{}}`,
+			},
+			want: nil,
+		},
+		{
+			name:  "renamed fields",
+			items: []string{`{"id":"a","name":"Pizza","category":"Mains","cost":5,"allergenList":[],"diets":[],"imageUrl":"","slots":[],"translations":{}}`},
+			want:  []string{"price", "allergens"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Arrange
+			items := make([]json.RawMessage, len(tt.items))
+			for i, item := range tt.items {
+				items[i] = json.RawMessage(item)
+			}
+
+			// Act
+			missing := foodji.MissingFields(items)
+
+			// Assert
+			assert.Equal(t, tt.want, missing)
+		})
+	}
+}
+
+func TestClient_FetchMachineProducts_Validation(t *testing.T) {
+	invalid := []json.RawMessage{
+		json.RawMessage(`{"id":"0b0ec0d4-4d8c-4b0c-9c25-f3b5f7a1e6a1"}`),
+		json.RawMessage(`{"id":"00000000-0000-0000-0000-000000000000"}`),
+	}
+
+	t.Run("lenient mode skips invalid products", func(t *testing.T) {
+		// Arrange
+		fakeServer, client := newFakeFoodji(t)
+		fakeServer.SetRawProducts(machineID, invalid)
+
+		// Act
+		result, err := client.FetchMachineProducts(context.Background(), machineID, foodji.Validators{})
+
+		// Assert
+		require.NoError(t, err)
+		assert.Len(t, result.Products, 1)
+		require.Len(t, result.Rejected, 1)
+		assert.Equal(t, 1, result.Rejected[0].Index)
+		assert.Contains(t, result.MissingFields, "name", "no product has a name")
+	})
+
+	t.Run("strict mode rejects the payload", func(t *testing.T) {
+		// Arrange
+		fakeServer, client := newFakeFoodji(t)
+		client.Validation = foodji.ValidationStrict
+		fakeServer.SetRawProducts(machineID, invalid)
+
+		// Act
+		result, err := client.FetchMachineProducts(context.Background(), machineID, foodji.Validators{})
+
+		// Assert
+		var validationErr *foodji.ValidationError
+		require.ErrorAs(t, err, &validationErr)
+		assert.ErrorIs(t, err, foodji.ErrInvalidPayload)
+		assert.Len(t, validationErr.Rejections, 1)
+		assert.Nil(t, result)
+		assert.Equal(t, 1, fakeServer.Requests(), "invalid payloads are not retried")
+	})
+}
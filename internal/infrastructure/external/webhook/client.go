@@ -0,0 +1,83 @@
+package webhook
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/ArtemSind/food_tinder/internal/domain"
+)
+
+const (
+	// DefaultTimeout is the default timeout for a single delivery attempt
+	DefaultTimeout = 10 * time.Second
+	// SignatureHeader carries the HMAC-SHA256 signature of the payload
+	SignatureHeader = "X-Food-Tinder-Signature"
+	// TimestampHeader carries the unix timestamp that is part of the signed message
+	TimestampHeader = "X-Food-Tinder-Timestamp"
+	// EventHeader carries the event type
+	EventHeader = "X-Food-Tinder-Event"
+	// DeliveryHeader carries the delivery ID, stable across retries
+	DeliveryHeader = "X-Food-Tinder-Delivery"
+)
+
+// Client sends signed webhook deliveries
+type Client struct {
+	HTTPClient *http.Client
+}
+
+// NewClient creates a new webhook client
+func NewClient() *Client {
+	return &Client{
+		HTTPClient: &http.Client{
+			Timeout: DefaultTimeout,
+		},
+	}
+}
+
+// Send posts the delivery payload to the subscription URL.
+// Any non-2xx response is reported as an error so the delivery is retried.
+func (c *Client) Send(ctx context.Context, delivery *domain.WebhookDelivery) error {
+	timestamp := strconv.FormatInt(time.Now().Unix(), 10)
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, delivery.URL, bytes.NewReader(delivery.Payload))
+	if err != nil {
+		return fmt.Errorf("error creating request: %w", err)
+	}
+
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set(EventHeader, delivery.EventType)
+	req.Header.Set(DeliveryHeader, delivery.ID.String())
+	req.Header.Set(TimestampHeader, timestamp)
+	req.Header.Set(SignatureHeader, Sign(delivery.Secret, timestamp, delivery.Payload))
+
+	resp, err := c.HTTPClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("error making request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		body, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+		return fmt.Errorf("unexpected status code: %d, body: %s", resp.StatusCode, string(body))
+	}
+
+	return nil
+}
+
+// Sign computes the signature header value for a payload.
+// The signed message is "<timestamp>.<payload>" so receivers can reject replays.
+func Sign(secret, timestamp string, payload []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(timestamp))
+	mac.Write([]byte("."))
+	mac.Write(payload)
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
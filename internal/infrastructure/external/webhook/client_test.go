@@ -0,0 +1,74 @@
+package webhook_test
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/ArtemSind/food_tinder/internal/domain"
+	"github.com/ArtemSind/food_tinder/internal/infrastructure/external/webhook"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestClient_Send(t *testing.T) {
+	subscription, _ := domain.NewWebhookSubscription("https://example.com", "s3cret", []string{domain.EventVoteCreated})
+	event, _ := domain.NewOutboxEvent(domain.EventVoteCreated, map[string]int{"score": 5})
+
+	t.Run("sends signed payload", func(t *testing.T) {
+		// Arrange
+		var received *http.Request
+		var body []byte
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			received = r
+			body, _ = io.ReadAll(r.Body)
+			w.WriteHeader(http.StatusNoContent)
+		}))
+		defer server.Close()
+
+		delivery := domain.NewWebhookDelivery(subscription, event)
+		delivery.URL = server.URL
+		delivery.Secret = subscription.Secret
+
+		// Act
+		err := webhook.NewClient().Send(context.Background(), delivery)
+
+		// Assert
+		require.NoError(t, err)
+		assert.JSONEq(t, string(event.Payload), string(body))
+		assert.Equal(t, domain.EventVoteCreated, received.Header.Get(webhook.EventHeader))
+		assert.Equal(t, delivery.ID.String(), received.Header.Get(webhook.DeliveryHeader))
+
+		timestamp := received.Header.Get(webhook.TimestampHeader)
+		assert.Equal(t, webhook.Sign("s3cret", timestamp, body), received.Header.Get(webhook.SignatureHeader))
+	})
+
+	t.Run("non-2xx response is an error", func(t *testing.T) {
+		// Arrange
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			json.NewEncoder(w).Encode(map[string]string{"error": "down"})
+		}))
+		defer server.Close()
+
+		delivery := domain.NewWebhookDelivery(subscription, event)
+		delivery.URL = server.URL
+
+		// Act
+		err := webhook.NewClient().Send(context.Background(), delivery)
+
+		// Assert
+		assert.Error(t, err)
+	})
+}
+
+func TestSign(t *testing.T) {
+	// Signature of "1700000000.{}" with key "key"
+	assert.Equal(t,
+		"sha256=9d713ed406bb7076d4123f0dc2c39d2df5c654ed4b0cd56b52c8b4c940bd63ae",
+		webhook.Sign("key", "1700000000", []byte("{}")),
+	)
+}
@@ -0,0 +1,195 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/ArtemSind/food_tinder/internal/domain"
+	httpModels "github.com/ArtemSind/food_tinder/internal/infrastructure/http/models"
+	"github.com/google/uuid"
+	"github.com/gorilla/mux"
+)
+
+type CatalogService interface {
+	GetValidation(ctx context.Context) (*domain.CatalogValidation, error)
+	RefreshCatalog(ctx context.Context, force bool) (bool, *domain.RefreshStatus, error)
+	GetRefreshStatus(ctx context.Context) (*domain.RefreshStatus, error)
+	ListSnapshots(ctx context.Context) ([]*domain.CatalogSnapshot, error)
+	RollbackCatalog(ctx context.Context, version int64) (*domain.CatalogSnapshot, error)
+	UnpinCatalog(ctx context.Context) error
+	GetProductCacheStats() *domain.ProductCacheStats
+	GetProductHistory(ctx context.Context, productID uuid.UUID) (*domain.CatalogProduct, []*domain.ProductEvent, error)
+	GetPriceHistory(ctx context.Context, productID uuid.UUID) ([]*domain.PricePoint, error)
+}
+
+type CatalogHandler struct {
+	catalogService CatalogService
+}
+
+func NewCatalogHandler(catalogService CatalogService) *CatalogHandler {
+	return &CatalogHandler{catalogService: catalogService}
+}
+
+func (h *CatalogHandler) GetValidation(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	validation, err := h.catalogService.GetValidation(ctx)
+	if err != nil {
+		if errors.Is(err, domain.ErrNoCatalogValidation) {
+			http.Error(w, err.Error(), http.StatusNotFound)
+			return
+		}
+		http.Error(w, "Failed to get catalog validation", http.StatusInternalServerError)
+		return
+	}
+
+	response := httpModels.CatalogValidationResponseFromDomain(validation)
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(response)
+}
+
+// RefreshCatalog starts a catalog refresh; a refresh that is already running is not duplicated.
+// With force=true the refresh replaces the catalog even if it shrinks too much.
+func (h *CatalogHandler) RefreshCatalog(w http.ResponseWriter, r *http.Request) {
+	force := false
+	if value := r.URL.Query().Get("force"); value != "" {
+		var err error
+		if force, err = strconv.ParseBool(value); err != nil {
+			http.Error(w, "Invalid force flag", http.StatusBadRequest)
+			return
+		}
+	}
+
+	ctx := r.Context()
+	started, status, err := h.catalogService.RefreshCatalog(ctx, force)
+	if err != nil {
+		http.Error(w, "Failed to refresh catalog", http.StatusInternalServerError)
+		return
+	}
+
+	response := httpModels.RefreshCatalogResponseFromDomain(started, status)
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusAccepted)
+	json.NewEncoder(w).Encode(response)
+}
+
+func (h *CatalogHandler) GetRefreshStatus(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	status, err := h.catalogService.GetRefreshStatus(ctx)
+	if err != nil {
+		http.Error(w, "Failed to get refresh status", http.StatusInternalServerError)
+		return
+	}
+
+	response := httpModels.RefreshStatusResponseFromDomain(status)
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(response)
+}
+
+// GetProductCacheStats reports the in-memory product cache of the replica serving the request
+func (h *CatalogHandler) GetProductCacheStats(w http.ResponseWriter, r *http.Request) {
+	response := httpModels.ProductCacheStatsResponseFromDomain(h.catalogService.GetProductCacheStats())
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(response)
+}
+
+func (h *CatalogHandler) ListSnapshots(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	snapshots, err := h.catalogService.ListSnapshots(ctx)
+	if err != nil {
+		http.Error(w, "Failed to list catalog snapshots", http.StatusInternalServerError)
+		return
+	}
+
+	response := httpModels.CatalogSnapshotListResponseFromDomain(snapshots)
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(response)
+}
+
+func (h *CatalogHandler) RollbackCatalog(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	version, err := strconv.ParseInt(vars["version"], 10, 64)
+	if err != nil || version <= 0 {
+		http.Error(w, "Invalid snapshot version", http.StatusBadRequest)
+		return
+	}
+
+	ctx := r.Context()
+	snapshot, err := h.catalogService.RollbackCatalog(ctx, version)
+	if err != nil {
+		switch {
+		case errors.Is(err, domain.ErrSnapshotNotFound):
+			http.Error(w, err.Error(), http.StatusNotFound)
+		case errors.Is(err, domain.ErrRefreshInProgress):
+			http.Error(w, err.Error(), http.StatusConflict)
+		default:
+			http.Error(w, "Failed to roll back catalog", http.StatusInternalServerError)
+		}
+		return
+	}
+
+	response := httpModels.CatalogSnapshotResponseFromDomain(snapshot)
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(response)
+}
+
+// UnpinCatalog lets refreshes replace the snapshot the catalog was rolled back to
+func (h *CatalogHandler) UnpinCatalog(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	if err := h.catalogService.UnpinCatalog(ctx); err != nil {
+		http.Error(w, "Failed to unpin catalog", http.StatusInternalServerError)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (h *CatalogHandler) GetProductHistory(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	productID, err := uuid.Parse(vars["productID"])
+	if err != nil {
+		http.Error(w, "Invalid product ID", http.StatusBadRequest)
+		return
+	}
+
+	ctx := r.Context()
+	product, events, err := h.catalogService.GetProductHistory(ctx, productID)
+	if err != nil {
+		if errors.Is(err, domain.ErrProductNotFound) {
+			http.Error(w, err.Error(), http.StatusNotFound)
+			return
+		}
+		http.Error(w, "Failed to get product history", http.StatusInternalServerError)
+		return
+	}
+
+	response := httpModels.ProductHistoryResponseFromDomain(product, events)
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(response)
+}
+
+// GetPriceHistory returns the prices of a product, oldest first, with the votes cast at each price
+func (h *CatalogHandler) GetPriceHistory(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	productID, err := uuid.Parse(vars["productID"])
+	if err != nil {
+		http.Error(w, "Invalid product ID", http.StatusBadRequest)
+		return
+	}
+
+	ctx := r.Context()
+	prices, err := h.catalogService.GetPriceHistory(ctx, productID)
+	if err != nil {
+		if errors.Is(err, domain.ErrProductNotFound) {
+			http.Error(w, err.Error(), http.StatusNotFound)
+			return
+		}
+		http.Error(w, "Failed to get price history", http.StatusInternalServerError)
+		return
+	}
+
+	response := httpModels.PriceHistoryResponseFromDomain(productID, prices)
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(response)
+}
@@ -0,0 +1,493 @@
+package handlers_test
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/ArtemSind/food_tinder/internal/domain"
+	"github.com/ArtemSind/food_tinder/internal/infrastructure/http/handlers"
+	"github.com/ArtemSind/food_tinder/internal/infrastructure/http/models"
+	"github.com/google/uuid"
+	"github.com/gorilla/mux"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+// Mock CatalogService
+type MockCatalogService struct {
+	mock.Mock
+}
+
+func (m *MockCatalogService) GetValidation(ctx context.Context) (*domain.CatalogValidation, error) {
+	args := m.Called(ctx)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*domain.CatalogValidation), args.Error(1)
+}
+
+func (m *MockCatalogService) RefreshCatalog(ctx context.Context, force bool) (bool, *domain.RefreshStatus, error) {
+	args := m.Called(ctx, force)
+	if args.Get(1) == nil {
+		return args.Bool(0), nil, args.Error(2)
+	}
+	return args.Bool(0), args.Get(1).(*domain.RefreshStatus), args.Error(2)
+}
+
+func (m *MockCatalogService) GetRefreshStatus(ctx context.Context) (*domain.RefreshStatus, error) {
+	args := m.Called(ctx)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*domain.RefreshStatus), args.Error(1)
+}
+
+func (m *MockCatalogService) GetProductCacheStats() *domain.ProductCacheStats {
+	args := m.Called()
+	return args.Get(0).(*domain.ProductCacheStats)
+}
+
+func (m *MockCatalogService) ListSnapshots(ctx context.Context) ([]*domain.CatalogSnapshot, error) {
+	args := m.Called(ctx)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*domain.CatalogSnapshot), args.Error(1)
+}
+
+func (m *MockCatalogService) UnpinCatalog(ctx context.Context) error {
+	return m.Called(ctx).Error(0)
+}
+
+func (m *MockCatalogService) RollbackCatalog(ctx context.Context, version int64) (*domain.CatalogSnapshot, error) {
+	args := m.Called(ctx, version)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*domain.CatalogSnapshot), args.Error(1)
+}
+
+func (m *MockCatalogService) GetProductHistory(ctx context.Context, productID uuid.UUID) (*domain.CatalogProduct, []*domain.ProductEvent, error) {
+	args := m.Called(ctx, productID)
+	if args.Get(0) == nil {
+		return nil, nil, args.Error(2)
+	}
+	return args.Get(0).(*domain.CatalogProduct), args.Get(1).([]*domain.ProductEvent), args.Error(2)
+}
+
+func (m *MockCatalogService) GetPriceHistory(ctx context.Context, productID uuid.UUID) ([]*domain.PricePoint, error) {
+	args := m.Called(ctx, productID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*domain.PricePoint), args.Error(1)
+}
+
+func TestCatalogHandler_GetValidation(t *testing.T) {
+	// Arrange
+	mockService := new(MockCatalogService)
+	handler := handlers.NewCatalogHandler(mockService)
+
+	t.Run("successful retrieval", func(t *testing.T) {
+		// Arrange
+		validation := domain.NewCatalogValidation(10, []domain.ProductRejection{{Index: 3, Reason: "missing product id"}})
+		validation.MissingFields = []string{"allergens"}
+		mockService.On("GetValidation", mock.Anything).Return(validation, nil).Once()
+
+		req := httptest.NewRequest("GET", "/admin/products/validation", nil)
+		rec := httptest.NewRecorder()
+
+		// Act
+		handler.GetValidation(rec, req)
+
+		// Assert
+		require.Equal(t, http.StatusOK, rec.Code)
+
+		var response models.CatalogValidationResponse
+		err := json.NewDecoder(rec.Body).Decode(&response)
+		require.NoError(t, err)
+		assert.Equal(t, 10, response.Accepted)
+		assert.Equal(t, 1, response.Rejected)
+		assert.Equal(t, "missing product id", response.Rejections[0].Reason)
+		assert.Equal(t, []string{"allergens"}, response.MissingFields)
+
+		mockService.AssertExpectations(t)
+	})
+
+	t.Run("nothing validated yet", func(t *testing.T) {
+		// Arrange
+		mockService.On("GetValidation", mock.Anything).Return(nil, domain.ErrNoCatalogValidation).Once()
+
+		req := httptest.NewRequest("GET", "/admin/products/validation", nil)
+		rec := httptest.NewRecorder()
+
+		// Act
+		handler.GetValidation(rec, req)
+
+		// Assert
+		assert.Equal(t, http.StatusNotFound, rec.Code)
+		mockService.AssertExpectations(t)
+	})
+}
+
+func TestCatalogHandler_RefreshCatalog(t *testing.T) {
+	// Arrange
+	mockService := new(MockCatalogService)
+	handler := handlers.NewCatalogHandler(mockService)
+
+	t.Run("already running", func(t *testing.T) {
+		// Arrange
+		since := time.Now().Add(-time.Second)
+		mockService.On("RefreshCatalog", mock.Anything, false).
+			Return(false, &domain.RefreshStatus{Running: true, RunningSince: &since}, nil).Once()
+
+		req := httptest.NewRequest("POST", "/admin/products/refresh", nil)
+		rec := httptest.NewRecorder()
+
+		// Act
+		handler.RefreshCatalog(rec, req)
+
+		// Assert
+		require.Equal(t, http.StatusAccepted, rec.Code)
+
+		var response models.RefreshCatalogResponse
+		err := json.NewDecoder(rec.Body).Decode(&response)
+		require.NoError(t, err)
+		assert.False(t, response.Started)
+		assert.True(t, response.Status.Running)
+
+		mockService.AssertExpectations(t)
+	})
+
+	t.Run("forced", func(t *testing.T) {
+		// Arrange
+		mockService.On("RefreshCatalog", mock.Anything, true).
+			Return(true, &domain.RefreshStatus{Running: true}, nil).Once()
+
+		req := httptest.NewRequest("POST", "/admin/products/refresh?force=true", nil)
+		rec := httptest.NewRecorder()
+
+		// Act
+		handler.RefreshCatalog(rec, req)
+
+		// Assert
+		assert.Equal(t, http.StatusAccepted, rec.Code)
+		mockService.AssertExpectations(t)
+	})
+
+	t.Run("invalid force flag", func(t *testing.T) {
+		// Arrange
+		mockService := new(MockCatalogService)
+		handler := handlers.NewCatalogHandler(mockService)
+		req := httptest.NewRequest("POST", "/admin/products/refresh?force=maybe", nil)
+		rec := httptest.NewRecorder()
+
+		// Act
+		handler.RefreshCatalog(rec, req)
+
+		// Assert
+		assert.Equal(t, http.StatusBadRequest, rec.Code)
+		mockService.AssertNotCalled(t, "RefreshCatalog", mock.Anything, mock.Anything)
+	})
+}
+
+func TestCatalogHandler_GetProductCacheStats(t *testing.T) {
+	// Arrange
+	mockService := new(MockCatalogService)
+	handler := handlers.NewCatalogHandler(mockService)
+	mockService.On("GetProductCacheStats").Return(&domain.ProductCacheStats{
+		Enabled: true, Capacity: 1024, Entries: 8, Hits: 30, Misses: 10,
+	}).Once()
+
+	req := httptest.NewRequest("GET", "/admin/products/cache", nil)
+	rec := httptest.NewRecorder()
+
+	// Act
+	handler.GetProductCacheStats(rec, req)
+
+	// Assert
+	require.Equal(t, http.StatusOK, rec.Code)
+	assert.JSONEq(t, `{"enabled":true,"capacity":1024,"entries":8,"hits":30,"misses":10,"hit_ratio":0.75}`, rec.Body.String())
+	mockService.AssertExpectations(t)
+}
+
+func TestCatalogHandler_GetRefreshStatus(t *testing.T) {
+	// Arrange
+	mockService := new(MockCatalogService)
+	handler := handlers.NewCatalogHandler(mockService)
+
+	run := domain.NewRefreshRun(domain.RefreshManual, time.Now().Add(-1500*time.Millisecond), domain.RefreshSucceeded, 42, nil)
+	mockService.On("GetRefreshStatus", mock.Anything).Return(&domain.RefreshStatus{LastRun: run}, nil).Once()
+
+	req := httptest.NewRequest("GET", "/admin/products/refresh", nil)
+	rec := httptest.NewRecorder()
+
+	// Act
+	handler.GetRefreshStatus(rec, req)
+
+	// Assert
+	require.Equal(t, http.StatusOK, rec.Code)
+
+	var response models.RefreshStatusResponse
+	err := json.NewDecoder(rec.Body).Decode(&response)
+	require.NoError(t, err)
+	assert.False(t, response.Running)
+	require.NotNil(t, response.LastRun)
+	assert.Equal(t, "succeeded", response.LastRun.Outcome)
+	assert.Equal(t, "manual", response.LastRun.Trigger)
+	assert.Equal(t, 42, response.LastRun.ProductCount)
+	assert.GreaterOrEqual(t, response.LastRun.DurationMs, int64(1500))
+
+	mockService.AssertExpectations(t)
+}
+
+func TestCatalogHandler_ListSnapshots(t *testing.T) {
+	// Arrange
+	mockService := new(MockCatalogService)
+	handler := handlers.NewCatalogHandler(mockService)
+
+	snapshots := []*domain.CatalogSnapshot{
+		{Version: 2, CreatedAt: time.Now(), ProductCount: 9, Current: true},
+		{Version: 1, CreatedAt: time.Now().Add(-24 * time.Hour), ProductCount: 8},
+	}
+	mockService.On("ListSnapshots", mock.Anything).Return(snapshots, nil).Once()
+
+	req := httptest.NewRequest("GET", "/admin/products/snapshots", nil)
+	rec := httptest.NewRecorder()
+
+	// Act
+	handler.ListSnapshots(rec, req)
+
+	// Assert
+	require.Equal(t, http.StatusOK, rec.Code)
+
+	var response models.CatalogSnapshotListResponse
+	err := json.NewDecoder(rec.Body).Decode(&response)
+	require.NoError(t, err)
+	assert.Equal(t, 2, response.Count)
+	assert.Equal(t, int64(2), response.Snapshots[0].Version)
+	assert.True(t, response.Snapshots[0].Current)
+	assert.Equal(t, 8, response.Snapshots[1].ProductCount)
+
+	mockService.AssertExpectations(t)
+}
+
+func TestCatalogHandler_RollbackCatalog(t *testing.T) {
+	tests := []struct {
+		name           string
+		version        string
+		serviceErr     error
+		expectedStatus int
+	}{
+		{name: "rolled back", version: "1", expectedStatus: http.StatusOK},
+		{name: "invalid version", version: "latest", expectedStatus: http.StatusBadRequest},
+		{name: "unknown snapshot", version: "42", serviceErr: domain.ErrSnapshotNotFound, expectedStatus: http.StatusNotFound},
+		{name: "refresh running", version: "1", serviceErr: domain.ErrRefreshInProgress, expectedStatus: http.StatusConflict},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Arrange
+			mockService := new(MockCatalogService)
+			handler := handlers.NewCatalogHandler(mockService)
+
+			if tt.expectedStatus != http.StatusBadRequest {
+				var snapshot *domain.CatalogSnapshot
+				if tt.serviceErr == nil {
+					snapshot = &domain.CatalogSnapshot{Version: 1, ProductCount: 8, Current: true}
+				}
+				mockService.On("RollbackCatalog", mock.Anything, mock.AnythingOfType("int64")).Return(snapshot, tt.serviceErr).Once()
+			}
+
+			req := httptest.NewRequest("POST", "/admin/products/snapshots/"+tt.version+"/rollback", nil)
+			req = mux.SetURLVars(req, map[string]string{"version": tt.version})
+			rec := httptest.NewRecorder()
+
+			// Act
+			handler.RollbackCatalog(rec, req)
+
+			// Assert
+			assert.Equal(t, tt.expectedStatus, rec.Code)
+			if tt.expectedStatus == http.StatusOK {
+				var response models.CatalogSnapshotResponse
+				err := json.NewDecoder(rec.Body).Decode(&response)
+				require.NoError(t, err)
+				assert.Equal(t, int64(1), response.Version)
+				assert.True(t, response.Current)
+			}
+
+			mockService.AssertExpectations(t)
+		})
+	}
+}
+
+func TestCatalogHandler_GetProductHistory(t *testing.T) {
+	t.Run("discontinued product", func(t *testing.T) {
+		// Arrange
+		mockService := new(MockCatalogService)
+		handler := handlers.NewCatalogHandler(mockService)
+
+		productID := uuid.New()
+		discontinuedAt := time.Now()
+		product := domain.NewCatalogProduct(productID, json.RawMessage(`{}`), json.RawMessage(`{"id":"`+productID.String()+`"}`))
+		product.DiscontinuedAt = &discontinuedAt
+		events := []*domain.ProductEvent{
+			{ProductID: productID, Type: domain.ProductAdded, ContentHash: product.ContentHash},
+			{ProductID: productID, Type: domain.ProductRemoved, ContentHash: product.ContentHash},
+		}
+		mockService.On("GetProductHistory", mock.Anything, productID).Return(product, events, nil).Once()
+
+		req := httptest.NewRequest("GET", "/admin/products/"+productID.String()+"/history", nil)
+		req = mux.SetURLVars(req, map[string]string{"productID": productID.String()})
+		rec := httptest.NewRecorder()
+
+		// Act
+		handler.GetProductHistory(rec, req)
+
+		// Assert
+		require.Equal(t, http.StatusOK, rec.Code)
+
+		var response models.ProductHistoryResponse
+		err := json.NewDecoder(rec.Body).Decode(&response)
+		require.NoError(t, err)
+		assert.Equal(t, productID, response.Product.ID)
+		assert.True(t, response.Product.Discontinued)
+		assert.JSONEq(t, `{"id":"`+productID.String()+`"}`, string(response.Product.Data))
+		require.Len(t, response.Events, 2)
+		assert.Equal(t, "added", response.Events[0].Type)
+		assert.Equal(t, "removed", response.Events[1].Type)
+
+		mockService.AssertExpectations(t)
+	})
+
+	t.Run("unknown product", func(t *testing.T) {
+		// Arrange
+		mockService := new(MockCatalogService)
+		handler := handlers.NewCatalogHandler(mockService)
+
+		productID := uuid.New()
+		mockService.On("GetProductHistory", mock.Anything, productID).Return(nil, nil, domain.ErrProductNotFound).Once()
+
+		req := httptest.NewRequest("GET", "/admin/products/"+productID.String()+"/history", nil)
+		req = mux.SetURLVars(req, map[string]string{"productID": productID.String()})
+		rec := httptest.NewRecorder()
+
+		// Act
+		handler.GetProductHistory(rec, req)
+
+		// Assert
+		assert.Equal(t, http.StatusNotFound, rec.Code)
+		mockService.AssertExpectations(t)
+	})
+}
+
+func TestCatalogHandler_GetPriceHistory(t *testing.T) {
+	t.Run("prices with their votes", func(t *testing.T) {
+		// Arrange
+		mockService := new(MockCatalogService)
+		handler := handlers.NewCatalogHandler(mockService)
+
+		productID := uuid.New()
+		recordedAt := time.Now().Add(-48 * time.Hour).UTC()
+		prices := []*domain.PricePoint{
+			{ProductID: productID, Price: 7.5, Version: "old-hash", RecordedAt: recordedAt,
+				LastRecordedAt: recordedAt.Add(12 * time.Hour), AvgScore: 4.2, VoteCount: 5},
+			{ProductID: productID, Price: 8.9, Version: "new-hash", RecordedAt: recordedAt.Add(24 * time.Hour), AvgScore: 3.1, VoteCount: 2},
+		}
+		mockService.On("GetPriceHistory", mock.Anything, productID).Return(prices, nil).Once()
+
+		req := httptest.NewRequest("GET", "/api/products/"+productID.String()+"/prices", nil)
+		req = mux.SetURLVars(req, map[string]string{"productID": productID.String()})
+		rec := httptest.NewRecorder()
+
+		// Act
+		handler.GetPriceHistory(rec, req)
+
+		// Assert
+		require.Equal(t, http.StatusOK, rec.Code)
+
+		var response models.PriceHistoryResponse
+		err := json.NewDecoder(rec.Body).Decode(&response)
+		require.NoError(t, err)
+		assert.Equal(t, productID, response.ProductID)
+		require.Equal(t, 2, response.Count)
+		assert.Equal(t, 7.5, response.Prices[0].Price)
+		assert.Equal(t, "old-hash", response.Prices[0].Version)
+		assert.True(t, recordedAt.Equal(response.Prices[0].RecordedAt))
+		assert.True(t, recordedAt.Add(12*time.Hour).Equal(response.Prices[0].LastRecordedAt))
+		assert.Equal(t, 8.9, response.Prices[1].Price)
+		assert.Equal(t, 2, response.Prices[1].VoteCount)
+
+		mockService.AssertExpectations(t)
+	})
+
+	t.Run("unknown product", func(t *testing.T) {
+		// Arrange
+		mockService := new(MockCatalogService)
+		handler := handlers.NewCatalogHandler(mockService)
+
+		productID := uuid.New()
+		mockService.On("GetPriceHistory", mock.Anything, productID).Return(nil, domain.ErrProductNotFound).Once()
+
+		req := httptest.NewRequest("GET", "/api/products/"+productID.String()+"/prices", nil)
+		req = mux.SetURLVars(req, map[string]string{"productID": productID.String()})
+		rec := httptest.NewRecorder()
+
+		// Act
+		handler.GetPriceHistory(rec, req)
+
+		// Assert
+		assert.Equal(t, http.StatusNotFound, rec.Code)
+		mockService.AssertExpectations(t)
+	})
+
+	t.Run("invalid product ID", func(t *testing.T) {
+		// Arrange
+		handler := handlers.NewCatalogHandler(new(MockCatalogService))
+
+		req := httptest.NewRequest("GET", "/api/products/not-a-uuid/prices", nil)
+		req = mux.SetURLVars(req, map[string]string{"productID": "not-a-uuid"})
+		rec := httptest.NewRecorder()
+
+		// Act
+		handler.GetPriceHistory(rec, req)
+
+		// Assert
+		assert.Equal(t, http.StatusBadRequest, rec.Code)
+	})
+}
+
+func TestCatalogHandler_UnpinCatalog(t *testing.T) {
+	tests := []struct {
+		name           string
+		serviceErr     error
+		expectedStatus int
+	}{
+		{name: "unpinned", expectedStatus: http.StatusNoContent},
+		{name: "service error", serviceErr: assert.AnError, expectedStatus: http.StatusInternalServerError},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Arrange
+			mockService := new(MockCatalogService)
+			handler := handlers.NewCatalogHandler(mockService)
+			mockService.On("UnpinCatalog", mock.Anything).Return(tt.serviceErr).Once()
+
+			req := httptest.NewRequest("DELETE", "/admin/products/snapshots/pin", nil)
+			rec := httptest.NewRecorder()
+
+			// Act
+			handler.UnpinCatalog(rec, req)
+
+			// Assert
+			assert.Equal(t, tt.expectedStatus, rec.Code)
+			mockService.AssertExpectations(t)
+		})
+	}
+}
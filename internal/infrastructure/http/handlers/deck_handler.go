@@ -0,0 +1,55 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+
+	"github.com/ArtemSind/food_tinder/internal/domain"
+	httpModels "github.com/ArtemSind/food_tinder/internal/infrastructure/http/models"
+	"github.com/google/uuid"
+	"github.com/gorilla/mux"
+)
+
+type DeckService interface {
+	GetDeck(ctx context.Context, sessionID uuid.UUID, limit int) (*domain.Deck, error)
+}
+
+type DeckHandler struct {
+	deckService DeckService
+}
+
+func NewDeckHandler(deckService DeckService) *DeckHandler {
+	return &DeckHandler{deckService: deckService}
+}
+
+func (h *DeckHandler) GetDeck(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	sessionID, err := uuid.Parse(vars["sessionID"])
+	if err != nil {
+		http.Error(w, "Invalid session ID", http.StatusBadRequest)
+		return
+	}
+
+	limit, err := parseLimit(r)
+	if err != nil {
+		http.Error(w, "Invalid limit", http.StatusBadRequest)
+		return
+	}
+
+	ctx := r.Context()
+	deck, err := h.deckService.GetDeck(ctx, sessionID, limit)
+	if err != nil {
+		if errors.Is(err, domain.ErrSessionNotFound) {
+			http.Error(w, "Session not found", http.StatusNotFound)
+			return
+		}
+		http.Error(w, "Failed to get deck", http.StatusInternalServerError)
+		return
+	}
+
+	response := httpModels.DeckResponseFromDomain(deck)
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(response)
+}
@@ -0,0 +1,79 @@
+package handlers_test
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/ArtemSind/food_tinder/internal/domain"
+	"github.com/ArtemSind/food_tinder/internal/infrastructure/http/handlers"
+	"github.com/ArtemSind/food_tinder/internal/infrastructure/http/models"
+	"github.com/google/uuid"
+	"github.com/gorilla/mux"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+// Mock DeckService
+type MockDeckService struct {
+	mock.Mock
+}
+
+func (m *MockDeckService) GetDeck(ctx context.Context, sessionID uuid.UUID, limit int) (*domain.Deck, error) {
+	args := m.Called(ctx, sessionID, limit)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*domain.Deck), args.Error(1)
+}
+
+func TestDeckHandler_GetDeck(t *testing.T) {
+	// Arrange
+	mockService := new(MockDeckService)
+	handler := handlers.NewDeckHandler(mockService)
+	router := mux.NewRouter()
+	router.HandleFunc("/api/sessions/{sessionID}/deck", handler.GetDeck).Methods("GET")
+
+	t.Run("successful retrieval", func(t *testing.T) {
+		// Arrange
+		sessionID := uuid.New()
+		deck := &domain.Deck{SessionID: sessionID, Strategy: domain.DeckBandit, Products: []uuid.UUID{uuid.New()}}
+		mockService.On("GetDeck", mock.Anything, sessionID, 10).Return(deck, nil).Once()
+
+		req := httptest.NewRequest("GET", "/api/sessions/"+sessionID.String()+"/deck?limit=10", nil)
+		rec := httptest.NewRecorder()
+
+		// Act
+		router.ServeHTTP(rec, req)
+
+		// Assert
+		require.Equal(t, http.StatusOK, rec.Code)
+
+		var response models.DeckResponse
+		err := json.NewDecoder(rec.Body).Decode(&response)
+		require.NoError(t, err)
+		assert.Equal(t, "bandit", response.Strategy)
+		assert.Equal(t, deck.Products, response.Products)
+
+		mockService.AssertExpectations(t)
+	})
+
+	t.Run("session not found", func(t *testing.T) {
+		// Arrange
+		sessionID := uuid.New()
+		mockService.On("GetDeck", mock.Anything, sessionID, 0).Return(nil, domain.ErrSessionNotFound).Once()
+
+		req := httptest.NewRequest("GET", "/api/sessions/"+sessionID.String()+"/deck", nil)
+		rec := httptest.NewRecorder()
+
+		// Act
+		router.ServeHTTP(rec, req)
+
+		// Assert
+		assert.Equal(t, http.StatusNotFound, rec.Code)
+		mockService.AssertExpectations(t)
+	})
+}
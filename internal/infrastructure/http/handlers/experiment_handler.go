@@ -0,0 +1,121 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+
+	"github.com/ArtemSind/food_tinder/internal/domain"
+	httpModels "github.com/ArtemSind/food_tinder/internal/infrastructure/http/models"
+	"github.com/google/uuid"
+	"github.com/gorilla/mux"
+)
+
+type ExperimentService interface {
+	CreateExperiment(ctx context.Context, name, description string, variants []domain.Variant) (*domain.Experiment, error)
+	ListExperiments(ctx context.Context) ([]*domain.Experiment, error)
+	StopExperiment(ctx context.Context, id uuid.UUID) (*domain.Experiment, error)
+	GetResults(ctx context.Context, id uuid.UUID) (*domain.Experiment, []*domain.VariantResult, error)
+}
+
+type ExperimentHandler struct {
+	experimentService ExperimentService
+}
+
+func NewExperimentHandler(experimentService ExperimentService) *ExperimentHandler {
+	return &ExperimentHandler{experimentService: experimentService}
+}
+
+func (h *ExperimentHandler) CreateExperiment(w http.ResponseWriter, r *http.Request) {
+	var req httpModels.ExperimentRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	ctx := r.Context()
+	experiment, err := h.experimentService.CreateExperiment(ctx, req.Name, req.Description, req.ToDomain())
+	if err != nil {
+		switch {
+		case errors.Is(err, domain.ErrExperimentAlreadyRunning):
+			http.Error(w, err.Error(), http.StatusConflict)
+		case errors.Is(err, domain.ErrExperimentNameRequired),
+			errors.Is(err, domain.ErrTooFewVariants),
+			errors.Is(err, domain.ErrInvalidVariantWeight),
+			errors.Is(err, domain.ErrDuplicateVariant),
+			errors.Is(err, domain.ErrVariantNameRequired),
+			errors.Is(err, domain.ErrInvalidDeckStrategy):
+			http.Error(w, err.Error(), http.StatusBadRequest)
+		default:
+			http.Error(w, "Failed to create experiment", http.StatusInternalServerError)
+		}
+		return
+	}
+
+	response := httpModels.ExperimentResponseFromDomain(experiment)
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(response)
+}
+
+func (h *ExperimentHandler) ListExperiments(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	experiments, err := h.experimentService.ListExperiments(ctx)
+	if err != nil {
+		http.Error(w, "Failed to get experiments", http.StatusInternalServerError)
+		return
+	}
+
+	response := httpModels.ExperimentListResponseFromDomain(experiments)
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(response)
+}
+
+func (h *ExperimentHandler) StopExperiment(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	experimentID, err := uuid.Parse(vars["experimentID"])
+	if err != nil {
+		http.Error(w, "Invalid experiment ID", http.StatusBadRequest)
+		return
+	}
+
+	ctx := r.Context()
+	experiment, err := h.experimentService.StopExperiment(ctx, experimentID)
+	if err != nil {
+		if errors.Is(err, domain.ErrExperimentNotFound) {
+			http.Error(w, "Experiment not found", http.StatusNotFound)
+			return
+		}
+		http.Error(w, "Failed to stop experiment", http.StatusInternalServerError)
+		return
+	}
+
+	response := httpModels.ExperimentResponseFromDomain(experiment)
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(response)
+}
+
+func (h *ExperimentHandler) GetResults(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	experimentID, err := uuid.Parse(vars["experimentID"])
+	if err != nil {
+		http.Error(w, "Invalid experiment ID", http.StatusBadRequest)
+		return
+	}
+
+	ctx := r.Context()
+	experiment, results, err := h.experimentService.GetResults(ctx, experimentID)
+	if err != nil {
+		if errors.Is(err, domain.ErrExperimentNotFound) {
+			http.Error(w, "Experiment not found", http.StatusNotFound)
+			return
+		}
+		http.Error(w, "Failed to get experiment results", http.StatusInternalServerError)
+		return
+	}
+
+	response := httpModels.ExperimentResultsResponseFromDomain(experiment, results)
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(response)
+}
@@ -0,0 +1,178 @@
+package handlers_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/ArtemSind/food_tinder/internal/domain"
+	"github.com/ArtemSind/food_tinder/internal/infrastructure/http/handlers"
+	"github.com/ArtemSind/food_tinder/internal/infrastructure/http/models"
+	"github.com/google/uuid"
+	"github.com/gorilla/mux"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+// Mock ExperimentService
+type MockExperimentService struct {
+	mock.Mock
+}
+
+func (m *MockExperimentService) CreateExperiment(ctx context.Context, name, description string, variants []domain.Variant) (*domain.Experiment, error) {
+	args := m.Called(ctx, name, description, variants)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*domain.Experiment), args.Error(1)
+}
+
+func (m *MockExperimentService) ListExperiments(ctx context.Context) ([]*domain.Experiment, error) {
+	args := m.Called(ctx)
+	return args.Get(0).([]*domain.Experiment), args.Error(1)
+}
+
+func (m *MockExperimentService) StopExperiment(ctx context.Context, id uuid.UUID) (*domain.Experiment, error) {
+	args := m.Called(ctx, id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*domain.Experiment), args.Error(1)
+}
+
+func (m *MockExperimentService) GetResults(ctx context.Context, id uuid.UUID) (*domain.Experiment, []*domain.VariantResult, error) {
+	args := m.Called(ctx, id)
+	if args.Get(0) == nil {
+		return nil, nil, args.Error(2)
+	}
+	return args.Get(0).(*domain.Experiment), args.Get(1).([]*domain.VariantResult), args.Error(2)
+}
+
+func TestExperimentHandler_CreateExperiment(t *testing.T) {
+	// Arrange
+	mockService := new(MockExperimentService)
+	handler := handlers.NewExperimentHandler(mockService)
+	variants := []domain.Variant{
+		{Name: "control", Strategy: domain.DeckRandom, Weight: 1},
+		{Name: "treatment", Strategy: domain.DeckBandit, Weight: 1},
+	}
+	body := `{"name":"bandit","variants":[` +
+		`{"name":"control","strategy":"random","weight":1},` +
+		`{"name":"treatment","strategy":"bandit","weight":1}]}`
+
+	t.Run("successful creation", func(t *testing.T) {
+		// Arrange
+		experiment, _ := domain.NewExperiment("bandit", "", variants)
+		mockService.On("CreateExperiment", mock.Anything, "bandit", "", variants).Return(experiment, nil).Once()
+
+		req := httptest.NewRequest("POST", "/admin/experiments", bytes.NewBufferString(body))
+		rec := httptest.NewRecorder()
+
+		// Act
+		handler.CreateExperiment(rec, req)
+
+		// Assert
+		require.Equal(t, http.StatusCreated, rec.Code)
+
+		var response models.ExperimentResponse
+		err := json.NewDecoder(rec.Body).Decode(&response)
+		require.NoError(t, err)
+		assert.Equal(t, experiment.ID, response.ID)
+		assert.Equal(t, "running", response.Status)
+		assert.Len(t, response.Variants, 2)
+
+		mockService.AssertExpectations(t)
+	})
+
+	t.Run("already running", func(t *testing.T) {
+		// Arrange
+		mockService.On("CreateExperiment", mock.Anything, "bandit", "", variants).
+			Return(nil, domain.ErrExperimentAlreadyRunning).Once()
+
+		req := httptest.NewRequest("POST", "/admin/experiments", bytes.NewBufferString(body))
+		rec := httptest.NewRecorder()
+
+		// Act
+		handler.CreateExperiment(rec, req)
+
+		// Assert
+		assert.Equal(t, http.StatusConflict, rec.Code)
+		mockService.AssertExpectations(t)
+	})
+
+	t.Run("unnamed variant", func(t *testing.T) {
+		// Arrange
+		mockService.On("CreateExperiment", mock.Anything, "bandit", "", variants).
+			Return(nil, domain.ErrVariantNameRequired).Once()
+
+		req := httptest.NewRequest("POST", "/admin/experiments", bytes.NewBufferString(body))
+		rec := httptest.NewRecorder()
+
+		// Act
+		handler.CreateExperiment(rec, req)
+
+		// Assert
+		assert.Equal(t, http.StatusBadRequest, rec.Code)
+		assert.Equal(t, "variant name is required\n", rec.Body.String())
+		mockService.AssertExpectations(t)
+	})
+}
+
+func TestExperimentHandler_GetResults(t *testing.T) {
+	// Arrange
+	mockService := new(MockExperimentService)
+	handler := handlers.NewExperimentHandler(mockService)
+	router := mux.NewRouter()
+	router.HandleFunc("/admin/experiments/{experimentID}/results", handler.GetResults).Methods("GET")
+
+	t.Run("successful retrieval", func(t *testing.T) {
+		// Arrange
+		experiment, _ := domain.NewExperiment("bandit", "", []domain.Variant{
+			{Name: "control", Strategy: domain.DeckRandom, Weight: 1},
+			{Name: "treatment", Strategy: domain.DeckBandit, Weight: 1},
+		})
+		results := []*domain.VariantResult{
+			{Variant: "control", Strategy: "random", Impressions: 10, Votes: 5, VotesPerImpression: 0.5},
+			{Variant: "treatment", Strategy: "bandit"},
+		}
+		mockService.On("GetResults", mock.Anything, experiment.ID).Return(experiment, results, nil).Once()
+
+		req := httptest.NewRequest("GET", "/admin/experiments/"+experiment.ID.String()+"/results", nil)
+		rec := httptest.NewRecorder()
+
+		// Act
+		router.ServeHTTP(rec, req)
+
+		// Assert
+		require.Equal(t, http.StatusOK, rec.Code)
+
+		var response models.ExperimentResultsResponse
+		err := json.NewDecoder(rec.Body).Decode(&response)
+		require.NoError(t, err)
+		assert.Equal(t, experiment.ID, response.Experiment.ID)
+		require.Len(t, response.Variants, 2)
+		assert.Equal(t, 0.5, response.Variants[0].VotesPerImpression)
+
+		mockService.AssertExpectations(t)
+	})
+
+	t.Run("experiment not found", func(t *testing.T) {
+		// Arrange
+		experimentID := uuid.New()
+		mockService.On("GetResults", mock.Anything, experimentID).Return(nil, nil, domain.ErrExperimentNotFound).Once()
+
+		req := httptest.NewRequest("GET", "/admin/experiments/"+experimentID.String()+"/results", nil)
+		rec := httptest.NewRecorder()
+
+		// Act
+		router.ServeHTTP(rec, req)
+
+		// Assert
+		assert.Equal(t, http.StatusNotFound, rec.Code)
+		mockService.AssertExpectations(t)
+	})
+}
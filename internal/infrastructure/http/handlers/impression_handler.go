@@ -0,0 +1,71 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+
+	"github.com/ArtemSind/food_tinder/internal/domain"
+	httpModels "github.com/ArtemSind/food_tinder/internal/infrastructure/http/models"
+	"github.com/google/uuid"
+	"github.com/gorilla/mux"
+)
+
+type ImpressionService interface {
+	RecordImpression(ctx context.Context, sessionID, productID uuid.UUID, action string, position int) (*domain.Impression, error)
+}
+
+type ImpressionHandler struct {
+	impressionService ImpressionService
+}
+
+func NewImpressionHandler(impressionService ImpressionService) *ImpressionHandler {
+	return &ImpressionHandler{impressionService: impressionService}
+}
+
+func (h *ImpressionHandler) RecordImpression(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	sessionID, err := uuid.Parse(vars["sessionID"])
+	if err != nil {
+		http.Error(w, "Invalid session ID", http.StatusBadRequest)
+		return
+	}
+
+	var req httpModels.ImpressionRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	productID, err := uuid.Parse(req.ProductID)
+	if err != nil {
+		http.Error(w, "Invalid product ID", http.StatusBadRequest)
+		return
+	}
+	if req.Position < 0 {
+		http.Error(w, "Invalid position", http.StatusBadRequest)
+		return
+	}
+
+	ctx := r.Context()
+	impression, err := h.impressionService.RecordImpression(ctx, sessionID, productID, req.Action, req.Position)
+	if err != nil {
+		switch {
+		case errors.Is(err, domain.ErrInvalidImpressionAction):
+			http.Error(w, err.Error(), http.StatusBadRequest)
+		case errors.Is(err, domain.ErrSessionNotFound):
+			http.Error(w, "Session not found", http.StatusNotFound)
+		case errors.Is(err, domain.ErrProductNotFound):
+			http.Error(w, "Product not found", http.StatusNotFound)
+		default:
+			http.Error(w, "Failed to record impression", http.StatusInternalServerError)
+		}
+		return
+	}
+
+	response := httpModels.ImpressionResponseFromDomain(impression)
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(response)
+}
@@ -0,0 +1,102 @@
+package handlers_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/ArtemSind/food_tinder/internal/domain"
+	"github.com/ArtemSind/food_tinder/internal/infrastructure/http/handlers"
+	"github.com/ArtemSind/food_tinder/internal/infrastructure/http/models"
+	"github.com/google/uuid"
+	"github.com/gorilla/mux"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+// Mock ImpressionService
+type MockImpressionService struct {
+	mock.Mock
+}
+
+func (m *MockImpressionService) RecordImpression(ctx context.Context, sessionID, productID uuid.UUID, action string, position int) (*domain.Impression, error) {
+	args := m.Called(ctx, sessionID, productID, action, position)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*domain.Impression), args.Error(1)
+}
+
+func TestImpressionHandler_RecordImpression(t *testing.T) {
+	// Arrange
+	mockService := new(MockImpressionService)
+	handler := handlers.NewImpressionHandler(mockService)
+	router := mux.NewRouter()
+	router.HandleFunc("/api/sessions/{sessionID}/impressions", handler.RecordImpression).Methods("POST")
+
+	t.Run("records a skip", func(t *testing.T) {
+		// Arrange
+		session := &domain.Session{ID: uuid.New(), DeckStrategy: domain.DeckPopularity}
+		productID := uuid.New()
+		impression := domain.NewImpression(session, productID, 2, domain.ActionSkip)
+		mockService.On("RecordImpression", mock.Anything, session.ID, productID, "skip", 2).Return(impression, nil).Once()
+
+		body, _ := json.Marshal(models.ImpressionRequest{ProductID: productID.String(), Action: "skip", Position: 2})
+		req := httptest.NewRequest("POST", "/api/sessions/"+session.ID.String()+"/impressions", bytes.NewBuffer(body))
+		rec := httptest.NewRecorder()
+
+		// Act
+		router.ServeHTTP(rec, req)
+
+		// Assert
+		require.Equal(t, http.StatusCreated, rec.Code)
+
+		var response models.ImpressionResponse
+		err := json.NewDecoder(rec.Body).Decode(&response)
+		require.NoError(t, err)
+		assert.Equal(t, "skip", response.Action)
+		assert.Equal(t, "popularity", response.Strategy)
+
+		mockService.AssertExpectations(t)
+	})
+
+	t.Run("invalid action", func(t *testing.T) {
+		// Arrange
+		sessionID, productID := uuid.New(), uuid.New()
+		mockService.On("RecordImpression", mock.Anything, sessionID, productID, "like", 0).
+			Return(nil, domain.ErrInvalidImpressionAction).Once()
+
+		body, _ := json.Marshal(models.ImpressionRequest{ProductID: productID.String(), Action: "like"})
+		req := httptest.NewRequest("POST", "/api/sessions/"+sessionID.String()+"/impressions", bytes.NewBuffer(body))
+		rec := httptest.NewRecorder()
+
+		// Act
+		router.ServeHTTP(rec, req)
+
+		// Assert
+		assert.Equal(t, http.StatusBadRequest, rec.Code)
+		mockService.AssertExpectations(t)
+	})
+
+	t.Run("session not found", func(t *testing.T) {
+		// Arrange
+		sessionID, productID := uuid.New(), uuid.New()
+		mockService.On("RecordImpression", mock.Anything, sessionID, productID, "", 0).
+			Return(nil, domain.ErrSessionNotFound).Once()
+
+		body, _ := json.Marshal(models.ImpressionRequest{ProductID: productID.String()})
+		req := httptest.NewRequest("POST", "/api/sessions/"+sessionID.String()+"/impressions", bytes.NewBuffer(body))
+		rec := httptest.NewRecorder()
+
+		// Act
+		router.ServeHTTP(rec, req)
+
+		// Assert
+		assert.Equal(t, http.StatusNotFound, rec.Code)
+		mockService.AssertExpectations(t)
+	})
+}
@@ -0,0 +1,74 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+
+	"github.com/ArtemSind/food_tinder/internal/domain"
+	httpModels "github.com/ArtemSind/food_tinder/internal/infrastructure/http/models"
+	"github.com/google/uuid"
+	"github.com/gorilla/mux"
+)
+
+type ProductAliasService interface {
+	CreateAlias(ctx context.Context, aliasID, productID uuid.UUID) (*domain.ProductAlias, error)
+	ListAliases(ctx context.Context) ([]*domain.ProductAlias, error)
+}
+
+type ProductAliasHandler struct {
+	productAliasService ProductAliasService
+}
+
+func NewProductAliasHandler(productAliasService ProductAliasService) *ProductAliasHandler {
+	return &ProductAliasHandler{productAliasService: productAliasService}
+}
+
+// CreateAlias declares the product in the body an alias of the product in the path
+func (h *ProductAliasHandler) CreateAlias(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	productID, err := uuid.Parse(vars["productID"])
+	if err != nil {
+		http.Error(w, "Invalid product ID", http.StatusBadRequest)
+		return
+	}
+
+	var req httpModels.ProductAliasRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil || req.AliasID == uuid.Nil {
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	ctx := r.Context()
+	alias, err := h.productAliasService.CreateAlias(ctx, req.AliasID, productID)
+	if err != nil {
+		switch {
+		case errors.Is(err, domain.ErrSelfAlias):
+			http.Error(w, err.Error(), http.StatusBadRequest)
+		case errors.Is(err, domain.ErrAlreadyAliased), errors.Is(err, domain.ErrAliasCycle):
+			http.Error(w, err.Error(), http.StatusConflict)
+		default:
+			http.Error(w, "Failed to create product alias", http.StatusInternalServerError)
+		}
+		return
+	}
+
+	response := httpModels.ProductAliasResponseFromDomain(alias)
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(response)
+}
+
+func (h *ProductAliasHandler) ListAliases(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	aliases, err := h.productAliasService.ListAliases(ctx)
+	if err != nil {
+		http.Error(w, "Failed to get product aliases", http.StatusInternalServerError)
+		return
+	}
+
+	response := httpModels.ProductAliasListResponseFromDomain(aliases)
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(response)
+}
@@ -0,0 +1,121 @@
+package handlers_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/ArtemSind/food_tinder/internal/domain"
+	"github.com/ArtemSind/food_tinder/internal/infrastructure/http/handlers"
+	"github.com/ArtemSind/food_tinder/internal/infrastructure/http/models"
+	"github.com/google/uuid"
+	"github.com/gorilla/mux"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+// Mock ProductAliasService
+type MockProductAliasService struct {
+	mock.Mock
+}
+
+func (m *MockProductAliasService) CreateAlias(ctx context.Context, aliasID, productID uuid.UUID) (*domain.ProductAlias, error) {
+	args := m.Called(ctx, aliasID, productID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*domain.ProductAlias), args.Error(1)
+}
+
+func (m *MockProductAliasService) ListAliases(ctx context.Context) ([]*domain.ProductAlias, error) {
+	args := m.Called(ctx)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*domain.ProductAlias), args.Error(1)
+}
+
+func TestProductAliasHandler_CreateAlias(t *testing.T) {
+	// Arrange
+	mockService := new(MockProductAliasService)
+	handler := handlers.NewProductAliasHandler(mockService)
+	router := mux.NewRouter()
+	router.HandleFunc("/admin/products/{productID}/aliases", handler.CreateAlias).Methods("POST")
+
+	t.Run("successful alias", func(t *testing.T) {
+		// Arrange
+		oldID, newID := uuid.New(), uuid.New()
+		expected := &domain.ProductAlias{AliasID: oldID, ProductID: newID, MovedVotes: 3, MergedVotes: 1}
+		mockService.On("CreateAlias", mock.Anything, oldID, newID).Return(expected, nil).Once()
+
+		body, _ := json.Marshal(map[string]string{"alias_id": oldID.String()})
+		req := httptest.NewRequest("POST", "/admin/products/"+newID.String()+"/aliases", bytes.NewBuffer(body))
+		rec := httptest.NewRecorder()
+
+		// Act
+		router.ServeHTTP(rec, req)
+
+		// Assert
+		require.Equal(t, http.StatusCreated, rec.Code)
+
+		var response models.ProductAliasResponse
+		err := json.NewDecoder(rec.Body).Decode(&response)
+		require.NoError(t, err)
+		assert.Equal(t, oldID, response.AliasID)
+		assert.Equal(t, newID, response.ProductID)
+		assert.Equal(t, 3, response.MovedVotes)
+		assert.Equal(t, 1, response.MergedVotes)
+
+		mockService.AssertExpectations(t)
+	})
+
+	t.Run("already aliased", func(t *testing.T) {
+		// Arrange
+		oldID, newID := uuid.New(), uuid.New()
+		mockService.On("CreateAlias", mock.Anything, oldID, newID).Return(nil, domain.ErrAlreadyAliased).Once()
+
+		body, _ := json.Marshal(map[string]string{"alias_id": oldID.String()})
+		req := httptest.NewRequest("POST", "/admin/products/"+newID.String()+"/aliases", bytes.NewBuffer(body))
+		rec := httptest.NewRecorder()
+
+		// Act
+		router.ServeHTTP(rec, req)
+
+		// Assert
+		assert.Equal(t, http.StatusConflict, rec.Code)
+		mockService.AssertExpectations(t)
+	})
+
+	t.Run("self alias", func(t *testing.T) {
+		// Arrange
+		id := uuid.New()
+		mockService.On("CreateAlias", mock.Anything, id, id).Return(nil, domain.ErrSelfAlias).Once()
+
+		body, _ := json.Marshal(map[string]string{"alias_id": id.String()})
+		req := httptest.NewRequest("POST", "/admin/products/"+id.String()+"/aliases", bytes.NewBuffer(body))
+		rec := httptest.NewRecorder()
+
+		// Act
+		router.ServeHTTP(rec, req)
+
+		// Assert
+		assert.Equal(t, http.StatusBadRequest, rec.Code)
+		mockService.AssertExpectations(t)
+	})
+
+	t.Run("missing alias ID", func(t *testing.T) {
+		// Arrange
+		req := httptest.NewRequest("POST", "/admin/products/"+uuid.New().String()+"/aliases", bytes.NewBufferString(`{}`))
+		rec := httptest.NewRecorder()
+
+		// Act
+		router.ServeHTTP(rec, req)
+
+		// Assert
+		assert.Equal(t, http.StatusBadRequest, rec.Code)
+	})
+}
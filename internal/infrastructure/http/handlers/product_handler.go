@@ -0,0 +1,95 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/ArtemSind/food_tinder/internal/domain"
+	"github.com/ArtemSind/food_tinder/internal/infrastructure/external/foodji"
+	"github.com/ArtemSind/food_tinder/internal/infrastructure/http/i18n"
+	httpModels "github.com/ArtemSind/food_tinder/internal/infrastructure/http/models"
+	"github.com/google/uuid"
+)
+
+// maxProductCards caps the IDs of one product card request at the size of the largest deck
+const maxProductCards = 100
+
+type ProductService interface {
+	SearchProducts(ctx context.Context, query domain.ProductQuery) ([]foodji.Product, []domain.CategoryFacet, error)
+	GetProducts(ctx context.Context, ids []uuid.UUID, locale domain.Locale) ([]foodji.Product, error)
+}
+
+type ProductHandler struct {
+	productService ProductService
+}
+
+func NewProductHandler(productService ProductService) *ProductHandler {
+	return &ProductHandler{productService: productService}
+}
+
+func (h *ProductHandler) SearchProducts(w http.ResponseWriter, r *http.Request) {
+	params := r.URL.Query()
+	query := domain.ProductQuery{
+		Text:      params.Get("q"),
+		Category:  params.Get("category"),
+		MachineID: params.Get("machine"),
+		Locale:    i18n.FromContext(r.Context()),
+	}
+
+	if rawMaxPrice := params.Get("max_price"); rawMaxPrice != "" {
+		maxPrice, err := strconv.ParseFloat(rawMaxPrice, 64)
+		if err != nil || maxPrice < 0 {
+			http.Error(w, "Invalid max price", http.StatusBadRequest)
+			return
+		}
+		query.MaxPrice = &maxPrice
+	}
+
+	if rawAllergens := params.Get("exclude_allergens"); rawAllergens != "" {
+		query.ExcludeAllergens = strings.Split(rawAllergens, ",")
+	}
+
+	ctx := r.Context()
+	products, categories, err := h.productService.SearchProducts(ctx, query)
+	if err != nil {
+		http.Error(w, "Failed to search products", http.StatusInternalServerError)
+		return
+	}
+
+	response := httpModels.ProductSearchResponseFromFoodji(products, categories, query.Locale)
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(response)
+}
+
+// GetProductCards returns the products with the IDs in ids, so the products of a deck,
+// recommendation or similar products list can be shown with their stock and warnings
+func (h *ProductHandler) GetProductCards(w http.ResponseWriter, r *http.Request) {
+	var ids []uuid.UUID
+	for _, rawID := range strings.Split(r.URL.Query().Get("ids"), ",") {
+		id, err := uuid.Parse(strings.TrimSpace(rawID))
+		if err != nil {
+			http.Error(w, "Invalid product IDs", http.StatusBadRequest)
+			return
+		}
+		ids = append(ids, id)
+	}
+	if len(ids) > maxProductCards {
+		http.Error(w, "Too many product IDs", http.StatusBadRequest)
+		return
+	}
+
+	ctx := r.Context()
+	locale := i18n.FromContext(ctx)
+	products, err := h.productService.GetProducts(ctx, ids, locale)
+	if err != nil {
+		http.Error(w, "Failed to get products", http.StatusInternalServerError)
+		return
+	}
+
+	response := httpModels.ProductListResponseFromFoodji(products, locale)
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(response)
+}
@@ -0,0 +1,285 @@
+package handlers_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/ArtemSind/food_tinder/internal/domain"
+	"github.com/ArtemSind/food_tinder/internal/infrastructure/external/foodji"
+	"github.com/ArtemSind/food_tinder/internal/infrastructure/http/handlers"
+	"github.com/ArtemSind/food_tinder/internal/infrastructure/http/i18n"
+	"github.com/ArtemSind/food_tinder/internal/infrastructure/http/models"
+	"github.com/google/uuid"
+	"github.com/gorilla/mux"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+// Mock ProductService
+type MockProductService struct {
+	mock.Mock
+}
+
+func (m *MockProductService) SearchProducts(ctx context.Context, query domain.ProductQuery) ([]foodji.Product, []domain.CategoryFacet, error) {
+	args := m.Called(ctx, query)
+	if args.Get(0) == nil {
+		return nil, nil, args.Error(2)
+	}
+	return args.Get(0).([]foodji.Product), args.Get(1).([]domain.CategoryFacet), args.Error(2)
+}
+
+func (m *MockProductService) GetProducts(ctx context.Context, ids []uuid.UUID, locale domain.Locale) ([]foodji.Product, error) {
+	args := m.Called(ctx, ids, locale)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]foodji.Product), args.Error(1)
+}
+
+func TestProductHandler_SearchProducts(t *testing.T) {
+	// Arrange
+	mockService := new(MockProductService)
+	handler := handlers.NewProductHandler(mockService)
+	router := mux.NewRouter()
+	router.HandleFunc("/api/products", handler.SearchProducts).Methods("GET")
+
+	t.Run("successful search", func(t *testing.T) {
+		// Arrange
+		maxPrice := 8.5
+		query := domain.ProductQuery{
+			Text:             "salad",
+			Category:         "Salads",
+			MaxPrice:         &maxPrice,
+			ExcludeAllergens: []string{"gluten", "nuts"},
+			MachineID:        "machine-1",
+			Locale:           domain.LocaleEnglish,
+		}
+		product := foodji.Product{ID: uuid.New(), Name: "Greek Salad", Category: "Salads", Price: 7.5, Allergens: []string{"milk"}, MachineID: "machine-1"}
+		categories := []domain.CategoryFacet{{Category: "Salads", Count: 1}, {Category: "Desserts", Count: 1}}
+		mockService.On("SearchProducts", mock.Anything, query).Return([]foodji.Product{product}, categories, nil).Once()
+
+		req := httptest.NewRequest("GET", "/api/products?q=salad&category=Salads&max_price=8.5&exclude_allergens=gluten,nuts&machine=machine-1", nil)
+		rec := httptest.NewRecorder()
+
+		// Act
+		router.ServeHTTP(rec, req)
+
+		// Assert
+		require.Equal(t, http.StatusOK, rec.Code)
+
+		var response models.ProductSearchResponse
+		err := json.NewDecoder(rec.Body).Decode(&response)
+		require.NoError(t, err)
+		require.Equal(t, 1, response.Count)
+		assert.Equal(t, product.ID, response.Products[0].ID)
+		assert.Equal(t, "Greek Salad", response.Products[0].Name)
+		assert.Equal(t, "machine-1", response.Products[0].MachineID)
+		assert.Equal(t, []string{"Contains milk"}, response.Products[0].AllergenWarnings)
+		assert.Equal(t, categories, response.Facets.Categories)
+
+		mockService.AssertExpectations(t)
+	})
+
+	t.Run("stock and availability", func(t *testing.T) {
+		// Arrange
+		soldOut := foodji.Product{ID: uuid.New(), Name: "Chocolate Brownie", Slots: []foodji.Slot{{Number: 7, Quantity: 0}}}
+		stocked := foodji.Product{ID: uuid.New(), Name: "Caesar Salad", Slots: []foodji.Slot{{Number: 1, Quantity: 3}, {Number: 2, Quantity: 2}}}
+		unknown := foodji.Product{ID: uuid.New(), Name: "Canteen Soup", Diets: []string{"vegan"}}
+		mockService.On("SearchProducts", mock.Anything, domain.ProductQuery{Text: "stock", Locale: domain.LocaleEnglish}).Return([]foodji.Product{soldOut, stocked, unknown}, []domain.CategoryFacet{}, nil).Once()
+
+		req := httptest.NewRequest("GET", "/api/products?q=stock", nil)
+		rec := httptest.NewRecorder()
+
+		// Act
+		router.ServeHTTP(rec, req)
+
+		// Assert
+		require.Equal(t, http.StatusOK, rec.Code)
+
+		var response models.ProductSearchResponse
+		err := json.NewDecoder(rec.Body).Decode(&response)
+		require.NoError(t, err)
+		require.Len(t, response.Products, 3)
+
+		require.NotNil(t, response.Products[0].Stock)
+		assert.Equal(t, 0, *response.Products[0].Stock)
+		assert.False(t, response.Products[0].Available)
+
+		require.NotNil(t, response.Products[1].Stock)
+		assert.Equal(t, 5, *response.Products[1].Stock)
+		assert.True(t, response.Products[1].Available)
+
+		assert.Nil(t, response.Products[2].Stock, "stock is omitted if the source does not report it")
+		assert.True(t, response.Products[2].Available)
+		assert.Equal(t, []string{"vegan"}, response.Products[2].Diets)
+		assert.Equal(t, []string{"Allergens unknown"}, response.Products[2].AllergenWarnings,
+			"products whose source does not report allergens are flagged")
+
+		mockService.AssertExpectations(t)
+	})
+
+	t.Run("German warnings for a German request", func(t *testing.T) {
+		// Arrange
+		localized := mux.NewRouter()
+		localized.Use(i18n.Middleware(nil))
+		localized.HandleFunc("/api/products", handler.SearchProducts).Methods("GET")
+
+		product := foodji.Product{ID: uuid.New(), Name: "Griechischer Salat", Allergens: []string{"milk", "quinoa"}}
+		mockService.On("SearchProducts", mock.Anything, domain.ProductQuery{Text: "salat", Locale: domain.LocaleGerman}).
+			Return([]foodji.Product{product}, []domain.CategoryFacet{}, nil).Once()
+
+		req := httptest.NewRequest("GET", "/api/products?q=salat", nil)
+		req.Header.Set("Accept-Language", "de-DE,de;q=0.9,en;q=0.8")
+		rec := httptest.NewRecorder()
+
+		// Act
+		localized.ServeHTTP(rec, req)
+
+		// Assert
+		require.Equal(t, http.StatusOK, rec.Code)
+
+		var response models.ProductSearchResponse
+		err := json.NewDecoder(rec.Body).Decode(&response)
+		require.NoError(t, err)
+		require.Len(t, response.Products, 1)
+		assert.Equal(t, []string{"Enthält Milch", "Contains quinoa"}, response.Products[0].AllergenWarnings)
+
+		mockService.AssertExpectations(t)
+	})
+
+	t.Run("empty result", func(t *testing.T) {
+		// Arrange
+		mockService.On("SearchProducts", mock.Anything, domain.ProductQuery{Locale: domain.LocaleEnglish}).Return([]foodji.Product{}, []domain.CategoryFacet{}, nil).Once()
+
+		req := httptest.NewRequest("GET", "/api/products", nil)
+		rec := httptest.NewRecorder()
+
+		// Act
+		router.ServeHTTP(rec, req)
+
+		// Assert
+		require.Equal(t, http.StatusOK, rec.Code)
+		assert.JSONEq(t, `{"products":[],"count":0,"facets":{"categories":[]}}`, rec.Body.String())
+
+		mockService.AssertExpectations(t)
+	})
+
+	t.Run("invalid max price", func(t *testing.T) {
+		// Arrange
+		req := httptest.NewRequest("GET", "/api/products?max_price=cheap", nil)
+		rec := httptest.NewRecorder()
+
+		// Act
+		router.ServeHTTP(rec, req)
+
+		// Assert
+		assert.Equal(t, http.StatusBadRequest, rec.Code)
+	})
+
+	t.Run("service error", func(t *testing.T) {
+		// Arrange
+		mockService.On("SearchProducts", mock.Anything, domain.ProductQuery{Text: "soup", Locale: domain.LocaleEnglish}).Return(nil, nil, errors.New("redis down")).Once()
+
+		req := httptest.NewRequest("GET", "/api/products?q=soup", nil)
+		rec := httptest.NewRecorder()
+
+		// Act
+		router.ServeHTTP(rec, req)
+
+		// Assert
+		assert.Equal(t, http.StatusInternalServerError, rec.Code)
+		mockService.AssertExpectations(t)
+	})
+}
+
+func TestProductHandler_GetProductCards(t *testing.T) {
+	// Arrange
+	mockService := new(MockProductService)
+	handler := handlers.NewProductHandler(mockService)
+	router := mux.NewRouter()
+	router.Use(i18n.Middleware(nil))
+	router.HandleFunc("/api/products/cards", handler.GetProductCards).Methods("GET")
+
+	t.Run("cards with stock and warnings", func(t *testing.T) {
+		// Arrange
+		soldOut := foodji.Product{ID: uuid.New(), Name: "Chocolate Brownie", Allergens: []string{"milk"}, Slots: []foodji.Slot{{Number: 7, Quantity: 0}}}
+		stocked := foodji.Product{ID: uuid.New(), Name: "Caesar Salad", Slots: []foodji.Slot{{Number: 1, Quantity: 3}}}
+		mockService.On("GetProducts", mock.Anything, []uuid.UUID{soldOut.ID, stocked.ID}, domain.LocaleGerman).
+			Return([]foodji.Product{soldOut, stocked}, nil).Once()
+
+		req := httptest.NewRequest("GET", "/api/products/cards?ids="+soldOut.ID.String()+","+stocked.ID.String(), nil)
+		req.Header.Set("Accept-Language", "de")
+		rec := httptest.NewRecorder()
+
+		// Act
+		router.ServeHTTP(rec, req)
+
+		// Assert
+		require.Equal(t, http.StatusOK, rec.Code)
+
+		var response models.ProductListResponse
+		err := json.NewDecoder(rec.Body).Decode(&response)
+		require.NoError(t, err)
+		require.Equal(t, 2, response.Count)
+		assert.False(t, response.Products[0].Available)
+		assert.Equal(t, []string{"Enthält Milch"}, response.Products[0].AllergenWarnings)
+		assert.Equal(t, []string{"Allergene unbekannt"}, response.Products[1].AllergenWarnings)
+		assert.True(t, response.Products[1].Available)
+		require.NotNil(t, response.Products[1].Stock)
+		assert.Equal(t, 3, *response.Products[1].Stock)
+
+		mockService.AssertExpectations(t)
+	})
+
+	t.Run("invalid product IDs", func(t *testing.T) {
+		for _, query := range []string{"", "?ids=", "?ids=" + uuid.NewString() + ",salad"} {
+			// Arrange
+			req := httptest.NewRequest("GET", "/api/products/cards"+query, nil)
+			rec := httptest.NewRecorder()
+
+			// Act
+			router.ServeHTTP(rec, req)
+
+			// Assert
+			assert.Equal(t, http.StatusBadRequest, rec.Code, query)
+		}
+	})
+
+	t.Run("too many product IDs", func(t *testing.T) {
+		// Arrange
+		ids := make([]string, 101)
+		for i := range ids {
+			ids[i] = uuid.NewString()
+		}
+		req := httptest.NewRequest("GET", "/api/products/cards?ids="+strings.Join(ids, ","), nil)
+		rec := httptest.NewRecorder()
+
+		// Act
+		router.ServeHTTP(rec, req)
+
+		// Assert
+		assert.Equal(t, http.StatusBadRequest, rec.Code)
+	})
+
+	t.Run("service error", func(t *testing.T) {
+		// Arrange
+		id := uuid.New()
+		mockService.On("GetProducts", mock.Anything, []uuid.UUID{id}, domain.LocaleEnglish).Return(nil, errors.New("redis down")).Once()
+
+		req := httptest.NewRequest("GET", "/api/products/cards?ids="+id.String(), nil)
+		rec := httptest.NewRecorder()
+
+		// Act
+		router.ServeHTTP(rec, req)
+
+		// Assert
+		assert.Equal(t, http.StatusInternalServerError, rec.Code)
+		mockService.AssertExpectations(t)
+	})
+}
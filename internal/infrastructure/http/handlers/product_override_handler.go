@@ -0,0 +1,120 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+
+	"github.com/ArtemSind/food_tinder/internal/domain"
+	httpModels "github.com/ArtemSind/food_tinder/internal/infrastructure/http/models"
+	"github.com/google/uuid"
+	"github.com/gorilla/mux"
+)
+
+type ProductOverrideService interface {
+	SetOverride(ctx context.Context, productID uuid.UUID, hidden, pinned bool, name, category, imageURL *string) (*domain.ProductOverride, error)
+	GetOverride(ctx context.Context, productID uuid.UUID) (*domain.ProductOverride, error)
+	ListOverrides(ctx context.Context) ([]*domain.ProductOverride, error)
+	DeleteOverride(ctx context.Context, productID uuid.UUID) error
+}
+
+type ProductOverrideHandler struct {
+	productOverrideService ProductOverrideService
+}
+
+func NewProductOverrideHandler(productOverrideService ProductOverrideService) *ProductOverrideHandler {
+	return &ProductOverrideHandler{productOverrideService: productOverrideService}
+}
+
+func (h *ProductOverrideHandler) SetOverride(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	productID, err := uuid.Parse(vars["productID"])
+	if err != nil {
+		http.Error(w, "Invalid product ID", http.StatusBadRequest)
+		return
+	}
+
+	var req httpModels.ProductOverrideRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	ctx := r.Context()
+	override, err := h.productOverrideService.SetOverride(ctx, productID, req.Hidden, req.Pinned, req.Name, req.Category, req.ImageURL)
+	if err != nil {
+		switch {
+		case errors.Is(err, domain.ErrEmptyOverride),
+			errors.Is(err, domain.ErrHiddenPinnedOverride),
+			errors.Is(err, domain.ErrInvalidOverrideField),
+			errors.Is(err, domain.ErrInvalidOverrideImage):
+			http.Error(w, err.Error(), http.StatusBadRequest)
+		default:
+			http.Error(w, "Failed to save product override", http.StatusInternalServerError)
+		}
+		return
+	}
+
+	response := httpModels.ProductOverrideResponseFromDomain(override)
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(response)
+}
+
+func (h *ProductOverrideHandler) GetOverride(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	productID, err := uuid.Parse(vars["productID"])
+	if err != nil {
+		http.Error(w, "Invalid product ID", http.StatusBadRequest)
+		return
+	}
+
+	ctx := r.Context()
+	override, err := h.productOverrideService.GetOverride(ctx, productID)
+	if err != nil {
+		if errors.Is(err, domain.ErrOverrideNotFound) {
+			http.Error(w, "Product override not found", http.StatusNotFound)
+			return
+		}
+		http.Error(w, "Failed to get product override", http.StatusInternalServerError)
+		return
+	}
+
+	response := httpModels.ProductOverrideResponseFromDomain(override)
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(response)
+}
+
+func (h *ProductOverrideHandler) ListOverrides(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	overrides, err := h.productOverrideService.ListOverrides(ctx)
+	if err != nil {
+		http.Error(w, "Failed to get product overrides", http.StatusInternalServerError)
+		return
+	}
+
+	response := httpModels.ProductOverrideListResponseFromDomain(overrides)
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(response)
+}
+
+func (h *ProductOverrideHandler) DeleteOverride(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	productID, err := uuid.Parse(vars["productID"])
+	if err != nil {
+		http.Error(w, "Invalid product ID", http.StatusBadRequest)
+		return
+	}
+
+	ctx := r.Context()
+	if err := h.productOverrideService.DeleteOverride(ctx, productID); err != nil {
+		if errors.Is(err, domain.ErrOverrideNotFound) {
+			http.Error(w, "Product override not found", http.StatusNotFound)
+			return
+		}
+		http.Error(w, "Failed to delete product override", http.StatusInternalServerError)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
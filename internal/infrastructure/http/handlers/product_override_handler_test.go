@@ -0,0 +1,226 @@
+package handlers_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/ArtemSind/food_tinder/internal/domain"
+	"github.com/ArtemSind/food_tinder/internal/infrastructure/http/handlers"
+	"github.com/ArtemSind/food_tinder/internal/infrastructure/http/models"
+	"github.com/google/uuid"
+	"github.com/gorilla/mux"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+// Mock ProductOverrideService
+type MockProductOverrideService struct {
+	mock.Mock
+}
+
+func (m *MockProductOverrideService) SetOverride(ctx context.Context, productID uuid.UUID, hidden, pinned bool, name, category, imageURL *string) (*domain.ProductOverride, error) {
+	args := m.Called(ctx, productID, hidden, pinned, name, category, imageURL)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*domain.ProductOverride), args.Error(1)
+}
+
+func (m *MockProductOverrideService) GetOverride(ctx context.Context, productID uuid.UUID) (*domain.ProductOverride, error) {
+	args := m.Called(ctx, productID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*domain.ProductOverride), args.Error(1)
+}
+
+func (m *MockProductOverrideService) ListOverrides(ctx context.Context) ([]*domain.ProductOverride, error) {
+	args := m.Called(ctx)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*domain.ProductOverride), args.Error(1)
+}
+
+func (m *MockProductOverrideService) DeleteOverride(ctx context.Context, productID uuid.UUID) error {
+	args := m.Called(ctx, productID)
+	return args.Error(0)
+}
+
+func newProductOverrideRouter(service *MockProductOverrideService) *mux.Router {
+	handler := handlers.NewProductOverrideHandler(service)
+	router := mux.NewRouter()
+	router.HandleFunc("/admin/products/overrides", handler.ListOverrides).Methods("GET")
+	router.HandleFunc("/admin/products/{productID}/override", handler.SetOverride).Methods("PUT")
+	router.HandleFunc("/admin/products/{productID}/override", handler.GetOverride).Methods("GET")
+	router.HandleFunc("/admin/products/{productID}/override", handler.DeleteOverride).Methods("DELETE")
+	return router
+}
+
+func TestProductOverrideHandler_SetOverride(t *testing.T) {
+	// Arrange
+	mockService := new(MockProductOverrideService)
+	router := newProductOverrideRouter(mockService)
+
+	t.Run("successful override", func(t *testing.T) {
+		// Arrange
+		productID := uuid.New()
+		name := "Greek Salad"
+		expected := &domain.ProductOverride{ProductID: productID, Pinned: true, Name: &name, CreatedAt: time.Now(), UpdatedAt: time.Now()}
+		mockService.On("SetOverride", mock.Anything, productID, false, true,
+			mock.MatchedBy(func(value *string) bool { return value != nil && *value == name }),
+			(*string)(nil), (*string)(nil)).Return(expected, nil).Once()
+
+		body, _ := json.Marshal(map[string]interface{}{"pinned": true, "name": name})
+		req := httptest.NewRequest("PUT", "/admin/products/"+productID.String()+"/override", bytes.NewBuffer(body))
+		rec := httptest.NewRecorder()
+
+		// Act
+		router.ServeHTTP(rec, req)
+
+		// Assert
+		require.Equal(t, http.StatusOK, rec.Code)
+
+		var response models.ProductOverrideResponse
+		err := json.NewDecoder(rec.Body).Decode(&response)
+		require.NoError(t, err)
+		assert.Equal(t, productID, response.ProductID)
+		assert.True(t, response.Pinned)
+		require.NotNil(t, response.Name)
+		assert.Equal(t, name, *response.Name)
+
+		mockService.AssertExpectations(t)
+	})
+
+	t.Run("invalid override", func(t *testing.T) {
+		// Arrange
+		productID := uuid.New()
+		mockService.On("SetOverride", mock.Anything, productID, false, false, (*string)(nil), (*string)(nil), (*string)(nil)).
+			Return(nil, domain.ErrEmptyOverride).Once()
+
+		req := httptest.NewRequest("PUT", "/admin/products/"+productID.String()+"/override", bytes.NewBufferString(`{}`))
+		rec := httptest.NewRecorder()
+
+		// Act
+		router.ServeHTTP(rec, req)
+
+		// Assert
+		assert.Equal(t, http.StatusBadRequest, rec.Code)
+		assert.Contains(t, rec.Body.String(), domain.ErrEmptyOverride.Error())
+		mockService.AssertExpectations(t)
+	})
+
+	t.Run("invalid product ID", func(t *testing.T) {
+		// Arrange
+		req := httptest.NewRequest("PUT", "/admin/products/not-a-uuid/override", bytes.NewBufferString(`{"hidden":true}`))
+		rec := httptest.NewRecorder()
+
+		// Act
+		router.ServeHTTP(rec, req)
+
+		// Assert
+		assert.Equal(t, http.StatusBadRequest, rec.Code)
+	})
+
+	t.Run("invalid request body", func(t *testing.T) {
+		// Arrange
+		req := httptest.NewRequest("PUT", "/admin/products/"+uuid.New().String()+"/override", bytes.NewBufferString(`{`))
+		rec := httptest.NewRecorder()
+
+		// Act
+		router.ServeHTTP(rec, req)
+
+		// Assert
+		assert.Equal(t, http.StatusBadRequest, rec.Code)
+	})
+}
+
+func TestProductOverrideHandler_GetOverride(t *testing.T) {
+	// Arrange
+	mockService := new(MockProductOverrideService)
+	router := newProductOverrideRouter(mockService)
+
+	t.Run("not found", func(t *testing.T) {
+		// Arrange
+		productID := uuid.New()
+		mockService.On("GetOverride", mock.Anything, productID).Return(nil, domain.ErrOverrideNotFound).Once()
+
+		req := httptest.NewRequest("GET", "/admin/products/"+productID.String()+"/override", nil)
+		rec := httptest.NewRecorder()
+
+		// Act
+		router.ServeHTTP(rec, req)
+
+		// Assert
+		assert.Equal(t, http.StatusNotFound, rec.Code)
+		mockService.AssertExpectations(t)
+	})
+}
+
+func TestProductOverrideHandler_ListOverrides(t *testing.T) {
+	// Arrange
+	mockService := new(MockProductOverrideService)
+	router := newProductOverrideRouter(mockService)
+	overrides := []*domain.ProductOverride{{ProductID: uuid.New(), Hidden: true}}
+	mockService.On("ListOverrides", mock.Anything).Return(overrides, nil).Once()
+
+	req := httptest.NewRequest("GET", "/admin/products/overrides", nil)
+	rec := httptest.NewRecorder()
+
+	// Act
+	router.ServeHTTP(rec, req)
+
+	// Assert
+	require.Equal(t, http.StatusOK, rec.Code)
+
+	var response models.ProductOverrideListResponse
+	err := json.NewDecoder(rec.Body).Decode(&response)
+	require.NoError(t, err)
+	require.Equal(t, 1, response.Count)
+	assert.True(t, response.Overrides[0].Hidden)
+	mockService.AssertExpectations(t)
+}
+
+func TestProductOverrideHandler_DeleteOverride(t *testing.T) {
+	// Arrange
+	mockService := new(MockProductOverrideService)
+	router := newProductOverrideRouter(mockService)
+
+	t.Run("successful deletion", func(t *testing.T) {
+		// Arrange
+		productID := uuid.New()
+		mockService.On("DeleteOverride", mock.Anything, productID).Return(nil).Once()
+
+		req := httptest.NewRequest("DELETE", "/admin/products/"+productID.String()+"/override", nil)
+		rec := httptest.NewRecorder()
+
+		// Act
+		router.ServeHTTP(rec, req)
+
+		// Assert
+		assert.Equal(t, http.StatusNoContent, rec.Code)
+		mockService.AssertExpectations(t)
+	})
+
+	t.Run("not found", func(t *testing.T) {
+		// Arrange
+		productID := uuid.New()
+		mockService.On("DeleteOverride", mock.Anything, productID).Return(domain.ErrOverrideNotFound).Once()
+
+		req := httptest.NewRequest("DELETE", "/admin/products/"+productID.String()+"/override", nil)
+		rec := httptest.NewRecorder()
+
+		// Act
+		router.ServeHTTP(rec, req)
+
+		// Assert
+		assert.Equal(t, http.StatusNotFound, rec.Code)
+		mockService.AssertExpectations(t)
+	})
+}
@@ -0,0 +1,108 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/ArtemSind/food_tinder/internal/domain"
+	"github.com/ArtemSind/food_tinder/internal/infrastructure/http/i18n"
+	httpModels "github.com/ArtemSind/food_tinder/internal/infrastructure/http/models"
+	"github.com/google/uuid"
+	"github.com/gorilla/mux"
+)
+
+type RecommendationService interface {
+	GetRecommendations(ctx context.Context, sessionID uuid.UUID, limit int, locale domain.Locale) ([]*domain.Recommendation, error)
+	GetSimilarProducts(ctx context.Context, productID uuid.UUID, limit int) ([]domain.SimilarProduct, error)
+}
+
+type RecommendationHandler struct {
+	recommendationService RecommendationService
+}
+
+func NewRecommendationHandler(recommendationService RecommendationService) *RecommendationHandler {
+	return &RecommendationHandler{recommendationService: recommendationService}
+}
+
+func (h *RecommendationHandler) GetRecommendations(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	sessionID, err := uuid.Parse(vars["sessionID"])
+	if err != nil {
+		http.Error(w, "Invalid session ID", http.StatusBadRequest)
+		return
+	}
+
+	limit, err := parseLimit(r)
+	if err != nil {
+		http.Error(w, "Invalid limit", http.StatusBadRequest)
+		return
+	}
+
+	ctx := r.Context()
+	locale := i18n.FromContext(ctx)
+	recommendations, err := h.recommendationService.GetRecommendations(ctx, sessionID, limit, locale)
+	if err != nil {
+		switch {
+		case errors.Is(err, domain.ErrSessionNotFound):
+			http.Error(w, "Session not found", http.StatusNotFound)
+		case errors.Is(err, domain.ErrModelNotReady):
+			http.Error(w, "Recommendations are not available yet", http.StatusServiceUnavailable)
+		default:
+			http.Error(w, "Failed to get recommendations", http.StatusInternalServerError)
+		}
+		return
+	}
+
+	response := httpModels.RecommendationListResponseFromDomain(recommendations, locale)
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(response)
+}
+
+func (h *RecommendationHandler) GetSimilarProducts(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	productID, err := uuid.Parse(vars["productID"])
+	if err != nil {
+		http.Error(w, "Invalid product ID", http.StatusBadRequest)
+		return
+	}
+
+	limit, err := parseLimit(r)
+	if err != nil {
+		http.Error(w, "Invalid limit", http.StatusBadRequest)
+		return
+	}
+
+	ctx := r.Context()
+	similar, err := h.recommendationService.GetSimilarProducts(ctx, productID, limit)
+	if err != nil {
+		switch {
+		case errors.Is(err, domain.ErrProductNotFound):
+			http.Error(w, "Product not found", http.StatusNotFound)
+		case errors.Is(err, domain.ErrModelNotReady):
+			http.Error(w, "Similar products are not available yet", http.StatusServiceUnavailable)
+		default:
+			http.Error(w, "Failed to get similar products", http.StatusInternalServerError)
+		}
+		return
+	}
+
+	response := httpModels.SimilarProductListResponseFromDomain(productID, similar)
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(response)
+}
+
+// parseLimit reads the optional positive "limit" query parameter; zero means not set
+func parseLimit(r *http.Request) (int, error) {
+	rawLimit := r.URL.Query().Get("limit")
+	if rawLimit == "" {
+		return 0, nil
+	}
+	limit, err := strconv.Atoi(rawLimit)
+	if err != nil || limit < 1 {
+		return 0, errors.New("invalid limit")
+	}
+	return limit, nil
+}
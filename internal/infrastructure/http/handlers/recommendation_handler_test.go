@@ -0,0 +1,219 @@
+package handlers_test
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/ArtemSind/food_tinder/internal/domain"
+	"github.com/ArtemSind/food_tinder/internal/infrastructure/http/handlers"
+	"github.com/ArtemSind/food_tinder/internal/infrastructure/http/i18n"
+	"github.com/ArtemSind/food_tinder/internal/infrastructure/http/models"
+	"github.com/google/uuid"
+	"github.com/gorilla/mux"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+// Mock RecommendationService
+type MockRecommendationService struct {
+	mock.Mock
+}
+
+func (m *MockRecommendationService) GetRecommendations(ctx context.Context, sessionID uuid.UUID, limit int, locale domain.Locale) ([]*domain.Recommendation, error) {
+	args := m.Called(ctx, sessionID, limit, locale)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*domain.Recommendation), args.Error(1)
+}
+
+func (m *MockRecommendationService) GetSimilarProducts(ctx context.Context, productID uuid.UUID, limit int) ([]domain.SimilarProduct, error) {
+	args := m.Called(ctx, productID, limit)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]domain.SimilarProduct), args.Error(1)
+}
+
+func TestRecommendationHandler_GetRecommendations(t *testing.T) {
+	// Arrange
+	mockService := new(MockRecommendationService)
+	handler := handlers.NewRecommendationHandler(mockService)
+	router := mux.NewRouter()
+	router.HandleFunc("/api/sessions/{sessionID}/recommendations", handler.GetRecommendations).Methods("GET")
+
+	t.Run("successful retrieval", func(t *testing.T) {
+		// Arrange
+		sessionID := uuid.New()
+		liked := uuid.New()
+		expected := []*domain.Recommendation{
+			{ProductID: uuid.New(), PredictedScore: 4.7, BecauseOf: liked, BecauseOfName: "Greek Salad"},
+		}
+		mockService.On("GetRecommendations", mock.Anything, sessionID, 5, domain.LocaleEnglish).Return(expected, nil).Once()
+
+		req := httptest.NewRequest("GET", "/api/sessions/"+sessionID.String()+"/recommendations?limit=5", nil)
+		rec := httptest.NewRecorder()
+
+		// Act
+		router.ServeHTTP(rec, req)
+
+		// Assert
+		require.Equal(t, http.StatusOK, rec.Code)
+
+		var response models.RecommendationListResponse
+		err := json.NewDecoder(rec.Body).Decode(&response)
+		require.NoError(t, err)
+		require.Len(t, response.Recommendations, 1)
+		assert.Equal(t, expected[0].ProductID, response.Recommendations[0].ProductID)
+		assert.Equal(t, liked, response.Recommendations[0].BecauseYouLiked)
+		assert.Equal(t, "Greek Salad", response.Recommendations[0].BecauseYouLikedName)
+		assert.Equal(t, "because you liked Greek Salad", response.Recommendations[0].Explanation)
+
+		mockService.AssertExpectations(t)
+	})
+
+	t.Run("German explanations for a German request", func(t *testing.T) {
+		// Arrange
+		localized := mux.NewRouter()
+		localized.Use(i18n.Middleware(nil))
+		localized.HandleFunc("/api/sessions/{sessionID}/recommendations", handler.GetRecommendations).Methods("GET")
+
+		sessionID := uuid.New()
+		expected := []*domain.Recommendation{
+			{ProductID: uuid.New(), PredictedScore: 4.2, BecauseOf: uuid.New(), BecauseOfName: "Griechischer Salat"},
+			{ProductID: uuid.New(), PredictedScore: 3.9, BecauseOf: uuid.New()},
+		}
+		mockService.On("GetRecommendations", mock.Anything, sessionID, 0, domain.LocaleGerman).Return(expected, nil).Once()
+
+		req := httptest.NewRequest("GET", "/api/sessions/"+sessionID.String()+"/recommendations", nil)
+		req.Header.Set("Accept-Language", "de")
+		rec := httptest.NewRecorder()
+
+		// Act
+		localized.ServeHTTP(rec, req)
+
+		// Assert
+		require.Equal(t, http.StatusOK, rec.Code)
+
+		var response models.RecommendationListResponse
+		err := json.NewDecoder(rec.Body).Decode(&response)
+		require.NoError(t, err)
+		require.Len(t, response.Recommendations, 2)
+		assert.Equal(t, "weil dir Griechischer Salat gefallen hat", response.Recommendations[0].Explanation)
+		assert.Equal(t, "weil dir ein Produkt gefallen hat, das du bewertet hast", response.Recommendations[1].Explanation,
+			"a liked product that left the catalog is not named")
+
+		mockService.AssertExpectations(t)
+	})
+
+	t.Run("invalid limit", func(t *testing.T) {
+		// Arrange
+		req := httptest.NewRequest("GET", "/api/sessions/"+uuid.New().String()+"/recommendations?limit=abc", nil)
+		rec := httptest.NewRecorder()
+
+		// Act
+		router.ServeHTTP(rec, req)
+
+		// Assert
+		assert.Equal(t, http.StatusBadRequest, rec.Code)
+	})
+
+	t.Run("model not ready", func(t *testing.T) {
+		// Arrange
+		sessionID := uuid.New()
+		mockService.On("GetRecommendations", mock.Anything, sessionID, 0, domain.LocaleEnglish).Return(nil, domain.ErrModelNotReady).Once()
+
+		req := httptest.NewRequest("GET", "/api/sessions/"+sessionID.String()+"/recommendations", nil)
+		rec := httptest.NewRecorder()
+
+		// Act
+		router.ServeHTTP(rec, req)
+
+		// Assert
+		assert.Equal(t, http.StatusServiceUnavailable, rec.Code)
+		mockService.AssertExpectations(t)
+	})
+
+	t.Run("session not found", func(t *testing.T) {
+		// Arrange
+		sessionID := uuid.New()
+		mockService.On("GetRecommendations", mock.Anything, sessionID, 0, domain.LocaleEnglish).Return(nil, domain.ErrSessionNotFound).Once()
+
+		req := httptest.NewRequest("GET", "/api/sessions/"+sessionID.String()+"/recommendations", nil)
+		rec := httptest.NewRecorder()
+
+		// Act
+		router.ServeHTTP(rec, req)
+
+		// Assert
+		assert.Equal(t, http.StatusNotFound, rec.Code)
+		assert.Contains(t, rec.Body.String(), "Session not found")
+		mockService.AssertExpectations(t)
+	})
+}
+
+func TestRecommendationHandler_GetSimilarProducts(t *testing.T) {
+	// Arrange
+	mockService := new(MockRecommendationService)
+	handler := handlers.NewRecommendationHandler(mockService)
+	router := mux.NewRouter()
+	router.HandleFunc("/api/products/{productID}/similar", handler.GetSimilarProducts).Methods("GET")
+
+	t.Run("successful retrieval", func(t *testing.T) {
+		// Arrange
+		productID := uuid.New()
+		expected := []domain.SimilarProduct{{ProductID: uuid.New(), Similarity: 0.8, CoRatings: 4}}
+		mockService.On("GetSimilarProducts", mock.Anything, productID, 0).Return(expected, nil).Once()
+
+		req := httptest.NewRequest("GET", "/api/products/"+productID.String()+"/similar", nil)
+		rec := httptest.NewRecorder()
+
+		// Act
+		router.ServeHTTP(rec, req)
+
+		// Assert
+		require.Equal(t, http.StatusOK, rec.Code)
+
+		var response models.SimilarProductListResponse
+		err := json.NewDecoder(rec.Body).Decode(&response)
+		require.NoError(t, err)
+		assert.Equal(t, productID, response.ProductID)
+		require.Len(t, response.Similar, 1)
+		assert.Equal(t, expected[0].ProductID, response.Similar[0].ProductID)
+		assert.Equal(t, 0.8, response.Similar[0].Similarity)
+
+		mockService.AssertExpectations(t)
+	})
+
+	t.Run("product not found", func(t *testing.T) {
+		// Arrange
+		productID := uuid.New()
+		mockService.On("GetSimilarProducts", mock.Anything, productID, 0).Return(nil, domain.ErrProductNotFound).Once()
+
+		req := httptest.NewRequest("GET", "/api/products/"+productID.String()+"/similar", nil)
+		rec := httptest.NewRecorder()
+
+		// Act
+		router.ServeHTTP(rec, req)
+
+		// Assert
+		assert.Equal(t, http.StatusNotFound, rec.Code)
+		mockService.AssertExpectations(t)
+	})
+
+	t.Run("invalid product ID", func(t *testing.T) {
+		// Arrange
+		req := httptest.NewRequest("GET", "/api/products/invalid-uuid/similar", nil)
+		rec := httptest.NewRecorder()
+
+		// Act
+		router.ServeHTTP(rec, req)
+
+		// Assert
+		assert.Equal(t, http.StatusBadRequest, rec.Code)
+	})
+}
@@ -3,6 +3,7 @@ package handlers
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"net/http"
 
 	"github.com/ArtemSind/food_tinder/internal/domain"
@@ -14,6 +15,9 @@ import (
 type SessionService interface {
 	CreateSession(ctx context.Context) (*domain.Session, error)
 	GetSession(ctx context.Context, id uuid.UUID) (*domain.Session, error)
+	SetDeckStrategy(ctx context.Context, id uuid.UUID, strategy string) (*domain.Session, error)
+	SetDietaryPreferences(ctx context.Context, id uuid.UUID, excludedAllergens, diets []string) (*domain.Session, error)
+	SetLocale(ctx context.Context, id uuid.UUID, locale string) (*domain.Session, error)
 }
 
 type SessionHandler struct {
@@ -57,3 +61,104 @@ func (h *SessionHandler) GetSession(w http.ResponseWriter, r *http.Request) {
 	w.Header().Set("Content-Type", "application/json")
 	json.NewEncoder(w).Encode(response)
 }
+
+func (h *SessionHandler) SetDeckStrategy(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	sessionID, err := uuid.Parse(vars["sessionID"])
+	if err != nil {
+		http.Error(w, "Invalid session ID", http.StatusBadRequest)
+		return
+	}
+
+	var req httpModels.DeckStrategyRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	ctx := r.Context()
+	session, err := h.sessionService.SetDeckStrategy(ctx, sessionID, req.DeckStrategy)
+	if err != nil {
+		switch {
+		case errors.Is(err, domain.ErrInvalidDeckStrategy):
+			http.Error(w, err.Error(), http.StatusBadRequest)
+		case errors.Is(err, domain.ErrSessionNotFound):
+			http.Error(w, "Session not found", http.StatusNotFound)
+		default:
+			http.Error(w, "Failed to update session", http.StatusInternalServerError)
+		}
+		return
+	}
+
+	response := httpModels.SessionResponseFromDomain(session)
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(response)
+}
+
+// SetDietaryPreferences replaces the allergens and diets that rule products out of the session's deck
+func (h *SessionHandler) SetDietaryPreferences(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	sessionID, err := uuid.Parse(vars["sessionID"])
+	if err != nil {
+		http.Error(w, "Invalid session ID", http.StatusBadRequest)
+		return
+	}
+
+	var req httpModels.DietaryPreferencesRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	ctx := r.Context()
+	session, err := h.sessionService.SetDietaryPreferences(ctx, sessionID, req.ExcludeAllergens, req.Diets)
+	if err != nil {
+		switch {
+		case errors.Is(err, domain.ErrInvalidDietaryPreferences):
+			http.Error(w, err.Error(), http.StatusBadRequest)
+		case errors.Is(err, domain.ErrSessionNotFound):
+			http.Error(w, "Session not found", http.StatusNotFound)
+		default:
+			http.Error(w, "Failed to update session", http.StatusInternalServerError)
+		}
+		return
+	}
+
+	response := httpModels.SessionResponseFromDomain(session)
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(response)
+}
+
+// SetLocale chooses the language of the session's product texts and messages
+func (h *SessionHandler) SetLocale(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	sessionID, err := uuid.Parse(vars["sessionID"])
+	if err != nil {
+		http.Error(w, "Invalid session ID", http.StatusBadRequest)
+		return
+	}
+
+	var req httpModels.LocaleRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	ctx := r.Context()
+	session, err := h.sessionService.SetLocale(ctx, sessionID, req.Locale)
+	if err != nil {
+		switch {
+		case errors.Is(err, domain.ErrInvalidLocale):
+			http.Error(w, err.Error(), http.StatusBadRequest)
+		case errors.Is(err, domain.ErrSessionNotFound):
+			http.Error(w, "Session not found", http.StatusNotFound)
+		default:
+			http.Error(w, "Failed to update session", http.StatusInternalServerError)
+		}
+		return
+	}
+
+	response := httpModels.SessionResponseFromDomain(session)
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(response)
+}
@@ -1,6 +1,7 @@
 package handlers_test
 
 import (
+	"bytes"
 	"context"
 	"encoding/json"
 	"errors"
@@ -10,6 +11,7 @@ import (
 
 	"github.com/ArtemSind/food_tinder/internal/domain"
 	"github.com/ArtemSind/food_tinder/internal/infrastructure/http/handlers"
+	"github.com/ArtemSind/food_tinder/internal/infrastructure/http/models"
 	"github.com/google/uuid"
 	"github.com/gorilla/mux"
 	"github.com/stretchr/testify/assert"
@@ -38,6 +40,30 @@ func (m *MockSessionService) GetSession(ctx context.Context, id uuid.UUID) (*dom
 	return args.Get(0).(*domain.Session), args.Error(1)
 }
 
+func (m *MockSessionService) SetDeckStrategy(ctx context.Context, id uuid.UUID, strategy string) (*domain.Session, error) {
+	args := m.Called(ctx, id, strategy)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*domain.Session), args.Error(1)
+}
+
+func (m *MockSessionService) SetDietaryPreferences(ctx context.Context, id uuid.UUID, excludedAllergens, diets []string) (*domain.Session, error) {
+	args := m.Called(ctx, id, excludedAllergens, diets)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*domain.Session), args.Error(1)
+}
+
+func (m *MockSessionService) SetLocale(ctx context.Context, id uuid.UUID, locale string) (*domain.Session, error) {
+	args := m.Called(ctx, id, locale)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*domain.Session), args.Error(1)
+}
+
 func TestSessionHandler_CreateSession(t *testing.T) {
 	// Arrange
 	mockService := new(MockSessionService)
@@ -152,3 +178,208 @@ func TestSessionHandler_GetSession(t *testing.T) {
 		mockService.AssertExpectations(t)
 	})
 }
+
+func TestSessionHandler_SetDeckStrategy(t *testing.T) {
+	// Arrange
+	mockService := new(MockSessionService)
+	handler := handlers.NewSessionHandler(mockService)
+	router := mux.NewRouter()
+	router.HandleFunc("/api/sessions/{sessionID}/deck-strategy", handler.SetDeckStrategy).Methods("PUT")
+
+	t.Run("successful update", func(t *testing.T) {
+		// Arrange
+		sessionID := uuid.New()
+		session := &domain.Session{ID: sessionID, DeckStrategy: domain.DeckPopularity}
+		mockService.On("SetDeckStrategy", mock.Anything, sessionID, "popularity").Return(session, nil).Once()
+
+		req := httptest.NewRequest("PUT", "/api/sessions/"+sessionID.String()+"/deck-strategy",
+			bytes.NewBufferString(`{"deck_strategy":"popularity"}`))
+		rec := httptest.NewRecorder()
+
+		// Act
+		router.ServeHTTP(rec, req)
+
+		// Assert
+		require.Equal(t, http.StatusOK, rec.Code)
+
+		var responseSession domain.Session
+		err := json.NewDecoder(rec.Body).Decode(&responseSession)
+		require.NoError(t, err)
+		assert.Equal(t, domain.DeckPopularity, responseSession.DeckStrategy)
+
+		mockService.AssertExpectations(t)
+	})
+
+	t.Run("unknown strategy", func(t *testing.T) {
+		// Arrange
+		sessionID := uuid.New()
+		mockService.On("SetDeckStrategy", mock.Anything, sessionID, "alphabetical").
+			Return(nil, domain.ErrInvalidDeckStrategy).Once()
+
+		req := httptest.NewRequest("PUT", "/api/sessions/"+sessionID.String()+"/deck-strategy",
+			bytes.NewBufferString(`{"deck_strategy":"alphabetical"}`))
+		rec := httptest.NewRecorder()
+
+		// Act
+		router.ServeHTTP(rec, req)
+
+		// Assert
+		assert.Equal(t, http.StatusBadRequest, rec.Code)
+		mockService.AssertExpectations(t)
+	})
+
+	t.Run("session not found", func(t *testing.T) {
+		// Arrange
+		sessionID := uuid.New()
+		mockService.On("SetDeckStrategy", mock.Anything, sessionID, "bandit").
+			Return(nil, domain.ErrSessionNotFound).Once()
+
+		req := httptest.NewRequest("PUT", "/api/sessions/"+sessionID.String()+"/deck-strategy",
+			bytes.NewBufferString(`{"deck_strategy":"bandit"}`))
+		rec := httptest.NewRecorder()
+
+		// Act
+		router.ServeHTTP(rec, req)
+
+		// Assert
+		assert.Equal(t, http.StatusNotFound, rec.Code)
+		mockService.AssertExpectations(t)
+	})
+}
+
+func TestSessionHandler_SetDietaryPreferences(t *testing.T) {
+	// Arrange
+	mockService := new(MockSessionService)
+	handler := handlers.NewSessionHandler(mockService)
+	router := mux.NewRouter()
+	router.HandleFunc("/api/sessions/{sessionID}/dietary-preferences", handler.SetDietaryPreferences).Methods("PUT")
+
+	t.Run("successful update", func(t *testing.T) {
+		// Arrange
+		sessionID := uuid.New()
+		session := &domain.Session{
+			ID:           sessionID,
+			DeckStrategy: domain.DeckRandom,
+			Dietary:      domain.DietaryPreferences{ExcludedAllergens: []string{"nuts"}, Diets: []string{"vegetarian"}},
+		}
+		mockService.On("SetDietaryPreferences", mock.Anything, sessionID, []string{"nuts"}, []string{"vegetarian"}).Return(session, nil).Once()
+
+		req := httptest.NewRequest("PUT", "/api/sessions/"+sessionID.String()+"/dietary-preferences",
+			bytes.NewBufferString(`{"exclude_allergens":["nuts"],"diets":["vegetarian"]}`))
+		rec := httptest.NewRecorder()
+
+		// Act
+		router.ServeHTTP(rec, req)
+
+		// Assert
+		require.Equal(t, http.StatusOK, rec.Code)
+
+		var response models.SessionResponse
+		err := json.NewDecoder(rec.Body).Decode(&response)
+		require.NoError(t, err)
+		require.NotNil(t, response.DietaryPreferences)
+		assert.Equal(t, []string{"nuts"}, response.DietaryPreferences.ExcludeAllergens)
+		assert.Equal(t, []string{"vegetarian"}, response.DietaryPreferences.Diets)
+
+		mockService.AssertExpectations(t)
+	})
+
+	t.Run("invalid preferences", func(t *testing.T) {
+		// Arrange
+		sessionID := uuid.New()
+		mockService.On("SetDietaryPreferences", mock.Anything, sessionID, []string{""}, []string(nil)).
+			Return(nil, domain.ErrInvalidDietaryPreferences).Once()
+
+		req := httptest.NewRequest("PUT", "/api/sessions/"+sessionID.String()+"/dietary-preferences",
+			bytes.NewBufferString(`{"exclude_allergens":[""]}`))
+		rec := httptest.NewRecorder()
+
+		// Act
+		router.ServeHTTP(rec, req)
+
+		// Assert
+		assert.Equal(t, http.StatusBadRequest, rec.Code)
+		mockService.AssertExpectations(t)
+	})
+
+	t.Run("session not found", func(t *testing.T) {
+		// Arrange
+		sessionID := uuid.New()
+		mockService.On("SetDietaryPreferences", mock.Anything, sessionID, []string(nil), []string{"vegan"}).
+			Return(nil, domain.ErrSessionNotFound).Once()
+
+		req := httptest.NewRequest("PUT", "/api/sessions/"+sessionID.String()+"/dietary-preferences",
+			bytes.NewBufferString(`{"diets":["vegan"]}`))
+		rec := httptest.NewRecorder()
+
+		// Act
+		router.ServeHTTP(rec, req)
+
+		// Assert
+		assert.Equal(t, http.StatusNotFound, rec.Code)
+		mockService.AssertExpectations(t)
+	})
+}
+
+func TestSessionHandler_SetLocale(t *testing.T) {
+	// Arrange
+	mockService := new(MockSessionService)
+	handler := handlers.NewSessionHandler(mockService)
+	router := mux.NewRouter()
+	router.HandleFunc("/api/sessions/{sessionID}/locale", handler.SetLocale).Methods("PUT")
+
+	t.Run("successful update", func(t *testing.T) {
+		// Arrange
+		sessionID := uuid.New()
+		session := &domain.Session{ID: sessionID, DeckStrategy: domain.DeckRandom, Locale: domain.LocaleGerman}
+		mockService.On("SetLocale", mock.Anything, sessionID, "de").Return(session, nil).Once()
+
+		req := httptest.NewRequest("PUT", "/api/sessions/"+sessionID.String()+"/locale", bytes.NewBufferString(`{"locale":"de"}`))
+		rec := httptest.NewRecorder()
+
+		// Act
+		router.ServeHTTP(rec, req)
+
+		// Assert
+		require.Equal(t, http.StatusOK, rec.Code)
+
+		var response models.SessionResponse
+		err := json.NewDecoder(rec.Body).Decode(&response)
+		require.NoError(t, err)
+		assert.Equal(t, "de", response.Locale)
+
+		mockService.AssertExpectations(t)
+	})
+
+	t.Run("unsupported locale", func(t *testing.T) {
+		// Arrange
+		sessionID := uuid.New()
+		mockService.On("SetLocale", mock.Anything, sessionID, "fr").Return(nil, domain.ErrInvalidLocale).Once()
+
+		req := httptest.NewRequest("PUT", "/api/sessions/"+sessionID.String()+"/locale", bytes.NewBufferString(`{"locale":"fr"}`))
+		rec := httptest.NewRecorder()
+
+		// Act
+		router.ServeHTTP(rec, req)
+
+		// Assert
+		assert.Equal(t, http.StatusBadRequest, rec.Code)
+		mockService.AssertExpectations(t)
+	})
+
+	t.Run("session not found", func(t *testing.T) {
+		// Arrange
+		sessionID := uuid.New()
+		mockService.On("SetLocale", mock.Anything, sessionID, "en").Return(nil, domain.ErrSessionNotFound).Once()
+
+		req := httptest.NewRequest("PUT", "/api/sessions/"+sessionID.String()+"/locale", bytes.NewBufferString(`{"locale":"en"}`))
+		rec := httptest.NewRecorder()
+
+		// Act
+		router.ServeHTTP(rec, req)
+
+		// Assert
+		assert.Equal(t, http.StatusNotFound, rec.Code)
+		mockService.AssertExpectations(t)
+	})
+}
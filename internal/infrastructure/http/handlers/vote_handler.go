@@ -14,7 +14,8 @@ import (
 type VoteService interface {
 	CreateOrUpdateVote(ctx context.Context, sessionID, productID uuid.UUID, score int) (*domain.Vote, error)
 	GetVotesBySession(ctx context.Context, sessionID uuid.UUID) ([]*domain.Vote, error)
-	GetAggregatedScores(ctx context.Context) ([]*domain.ProductScore, error)
+	GetAggregatedScores(ctx context.Context, ranking domain.ScoreRanking) ([]*domain.ProductScore, error)
+	GetUnvotedViews(ctx context.Context) ([]*domain.ProductViews, error)
 }
 
 type VoteHandler struct {
@@ -78,15 +79,28 @@ func (h *VoteHandler) GetVotesBySession(w http.ResponseWriter, r *http.Request)
 	json.NewEncoder(w).Encode(response)
 }
 
+// GetAggregatedScores returns the aggregated scores of all voted products, ranked by score or
+// by value, and lists the products that were shown but never voted on separately
 func (h *VoteHandler) GetAggregatedScores(w http.ResponseWriter, r *http.Request) {
+	ranking, err := domain.ParseScoreRanking(r.URL.Query().Get("rank"))
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
 	ctx := r.Context()
-	scores, err := h.voteService.GetAggregatedScores(ctx)
+	scores, err := h.voteService.GetAggregatedScores(ctx, ranking)
+	if err != nil {
+		http.Error(w, "Failed to get aggregated scores", http.StatusInternalServerError)
+		return
+	}
+	unvoted, err := h.voteService.GetUnvotedViews(ctx)
 	if err != nil {
 		http.Error(w, "Failed to get aggregated scores", http.StatusInternalServerError)
 		return
 	}
 
-	response := httpModels.ProductScoreListResponseFromDomain(scores)
+	response := httpModels.ProductScoreListResponseFromDomain(scores, unvoted)
 	w.Header().Set("Content-Type", "application/json")
 	json.NewEncoder(w).Encode(response)
 }
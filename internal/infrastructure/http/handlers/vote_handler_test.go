@@ -8,6 +8,7 @@ import (
 	"net/http"
 	"net/http/httptest"
 	"testing"
+	"time"
 
 	"github.com/ArtemSind/food_tinder/internal/domain"
 	"github.com/ArtemSind/food_tinder/internal/infrastructure/http/handlers"
@@ -37,11 +38,16 @@ func (m *MockVoteService) GetVotesBySession(ctx context.Context, sessionID uuid.
 	return args.Get(0).([]*domain.Vote), args.Error(1)
 }
 
-func (m *MockVoteService) GetAggregatedScores(ctx context.Context) ([]*domain.ProductScore, error) {
-	args := m.Called(ctx)
+func (m *MockVoteService) GetAggregatedScores(ctx context.Context, ranking domain.ScoreRanking) ([]*domain.ProductScore, error) {
+	args := m.Called(ctx, ranking)
 	return args.Get(0).([]*domain.ProductScore), args.Error(1)
 }
 
+func (m *MockVoteService) GetUnvotedViews(ctx context.Context) ([]*domain.ProductViews, error) {
+	args := m.Called(ctx)
+	return args.Get(0).([]*domain.ProductViews), args.Error(1)
+}
+
 func TestVoteHandler_CreateOrUpdateVote(t *testing.T) {
 	// Arrange
 	mockService := new(MockVoteService)
@@ -280,7 +286,8 @@ func TestVoteHandler_GetAggregatedScores(t *testing.T) {
 			},
 		}
 
-		mockService.On("GetAggregatedScores", mock.Anything).Return(expectedScores, nil).Once()
+		mockService.On("GetAggregatedScores", mock.Anything, domain.RankByScore).Return(expectedScores, nil).Once()
+		mockService.On("GetUnvotedViews", mock.Anything).Return([]*domain.ProductViews{}, nil).Once()
 
 		// Create request and recorder
 		req := httptest.NewRequest("GET", "/api/votes/aggregated", nil)
@@ -306,9 +313,159 @@ func TestVoteHandler_GetAggregatedScores(t *testing.T) {
 		mockService.AssertExpectations(t)
 	})
 
+	t.Run("scores are split by product version", func(t *testing.T) {
+		// Arrange
+		expectedScores := []*domain.ProductScore{{
+			ProductID: uuid.New(),
+			AvgScore:  3.8,
+			VoteCount: 10,
+			Versions: []domain.ProductVersionScore{
+				{Version: "old-hash", AvgScore: 4.25, VoteCount: 8},
+				{Version: "new-hash", AvgScore: 2, VoteCount: 2, Current: true},
+			},
+		}}
+
+		mockService.On("GetAggregatedScores", mock.Anything, domain.RankByScore).Return(expectedScores, nil).Once()
+		mockService.On("GetUnvotedViews", mock.Anything).Return([]*domain.ProductViews{}, nil).Once()
+
+		req := httptest.NewRequest("GET", "/api/votes/aggregated", nil)
+		rec := httptest.NewRecorder()
+
+		// Act
+		handler.GetAggregatedScores(rec, req)
+
+		// Assert
+		require.Equal(t, http.StatusOK, rec.Code)
+
+		var response models.ProductScoreListResponse
+		err := json.NewDecoder(rec.Body).Decode(&response)
+		require.NoError(t, err)
+		require.Len(t, response.Scores[0].Versions, 2)
+		assert.Equal(t, "old-hash", response.Scores[0].Versions[0].Version)
+		assert.False(t, response.Scores[0].Versions[0].Current)
+		assert.Equal(t, 2, response.Scores[0].Versions[1].VoteCount)
+		assert.True(t, response.Scores[0].Versions[1].Current)
+
+		mockService.AssertExpectations(t)
+	})
+
+	t.Run("discontinued products keep their details", func(t *testing.T) {
+		// Arrange
+		productID := uuid.New()
+		discontinuedAt := time.Now()
+		product := domain.NewCatalogProduct(productID, json.RawMessage(`{}`), json.RawMessage(`{"id":"`+productID.String()+`"}`))
+		product.DiscontinuedAt = &discontinuedAt
+		expectedScores := []*domain.ProductScore{
+			{ProductID: productID, AvgScore: 4, VoteCount: 3, Product: product},
+			{ProductID: uuid.New(), AvgScore: 2, VoteCount: 1},
+		}
+
+		mockService.On("GetAggregatedScores", mock.Anything, domain.RankByScore).Return(expectedScores, nil).Once()
+		mockService.On("GetUnvotedViews", mock.Anything).Return([]*domain.ProductViews{}, nil).Once()
+
+		req := httptest.NewRequest("GET", "/api/votes/aggregated", nil)
+		rec := httptest.NewRecorder()
+
+		// Act
+		handler.GetAggregatedScores(rec, req)
+
+		// Assert
+		require.Equal(t, http.StatusOK, rec.Code)
+
+		var responseData models.ProductScoreListResponse
+		err := json.NewDecoder(rec.Body).Decode(&responseData)
+		require.NoError(t, err)
+		require.Len(t, responseData.Scores, 2)
+		assert.True(t, responseData.Scores[0].Discontinued)
+		assert.JSONEq(t, `{"id":"`+productID.String()+`"}`, string(responseData.Scores[0].Product))
+		assert.NotNil(t, responseData.Scores[0].LastSeenAt)
+		assert.False(t, responseData.Scores[1].Discontinued)
+		assert.Nil(t, responseData.Scores[1].Product)
+
+		mockService.AssertExpectations(t)
+	})
+
+	t.Run("ranked by value", func(t *testing.T) {
+		// Arrange
+		price := 4.0
+		expectedScores := []*domain.ProductScore{
+			{ProductID: uuid.New(), AvgScore: 3, VoteCount: 2, Price: &price},
+			{ProductID: uuid.New(), AvgScore: 5, VoteCount: 1},
+		}
+
+		mockService.On("GetAggregatedScores", mock.Anything, domain.RankByValue).Return(expectedScores, nil).Once()
+		mockService.On("GetUnvotedViews", mock.Anything).Return([]*domain.ProductViews{}, nil).Once()
+
+		req := httptest.NewRequest("GET", "/api/votes/aggregated?rank=value", nil)
+		rec := httptest.NewRecorder()
+
+		// Act
+		handler.GetAggregatedScores(rec, req)
+
+		// Assert
+		require.Equal(t, http.StatusOK, rec.Code)
+
+		var response models.ProductScoreListResponse
+		err := json.NewDecoder(rec.Body).Decode(&response)
+		require.NoError(t, err)
+		require.Len(t, response.Scores, 2)
+		require.NotNil(t, response.Scores[0].Price)
+		assert.Equal(t, 4.0, *response.Scores[0].Price)
+		require.NotNil(t, response.Scores[0].Value)
+		assert.InDelta(t, 0.75, *response.Scores[0].Value, 1e-9)
+		assert.Nil(t, response.Scores[1].Price)
+		assert.Nil(t, response.Scores[1].Value, "a product without a price has no value")
+
+		mockService.AssertExpectations(t)
+	})
+
+	t.Run("products that were only shown are listed apart", func(t *testing.T) {
+		// Arrange
+		votedID, shownID := uuid.New(), uuid.New()
+		mockService.On("GetAggregatedScores", mock.Anything, domain.RankByScore).
+			Return([]*domain.ProductScore{{ProductID: votedID, AvgScore: 2, VoteCount: 1, Views: 3}}, nil).Once()
+		mockService.On("GetUnvotedViews", mock.Anything).
+			Return([]*domain.ProductViews{{ProductID: shownID, Views: 5, Skips: 2}}, nil).Once()
+
+		req := httptest.NewRequest("GET", "/api/votes/aggregated", nil)
+		rec := httptest.NewRecorder()
+
+		// Act
+		handler.GetAggregatedScores(rec, req)
+
+		// Assert
+		require.Equal(t, http.StatusOK, rec.Code)
+
+		var response models.ProductScoreListResponse
+		err := json.NewDecoder(rec.Body).Decode(&response)
+		require.NoError(t, err)
+		require.Len(t, response.Scores, 1)
+		assert.Equal(t, votedID, response.Scores[0].ProductID)
+		assert.Equal(t, 1, response.Count)
+		require.Len(t, response.Unvoted, 1)
+		assert.Equal(t, &models.ProductViewsResponse{ProductID: shownID, Views: 5, Skips: 2}, response.Unvoted[0])
+
+		mockService.AssertExpectations(t)
+	})
+
+	t.Run("unknown ranking", func(t *testing.T) {
+		// Arrange
+		mockService := new(MockVoteService)
+		handler := handlers.NewVoteHandler(mockService)
+		req := httptest.NewRequest("GET", "/api/votes/aggregated?rank=cheapest", nil)
+		rec := httptest.NewRecorder()
+
+		// Act
+		handler.GetAggregatedScores(rec, req)
+
+		// Assert
+		assert.Equal(t, http.StatusBadRequest, rec.Code)
+		mockService.AssertNotCalled(t, "GetAggregatedScores", mock.Anything, mock.Anything)
+	})
+
 	t.Run("service error", func(t *testing.T) {
 		// Arrange
-		mockService.On("GetAggregatedScores", mock.Anything).
+		mockService.On("GetAggregatedScores", mock.Anything, domain.RankByScore).
 			Return([]*domain.ProductScore{}, errors.New("service error")).Once()
 
 		// Create request and recorder
@@ -0,0 +1,124 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+
+	"github.com/ArtemSind/food_tinder/internal/domain"
+	httpModels "github.com/ArtemSind/food_tinder/internal/infrastructure/http/models"
+	"github.com/google/uuid"
+	"github.com/gorilla/mux"
+)
+
+type WebhookService interface {
+	CreateSubscription(ctx context.Context, url, secret string, eventTypes []string) (*domain.WebhookSubscription, error)
+	ListSubscriptions(ctx context.Context) ([]*domain.WebhookSubscription, error)
+	DeleteSubscription(ctx context.Context, id uuid.UUID) error
+	ListDeadLetters(ctx context.Context) ([]*domain.WebhookDelivery, error)
+	RetryDelivery(ctx context.Context, id uuid.UUID) error
+}
+
+type WebhookHandler struct {
+	webhookService WebhookService
+}
+
+func NewWebhookHandler(webhookService WebhookService) *WebhookHandler {
+	return &WebhookHandler{webhookService: webhookService}
+}
+
+func (h *WebhookHandler) CreateWebhook(w http.ResponseWriter, r *http.Request) {
+	var req httpModels.WebhookRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	ctx := r.Context()
+	subscription, err := h.webhookService.CreateSubscription(ctx, req.URL, req.Secret, req.EventTypes)
+	if err != nil {
+		switch {
+		case errors.Is(err, domain.ErrInvalidWebhookURL),
+			errors.Is(err, domain.ErrInvalidEventType),
+			errors.Is(err, domain.ErrNoEventTypes):
+			http.Error(w, err.Error(), http.StatusBadRequest)
+		default:
+			http.Error(w, "Failed to create webhook", http.StatusInternalServerError)
+		}
+		return
+	}
+
+	response := httpModels.WebhookCreatedResponseFromDomain(subscription)
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(response)
+}
+
+func (h *WebhookHandler) ListWebhooks(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	subscriptions, err := h.webhookService.ListSubscriptions(ctx)
+	if err != nil {
+		http.Error(w, "Failed to get webhooks", http.StatusInternalServerError)
+		return
+	}
+
+	response := httpModels.WebhookListResponseFromDomain(subscriptions)
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(response)
+}
+
+func (h *WebhookHandler) DeleteWebhook(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	webhookID, err := uuid.Parse(vars["webhookID"])
+	if err != nil {
+		http.Error(w, "Invalid webhook ID", http.StatusBadRequest)
+		return
+	}
+
+	ctx := r.Context()
+	if err := h.webhookService.DeleteSubscription(ctx, webhookID); err != nil {
+		if errors.Is(err, domain.ErrWebhookNotFound) {
+			http.Error(w, "Webhook not found", http.StatusNotFound)
+			return
+		}
+		http.Error(w, "Failed to delete webhook", http.StatusInternalServerError)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (h *WebhookHandler) ListDeadLetters(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	deliveries, err := h.webhookService.ListDeadLetters(ctx)
+	if err != nil {
+		http.Error(w, "Failed to get dead letters", http.StatusInternalServerError)
+		return
+	}
+
+	response := httpModels.WebhookDeliveryListResponseFromDomain(deliveries)
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(response)
+}
+
+func (h *WebhookHandler) RetryDelivery(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	deliveryID, err := uuid.Parse(vars["deliveryID"])
+	if err != nil {
+		http.Error(w, "Invalid delivery ID", http.StatusBadRequest)
+		return
+	}
+
+	ctx := r.Context()
+	if err := h.webhookService.RetryDelivery(ctx, deliveryID); err != nil {
+		if errors.Is(err, domain.ErrDeliveryNotFound) {
+			http.Error(w, "Dead-lettered delivery not found", http.StatusNotFound)
+			return
+		}
+		http.Error(w, "Failed to retry delivery", http.StatusInternalServerError)
+		return
+	}
+
+	w.WriteHeader(http.StatusAccepted)
+}
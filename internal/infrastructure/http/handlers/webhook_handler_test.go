@@ -0,0 +1,207 @@
+package handlers_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/ArtemSind/food_tinder/internal/domain"
+	"github.com/ArtemSind/food_tinder/internal/infrastructure/http/handlers"
+	"github.com/ArtemSind/food_tinder/internal/infrastructure/http/models"
+	"github.com/google/uuid"
+	"github.com/gorilla/mux"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+// Mock WebhookService
+type MockWebhookService struct {
+	mock.Mock
+}
+
+func (m *MockWebhookService) CreateSubscription(ctx context.Context, url, secret string, eventTypes []string) (*domain.WebhookSubscription, error) {
+	args := m.Called(ctx, url, secret, eventTypes)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*domain.WebhookSubscription), args.Error(1)
+}
+
+func (m *MockWebhookService) ListSubscriptions(ctx context.Context) ([]*domain.WebhookSubscription, error) {
+	args := m.Called(ctx)
+	return args.Get(0).([]*domain.WebhookSubscription), args.Error(1)
+}
+
+func (m *MockWebhookService) DeleteSubscription(ctx context.Context, id uuid.UUID) error {
+	args := m.Called(ctx, id)
+	return args.Error(0)
+}
+
+func (m *MockWebhookService) ListDeadLetters(ctx context.Context) ([]*domain.WebhookDelivery, error) {
+	args := m.Called(ctx)
+	return args.Get(0).([]*domain.WebhookDelivery), args.Error(1)
+}
+
+func (m *MockWebhookService) RetryDelivery(ctx context.Context, id uuid.UUID) error {
+	args := m.Called(ctx, id)
+	return args.Error(0)
+}
+
+func TestWebhookHandler_CreateWebhook(t *testing.T) {
+	// Arrange
+	mockService := new(MockWebhookService)
+	handler := handlers.NewWebhookHandler(mockService)
+
+	t.Run("successful registration returns secret", func(t *testing.T) {
+		// Arrange
+		subscription, _ := domain.NewWebhookSubscription("https://example.com/hook", "s3cret", []string{domain.EventVoteCreated})
+		mockService.On("CreateSubscription", mock.Anything, "https://example.com/hook", "", []string{domain.EventVoteCreated}).
+			Return(subscription, nil).Once()
+
+		requestBody, _ := json.Marshal(map[string]interface{}{
+			"url":         "https://example.com/hook",
+			"event_types": []string{domain.EventVoteCreated},
+		})
+		req := httptest.NewRequest("POST", "/admin/webhooks", bytes.NewBuffer(requestBody))
+		rec := httptest.NewRecorder()
+
+		// Act
+		handler.CreateWebhook(rec, req)
+
+		// Assert
+		require.Equal(t, http.StatusCreated, rec.Code)
+
+		var response models.WebhookCreatedResponse
+		err := json.NewDecoder(rec.Body).Decode(&response)
+		require.NoError(t, err)
+		assert.Equal(t, subscription.ID, response.ID)
+		assert.Equal(t, "s3cret", response.Secret)
+
+		mockService.AssertExpectations(t)
+	})
+
+	t.Run("validation error", func(t *testing.T) {
+		// Arrange
+		mockService.On("CreateSubscription", mock.Anything, "not a url", "", []string{domain.EventVoteCreated}).
+			Return(nil, domain.ErrInvalidWebhookURL).Once()
+
+		requestBody, _ := json.Marshal(map[string]interface{}{
+			"url":         "not a url",
+			"event_types": []string{domain.EventVoteCreated},
+		})
+		req := httptest.NewRequest("POST", "/admin/webhooks", bytes.NewBuffer(requestBody))
+		rec := httptest.NewRecorder()
+
+		// Act
+		handler.CreateWebhook(rec, req)
+
+		// Assert
+		assert.Equal(t, http.StatusBadRequest, rec.Code)
+		mockService.AssertExpectations(t)
+	})
+
+	t.Run("invalid JSON body", func(t *testing.T) {
+		// Arrange
+		req := httptest.NewRequest("POST", "/admin/webhooks", bytes.NewBufferString(`{"url":`))
+		rec := httptest.NewRecorder()
+
+		// Act
+		handler.CreateWebhook(rec, req)
+
+		// Assert
+		assert.Equal(t, http.StatusBadRequest, rec.Code)
+	})
+}
+
+func TestWebhookHandler_DeleteWebhook(t *testing.T) {
+	// Arrange
+	mockService := new(MockWebhookService)
+	handler := handlers.NewWebhookHandler(mockService)
+	router := mux.NewRouter()
+	router.HandleFunc("/admin/webhooks/{webhookID}", handler.DeleteWebhook).Methods("DELETE")
+
+	t.Run("successful deletion", func(t *testing.T) {
+		// Arrange
+		webhookID := uuid.New()
+		mockService.On("DeleteSubscription", mock.Anything, webhookID).Return(nil).Once()
+
+		req := httptest.NewRequest("DELETE", "/admin/webhooks/"+webhookID.String(), nil)
+		rec := httptest.NewRecorder()
+
+		// Act
+		router.ServeHTTP(rec, req)
+
+		// Assert
+		assert.Equal(t, http.StatusNoContent, rec.Code)
+		mockService.AssertExpectations(t)
+	})
+
+	t.Run("webhook not found", func(t *testing.T) {
+		// Arrange
+		webhookID := uuid.New()
+		mockService.On("DeleteSubscription", mock.Anything, webhookID).Return(domain.ErrWebhookNotFound).Once()
+
+		req := httptest.NewRequest("DELETE", "/admin/webhooks/"+webhookID.String(), nil)
+		rec := httptest.NewRecorder()
+
+		// Act
+		router.ServeHTTP(rec, req)
+
+		// Assert
+		assert.Equal(t, http.StatusNotFound, rec.Code)
+		mockService.AssertExpectations(t)
+	})
+}
+
+func TestWebhookHandler_ListDeadLetters(t *testing.T) {
+	// Arrange
+	mockService := new(MockWebhookService)
+	handler := handlers.NewWebhookHandler(mockService)
+
+	t.Run("returns dead-lettered deliveries", func(t *testing.T) {
+		// Arrange
+		subscription, _ := domain.NewWebhookSubscription("https://example.com", "", []string{domain.EventVoteCreated})
+		event, _ := domain.NewOutboxEvent(domain.EventVoteCreated, map[string]int{"score": 2})
+		delivery := domain.NewWebhookDelivery(subscription, event)
+		delivery.Status = domain.DeliveryDead
+		mockService.On("ListDeadLetters", mock.Anything).Return([]*domain.WebhookDelivery{delivery}, nil).Once()
+
+		req := httptest.NewRequest("GET", "/admin/webhooks/dead-letters", nil)
+		rec := httptest.NewRecorder()
+
+		// Act
+		handler.ListDeadLetters(rec, req)
+
+		// Assert
+		require.Equal(t, http.StatusOK, rec.Code)
+
+		var response models.WebhookDeliveryListResponse
+		err := json.NewDecoder(rec.Body).Decode(&response)
+		require.NoError(t, err)
+		assert.Equal(t, 1, response.Count)
+		assert.Equal(t, delivery.ID, response.Deliveries[0].ID)
+		assert.Equal(t, "dead", response.Deliveries[0].Status)
+
+		mockService.AssertExpectations(t)
+	})
+
+	t.Run("service error", func(t *testing.T) {
+		// Arrange
+		mockService.On("ListDeadLetters", mock.Anything).Return([]*domain.WebhookDelivery{}, errors.New("service error")).Once()
+
+		req := httptest.NewRequest("GET", "/admin/webhooks/dead-letters", nil)
+		rec := httptest.NewRecorder()
+
+		// Act
+		handler.ListDeadLetters(rec, req)
+
+		// Assert
+		assert.Equal(t, http.StatusInternalServerError, rec.Code)
+		mockService.AssertExpectations(t)
+	})
+}
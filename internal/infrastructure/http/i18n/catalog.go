@@ -0,0 +1,143 @@
+package i18n
+
+import "github.com/ArtemSind/food_tinder/internal/domain"
+
+// messages translates the API's English messages, keyed by the English text. Messages
+// without a translation are served in English.
+var messages = map[domain.Locale]map[string]string{
+	domain.LocaleGerman: {
+		// Request validation
+		"Invalid request body":     "Ungültiger Anfrageinhalt",
+		"Invalid session ID":       "Ungültige Sitzungs-ID",
+		"Invalid product ID":       "Ungültige Produkt-ID",
+		"Invalid experiment ID":    "Ungültige Experiment-ID",
+		"Invalid webhook ID":       "Ungültige Webhook-ID",
+		"Invalid delivery ID":      "Ungültige Zustell-ID",
+		"Invalid snapshot version": "Ungültige Snapshot-Version",
+		"Invalid limit":            "Ungültiges Limit",
+		"Invalid position":         "Ungültige Position",
+		"Invalid max price":        "Ungültiger Höchstpreis",
+		"Invalid force flag":       "Ungültiger force-Wert",
+		"Invalid product IDs":      "Ungültige Produkt-IDs",
+		"Too many product IDs":     "Zu viele Produkt-IDs",
+
+		// Missing resources
+		"Session not found":                      "Sitzung nicht gefunden",
+		"Product not found":                      "Produkt nicht gefunden",
+		"Product override not found":             "Produktanpassung nicht gefunden",
+		"Experiment not found":                   "Experiment nicht gefunden",
+		"Webhook not found":                      "Webhook nicht gefunden",
+		"Dead-lettered delivery not found":       "Unzustellbare Zustellung nicht gefunden",
+		"Recommendations are not available yet":  "Empfehlungen sind noch nicht verfügbar",
+		"Similar products are not available yet": "Ähnliche Produkte sind noch nicht verfügbar",
+
+		// Failures
+		"Failed to create session":          "Sitzung konnte nicht erstellt werden",
+		"Failed to update session":          "Sitzung konnte nicht aktualisiert werden",
+		"Failed to get deck":                "Kartenstapel konnte nicht geladen werden",
+		"Failed to record impression":       "Anzeige konnte nicht erfasst werden",
+		"Failed to get votes":               "Bewertungen konnten nicht geladen werden",
+		"Failed to get aggregated scores":   "Gesamtbewertungen konnten nicht geladen werden",
+		"Failed to search products":         "Produktsuche fehlgeschlagen",
+		"Failed to get products":            "Produkte konnten nicht geladen werden",
+		"Failed to get recommendations":     "Empfehlungen konnten nicht geladen werden",
+		"Failed to get similar products":    "Ähnliche Produkte konnten nicht geladen werden",
+		"Failed to get price history":       "Preisverlauf konnte nicht geladen werden",
+		"Failed to get product history":     "Produktverlauf konnte nicht geladen werden",
+		"Failed to get product overrides":   "Produktanpassungen konnten nicht geladen werden",
+		"Failed to get product override":    "Produktanpassung konnte nicht geladen werden",
+		"Failed to save product override":   "Produktanpassung konnte nicht gespeichert werden",
+		"Failed to delete product override": "Produktanpassung konnte nicht gelöscht werden",
+		"Failed to get product aliases":     "Produktaliase konnten nicht geladen werden",
+		"Failed to create product alias":    "Produktalias konnte nicht erstellt werden",
+		"Failed to refresh catalog":         "Katalog konnte nicht aktualisiert werden",
+		"Failed to roll back catalog":       "Katalog konnte nicht zurückgesetzt werden",
+		"Failed to unpin catalog":           "Katalog konnte nicht freigegeben werden",
+		"Failed to get refresh status":      "Aktualisierungsstatus konnte nicht geladen werden",
+		"Failed to list catalog snapshots":  "Katalog-Snapshots konnten nicht geladen werden",
+		"Failed to get catalog validation":  "Katalogprüfung konnte nicht geladen werden",
+		"Failed to create experiment":       "Experiment konnte nicht erstellt werden",
+		"Failed to get experiments":         "Experimente konnten nicht geladen werden",
+		"Failed to stop experiment":         "Experiment konnte nicht beendet werden",
+		"Failed to get experiment results":  "Experimentergebnisse konnten nicht geladen werden",
+		"Failed to create webhook":          "Webhook konnte nicht erstellt werden",
+		"Failed to get webhooks":            "Webhooks konnten nicht geladen werden",
+		"Failed to delete webhook":          "Webhook konnte nicht gelöscht werden",
+		"Failed to get dead letters":        "Unzustellbare Zustellungen konnten nicht geladen werden",
+		"Failed to retry delivery":          "Zustellung konnte nicht wiederholt werden",
+
+		// Domain errors
+		"unsupported locale":                                             "nicht unterstützte Sprache",
+		"unknown deck strategy":                                          "unbekannte Kartenstapel-Strategie",
+		"unknown score ranking":                                          "unbekannte Rangfolge",
+		"invalid dietary preferences":                                    "ungültige Ernährungspräferenzen",
+		"score must be between 1 and 5":                                  "die Bewertung muss zwischen 1 und 5 liegen",
+		"impression action must be impression or skip":                   "die Anzeigeaktion muss impression oder skip sein",
+		"product not found":                                              "Produkt nicht gefunden",
+		"session not found":                                              "Sitzung nicht gefunden",
+		"recommendation model has not been computed yet":                 "das Empfehlungsmodell wurde noch nicht berechnet",
+		"catalog snapshot not found":                                     "Katalog-Snapshot nicht gefunden",
+		"no catalog payload has been validated yet":                      "es wurde noch keine Katalogantwort geprüft",
+		"a catalog refresh is already running":                           "eine Katalogaktualisierung läuft bereits",
+		"catalog was written by a newer refresh":                         "der Katalog wurde von einer neueren Aktualisierung geschrieben",
+		"refusing to replace catalog: new product list shrinks too much": "Katalog wird nicht ersetzt: die neue Produktliste ist zu stark geschrumpft",
+		"experiment not found":                                           "Experiment nicht gefunden",
+		"another experiment is already running":                          "es läuft bereits ein anderes Experiment",
+		"experiment name is required":                                    "der Experimentname ist erforderlich",
+		"an experiment needs at least two variants":                      "ein Experiment braucht mindestens zwei Varianten",
+		"variant weight must be positive":                                "die Variantengewichtung muss positiv sein",
+		"variant names must be unique":                                   "die Variantennamen müssen eindeutig sein",
+		"variant name is required":                                       "der Variantenname ist erforderlich",
+		"a product cannot be an alias of itself":                         "ein Produkt kann kein Alias von sich selbst sein",
+		"the product is already an alias of the aliased product":         "das Produkt ist bereits ein Alias des zugeordneten Produkts",
+		"the product is already an alias of another product":             "das Produkt ist bereits ein Alias eines anderen Produkts",
+		"product override not found":                                     "Produktanpassung nicht gefunden",
+		"override must hide, pin or change the product":                  "die Anpassung muss das Produkt ausblenden, anheften oder ändern",
+		"a product cannot be both hidden and pinned":                     "ein Produkt kann nicht zugleich ausgeblendet und angeheftet sein",
+		"overridden name and category must not be blank":                 "angepasster Name und Kategorie dürfen nicht leer sein",
+		"overridden image must be an absolute http or https URL":         "das angepasste Bild muss eine absolute http- oder https-URL sein",
+		"webhook URL must be an absolute http or https URL":              "die Webhook-URL muss eine absolute http- oder https-URL sein",
+		"unknown event type":                                             "unbekannter Ereignistyp",
+		"at least one event type is required":                            "mindestens ein Ereignistyp ist erforderlich",
+		"webhook not found":                                              "Webhook nicht gefunden",
+		"webhook delivery not found":                                     "Webhook-Zustellung nicht gefunden",
+
+		// Allergen warnings for the allergens that must be declared in the EU
+		"Contains celery":      "Enthält Sellerie",
+		"Contains crustaceans": "Enthält Krebstiere",
+		"Contains egg":         "Enthält Ei",
+		"Contains fish":        "Enthält Fisch",
+		"Contains gluten":      "Enthält Gluten",
+		"Contains lupin":       "Enthält Lupinen",
+		"Contains milk":        "Enthält Milch",
+		"Contains molluscs":    "Enthält Weichtiere",
+		"Contains mustard":     "Enthält Senf",
+		"Contains nuts":        "Enthält Schalenfrüchte",
+		"Contains peanuts":     "Enthält Erdnüsse",
+		"Contains sesame":      "Enthält Sesam",
+		"Contains soy":         "Enthält Soja",
+		"Contains sulphites":   "Enthält Sulfite",
+		"Allergens unknown":    "Allergene unbekannt",
+
+		// Recommendation explanations
+		"because you liked %s":                  "weil dir %s gefallen hat",
+		"because you liked a product you rated": "weil dir ein Produkt gefallen hat, das du bewertet hast",
+	},
+}
+
+// Translate returns the message in the locale, or the English message if it has no translation
+func Translate(locale domain.Locale, message string) string {
+	if translated, ok := messages[locale][message]; ok {
+		return translated
+	}
+	return message
+}
+
+// TranslateAll translates every message of a list
+func TranslateAll(locale domain.Locale, list []string) []string {
+	translated := make([]string, len(list))
+	for i, message := range list {
+		translated[i] = Translate(locale, message)
+	}
+	return translated
+}
@@ -0,0 +1,117 @@
+package i18n_test
+
+import (
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"io/fs"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/ArtemSind/food_tinder/internal/domain"
+	"github.com/ArtemSind/food_tinder/internal/infrastructure/http/i18n"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestCatalogCoversMessages fails for every message the API sends that the catalog has no
+// German text for. The catalog is keyed by the English text, so rewording a message without
+// rewording its key would silently serve it in English. Handlers send literal messages and
+// the texts of domain errors.
+func TestCatalogCoversMessages(t *testing.T) {
+	tests := []struct {
+		name    string
+		dir     string
+		message func(node ast.Node) (string, token.Pos, bool)
+	}{
+		{name: "HTTP layer messages", dir: "..", message: messageLiteral},
+		{name: "domain errors", dir: "../../../domain", message: errorLiteral},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Arrange
+			messages := collectMessages(t, tt.dir, tt.message)
+			require.NotEmpty(t, messages)
+
+			// Act & Assert
+			for message, position := range messages {
+				assert.NotEqual(t, message, i18n.Translate(domain.LocaleGerman, message),
+					"%s: %q has no German translation", position, message)
+			}
+		})
+	}
+}
+
+// collectMessages returns the messages found in the Go files under dir, with where they are
+func collectMessages(t *testing.T, dir string, message func(node ast.Node) (string, token.Pos, bool)) map[string]token.Position {
+	t.Helper()
+
+	messages := map[string]token.Position{}
+	fset := token.NewFileSet()
+	err := filepath.WalkDir(dir, func(path string, entry fs.DirEntry, err error) error {
+		if err != nil || entry.IsDir() || !strings.HasSuffix(path, ".go") || strings.HasSuffix(path, "_test.go") {
+			return err
+		}
+		file, err := parser.ParseFile(fset, path, nil, 0)
+		if err != nil {
+			return err
+		}
+		ast.Inspect(file, func(node ast.Node) bool {
+			if text, pos, ok := message(node); ok {
+				messages[text] = fset.Position(pos)
+			}
+			return true
+		})
+		return nil
+	})
+	require.NoError(t, err)
+	return messages
+}
+
+// messageLiteral returns the message of an http.Error or i18n.Translate call that passes a
+// string literal
+func messageLiteral(node ast.Node) (string, token.Pos, bool) {
+	pkg, name, arg, ok := callLiteral(node, 1)
+	if !ok || !(pkg == "http" && name == "Error" || pkg == "i18n" && name == "Translate") {
+		return "", 0, false
+	}
+	return arg, node.Pos(), true
+}
+
+// errorLiteral returns the text of an errors.New call
+func errorLiteral(node ast.Node) (string, token.Pos, bool) {
+	pkg, name, arg, ok := callLiteral(node, 0)
+	if !ok || pkg != "errors" || name != "New" {
+		return "", 0, false
+	}
+	return arg, node.Pos(), true
+}
+
+// callLiteral returns the package and function of a call of a package function whose argument
+// at index is a string literal, together with the string
+func callLiteral(node ast.Node, index int) (pkg, name, arg string, ok bool) {
+	call, ok := node.(*ast.CallExpr)
+	if !ok || len(call.Args) <= index {
+		return "", "", "", false
+	}
+	selector, ok := call.Fun.(*ast.SelectorExpr)
+	if !ok {
+		return "", "", "", false
+	}
+	ident, ok := selector.X.(*ast.Ident)
+	if !ok {
+		return "", "", "", false
+	}
+	literal, ok := call.Args[index].(*ast.BasicLit)
+	if !ok || literal.Kind != token.STRING {
+		return "", "", "", false
+	}
+	arg, err := strconv.Unquote(literal.Value)
+	if err != nil {
+		return "", "", "", false
+	}
+	return ident.Name, selector.Sel.Name, arg, true
+}
@@ -0,0 +1,85 @@
+package i18n
+
+import (
+	"context"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+
+	"github.com/ArtemSind/food_tinder/internal/domain"
+)
+
+type contextKey struct{}
+
+// WithLocale returns a context that carries the locale of the request
+func WithLocale(ctx context.Context, locale domain.Locale) context.Context {
+	return context.WithValue(ctx, contextKey{}, locale)
+}
+
+// FromContext returns the locale of the request, or the default locale if none was chosen
+func FromContext(ctx context.Context) domain.Locale {
+	switch locale := ctx.Value(contextKey{}).(type) {
+	case domain.Locale:
+		if locale != "" {
+			return locale
+		}
+	case *lazyLocale:
+		return locale.get()
+	}
+	return domain.DefaultLocale
+}
+
+// lazyLocale chooses the locale of a request the first time it is needed, so requests that
+// return no localized text do not look it up
+type lazyLocale struct {
+	choose   func() domain.Locale
+	once     sync.Once
+	locale   domain.Locale
+	resolved atomic.Bool
+}
+
+func (l *lazyLocale) get() domain.Locale {
+	l.once.Do(func() {
+		l.locale = l.choose()
+		l.resolved.Store(true)
+	})
+	return l.locale
+}
+
+// Negotiate picks the supported locale the Accept-Language header prefers most. Tags with
+// equal weight keep their order; false is returned if the header names no supported locale.
+func Negotiate(acceptLanguage string) (domain.Locale, bool) {
+	type weighted struct {
+		locale domain.Locale
+		weight float64
+	}
+
+	var candidates []weighted
+	for _, part := range strings.Split(acceptLanguage, ",") {
+		tag, params, _ := strings.Cut(part, ";")
+		locale, err := domain.ParseLocale(tag)
+		if err != nil || locale == "" {
+			continue
+		}
+
+		weight := 1.0
+		if value, ok := strings.CutPrefix(strings.TrimSpace(params), "q="); ok {
+			if weight, err = strconv.ParseFloat(value, 64); err != nil {
+				continue
+			}
+		}
+		if weight > 0 {
+			candidates = append(candidates, weighted{locale: locale, weight: weight})
+		}
+	}
+	if len(candidates) == 0 {
+		return "", false
+	}
+
+	sort.SliceStable(candidates, func(i, j int) bool {
+		return candidates[i].weight > candidates[j].weight
+	})
+	return candidates[0].locale, true
+}
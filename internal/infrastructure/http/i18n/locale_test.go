@@ -0,0 +1,45 @@
+package i18n_test
+
+import (
+	"testing"
+
+	"github.com/ArtemSind/food_tinder/internal/domain"
+	"github.com/ArtemSind/food_tinder/internal/infrastructure/http/i18n"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNegotiate(t *testing.T) {
+	tests := []struct {
+		name   string
+		header string
+		want   domain.Locale
+		found  bool
+	}{
+		{name: "no header", header: ""},
+		{name: "single language", header: "de", want: domain.LocaleGerman, found: true},
+		{name: "regional variant", header: "de-CH", want: domain.LocaleGerman, found: true},
+		{name: "highest weight wins", header: "en;q=0.5, de;q=0.8", want: domain.LocaleGerman, found: true},
+		{name: "order breaks ties", header: "en, de", want: domain.LocaleEnglish, found: true},
+		{name: "unsupported languages are skipped", header: "fr-FR, fr;q=0.9, de;q=0.7, *;q=0.5", want: domain.LocaleGerman, found: true},
+		{name: "zero weight rules a language out", header: "de;q=0, en;q=0.1", want: domain.LocaleEnglish, found: true},
+		{name: "nothing supported", header: "fr, it", found: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Act
+			locale, found := i18n.Negotiate(tt.header)
+
+			// Assert
+			assert.Equal(t, tt.found, found)
+			assert.Equal(t, tt.want, locale)
+		})
+	}
+}
+
+func TestTranslate(t *testing.T) {
+	assert.Equal(t, "Sitzung nicht gefunden", i18n.Translate(domain.LocaleGerman, "Session not found"))
+	assert.Equal(t, "Enthält Milch", i18n.Translate(domain.LocaleGerman, "Contains milk"))
+	assert.Equal(t, "Contains quinoa", i18n.Translate(domain.LocaleGerman, "Contains quinoa"), "messages without a translation stay in English")
+	assert.Equal(t, "Session not found", i18n.Translate(domain.LocaleEnglish, "Session not found"))
+}
@@ -0,0 +1,103 @@
+package i18n
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/ArtemSind/food_tinder/internal/domain"
+	"github.com/google/uuid"
+	"github.com/gorilla/mux"
+)
+
+// SessionLocales looks up the locale a session chose
+type SessionLocales interface {
+	GetSession(ctx context.Context, id uuid.UUID) (*domain.Session, error)
+}
+
+// Middleware chooses the locale of each request and translates its error messages. Requests
+// on a session that chose a locale use it; other requests follow their Accept-Language header.
+// The locale is only chosen once the handler asks for it or writes an error message, so
+// responses without localized text do not look up the session, and have no Content-Language.
+// The session lookup is optional; without it only the header is used.
+func Middleware(sessions SessionLocales) mux.MiddlewareFunc {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			locale := &lazyLocale{choose: func() domain.Locale { return requestLocale(r, sessions) }}
+			w.Header().Add("Vary", "Accept-Language")
+
+			lw := &localizingWriter{ResponseWriter: w, locale: locale}
+			next.ServeHTTP(lw, r.WithContext(context.WithValue(r.Context(), contextKey{}, locale)))
+			lw.flush()
+		})
+	}
+}
+
+func requestLocale(r *http.Request, sessions SessionLocales) domain.Locale {
+	if sessions != nil {
+		if sessionID, err := uuid.Parse(mux.Vars(r)["sessionID"]); err == nil {
+			// A missing session is reported by the handler itself
+			if session, err := sessions.GetSession(r.Context(), sessionID); err == nil && session.Locale != "" {
+				return session.Locale
+			}
+		}
+	}
+	if locale, ok := Negotiate(r.Header.Get("Accept-Language")); ok {
+		return locale
+	}
+	return domain.DefaultLocale
+}
+
+// localizingWriter holds back plain text error responses, as written by http.Error, until the
+// handler is done, so the message can be translated as a whole
+type localizingWriter struct {
+	http.ResponseWriter
+	locale      *lazyLocale
+	wroteHeader bool
+	status      int
+	buffered    bool
+	body        bytes.Buffer
+}
+
+func (w *localizingWriter) WriteHeader(status int) {
+	if w.wroteHeader {
+		return
+	}
+	w.wroteHeader = true
+
+	contentType := w.Header().Get("Content-Type")
+	if status >= http.StatusBadRequest && strings.HasPrefix(contentType, "text/plain") {
+		w.status = status
+		w.buffered = true
+		return
+	}
+	// The handler asked for the locale if the response has localized text
+	if w.locale.resolved.Load() {
+		w.Header().Set("Content-Language", string(w.locale.locale))
+	}
+	w.ResponseWriter.WriteHeader(status)
+}
+
+func (w *localizingWriter) Write(b []byte) (int, error) {
+	if !w.wroteHeader {
+		w.WriteHeader(http.StatusOK)
+	}
+	if w.buffered {
+		return w.body.Write(b)
+	}
+	return w.ResponseWriter.Write(b)
+}
+
+// flush writes the translated error message held back by WriteHeader
+func (w *localizingWriter) flush() {
+	if !w.buffered {
+		return
+	}
+	message := strings.TrimSuffix(w.body.String(), "\n")
+	locale := w.locale.get()
+	w.Header().Set("Content-Language", string(locale))
+	w.ResponseWriter.WriteHeader(w.status)
+	fmt.Fprintln(w.ResponseWriter, Translate(locale, message))
+}
@@ -0,0 +1,180 @@
+package i18n_test
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/ArtemSind/food_tinder/internal/domain"
+	"github.com/ArtemSind/food_tinder/internal/infrastructure/http/i18n"
+	"github.com/google/uuid"
+	"github.com/gorilla/mux"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// FakeSessionLocales serves the sessions it holds
+type FakeSessionLocales map[uuid.UUID]*domain.Session
+
+func (f FakeSessionLocales) GetSession(ctx context.Context, id uuid.UUID) (*domain.Session, error) {
+	if session, ok := f[id]; ok {
+		return session, nil
+	}
+	return nil, domain.ErrSessionNotFound
+}
+
+// CountingSessionLocales counts the session lookups
+type CountingSessionLocales struct {
+	FakeSessionLocales
+	lookups int
+}
+
+func (c *CountingSessionLocales) GetSession(ctx context.Context, id uuid.UUID) (*domain.Session, error) {
+	c.lookups++
+	return c.FakeSessionLocales.GetSession(ctx, id)
+}
+
+func newLocalizedRouter(sessions i18n.SessionLocales) *mux.Router {
+	router := mux.NewRouter()
+	router.Use(i18n.Middleware(sessions))
+	router.HandleFunc("/locale", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(map[string]string{"locale": string(i18n.FromContext(r.Context()))})
+	})
+	router.HandleFunc("/sessions/{sessionID}", func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, "Session not found", http.StatusNotFound)
+	})
+	router.HandleFunc("/sessions/{sessionID}/locale", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(map[string]string{"locale": string(i18n.FromContext(r.Context()))})
+	})
+	router.HandleFunc("/sessions/{sessionID}/votes", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNoContent)
+	})
+	router.HandleFunc("/untranslated", func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, "Something unexpected", http.StatusInternalServerError)
+	})
+	return router
+}
+
+func TestMiddleware(t *testing.T) {
+	// Arrange
+	germanSession := &domain.Session{ID: uuid.New(), Locale: domain.LocaleGerman}
+	englishSession := &domain.Session{ID: uuid.New(), Locale: domain.LocaleEnglish}
+	router := newLocalizedRouter(FakeSessionLocales{germanSession.ID: germanSession, englishSession.ID: englishSession})
+
+	t.Run("follows Accept-Language", func(t *testing.T) {
+		// Arrange
+		req := httptest.NewRequest("GET", "/locale", nil)
+		req.Header.Set("Accept-Language", "de-DE,de;q=0.9,en;q=0.8")
+		rec := httptest.NewRecorder()
+
+		// Act
+		router.ServeHTTP(rec, req)
+
+		// Assert
+		require.Equal(t, http.StatusOK, rec.Code)
+		assert.JSONEq(t, `{"locale":"de"}`, rec.Body.String())
+		assert.Equal(t, "de", rec.Header().Get("Content-Language"))
+		assert.Equal(t, "Accept-Language", rec.Header().Get("Vary"))
+	})
+
+	t.Run("defaults to English", func(t *testing.T) {
+		// Arrange
+		req := httptest.NewRequest("GET", "/locale", nil)
+		req.Header.Set("Accept-Language", "fr")
+		rec := httptest.NewRecorder()
+
+		// Act
+		router.ServeHTTP(rec, req)
+
+		// Assert
+		assert.JSONEq(t, `{"locale":"en"}`, rec.Body.String())
+	})
+
+	t.Run("translates error messages", func(t *testing.T) {
+		// Arrange
+		req := httptest.NewRequest("GET", "/sessions/"+uuid.NewString(), nil)
+		req.Header.Set("Accept-Language", "de")
+		rec := httptest.NewRecorder()
+
+		// Act
+		router.ServeHTTP(rec, req)
+
+		// Assert
+		assert.Equal(t, http.StatusNotFound, rec.Code)
+		assert.Equal(t, "Sitzung nicht gefunden\n", rec.Body.String())
+		assert.Equal(t, "de", rec.Header().Get("Content-Language"))
+	})
+
+	t.Run("the session locale wins over Accept-Language", func(t *testing.T) {
+		// Arrange
+		toGerman := httptest.NewRequest("GET", "/sessions/"+germanSession.ID.String(), nil)
+		toGerman.Header.Set("Accept-Language", "en")
+		toEnglish := httptest.NewRequest("GET", "/sessions/"+englishSession.ID.String(), nil)
+		toEnglish.Header.Set("Accept-Language", "de")
+		germanRec := httptest.NewRecorder()
+		englishRec := httptest.NewRecorder()
+
+		// Act
+		router.ServeHTTP(germanRec, toGerman)
+		router.ServeHTTP(englishRec, toEnglish)
+
+		// Assert
+		assert.Equal(t, "Sitzung nicht gefunden\n", germanRec.Body.String())
+		assert.Equal(t, "Session not found\n", englishRec.Body.String())
+	})
+
+	t.Run("keeps messages without a translation", func(t *testing.T) {
+		// Arrange
+		req := httptest.NewRequest("GET", "/untranslated", nil)
+		req.Header.Set("Accept-Language", "de")
+		rec := httptest.NewRecorder()
+
+		// Act
+		router.ServeHTTP(rec, req)
+
+		// Assert
+		assert.Equal(t, http.StatusInternalServerError, rec.Code)
+		assert.Equal(t, "Something unexpected\n", rec.Body.String())
+	})
+
+	t.Run("works without session lookup", func(t *testing.T) {
+		// Arrange
+		router := newLocalizedRouter(nil)
+		req := httptest.NewRequest("GET", "/sessions/"+germanSession.ID.String(), nil)
+		rec := httptest.NewRecorder()
+
+		// Act
+		router.ServeHTTP(rec, req)
+
+		// Assert
+		assert.Equal(t, "Session not found\n", rec.Body.String())
+	})
+
+	t.Run("looks up the session locale only for localized responses", func(t *testing.T) {
+		// Arrange
+		sessions := &CountingSessionLocales{FakeSessionLocales: FakeSessionLocales{germanSession.ID: germanSession}}
+		router := newLocalizedRouter(sessions)
+		vote := httptest.NewRequest("POST", "/sessions/"+germanSession.ID.String()+"/votes", nil)
+		locale := httptest.NewRequest("GET", "/sessions/"+germanSession.ID.String()+"/locale", nil)
+		voteRec := httptest.NewRecorder()
+		localeRec := httptest.NewRecorder()
+
+		// Act
+		router.ServeHTTP(voteRec, vote)
+		lookupsAfterVote := sessions.lookups
+		router.ServeHTTP(localeRec, locale)
+
+		// Assert
+		assert.Equal(t, http.StatusNoContent, voteRec.Code)
+		assert.Zero(t, lookupsAfterVote, "a response without localized text does not look up the session")
+		assert.Empty(t, voteRec.Header().Get("Content-Language"))
+
+		assert.JSONEq(t, `{"locale":"de"}`, localeRec.Body.String())
+		assert.Equal(t, "de", localeRec.Header().Get("Content-Language"))
+		assert.Equal(t, 1, sessions.lookups)
+	})
+}
@@ -0,0 +1,254 @@
+package models
+
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/ArtemSind/food_tinder/internal/domain"
+	"github.com/google/uuid"
+)
+
+// ProductRejectionResponse represents a product that failed validation in the response
+type ProductRejectionResponse struct {
+	Index     int    `json:"index"`
+	ProductID string `json:"product_id,omitempty"`
+	Reason    string `json:"reason"`
+}
+
+// CatalogValidationResponse represents the validation report of the last Foodji payload
+type CatalogValidationResponse struct {
+	CheckedAt     time.Time                   `json:"checked_at"`
+	Accepted      int                         `json:"accepted"`
+	Rejected      int                         `json:"rejected"`
+	Rejections    []*ProductRejectionResponse `json:"rejections"`
+	MissingFields []string                    `json:"missing_fields,omitempty"`
+	Refused       bool                        `json:"refused"`
+	RefusedReason string                      `json:"refused_reason,omitempty"`
+}
+
+// CatalogValidationResponseFromDomain converts a domain catalog validation to an HTTP response
+func CatalogValidationResponseFromDomain(validation *domain.CatalogValidation) *CatalogValidationResponse {
+	rejections := make([]*ProductRejectionResponse, len(validation.Rejections))
+	for i, rejection := range validation.Rejections {
+		rejections[i] = &ProductRejectionResponse{
+			Index:     rejection.Index,
+			ProductID: rejection.ProductID,
+			Reason:    rejection.Reason,
+		}
+	}
+	return &CatalogValidationResponse{
+		CheckedAt:     validation.CheckedAt,
+		Accepted:      validation.Accepted,
+		Rejected:      validation.Rejected,
+		Rejections:    rejections,
+		MissingFields: validation.MissingFields,
+		Refused:       validation.Refused,
+		RefusedReason: validation.RefusedReason,
+	}
+}
+
+// RefreshRunResponse represents a finished catalog refresh in the response
+type RefreshRunResponse struct {
+	Trigger      string    `json:"trigger"`
+	StartedAt    time.Time `json:"started_at"`
+	FinishedAt   time.Time `json:"finished_at"`
+	DurationMs   int64     `json:"duration_ms"`
+	Outcome      string    `json:"outcome"`
+	ProductCount int       `json:"product_count"`
+	Error        string    `json:"error,omitempty"`
+}
+
+// RefreshStatusResponse represents the catalog refresh status in the response
+type RefreshStatusResponse struct {
+	Running      bool                `json:"running"`
+	RunningSince *time.Time          `json:"running_since,omitempty"`
+	LastRun      *RefreshRunResponse `json:"last_run,omitempty"`
+}
+
+// RefreshStatusResponseFromDomain converts a domain refresh status to an HTTP response
+func RefreshStatusResponseFromDomain(status *domain.RefreshStatus) *RefreshStatusResponse {
+	response := &RefreshStatusResponse{
+		Running:      status.Running,
+		RunningSince: status.RunningSince,
+	}
+	if run := status.LastRun; run != nil {
+		response.LastRun = &RefreshRunResponse{
+			Trigger:      string(run.Trigger),
+			StartedAt:    run.StartedAt,
+			FinishedAt:   run.FinishedAt,
+			DurationMs:   run.Duration.Milliseconds(),
+			Outcome:      string(run.Outcome),
+			ProductCount: run.ProductCount,
+			Error:        run.Error,
+		}
+	}
+	return response
+}
+
+// RefreshCatalogResponse is returned when a refresh is requested
+type RefreshCatalogResponse struct {
+	// Started is false when a refresh was already running
+	Started bool                   `json:"started"`
+	Status  *RefreshStatusResponse `json:"status"`
+}
+
+// RefreshCatalogResponseFromDomain converts the result of a refresh request to an HTTP response
+func RefreshCatalogResponseFromDomain(started bool, status *domain.RefreshStatus) *RefreshCatalogResponse {
+	return &RefreshCatalogResponse{
+		Started: started,
+		Status:  RefreshStatusResponseFromDomain(status),
+	}
+}
+
+// ProductCacheStatsResponse represents the product cache counters of a replica in the response
+type ProductCacheStatsResponse struct {
+	Enabled  bool    `json:"enabled"`
+	Capacity int     `json:"capacity"`
+	Entries  int     `json:"entries"`
+	Hits     int64   `json:"hits"`
+	Misses   int64   `json:"misses"`
+	HitRatio float64 `json:"hit_ratio"`
+}
+
+// ProductCacheStatsResponseFromDomain converts domain product cache counters to an HTTP response
+func ProductCacheStatsResponseFromDomain(stats *domain.ProductCacheStats) *ProductCacheStatsResponse {
+	return &ProductCacheStatsResponse{
+		Enabled:  stats.Enabled,
+		Capacity: stats.Capacity,
+		Entries:  stats.Entries,
+		Hits:     stats.Hits,
+		Misses:   stats.Misses,
+		HitRatio: stats.HitRatio(),
+	}
+}
+
+// CatalogSnapshotResponse represents a catalog snapshot in the response
+type CatalogSnapshotResponse struct {
+	Version      int64     `json:"version"`
+	CreatedAt    time.Time `json:"created_at"`
+	ProductCount int       `json:"product_count"`
+	// StockUpdatedAt is omitted until the snapshot's stock is polled
+	StockUpdatedAt *time.Time `json:"stock_updated_at,omitempty"`
+	Current        bool       `json:"current"`
+	Pinned         bool       `json:"pinned"`
+}
+
+// CatalogSnapshotResponseFromDomain converts a domain catalog snapshot to an HTTP response
+func CatalogSnapshotResponseFromDomain(snapshot *domain.CatalogSnapshot) *CatalogSnapshotResponse {
+	return &CatalogSnapshotResponse{
+		Version:        snapshot.Version,
+		CreatedAt:      snapshot.CreatedAt,
+		ProductCount:   snapshot.ProductCount,
+		StockUpdatedAt: snapshot.StockUpdatedAt,
+		Current:        snapshot.Current,
+		Pinned:         snapshot.Pinned,
+	}
+}
+
+// CatalogSnapshotListResponse represents a list of catalog snapshots in the response
+type CatalogSnapshotListResponse struct {
+	Snapshots []*CatalogSnapshotResponse `json:"snapshots"`
+	Count     int                        `json:"count"`
+}
+
+// CatalogSnapshotListResponseFromDomain converts a list of domain catalog snapshots to an HTTP response
+func CatalogSnapshotListResponseFromDomain(snapshots []*domain.CatalogSnapshot) *CatalogSnapshotListResponse {
+	result := make([]*CatalogSnapshotResponse, len(snapshots))
+	for i, snapshot := range snapshots {
+		result[i] = CatalogSnapshotResponseFromDomain(snapshot)
+	}
+	return &CatalogSnapshotListResponse{
+		Snapshots: result,
+		Count:     len(result),
+	}
+}
+
+// CatalogProductResponse represents a product as last seen in the catalog in the response
+type CatalogProductResponse struct {
+	ID             uuid.UUID       `json:"id"`
+	Data           json.RawMessage `json:"data"`
+	ContentHash    string          `json:"content_hash"`
+	FirstSeenAt    time.Time       `json:"first_seen_at"`
+	LastSeenAt     time.Time       `json:"last_seen_at"`
+	Discontinued   bool            `json:"discontinued"`
+	DiscontinuedAt *time.Time      `json:"discontinued_at,omitempty"`
+}
+
+// CatalogProductResponseFromDomain converts a domain catalog product to an HTTP response
+func CatalogProductResponseFromDomain(product *domain.CatalogProduct) *CatalogProductResponse {
+	return &CatalogProductResponse{
+		ID:             product.ID,
+		Data:           product.Data,
+		ContentHash:    product.ContentHash,
+		FirstSeenAt:    product.FirstSeenAt,
+		LastSeenAt:     product.LastSeenAt,
+		Discontinued:   product.Discontinued(),
+		DiscontinuedAt: product.DiscontinuedAt,
+	}
+}
+
+// ProductEventResponse represents a catalog change of a product in the response
+type ProductEventResponse struct {
+	Type        string    `json:"type"`
+	ContentHash string    `json:"content_hash"`
+	CreatedAt   time.Time `json:"created_at"`
+}
+
+// ProductHistoryResponse represents a product and its catalog changes in the response
+type ProductHistoryResponse struct {
+	Product *CatalogProductResponse `json:"product"`
+	Events  []*ProductEventResponse `json:"events"`
+}
+
+// ProductHistoryResponseFromDomain converts a domain catalog product and its events to an HTTP response
+func ProductHistoryResponseFromDomain(product *domain.CatalogProduct, events []*domain.ProductEvent) *ProductHistoryResponse {
+	result := make([]*ProductEventResponse, len(events))
+	for i, event := range events {
+		result[i] = &ProductEventResponse{
+			Type:        string(event.Type),
+			ContentHash: event.ContentHash,
+			CreatedAt:   event.CreatedAt,
+		}
+	}
+	return &ProductHistoryResponse{
+		Product: CatalogProductResponseFromDomain(product),
+		Events:  result,
+	}
+}
+
+// PricePointResponse represents a price of a product in the response
+type PricePointResponse struct {
+	Price          float64   `json:"price"`
+	Version        string    `json:"version"`
+	RecordedAt     time.Time `json:"recorded_at"`
+	LastRecordedAt time.Time `json:"last_recorded_at"`
+	AvgScore       float64   `json:"avg_score"`
+	VoteCount      int       `json:"vote_count"`
+}
+
+// PriceHistoryResponse represents the prices of a product in the response
+type PriceHistoryResponse struct {
+	ProductID uuid.UUID             `json:"product_id"`
+	Prices    []*PricePointResponse `json:"prices"`
+	Count     int                   `json:"count"`
+}
+
+// PriceHistoryResponseFromDomain converts the domain price points of a product to an HTTP response
+func PriceHistoryResponseFromDomain(productID uuid.UUID, prices []*domain.PricePoint) *PriceHistoryResponse {
+	result := make([]*PricePointResponse, len(prices))
+	for i, price := range prices {
+		result[i] = &PricePointResponse{
+			Price:          price.Price,
+			Version:        price.Version,
+			RecordedAt:     price.RecordedAt,
+			LastRecordedAt: price.LastRecordedAt,
+			AvgScore:       price.AvgScore,
+			VoteCount:      price.VoteCount,
+		}
+	}
+	return &PriceHistoryResponse{
+		ProductID: productID,
+		Prices:    result,
+		Count:     len(result),
+	}
+}
@@ -0,0 +1,28 @@
+package models
+
+import (
+	"github.com/ArtemSind/food_tinder/internal/domain"
+	"github.com/google/uuid"
+)
+
+// DeckResponse represents the ordered products to show to a session
+type DeckResponse struct {
+	SessionID uuid.UUID   `json:"session_id"`
+	Strategy  string      `json:"strategy"`
+	Products  []uuid.UUID `json:"products"`
+	Count     int         `json:"count"`
+}
+
+// DeckResponseFromDomain converts a domain deck to an HTTP response
+func DeckResponseFromDomain(deck *domain.Deck) *DeckResponse {
+	products := deck.Products
+	if products == nil {
+		products = []uuid.UUID{}
+	}
+	return &DeckResponse{
+		SessionID: deck.SessionID,
+		Strategy:  string(deck.Strategy),
+		Products:  products,
+		Count:     len(products),
+	}
+}
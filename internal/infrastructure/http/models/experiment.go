@@ -0,0 +1,106 @@
+package models
+
+import (
+	"time"
+
+	"github.com/ArtemSind/food_tinder/internal/domain"
+	"github.com/google/uuid"
+)
+
+// VariantRequest represents one variant of an experiment in the request
+type VariantRequest struct {
+	Name     string `json:"name" validate:"required"`
+	Strategy string `json:"strategy" validate:"required,oneof=random popularity bandit recommendation"`
+	Weight   int    `json:"weight" validate:"required,min=1"`
+}
+
+// ExperimentRequest represents the request body for starting an experiment
+type ExperimentRequest struct {
+	Name        string           `json:"name" validate:"required"`
+	Description string           `json:"description,omitempty"`
+	Variants    []VariantRequest `json:"variants" validate:"required,min=2,dive"`
+}
+
+// ToDomain converts the requested variants to domain variants
+func (r *ExperimentRequest) ToDomain() []domain.Variant {
+	variants := make([]domain.Variant, len(r.Variants))
+	for i, variant := range r.Variants {
+		variants[i] = domain.Variant{
+			Name:     variant.Name,
+			Strategy: domain.DeckStrategy(variant.Strategy),
+			Weight:   variant.Weight,
+		}
+	}
+	return variants
+}
+
+// VariantResponse represents one variant of an experiment in the response
+type VariantResponse struct {
+	Name     string `json:"name"`
+	Strategy string `json:"strategy"`
+	Weight   int    `json:"weight"`
+}
+
+// ExperimentResponse represents an experiment in the response
+type ExperimentResponse struct {
+	ID          uuid.UUID          `json:"id"`
+	Name        string             `json:"name"`
+	Description string             `json:"description,omitempty"`
+	Status      string             `json:"status"`
+	Variants    []*VariantResponse `json:"variants"`
+	CreatedAt   time.Time          `json:"created_at"`
+	StoppedAt   *time.Time         `json:"stopped_at,omitempty"`
+}
+
+// ExperimentResponseFromDomain converts a domain experiment to an HTTP response
+func ExperimentResponseFromDomain(experiment *domain.Experiment) *ExperimentResponse {
+	variants := make([]*VariantResponse, len(experiment.Variants))
+	for i, variant := range experiment.Variants {
+		variants[i] = &VariantResponse{
+			Name:     variant.Name,
+			Strategy: string(variant.Strategy),
+			Weight:   variant.Weight,
+		}
+	}
+	return &ExperimentResponse{
+		ID:          experiment.ID,
+		Name:        experiment.Name,
+		Description: experiment.Description,
+		Status:      string(experiment.Status),
+		Variants:    variants,
+		CreatedAt:   experiment.CreatedAt,
+		StoppedAt:   experiment.StoppedAt,
+	}
+}
+
+// ExperimentListResponse represents a list of experiments in the response
+type ExperimentListResponse struct {
+	Experiments []*ExperimentResponse `json:"experiments"`
+	Count       int                   `json:"count"`
+}
+
+// ExperimentListResponseFromDomain converts a list of domain experiments to an HTTP response
+func ExperimentListResponseFromDomain(experiments []*domain.Experiment) *ExperimentListResponse {
+	result := make([]*ExperimentResponse, len(experiments))
+	for i, experiment := range experiments {
+		result[i] = ExperimentResponseFromDomain(experiment)
+	}
+	return &ExperimentListResponse{
+		Experiments: result,
+		Count:       len(result),
+	}
+}
+
+// ExperimentResultsResponse represents the per-variant metrics of an experiment
+type ExperimentResultsResponse struct {
+	Experiment *ExperimentResponse     `json:"experiment"`
+	Variants   []*domain.VariantResult `json:"variants"`
+}
+
+// ExperimentResultsResponseFromDomain converts an experiment and its results to an HTTP response
+func ExperimentResultsResponseFromDomain(experiment *domain.Experiment, results []*domain.VariantResult) *ExperimentResultsResponse {
+	return &ExperimentResultsResponse{
+		Experiment: ExperimentResponseFromDomain(experiment),
+		Variants:   results,
+	}
+}
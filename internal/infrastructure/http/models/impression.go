@@ -0,0 +1,39 @@
+package models
+
+import (
+	"time"
+
+	"github.com/ArtemSind/food_tinder/internal/domain"
+	"github.com/google/uuid"
+)
+
+// ImpressionRequest represents the request body for recording an impression or skip
+type ImpressionRequest struct {
+	ProductID string `json:"product_id" validate:"required,uuid4"`
+	Action    string `json:"action,omitempty" validate:"omitempty,oneof=impression skip"`
+	Position  int    `json:"position,omitempty" validate:"min=0"`
+}
+
+// ImpressionResponse represents a recorded impression in the response
+type ImpressionResponse struct {
+	ID        uuid.UUID `json:"id"`
+	SessionID uuid.UUID `json:"session_id"`
+	ProductID uuid.UUID `json:"product_id"`
+	Action    string    `json:"action"`
+	Strategy  string    `json:"strategy"`
+	Position  int       `json:"position"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// ImpressionResponseFromDomain converts a domain impression to an HTTP response
+func ImpressionResponseFromDomain(impression *domain.Impression) *ImpressionResponse {
+	return &ImpressionResponse{
+		ID:        impression.ID,
+		SessionID: impression.SessionID,
+		ProductID: impression.ProductID,
+		Action:    string(impression.Action),
+		Strategy:  string(impression.Strategy),
+		Position:  impression.Position,
+		CreatedAt: impression.CreatedAt,
+	}
+}
@@ -0,0 +1,95 @@
+package models
+
+import (
+	"github.com/ArtemSind/food_tinder/internal/domain"
+	"github.com/ArtemSind/food_tinder/internal/infrastructure/external/foodji"
+	"github.com/ArtemSind/food_tinder/internal/infrastructure/http/i18n"
+	"github.com/google/uuid"
+)
+
+// ProductResponse represents a catalog product in the response
+type ProductResponse struct {
+	ID        uuid.UUID `json:"id"`
+	Name      string    `json:"name,omitempty"`
+	Category  string    `json:"category,omitempty"`
+	Price     float64   `json:"price,omitempty"`
+	Allergens []string  `json:"allergens,omitempty"`
+	// AllergenWarnings has a warning for every allergen in the request's language, a single warning
+	// if the allergens are unknown, and is empty if there are none
+	AllergenWarnings []string `json:"allergen_warnings"`
+	Diets            []string `json:"diets,omitempty"`
+	ImageURL         string   `json:"image_url,omitempty"`
+	MachineID        string   `json:"machine_id,omitempty"`
+	Origin           string   `json:"origin,omitempty"`
+	// Stock is the number of units left, omitted if the source does not report stock
+	Stock     *int `json:"stock,omitempty"`
+	Available bool `json:"available"`
+}
+
+// ProductResponseFromFoodji converts a catalog product to an HTTP response with its warnings in the locale
+func ProductResponseFromFoodji(product foodji.Product, locale domain.Locale) *ProductResponse {
+	var stock *int
+	if units, known := product.Stock(); known {
+		stock = &units
+	}
+	return &ProductResponse{
+		ID:               product.ID,
+		Name:             product.Name,
+		Category:         product.Category,
+		Price:            product.Price,
+		Allergens:        product.Allergens,
+		AllergenWarnings: i18n.TranslateAll(locale, domain.AllergenWarnings(product.Allergens)),
+		Diets:            product.Diets,
+		ImageURL:         product.ImageURL,
+		MachineID:        product.MachineID,
+		Origin:           product.Origin,
+		Stock:            stock,
+		Available:        !product.SoldOut(),
+	}
+}
+
+// ProductListResponse represents products looked up by ID in the response
+type ProductListResponse struct {
+	Products []*ProductResponse `json:"products"`
+	Count    int                `json:"count"`
+}
+
+// ProductListResponseFromFoodji converts catalog products to an HTTP response with their warnings in the locale
+func ProductListResponseFromFoodji(products []foodji.Product, locale domain.Locale) *ProductListResponse {
+	result := make([]*ProductResponse, len(products))
+	for i, product := range products {
+		result[i] = ProductResponseFromFoodji(product, locale)
+	}
+	return &ProductListResponse{
+		Products: result,
+		Count:    len(result),
+	}
+}
+
+// ProductFacetsResponse represents the counts of matching products in the response
+type ProductFacetsResponse struct {
+	Categories []domain.CategoryFacet `json:"categories"`
+}
+
+// ProductSearchResponse represents the result of a product search in the response
+type ProductSearchResponse struct {
+	Products []*ProductResponse    `json:"products"`
+	Count    int                   `json:"count"`
+	Facets   ProductFacetsResponse `json:"facets"`
+}
+
+// ProductSearchResponseFromFoodji converts matching products and their facets to an HTTP response
+func ProductSearchResponseFromFoodji(products []foodji.Product, categories []domain.CategoryFacet, locale domain.Locale) *ProductSearchResponse {
+	result := make([]*ProductResponse, len(products))
+	for i, product := range products {
+		result[i] = ProductResponseFromFoodji(product, locale)
+	}
+	if categories == nil {
+		categories = []domain.CategoryFacet{}
+	}
+	return &ProductSearchResponse{
+		Products: result,
+		Count:    len(result),
+		Facets:   ProductFacetsResponse{Categories: categories},
+	}
+}
@@ -0,0 +1,53 @@
+package models
+
+import (
+	"time"
+
+	"github.com/ArtemSind/food_tinder/internal/domain"
+	"github.com/google/uuid"
+)
+
+// ProductAliasRequest represents the request body for declaring a product alias
+type ProductAliasRequest struct {
+	AliasID uuid.UUID `json:"alias_id" validate:"required"`
+}
+
+// ProductAliasResponse represents a product alias in the response
+type ProductAliasResponse struct {
+	AliasID          uuid.UUID `json:"alias_id"`
+	ProductID        uuid.UUID `json:"product_id"`
+	MovedVotes       int       `json:"moved_votes"`
+	MergedVotes      int       `json:"merged_votes"`
+	MovedImpressions int       `json:"moved_impressions"`
+	CreatedAt        time.Time `json:"created_at"`
+}
+
+// ProductAliasResponseFromDomain converts a domain product alias to an HTTP response
+func ProductAliasResponseFromDomain(alias *domain.ProductAlias) *ProductAliasResponse {
+	return &ProductAliasResponse{
+		AliasID:          alias.AliasID,
+		ProductID:        alias.ProductID,
+		MovedVotes:       alias.MovedVotes,
+		MergedVotes:      alias.MergedVotes,
+		MovedImpressions: alias.MovedImpressions,
+		CreatedAt:        alias.CreatedAt,
+	}
+}
+
+// ProductAliasListResponse represents a list of product aliases in the response
+type ProductAliasListResponse struct {
+	Aliases []*ProductAliasResponse `json:"aliases"`
+	Count   int                     `json:"count"`
+}
+
+// ProductAliasListResponseFromDomain converts a list of domain product aliases to an HTTP response
+func ProductAliasListResponseFromDomain(aliases []*domain.ProductAlias) *ProductAliasListResponse {
+	result := make([]*ProductAliasResponse, len(aliases))
+	for i, alias := range aliases {
+		result[i] = ProductAliasResponseFromDomain(alias)
+	}
+	return &ProductAliasListResponse{
+		Aliases: result,
+		Count:   len(result),
+	}
+}
@@ -0,0 +1,61 @@
+package models
+
+import (
+	"time"
+
+	"github.com/ArtemSind/food_tinder/internal/domain"
+	"github.com/google/uuid"
+)
+
+// ProductOverrideRequest represents the request body for overriding a product
+type ProductOverrideRequest struct {
+	Hidden   bool    `json:"hidden"`
+	Pinned   bool    `json:"pinned"`
+	Name     *string `json:"name,omitempty"`
+	Category *string `json:"category,omitempty"`
+	ImageURL *string `json:"image_url,omitempty"`
+}
+
+// ProductOverrideResponse represents a product override in the response
+type ProductOverrideResponse struct {
+	ProductID uuid.UUID `json:"product_id"`
+	Hidden    bool      `json:"hidden"`
+	Pinned    bool      `json:"pinned"`
+	Name      *string   `json:"name,omitempty"`
+	Category  *string   `json:"category,omitempty"`
+	ImageURL  *string   `json:"image_url,omitempty"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// ProductOverrideResponseFromDomain converts a domain product override to an HTTP response
+func ProductOverrideResponseFromDomain(override *domain.ProductOverride) *ProductOverrideResponse {
+	return &ProductOverrideResponse{
+		ProductID: override.ProductID,
+		Hidden:    override.Hidden,
+		Pinned:    override.Pinned,
+		Name:      override.Name,
+		Category:  override.Category,
+		ImageURL:  override.ImageURL,
+		CreatedAt: override.CreatedAt,
+		UpdatedAt: override.UpdatedAt,
+	}
+}
+
+// ProductOverrideListResponse represents a list of product overrides in the response
+type ProductOverrideListResponse struct {
+	Overrides []*ProductOverrideResponse `json:"overrides"`
+	Count     int                        `json:"count"`
+}
+
+// ProductOverrideListResponseFromDomain converts a list of domain product overrides to an HTTP response
+func ProductOverrideListResponseFromDomain(overrides []*domain.ProductOverride) *ProductOverrideListResponse {
+	result := make([]*ProductOverrideResponse, len(overrides))
+	for i, override := range overrides {
+		result[i] = ProductOverrideResponseFromDomain(override)
+	}
+	return &ProductOverrideListResponse{
+		Overrides: result,
+		Count:     len(result),
+	}
+}
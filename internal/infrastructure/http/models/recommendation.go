@@ -0,0 +1,85 @@
+package models
+
+import (
+	"fmt"
+
+	"github.com/ArtemSind/food_tinder/internal/domain"
+	"github.com/ArtemSind/food_tinder/internal/infrastructure/http/i18n"
+	"github.com/google/uuid"
+)
+
+// RecommendationResponse represents a recommended product in the response
+type RecommendationResponse struct {
+	ProductID       uuid.UUID `json:"product_id"`
+	PredictedScore  float64   `json:"predicted_score"`
+	BecauseYouLiked uuid.UUID `json:"because_you_liked"`
+	// BecauseYouLikedName is the name of the liked product, omitted if it left the catalog
+	BecauseYouLikedName string `json:"because_you_liked_name,omitempty"`
+	// Explanation names the liked product in the request's language
+	Explanation string `json:"explanation"`
+}
+
+// RecommendationResponseFromDomain converts a domain recommendation to an HTTP response with its
+// explanation in the locale
+func RecommendationResponseFromDomain(recommendation *domain.Recommendation, locale domain.Locale) *RecommendationResponse {
+	explanation := i18n.Translate(locale, "because you liked a product you rated")
+	if recommendation.BecauseOfName != "" {
+		explanation = fmt.Sprintf(i18n.Translate(locale, "because you liked %s"), recommendation.BecauseOfName)
+	}
+	return &RecommendationResponse{
+		ProductID:           recommendation.ProductID,
+		PredictedScore:      recommendation.PredictedScore,
+		BecauseYouLiked:     recommendation.BecauseOf,
+		BecauseYouLikedName: recommendation.BecauseOfName,
+		Explanation:         explanation,
+	}
+}
+
+// RecommendationListResponse represents a list of recommendations in the response
+type RecommendationListResponse struct {
+	Recommendations []*RecommendationResponse `json:"recommendations"`
+	Count           int                       `json:"count"`
+}
+
+// RecommendationListResponseFromDomain converts a list of domain recommendations to an HTTP response
+func RecommendationListResponseFromDomain(recommendations []*domain.Recommendation, locale domain.Locale) *RecommendationListResponse {
+	result := make([]*RecommendationResponse, len(recommendations))
+	for i, recommendation := range recommendations {
+		result[i] = RecommendationResponseFromDomain(recommendation, locale)
+	}
+	return &RecommendationListResponse{
+		Recommendations: result,
+		Count:           len(result),
+	}
+}
+
+// SimilarProductResponse represents a similar product in the response
+type SimilarProductResponse struct {
+	ProductID  uuid.UUID `json:"product_id"`
+	Similarity float64   `json:"similarity"`
+	CoRatings  int       `json:"co_ratings"`
+}
+
+// SimilarProductListResponse represents the products similar to a product in the response
+type SimilarProductListResponse struct {
+	ProductID uuid.UUID                 `json:"product_id"`
+	Similar   []*SimilarProductResponse `json:"similar"`
+	Count     int                       `json:"count"`
+}
+
+// SimilarProductListResponseFromDomain converts a list of domain similar products to an HTTP response
+func SimilarProductListResponseFromDomain(productID uuid.UUID, similar []domain.SimilarProduct) *SimilarProductListResponse {
+	result := make([]*SimilarProductResponse, len(similar))
+	for i, product := range similar {
+		result[i] = &SimilarProductResponse{
+			ProductID:  product.ProductID,
+			Similarity: product.Similarity,
+			CoRatings:  product.CoRatings,
+		}
+	}
+	return &SimilarProductListResponse{
+		ProductID: productID,
+		Similar:   result,
+		Count:     len(result),
+	}
+}
@@ -9,18 +9,57 @@ import (
 
 // SessionResponse represents the response body for a session
 type SessionResponse struct {
-	ID        uuid.UUID `json:"id"`
-	CreatedAt time.Time `json:"created_at"`
+	ID                 uuid.UUID                   `json:"id"`
+	DeckStrategy       string                      `json:"deck_strategy"`
+	DietaryPreferences *DietaryPreferencesResponse `json:"dietary_preferences"`
+	Locale             string                      `json:"locale,omitempty"`
+	CreatedAt          time.Time                   `json:"created_at"`
+}
+
+// DietaryPreferencesResponse represents the exclusions a session declared in the response
+type DietaryPreferencesResponse struct {
+	ExcludeAllergens []string `json:"exclude_allergens"`
+	Diets            []string `json:"diets"`
+}
+
+// DeckStrategyRequest represents the request body for choosing a session's deck strategy
+type DeckStrategyRequest struct {
+	DeckStrategy string `json:"deck_strategy" validate:"required,oneof=random popularity bandit recommendation"`
+}
+
+// DietaryPreferencesRequest represents the request body for declaring a session's dietary exclusions
+type DietaryPreferencesRequest struct {
+	ExcludeAllergens []string `json:"exclude_allergens"`
+	Diets            []string `json:"diets"`
+}
+
+// LocaleRequest represents the request body for choosing a session's language
+type LocaleRequest struct {
+	Locale string `json:"locale"`
 }
 
 // FromDomain converts a domain session to an HTTP response
 func SessionResponseFromDomain(session *domain.Session) *SessionResponse {
 	return &SessionResponse{
-		ID:        session.ID,
+		ID:           session.ID,
+		DeckStrategy: string(session.DeckStrategy),
+		DietaryPreferences: &DietaryPreferencesResponse{
+			ExcludeAllergens: nonNilStrings(session.Dietary.ExcludedAllergens),
+			Diets:            nonNilStrings(session.Dietary.Diets),
+		},
+		Locale:    string(session.Locale),
 		CreatedAt: session.CreatedAt,
 	}
 }
 
+// nonNilStrings returns an empty list for nil, so lists are encoded as [] instead of null
+func nonNilStrings(values []string) []string {
+	if values == nil {
+		return []string{}
+	}
+	return values
+}
+
 // SessionListResponse represents a list of sessions in the response
 type SessionListResponse struct {
 	Sessions []*SessionResponse `json:"sessions"`
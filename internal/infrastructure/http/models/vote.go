@@ -1,6 +1,7 @@
 package models
 
 import (
+	"encoding/json"
 	"time"
 
 	"github.com/ArtemSind/food_tinder/internal/domain"
@@ -20,23 +21,50 @@ type VoteResponse struct {
 	ProductID uuid.UUID `json:"product_id"`
 	Score     int       `json:"score"`
 	CreatedAt time.Time `json:"created_at"`
+	// ProductVersion is the content hash of the product when it was rated
+	ProductVersion string `json:"product_version,omitempty"`
+	// Outdated is set when the product changed since it was rated
+	Outdated bool `json:"outdated"`
 }
 
 // ProductScoreResponse represents a product with its aggregated score in the response
 type ProductScoreResponse struct {
-	ProductID uuid.UUID `json:"product_id"`
-	AvgScore  float64   `json:"avg_score"`
-	VoteCount int       `json:"vote_count"`
+	ProductID      uuid.UUID `json:"product_id"`
+	AvgScore       float64   `json:"avg_score"`
+	VoteCount      int       `json:"vote_count"`
+	Views          int       `json:"views"`
+	Skips          int       `json:"skips"`
+	ConversionRate float64   `json:"conversion_rate"`
+	SkipRate       float64   `json:"skip_rate"`
+	// Product is omitted for products that were never recorded in the catalog
+	Product      json.RawMessage `json:"product,omitempty"`
+	Discontinued bool            `json:"discontinued"`
+	LastSeenAt   *time.Time      `json:"last_seen_at,omitempty"`
+	// Versions splits the votes by the product version they were cast on, oldest first
+	Versions []*ProductVersionScoreResponse `json:"versions"`
+	// Price is the last recorded price; Value is the vote-weighted score per unit of it
+	Price *float64 `json:"price,omitempty"`
+	Value *float64 `json:"value,omitempty"`
+}
+
+// ProductVersionScoreResponse represents the votes on one version of a product in the response
+type ProductVersionScoreResponse struct {
+	Version   string  `json:"version"`
+	AvgScore  float64 `json:"avg_score"`
+	VoteCount int     `json:"vote_count"`
+	Current   bool    `json:"current"`
 }
 
 // FromDomain converts a domain vote to an HTTP response
 func VoteResponseFromDomain(vote *domain.Vote) *VoteResponse {
 	return &VoteResponse{
-		ID:        vote.ID,
-		SessionID: vote.SessionID,
-		ProductID: vote.ProductID,
-		Score:     vote.Score,
-		CreatedAt: vote.CreatedAt,
+		ID:             vote.ID,
+		SessionID:      vote.SessionID,
+		ProductID:      vote.ProductID,
+		Score:          vote.Score,
+		CreatedAt:      vote.CreatedAt,
+		ProductVersion: vote.ProductVersion,
+		Outdated:       vote.Outdated,
 	}
 }
 
@@ -69,27 +97,65 @@ func VoteListResponseFromDomain(votes []*domain.Vote) *VoteListResponse {
 
 // ProductScoreResponseFromDomain converts a domain product score to an HTTP response
 func ProductScoreResponseFromDomain(score *domain.ProductScore) *ProductScoreResponse {
-	return &ProductScoreResponse{
-		ProductID: score.ProductID,
-		AvgScore:  score.AvgScore,
-		VoteCount: score.VoteCount,
+	response := &ProductScoreResponse{
+		ProductID:      score.ProductID,
+		AvgScore:       score.AvgScore,
+		VoteCount:      score.VoteCount,
+		Views:          score.Views,
+		Skips:          score.Skips,
+		ConversionRate: score.ConversionRate(),
+		SkipRate:       score.SkipRate(),
+		Discontinued:   score.Discontinued(),
+		Versions:       make([]*ProductVersionScoreResponse, len(score.Versions)),
+		Price:          score.Price,
+	}
+	if value, ok := score.Value(); ok {
+		response.Value = &value
+	}
+	for i, version := range score.Versions {
+		response.Versions[i] = &ProductVersionScoreResponse{
+			Version:   version.Version,
+			AvgScore:  version.AvgScore,
+			VoteCount: version.VoteCount,
+			Current:   version.Current,
+		}
 	}
+	if score.Product != nil {
+		response.Product = score.Product.Data
+		response.LastSeenAt = &score.Product.LastSeenAt
+	}
+	return response
 }
 
 // ProductScoreListResponse represents a list of product scores in the response
 type ProductScoreListResponse struct {
 	Scores []*ProductScoreResponse `json:"scores"`
 	Count  int                     `json:"count"`
+	// Unvoted lists the products that were shown but never voted on; they are not ranked
+	Unvoted []*ProductViewsResponse `json:"unvoted"`
 }
 
-// ProductScoreListResponseFromDomain converts a list of domain product scores to an HTTP response
-func ProductScoreListResponseFromDomain(scores []*domain.ProductScore) *ProductScoreListResponse {
+// ProductViewsResponse represents the views of a product that was never voted on in the response
+type ProductViewsResponse struct {
+	ProductID uuid.UUID `json:"product_id"`
+	Views     int       `json:"views"`
+	Skips     int       `json:"skips"`
+}
+
+// ProductScoreListResponseFromDomain converts domain product scores and the views of unvoted
+// products to an HTTP response
+func ProductScoreListResponseFromDomain(scores []*domain.ProductScore, unvoted []*domain.ProductViews) *ProductScoreListResponse {
 	result := make([]*ProductScoreResponse, len(scores))
 	for i, score := range scores {
 		result[i] = ProductScoreResponseFromDomain(score)
 	}
+	views := make([]*ProductViewsResponse, len(unvoted))
+	for i, v := range unvoted {
+		views[i] = &ProductViewsResponse{ProductID: v.ProductID, Views: v.Views, Skips: v.Skips}
+	}
 	return &ProductScoreListResponse{
-		Scores: result,
-		Count:  len(result),
+		Scores:  result,
+		Count:   len(result),
+		Unvoted: views,
 	}
 }
@@ -0,0 +1,114 @@
+package models
+
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/ArtemSind/food_tinder/internal/domain"
+	"github.com/google/uuid"
+)
+
+// WebhookRequest represents the request body for registering a webhook
+type WebhookRequest struct {
+	URL        string   `json:"url" validate:"required,url"`
+	Secret     string   `json:"secret,omitempty"`
+	EventTypes []string `json:"event_types" validate:"required,min=1"`
+}
+
+// WebhookResponse represents a webhook subscription in the response
+type WebhookResponse struct {
+	ID         uuid.UUID `json:"id"`
+	URL        string    `json:"url"`
+	EventTypes []string  `json:"event_types"`
+	Active     bool      `json:"active"`
+	CreatedAt  time.Time `json:"created_at"`
+}
+
+// WebhookCreatedResponse is returned once on registration and includes the signing secret
+type WebhookCreatedResponse struct {
+	WebhookResponse
+	Secret string `json:"secret"`
+}
+
+// WebhookResponseFromDomain converts a domain webhook subscription to an HTTP response
+func WebhookResponseFromDomain(subscription *domain.WebhookSubscription) *WebhookResponse {
+	return &WebhookResponse{
+		ID:         subscription.ID,
+		URL:        subscription.URL,
+		EventTypes: subscription.EventTypes,
+		Active:     subscription.Active,
+		CreatedAt:  subscription.CreatedAt,
+	}
+}
+
+// WebhookCreatedResponseFromDomain converts a newly created subscription to an HTTP response
+func WebhookCreatedResponseFromDomain(subscription *domain.WebhookSubscription) *WebhookCreatedResponse {
+	return &WebhookCreatedResponse{
+		WebhookResponse: *WebhookResponseFromDomain(subscription),
+		Secret:          subscription.Secret,
+	}
+}
+
+// WebhookListResponse represents a list of webhook subscriptions in the response
+type WebhookListResponse struct {
+	Webhooks []*WebhookResponse `json:"webhooks"`
+	Count    int                `json:"count"`
+}
+
+// WebhookListResponseFromDomain converts a list of domain webhook subscriptions to an HTTP response
+func WebhookListResponseFromDomain(subscriptions []*domain.WebhookSubscription) *WebhookListResponse {
+	result := make([]*WebhookResponse, len(subscriptions))
+	for i, subscription := range subscriptions {
+		result[i] = WebhookResponseFromDomain(subscription)
+	}
+	return &WebhookListResponse{
+		Webhooks: result,
+		Count:    len(result),
+	}
+}
+
+// WebhookDeliveryResponse represents a webhook delivery in the response
+type WebhookDeliveryResponse struct {
+	ID             uuid.UUID       `json:"id"`
+	SubscriptionID uuid.UUID       `json:"subscription_id"`
+	EventID        uuid.UUID       `json:"event_id"`
+	EventType      string          `json:"event_type"`
+	Payload        json.RawMessage `json:"payload"`
+	Status         string          `json:"status"`
+	Attempts       int             `json:"attempts"`
+	LastError      string          `json:"last_error,omitempty"`
+	CreatedAt      time.Time       `json:"created_at"`
+}
+
+// WebhookDeliveryResponseFromDomain converts a domain webhook delivery to an HTTP response
+func WebhookDeliveryResponseFromDomain(delivery *domain.WebhookDelivery) *WebhookDeliveryResponse {
+	return &WebhookDeliveryResponse{
+		ID:             delivery.ID,
+		SubscriptionID: delivery.SubscriptionID,
+		EventID:        delivery.EventID,
+		EventType:      delivery.EventType,
+		Payload:        delivery.Payload,
+		Status:         string(delivery.Status),
+		Attempts:       delivery.Attempts,
+		LastError:      delivery.LastError,
+		CreatedAt:      delivery.CreatedAt,
+	}
+}
+
+// WebhookDeliveryListResponse represents a list of webhook deliveries in the response
+type WebhookDeliveryListResponse struct {
+	Deliveries []*WebhookDeliveryResponse `json:"deliveries"`
+	Count      int                        `json:"count"`
+}
+
+// WebhookDeliveryListResponseFromDomain converts a list of domain webhook deliveries to an HTTP response
+func WebhookDeliveryListResponseFromDomain(deliveries []*domain.WebhookDelivery) *WebhookDeliveryListResponse {
+	result := make([]*WebhookDeliveryResponse, len(deliveries))
+	for i, delivery := range deliveries {
+		result[i] = WebhookDeliveryResponseFromDomain(delivery)
+	}
+	return &WebhookDeliveryListResponse{
+		Deliveries: result,
+		Count:      len(result),
+	}
+}
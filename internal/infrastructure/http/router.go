@@ -3,19 +3,41 @@ package http
 import (
 	"github.com/ArtemSind/food_tinder/internal/application"
 	"github.com/ArtemSind/food_tinder/internal/infrastructure/http/handlers"
+	"github.com/ArtemSind/food_tinder/internal/infrastructure/http/i18n"
 	"github.com/gorilla/mux"
 )
 
 func NewRouter(
 	sessionService *application.SessionService,
 	voteService *application.VoteService,
+	webhookService *application.WebhookService,
+	recommendationService *application.RecommendationService,
+	deckService *application.DeckService,
+	experimentService *application.ExperimentService,
+	impressionService *application.ImpressionService,
+	catalogService *application.CatalogService,
+	productService *application.ProductService,
+	productOverrideService *application.ProductOverrideService,
+	productAliasService *application.ProductAliasService,
 ) *mux.Router {
 	r := mux.NewRouter()
+	r.Use(i18n.Middleware(sessionService))
 
 	// Session handlers
 	sessionHandler := handlers.NewSessionHandler(sessionService)
 	r.HandleFunc("/api/sessions", sessionHandler.CreateSession).Methods("POST")
 	r.HandleFunc("/api/sessions/{sessionID}", sessionHandler.GetSession).Methods("GET")
+	r.HandleFunc("/api/sessions/{sessionID}/deck-strategy", sessionHandler.SetDeckStrategy).Methods("PUT")
+	r.HandleFunc("/api/sessions/{sessionID}/dietary-preferences", sessionHandler.SetDietaryPreferences).Methods("PUT")
+	r.HandleFunc("/api/sessions/{sessionID}/locale", sessionHandler.SetLocale).Methods("PUT")
+
+	// Deck handlers
+	deckHandler := handlers.NewDeckHandler(deckService)
+	r.HandleFunc("/api/sessions/{sessionID}/deck", deckHandler.GetDeck).Methods("GET")
+
+	// Impression handlers
+	impressionHandler := handlers.NewImpressionHandler(impressionService)
+	r.HandleFunc("/api/sessions/{sessionID}/impressions", impressionHandler.RecordImpression).Methods("POST")
 
 	// Vote handlers
 	voteHandler := handlers.NewVoteHandler(voteService)
@@ -23,5 +45,54 @@ func NewRouter(
 	r.HandleFunc("/api/sessions/{sessionID}/votes", voteHandler.GetVotesBySession).Methods("GET")
 	r.HandleFunc("/api/votes/aggregated", voteHandler.GetAggregatedScores).Methods("GET")
 
+	// Product handlers
+	productHandler := handlers.NewProductHandler(productService)
+	r.HandleFunc("/api/products", productHandler.SearchProducts).Methods("GET")
+	r.HandleFunc("/api/products/cards", productHandler.GetProductCards).Methods("GET")
+
+	// Recommendation handlers
+	recommendationHandler := handlers.NewRecommendationHandler(recommendationService)
+	r.HandleFunc("/api/sessions/{sessionID}/recommendations", recommendationHandler.GetRecommendations).Methods("GET")
+	r.HandleFunc("/api/products/{productID}/similar", recommendationHandler.GetSimilarProducts).Methods("GET")
+
+	// Webhook handlers
+	webhookHandler := handlers.NewWebhookHandler(webhookService)
+	r.HandleFunc("/admin/webhooks", webhookHandler.CreateWebhook).Methods("POST")
+	r.HandleFunc("/admin/webhooks", webhookHandler.ListWebhooks).Methods("GET")
+	r.HandleFunc("/admin/webhooks/dead-letters", webhookHandler.ListDeadLetters).Methods("GET")
+	r.HandleFunc("/admin/webhooks/dead-letters/{deliveryID}/retry", webhookHandler.RetryDelivery).Methods("POST")
+	r.HandleFunc("/admin/webhooks/{webhookID}", webhookHandler.DeleteWebhook).Methods("DELETE")
+
+	// Catalog handlers
+	catalogHandler := handlers.NewCatalogHandler(catalogService)
+	r.HandleFunc("/admin/products/validation", catalogHandler.GetValidation).Methods("GET")
+	r.HandleFunc("/admin/products/refresh", catalogHandler.RefreshCatalog).Methods("POST")
+	r.HandleFunc("/admin/products/refresh", catalogHandler.GetRefreshStatus).Methods("GET")
+	r.HandleFunc("/admin/products/cache", catalogHandler.GetProductCacheStats).Methods("GET")
+	r.HandleFunc("/admin/products/snapshots", catalogHandler.ListSnapshots).Methods("GET")
+	r.HandleFunc("/admin/products/snapshots/{version}/rollback", catalogHandler.RollbackCatalog).Methods("POST")
+	r.HandleFunc("/admin/products/snapshots/pin", catalogHandler.UnpinCatalog).Methods("DELETE")
+	r.HandleFunc("/admin/products/{productID}/history", catalogHandler.GetProductHistory).Methods("GET")
+	r.HandleFunc("/api/products/{productID}/prices", catalogHandler.GetPriceHistory).Methods("GET")
+
+	// Product override handlers
+	productOverrideHandler := handlers.NewProductOverrideHandler(productOverrideService)
+	r.HandleFunc("/admin/products/overrides", productOverrideHandler.ListOverrides).Methods("GET")
+	r.HandleFunc("/admin/products/{productID}/override", productOverrideHandler.SetOverride).Methods("PUT")
+	r.HandleFunc("/admin/products/{productID}/override", productOverrideHandler.GetOverride).Methods("GET")
+	r.HandleFunc("/admin/products/{productID}/override", productOverrideHandler.DeleteOverride).Methods("DELETE")
+
+	// Product alias handlers
+	productAliasHandler := handlers.NewProductAliasHandler(productAliasService)
+	r.HandleFunc("/admin/products/aliases", productAliasHandler.ListAliases).Methods("GET")
+	r.HandleFunc("/admin/products/{productID}/aliases", productAliasHandler.CreateAlias).Methods("POST")
+
+	// Experiment handlers
+	experimentHandler := handlers.NewExperimentHandler(experimentService)
+	r.HandleFunc("/admin/experiments", experimentHandler.CreateExperiment).Methods("POST")
+	r.HandleFunc("/admin/experiments", experimentHandler.ListExperiments).Methods("GET")
+	r.HandleFunc("/admin/experiments/{experimentID}/stop", experimentHandler.StopExperiment).Methods("POST")
+	r.HandleFunc("/admin/experiments/{experimentID}/results", experimentHandler.GetResults).Methods("GET")
+
 	return r
 }
@@ -0,0 +1,158 @@
+package persistence
+
+import (
+	"context"
+	"errors"
+
+	"github.com/ArtemSind/food_tinder/internal/domain"
+	"github.com/ArtemSind/food_tinder/internal/infrastructure/persistence/models"
+	"github.com/google/uuid"
+	"github.com/jackc/pgconn"
+	"github.com/jackc/pgx/v4"
+	"github.com/jackc/pgx/v4/pgxpool"
+)
+
+const experimentColumns = "id, name, description, status, variants, created_at, stopped_at"
+
+// uniqueViolation is the Postgres error code of a unique index violation
+const uniqueViolation = "23505"
+
+type ExperimentRepository struct {
+	db *pgxpool.Pool
+}
+
+func NewExperimentRepository(db *pgxpool.Pool) *ExperimentRepository {
+	return &ExperimentRepository{db: db}
+}
+
+func (r *ExperimentRepository) Create(ctx context.Context, experiment *domain.Experiment) error {
+	dbExperiment, err := models.ExperimentFromDomain(experiment)
+	if err != nil {
+		return err
+	}
+	_, err = r.db.Exec(ctx,
+		"INSERT INTO experiments ("+experimentColumns+") VALUES ($1, $2, $3, $4, $5, $6, $7)",
+		dbExperiment.ID, dbExperiment.Name, dbExperiment.Description, dbExperiment.Status,
+		dbExperiment.Variants, dbExperiment.CreatedAt, dbExperiment.StoppedAt)
+	// Two creates racing past the service's check meet at the index of running experiments
+	var pgErr *pgconn.PgError
+	if errors.As(err, &pgErr) && pgErr.Code == uniqueViolation && pgErr.ConstraintName == "experiments_single_running_idx" {
+		return domain.ErrExperimentAlreadyRunning
+	}
+	return err
+}
+
+func (r *ExperimentRepository) GetByID(ctx context.Context, id uuid.UUID) (*domain.Experiment, error) {
+	experiment, err := r.queryOne(ctx,
+		"SELECT "+experimentColumns+" FROM experiments WHERE id = $1", id)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, domain.ErrExperimentNotFound
+	}
+	return experiment, err
+}
+
+// GetRunning returns the running experiment, or nil when none is running
+func (r *ExperimentRepository) GetRunning(ctx context.Context) (*domain.Experiment, error) {
+	experiment, err := r.queryOne(ctx,
+		"SELECT "+experimentColumns+" FROM experiments WHERE status = 'running'")
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, nil
+	}
+	return experiment, err
+}
+
+func (r *ExperimentRepository) List(ctx context.Context) ([]*domain.Experiment, error) {
+	rows, err := r.db.Query(ctx,
+		"SELECT "+experimentColumns+" FROM experiments ORDER BY created_at DESC")
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var experiments []*domain.Experiment
+	for rows.Next() {
+		experiment, err := scanExperiment(rows)
+		if err != nil {
+			return nil, err
+		}
+		experiments = append(experiments, experiment)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return experiments, nil
+}
+
+func (r *ExperimentRepository) Update(ctx context.Context, experiment *domain.Experiment) error {
+	tag, err := r.db.Exec(ctx,
+		"UPDATE experiments SET status = $1, stopped_at = $2 WHERE id = $3",
+		string(experiment.Status), experiment.StoppedAt, experiment.ID)
+	if err != nil {
+		return err
+	}
+	if tag.RowsAffected() == 0 {
+		return domain.ErrExperimentNotFound
+	}
+	return nil
+}
+
+// GetVariantStats totals impressions and votes of the sessions assigned to each variant. A
+// product shown to a session more than once, by the deck and by the client, counts once.
+func (r *ExperimentRepository) GetVariantStats(ctx context.Context, experimentID uuid.UUID) ([]*domain.VariantStats, error) {
+	rows, err := r.db.Query(ctx,
+		`SELECT
+			a.variant,
+			COUNT(*) AS sessions,
+			COALESCE(SUM(i.impressions), 0)::bigint AS impressions,
+			COALESCE(SUM(v.votes), 0)::bigint AS votes,
+			COALESCE(SUM(v.score_sum), 0)::bigint AS score_sum,
+			COALESCE(SUM(v.score_squared_sum), 0)::bigint AS score_squared_sum
+		FROM experiment_assignments a
+		LEFT JOIN (
+			SELECT session_id, COUNT(DISTINCT product_id) AS impressions
+			FROM impressions
+			WHERE action = 'impression'
+			GROUP BY session_id
+		) i ON i.session_id = a.session_id
+		LEFT JOIN (
+			SELECT session_id, COUNT(*) AS votes, SUM(score) AS score_sum, SUM(score * score) AS score_squared_sum
+			FROM votes
+			GROUP BY session_id
+		) v ON v.session_id = a.session_id
+		WHERE a.experiment_id = $1
+		GROUP BY a.variant`, experimentID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var stats []*domain.VariantStats
+	for rows.Next() {
+		s := &domain.VariantStats{}
+		if err := rows.Scan(&s.Variant, &s.Sessions, &s.Impressions, &s.Votes, &s.ScoreSum, &s.ScoreSquaredSum); err != nil {
+			return nil, err
+		}
+		stats = append(stats, s)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return stats, nil
+}
+
+func (r *ExperimentRepository) queryOne(ctx context.Context, query string, args ...interface{}) (*domain.Experiment, error) {
+	return scanExperiment(r.db.QueryRow(ctx, query, args...))
+}
+
+func scanExperiment(row pgx.Row) (*domain.Experiment, error) {
+	var dbExperiment models.ExperimentDB
+	if err := row.Scan(&dbExperiment.ID, &dbExperiment.Name, &dbExperiment.Description, &dbExperiment.Status,
+		&dbExperiment.Variants, &dbExperiment.CreatedAt, &dbExperiment.StoppedAt); err != nil {
+		return nil, err
+	}
+	return dbExperiment.ToDomain()
+}
@@ -0,0 +1,42 @@
+package persistence
+
+import (
+	"context"
+
+	"github.com/ArtemSind/food_tinder/internal/domain"
+	"github.com/jackc/pgx/v4"
+	"github.com/jackc/pgx/v4/pgxpool"
+)
+
+type ImpressionRepository struct {
+	db *pgxpool.Pool
+}
+
+func NewImpressionRepository(db *pgxpool.Pool) *ImpressionRepository {
+	return &ImpressionRepository{db: db}
+}
+
+// CreateBatch stores the impressions in a single round trip
+func (r *ImpressionRepository) CreateBatch(ctx context.Context, impressions []*domain.Impression) error {
+	if len(impressions) == 0 {
+		return nil
+	}
+
+	batch := &pgx.Batch{}
+	for _, impression := range impressions {
+		batch.Queue(
+			"INSERT INTO impressions (id, session_id, product_id, strategy, position, action, created_at) VALUES ($1, $2, $3, $4, $5, $6, $7)",
+			impression.ID, impression.SessionID, impression.ProductID, string(impression.Strategy),
+			impression.Position, string(impression.Action), impression.CreatedAt)
+	}
+
+	results := r.db.SendBatch(ctx, batch)
+	defer results.Close()
+
+	for range impressions {
+		if _, err := results.Exec(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
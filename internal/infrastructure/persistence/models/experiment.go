@@ -0,0 +1,54 @@
+package models
+
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/ArtemSind/food_tinder/internal/domain"
+	"github.com/google/uuid"
+)
+
+// ExperimentDB represents an experiment entity in the database
+type ExperimentDB struct {
+	ID          uuid.UUID  `db:"id"`
+	Name        string     `db:"name"`
+	Description string     `db:"description"`
+	Status      string     `db:"status"`
+	Variants    []byte     `db:"variants"`
+	CreatedAt   time.Time  `db:"created_at"`
+	StoppedAt   *time.Time `db:"stopped_at"`
+}
+
+// ToDomain converts a database experiment model to a domain experiment model
+func (e *ExperimentDB) ToDomain() (*domain.Experiment, error) {
+	var variants []domain.Variant
+	if err := json.Unmarshal(e.Variants, &variants); err != nil {
+		return nil, err
+	}
+	return &domain.Experiment{
+		ID:          e.ID,
+		Name:        e.Name,
+		Description: e.Description,
+		Status:      domain.ExperimentStatus(e.Status),
+		Variants:    variants,
+		CreatedAt:   e.CreatedAt,
+		StoppedAt:   e.StoppedAt,
+	}, nil
+}
+
+// ExperimentFromDomain converts a domain experiment model to a database experiment model
+func ExperimentFromDomain(experiment *domain.Experiment) (*ExperimentDB, error) {
+	variants, err := json.Marshal(experiment.Variants)
+	if err != nil {
+		return nil, err
+	}
+	return &ExperimentDB{
+		ID:          experiment.ID,
+		Name:        experiment.Name,
+		Description: experiment.Description,
+		Status:      string(experiment.Status),
+		Variants:    variants,
+		CreatedAt:   experiment.CreatedAt,
+		StoppedAt:   experiment.StoppedAt,
+	}, nil
+}
@@ -0,0 +1,85 @@
+package models
+
+import (
+	"time"
+
+	"github.com/ArtemSind/food_tinder/internal/domain"
+	"github.com/google/uuid"
+)
+
+// ProductDB represents a catalog product entity in the database
+type ProductDB struct {
+	ID             uuid.UUID  `db:"id"`
+	ContentHash    string     `db:"content_hash"`
+	Data           []byte     `db:"data"`
+	FirstSeenAt    time.Time  `db:"first_seen_at"`
+	LastSeenAt     time.Time  `db:"last_seen_at"`
+	DiscontinuedAt *time.Time `db:"discontinued_at"`
+}
+
+// ToDomain converts a database product model to a domain catalog product
+func (p *ProductDB) ToDomain() *domain.CatalogProduct {
+	return &domain.CatalogProduct{
+		ID:             p.ID,
+		ContentHash:    p.ContentHash,
+		Data:           p.Data,
+		FirstSeenAt:    p.FirstSeenAt,
+		LastSeenAt:     p.LastSeenAt,
+		DiscontinuedAt: p.DiscontinuedAt,
+	}
+}
+
+// ProductEventDB represents a catalog change of a product in the database
+type ProductEventDB struct {
+	ID          uuid.UUID `db:"id"`
+	ProductID   uuid.UUID `db:"product_id"`
+	EventType   string    `db:"event_type"`
+	ContentHash string    `db:"content_hash"`
+	CreatedAt   time.Time `db:"created_at"`
+}
+
+// ToDomain converts a database product event model to a domain product event
+func (e *ProductEventDB) ToDomain() *domain.ProductEvent {
+	return &domain.ProductEvent{
+		ID:          e.ID,
+		ProductID:   e.ProductID,
+		Type:        domain.ProductEventType(e.EventType),
+		ContentHash: e.ContentHash,
+		CreatedAt:   e.CreatedAt,
+	}
+}
+
+// ProductEventFromDomain converts a domain product event to a database model
+func ProductEventFromDomain(event *domain.ProductEvent) *ProductEventDB {
+	return &ProductEventDB{
+		ID:          event.ID,
+		ProductID:   event.ProductID,
+		EventType:   string(event.Type),
+		ContentHash: event.ContentHash,
+		CreatedAt:   event.CreatedAt,
+	}
+}
+
+// PricePointDB represents a price of a product in the database, with the votes cast at that price
+type PricePointDB struct {
+	ProductID      uuid.UUID `db:"product_id"`
+	Price          float64   `db:"price"`
+	ContentHash    string    `db:"content_hash"`
+	RecordedAt     time.Time `db:"recorded_at"`
+	LastRecordedAt time.Time `db:"last_recorded_at"`
+	AvgScore       float64   `db:"avg_score"`
+	VoteCount      int       `db:"vote_count"`
+}
+
+// ToDomain converts a database price model to a domain price point
+func (p *PricePointDB) ToDomain() *domain.PricePoint {
+	return &domain.PricePoint{
+		ProductID:      p.ProductID,
+		Price:          p.Price,
+		Version:        p.ContentHash,
+		RecordedAt:     p.RecordedAt,
+		LastRecordedAt: p.LastRecordedAt,
+		AvgScore:       p.AvgScore,
+		VoteCount:      p.VoteCount,
+	}
+}
@@ -0,0 +1,42 @@
+package models
+
+import (
+	"time"
+
+	"github.com/ArtemSind/food_tinder/internal/domain"
+	"github.com/google/uuid"
+)
+
+// ProductAliasDB represents a product alias entity in the database
+type ProductAliasDB struct {
+	AliasID          uuid.UUID `db:"alias_id"`
+	ProductID        uuid.UUID `db:"product_id"`
+	MovedVotes       int       `db:"moved_votes"`
+	MergedVotes      int       `db:"merged_votes"`
+	MovedImpressions int       `db:"moved_impressions"`
+	CreatedAt        time.Time `db:"created_at"`
+}
+
+// ToDomain converts a database product alias model to a domain model
+func (a *ProductAliasDB) ToDomain() *domain.ProductAlias {
+	return &domain.ProductAlias{
+		AliasID:          a.AliasID,
+		ProductID:        a.ProductID,
+		MovedVotes:       a.MovedVotes,
+		MergedVotes:      a.MergedVotes,
+		MovedImpressions: a.MovedImpressions,
+		CreatedAt:        a.CreatedAt,
+	}
+}
+
+// ProductAliasFromDomain converts a domain product alias to a database model
+func ProductAliasFromDomain(alias *domain.ProductAlias) *ProductAliasDB {
+	return &ProductAliasDB{
+		AliasID:          alias.AliasID,
+		ProductID:        alias.ProductID,
+		MovedVotes:       alias.MovedVotes,
+		MergedVotes:      alias.MergedVotes,
+		MovedImpressions: alias.MovedImpressions,
+		CreatedAt:        alias.CreatedAt,
+	}
+}
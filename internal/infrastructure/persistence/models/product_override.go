@@ -0,0 +1,48 @@
+package models
+
+import (
+	"time"
+
+	"github.com/ArtemSind/food_tinder/internal/domain"
+	"github.com/google/uuid"
+)
+
+// ProductOverrideDB represents a product override entity in the database
+type ProductOverrideDB struct {
+	ProductID uuid.UUID `db:"product_id"`
+	Hidden    bool      `db:"hidden"`
+	Pinned    bool      `db:"pinned"`
+	Name      *string   `db:"name"`
+	Category  *string   `db:"category"`
+	ImageURL  *string   `db:"image_url"`
+	CreatedAt time.Time `db:"created_at"`
+	UpdatedAt time.Time `db:"updated_at"`
+}
+
+// ToDomain converts a database product override model to a domain model
+func (o *ProductOverrideDB) ToDomain() *domain.ProductOverride {
+	return &domain.ProductOverride{
+		ProductID: o.ProductID,
+		Hidden:    o.Hidden,
+		Pinned:    o.Pinned,
+		Name:      o.Name,
+		Category:  o.Category,
+		ImageURL:  o.ImageURL,
+		CreatedAt: o.CreatedAt,
+		UpdatedAt: o.UpdatedAt,
+	}
+}
+
+// ProductOverrideFromDomain converts a domain product override to a database model
+func ProductOverrideFromDomain(override *domain.ProductOverride) *ProductOverrideDB {
+	return &ProductOverrideDB{
+		ProductID: override.ProductID,
+		Hidden:    override.Hidden,
+		Pinned:    override.Pinned,
+		Name:      override.Name,
+		Category:  override.Category,
+		ImageURL:  override.ImageURL,
+		CreatedAt: override.CreatedAt,
+		UpdatedAt: override.UpdatedAt,
+	}
+}
@@ -9,24 +9,53 @@ import (
 
 // SessionDB represents a session entity in the database
 type SessionDB struct {
-	ID        uuid.UUID `db:"id"`
-	CreatedAt time.Time `db:"created_at"`
+	ID           uuid.UUID `db:"id"`
+	DeckStrategy string    `db:"deck_strategy"`
+	CreatedAt    time.Time `db:"created_at"`
+	// ExperimentID and Variant are empty for sessions outside any experiment
+	ExperimentID      string   `db:"experiment_id"`
+	Variant           string   `db:"variant"`
+	ExcludedAllergens []string `db:"excluded_allergens"`
+	Diets             []string `db:"diets"`
+	Locale            string   `db:"locale"`
 }
 
 // ToDomain converts a database session model to a domain session model
 func (s *SessionDB) ToDomain() *domain.Session {
-	return &domain.Session{
-		ID:        s.ID,
+	session := &domain.Session{
+		ID:           s.ID,
+		DeckStrategy: domain.DeckStrategy(s.DeckStrategy),
+		Dietary: domain.DietaryPreferences{
+			ExcludedAllergens: nonNilStrings(s.ExcludedAllergens),
+			Diets:             nonNilStrings(s.Diets),
+		},
+		Locale:    domain.Locale(s.Locale),
 		CreatedAt: s.CreatedAt,
 	}
+	if experimentID, err := uuid.Parse(s.ExperimentID); err == nil {
+		session.Experiment = &domain.ExperimentAssignment{
+			ExperimentID: experimentID,
+			Variant:      s.Variant,
+		}
+	}
+	return session
 }
 
 // FromDomain converts a domain session model to a database session model
 func SessionFromDomain(session *domain.Session) *SessionDB {
-	return &SessionDB{
-		ID:        session.ID,
-		CreatedAt: session.CreatedAt,
+	dbSession := &SessionDB{
+		ID:                session.ID,
+		DeckStrategy:      string(session.DeckStrategy),
+		CreatedAt:         session.CreatedAt,
+		ExcludedAllergens: nonNilStrings(session.Dietary.ExcludedAllergens),
+		Diets:             nonNilStrings(session.Dietary.Diets),
+		Locale:            string(session.Locale),
+	}
+	if session.Experiment != nil {
+		dbSession.ExperimentID = session.Experiment.ExperimentID.String()
+		dbSession.Variant = session.Experiment.Variant
 	}
+	return dbSession
 }
 
 // ToDomainList converts a list of database session models to domain session models
@@ -46,3 +75,11 @@ func SessionsFromDomain(sessions []*domain.Session) []*SessionDB {
 	}
 	return result
 }
+
+// nonNilStrings returns an empty list for nil, so empty arrays are stored as '{}' instead of NULL
+func nonNilStrings(values []string) []string {
+	if values == nil {
+		return []string{}
+	}
+	return values
+}
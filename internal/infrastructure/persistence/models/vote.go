@@ -14,28 +14,45 @@ type VoteDB struct {
 	ProductID uuid.UUID `db:"product_id"`
 	Score     int       `db:"score"`
 	CreatedAt time.Time `db:"created_at"`
+	UpdatedAt time.Time `db:"updated_at"`
+	// ProductVersion is NULL for votes from before products were tracked
+	ProductVersion *string `db:"product_version"`
+	// Outdated is computed against the current product when reading votes
+	Outdated bool `db:"outdated"`
 }
 
 // ToDomain converts a database vote model to a domain vote model
 func (v *VoteDB) ToDomain() *domain.Vote {
-	return &domain.Vote{
+	vote := &domain.Vote{
 		ID:        v.ID,
 		SessionID: v.SessionID,
 		ProductID: v.ProductID,
 		Score:     v.Score,
 		CreatedAt: v.CreatedAt,
+		UpdatedAt: v.UpdatedAt,
+		Outdated:  v.Outdated,
 	}
+	if v.ProductVersion != nil {
+		vote.ProductVersion = *v.ProductVersion
+	}
+	return vote
 }
 
 // FromDomain converts a domain vote model to a database vote model
 func VoteFromDomain(vote *domain.Vote) *VoteDB {
-	return &VoteDB{
+	dbVote := &VoteDB{
 		ID:        vote.ID,
 		SessionID: vote.SessionID,
 		ProductID: vote.ProductID,
 		Score:     vote.Score,
 		CreatedAt: vote.CreatedAt,
+		UpdatedAt: vote.UpdatedAt,
+		Outdated:  vote.Outdated,
+	}
+	if vote.ProductVersion != "" {
+		dbVote.ProductVersion = &vote.ProductVersion
 	}
+	return dbVote
 }
 
 // ToDomainList converts a list of database vote models to domain vote models
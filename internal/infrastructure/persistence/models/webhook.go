@@ -0,0 +1,123 @@
+package models
+
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/ArtemSind/food_tinder/internal/domain"
+	"github.com/google/uuid"
+)
+
+// WebhookSubscriptionDB represents a webhook subscription entity in the database
+type WebhookSubscriptionDB struct {
+	ID         uuid.UUID `db:"id"`
+	URL        string    `db:"url"`
+	Secret     string    `db:"secret"`
+	EventTypes []string  `db:"event_types"`
+	Active     bool      `db:"active"`
+	CreatedAt  time.Time `db:"created_at"`
+}
+
+// ToDomain converts a database webhook subscription model to a domain model
+func (s *WebhookSubscriptionDB) ToDomain() *domain.WebhookSubscription {
+	return &domain.WebhookSubscription{
+		ID:         s.ID,
+		URL:        s.URL,
+		Secret:     s.Secret,
+		EventTypes: s.EventTypes,
+		Active:     s.Active,
+		CreatedAt:  s.CreatedAt,
+	}
+}
+
+// WebhookSubscriptionFromDomain converts a domain webhook subscription to a database model
+func WebhookSubscriptionFromDomain(subscription *domain.WebhookSubscription) *WebhookSubscriptionDB {
+	return &WebhookSubscriptionDB{
+		ID:         subscription.ID,
+		URL:        subscription.URL,
+		Secret:     subscription.Secret,
+		EventTypes: subscription.EventTypes,
+		Active:     subscription.Active,
+		CreatedAt:  subscription.CreatedAt,
+	}
+}
+
+// WebhookSubscriptionsToDomain converts a list of database webhook subscriptions to domain models
+func WebhookSubscriptionsToDomain(subscriptions []*WebhookSubscriptionDB) []*domain.WebhookSubscription {
+	result := make([]*domain.WebhookSubscription, len(subscriptions))
+	for i, subscription := range subscriptions {
+		result[i] = subscription.ToDomain()
+	}
+	return result
+}
+
+// OutboxEventDB represents an outbox event entity in the database
+type OutboxEventDB struct {
+	ID        uuid.UUID `db:"id"`
+	EventType string    `db:"event_type"`
+	Payload   []byte    `db:"payload"`
+	CreatedAt time.Time `db:"created_at"`
+}
+
+// ToDomain converts a database outbox event model to a domain model
+func (e *OutboxEventDB) ToDomain() *domain.OutboxEvent {
+	return &domain.OutboxEvent{
+		ID:        e.ID,
+		EventType: e.EventType,
+		Payload:   json.RawMessage(e.Payload),
+		CreatedAt: e.CreatedAt,
+	}
+}
+
+// OutboxEventFromDomain converts a domain outbox event to a database model
+func OutboxEventFromDomain(event *domain.OutboxEvent) *OutboxEventDB {
+	return &OutboxEventDB{
+		ID:        event.ID,
+		EventType: event.EventType,
+		Payload:   []byte(event.Payload),
+		CreatedAt: event.CreatedAt,
+	}
+}
+
+// WebhookDeliveryDB represents a webhook delivery joined with its event and subscription
+type WebhookDeliveryDB struct {
+	ID             uuid.UUID `db:"id"`
+	SubscriptionID uuid.UUID `db:"subscription_id"`
+	EventID        uuid.UUID `db:"event_id"`
+	EventType      string    `db:"event_type"`
+	Payload        []byte    `db:"payload"`
+	Status         string    `db:"status"`
+	Attempts       int       `db:"attempts"`
+	NextAttemptAt  time.Time `db:"next_attempt_at"`
+	LastError      string    `db:"last_error"`
+	CreatedAt      time.Time `db:"created_at"`
+	URL            string    `db:"url"`
+	Secret         string    `db:"secret"`
+}
+
+// ToDomain converts a database webhook delivery model to a domain model
+func (d *WebhookDeliveryDB) ToDomain() *domain.WebhookDelivery {
+	return &domain.WebhookDelivery{
+		ID:             d.ID,
+		SubscriptionID: d.SubscriptionID,
+		EventID:        d.EventID,
+		EventType:      d.EventType,
+		Payload:        json.RawMessage(d.Payload),
+		Status:         domain.DeliveryStatus(d.Status),
+		Attempts:       d.Attempts,
+		NextAttemptAt:  d.NextAttemptAt,
+		LastError:      d.LastError,
+		CreatedAt:      d.CreatedAt,
+		URL:            d.URL,
+		Secret:         d.Secret,
+	}
+}
+
+// WebhookDeliveriesToDomain converts a list of database webhook deliveries to domain models
+func WebhookDeliveriesToDomain(deliveries []*WebhookDeliveryDB) []*domain.WebhookDelivery {
+	result := make([]*domain.WebhookDelivery, len(deliveries))
+	for i, delivery := range deliveries {
+		result[i] = delivery.ToDomain()
+	}
+	return result
+}
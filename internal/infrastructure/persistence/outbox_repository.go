@@ -0,0 +1,66 @@
+package persistence
+
+import (
+	"context"
+
+	"github.com/ArtemSind/food_tinder/internal/domain"
+	"github.com/ArtemSind/food_tinder/internal/infrastructure/persistence/models"
+	"github.com/jackc/pgx/v4"
+	"github.com/jackc/pgx/v4/pgxpool"
+)
+
+const insertOutboxEventQuery = "INSERT INTO outbox_events (id, event_type, payload, created_at) VALUES ($1, $2, $3, $4)"
+
+// OutboxRepository stores events that still have to be fanned out to webhooks
+type OutboxRepository struct {
+	db *pgxpool.Pool
+}
+
+func NewOutboxRepository(db *pgxpool.Pool) *OutboxRepository {
+	return &OutboxRepository{db: db}
+}
+
+// Enqueue records an event that is not tied to a Postgres write
+func (r *OutboxRepository) Enqueue(ctx context.Context, event *domain.OutboxEvent) error {
+	dbEvent := models.OutboxEventFromDomain(event)
+	_, err := r.db.Exec(ctx, insertOutboxEventQuery,
+		dbEvent.ID, dbEvent.EventType, dbEvent.Payload, dbEvent.CreatedAt)
+	return err
+}
+
+// FetchPending returns the oldest events that have not been fanned out yet
+func (r *OutboxRepository) FetchPending(ctx context.Context, limit int) ([]*domain.OutboxEvent, error) {
+	rows, err := r.db.Query(ctx,
+		`SELECT id, event_type, payload, created_at
+		FROM outbox_events
+		WHERE processed_at IS NULL
+		ORDER BY created_at
+		LIMIT $1`, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var events []*domain.OutboxEvent
+	for rows.Next() {
+		var dbEvent models.OutboxEventDB
+		if err := rows.Scan(&dbEvent.ID, &dbEvent.EventType, &dbEvent.Payload, &dbEvent.CreatedAt); err != nil {
+			return nil, err
+		}
+		events = append(events, dbEvent.ToDomain())
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return events, nil
+}
+
+// insertOutboxEvent records an event inside the transaction of the write that caused it
+func insertOutboxEvent(ctx context.Context, tx pgx.Tx, event *domain.OutboxEvent) error {
+	dbEvent := models.OutboxEventFromDomain(event)
+	_, err := tx.Exec(ctx, insertOutboxEventQuery,
+		dbEvent.ID, dbEvent.EventType, dbEvent.Payload, dbEvent.CreatedAt)
+	return err
+}
@@ -0,0 +1,195 @@
+package persistence
+
+import (
+	"context"
+
+	"github.com/ArtemSind/food_tinder/internal/domain"
+	"github.com/ArtemSind/food_tinder/internal/infrastructure/persistence/models"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v4"
+	"github.com/jackc/pgx/v4/pgxpool"
+)
+
+// productAliasLockID serializes alias changes, so that concurrent aliases cannot form a cycle
+const productAliasLockID = 4402
+
+// ProductAliasRepository stores which products Foodji re-issued under a new ID
+type ProductAliasRepository struct {
+	db *pgxpool.Pool
+}
+
+func NewProductAliasRepository(db *pgxpool.Pool) *ProductAliasRepository {
+	return &ProductAliasRepository{db: db}
+}
+
+// CreateAlias records the alias and moves its votes and impressions to the product in one
+// transaction. If the product is itself an alias, the alias points to the product it resolves
+// to, and aliases of the alias are repointed, so aliases never chain. A session that rated both
+// products keeps the vote they changed last, on the product. Every vote the merge changes, moves
+// or deletes records an outbox event. The alias is updated with the moved counts.
+func (r *ProductAliasRepository) CreateAlias(ctx context.Context, alias *domain.ProductAlias) error {
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback(ctx)
+
+	if _, err := tx.Exec(ctx, "SELECT pg_advisory_xact_lock($1)", productAliasLockID); err != nil {
+		return err
+	}
+
+	var existing uuid.UUID
+	err = tx.QueryRow(ctx, "SELECT product_id FROM product_aliases WHERE alias_id = $1", alias.AliasID).Scan(&existing)
+	if err == nil {
+		return domain.ErrAlreadyAliased
+	}
+	if err != pgx.ErrNoRows {
+		return err
+	}
+
+	err = tx.QueryRow(ctx, "SELECT product_id FROM product_aliases WHERE alias_id = $1", alias.ProductID).Scan(&alias.ProductID)
+	if err != nil && err != pgx.ErrNoRows {
+		return err
+	}
+	if alias.ProductID == alias.AliasID {
+		return domain.ErrAliasCycle
+	}
+
+	// Sessions that rated both keep the later of their votes on the product
+	if _, err := recordVoteEvents(ctx, tx, domain.EventVoteUpdated,
+		`UPDATE votes p SET score = a.score, updated_at = a.updated_at
+		FROM votes a
+		WHERE a.product_id = $1 AND p.product_id = $2
+			AND a.session_id = p.session_id AND a.updated_at > p.updated_at
+		RETURNING `+aliasVoteColumns("p"),
+		alias.AliasID, alias.ProductID); err != nil {
+		return err
+	}
+
+	merged, err := recordVoteEvents(ctx, tx, domain.EventVoteDeleted,
+		`DELETE FROM votes a
+		USING votes p
+		WHERE a.product_id = $1 AND p.product_id = $2 AND a.session_id = p.session_id
+		RETURNING `+aliasVoteColumns("a"),
+		alias.AliasID, alias.ProductID)
+	if err != nil {
+		return err
+	}
+	alias.MergedVotes = merged
+
+	// The product is the same item, so the moved votes count for its current version
+	moved, err := recordVoteEvents(ctx, tx, domain.EventVoteUpdated,
+		`UPDATE votes v SET product_id = $2, product_version = (SELECT content_hash FROM products WHERE id = $2)
+		WHERE v.product_id = $1
+		RETURNING `+aliasVoteColumns("v"),
+		alias.AliasID, alias.ProductID)
+	if err != nil {
+		return err
+	}
+	alias.MovedVotes = moved
+
+	tag, err := tx.Exec(ctx, "UPDATE impressions SET product_id = $2 WHERE product_id = $1", alias.AliasID, alias.ProductID)
+	if err != nil {
+		return err
+	}
+	alias.MovedImpressions = int(tag.RowsAffected())
+
+	if _, err := tx.Exec(ctx,
+		"UPDATE product_aliases SET product_id = $2 WHERE product_id = $1", alias.AliasID, alias.ProductID); err != nil {
+		return err
+	}
+
+	dbAlias := models.ProductAliasFromDomain(alias)
+	if _, err := tx.Exec(ctx,
+		`INSERT INTO product_aliases (alias_id, product_id, moved_votes, merged_votes, moved_impressions, created_at)
+		VALUES ($1, $2, $3, $4, $5, $6)`,
+		dbAlias.AliasID, dbAlias.ProductID, dbAlias.MovedVotes, dbAlias.MergedVotes,
+		dbAlias.MovedImpressions, dbAlias.CreatedAt); err != nil {
+		return err
+	}
+
+	return tx.Commit(ctx)
+}
+
+// aliasVoteColumns returns the vote columns of the given table alias, as written by the statement
+func aliasVoteColumns(table string) string {
+	return table + ".id, " + table + ".session_id, " + table + ".product_id, " + table + ".score, " +
+		table + ".created_at, " + table + ".updated_at, " + table + ".product_version"
+}
+
+// recordVoteEvents runs a statement returning the votes it changed and records an outbox event
+// of the given type for each of them, so webhooks see votes an alias merge moved or removed.
+// It returns the number of votes.
+func recordVoteEvents(ctx context.Context, tx pgx.Tx, eventType, query string, args ...interface{}) (int, error) {
+	rows, err := tx.Query(ctx, query, args...)
+	if err != nil {
+		return 0, err
+	}
+	defer rows.Close()
+
+	var dbVotes []*models.VoteDB
+	for rows.Next() {
+		var dbVote models.VoteDB
+		if err := rows.Scan(&dbVote.ID, &dbVote.SessionID, &dbVote.ProductID, &dbVote.Score, &dbVote.CreatedAt,
+			&dbVote.UpdatedAt, &dbVote.ProductVersion); err != nil {
+			return 0, err
+		}
+		dbVotes = append(dbVotes, &dbVote)
+	}
+	if err := rows.Err(); err != nil {
+		return 0, err
+	}
+	rows.Close()
+
+	for _, vote := range models.VotesToDomain(dbVotes) {
+		event, err := domain.NewOutboxEvent(eventType, vote)
+		if err != nil {
+			return 0, err
+		}
+		if err := insertOutboxEvent(ctx, tx, event); err != nil {
+			return 0, err
+		}
+	}
+	return len(dbVotes), nil
+}
+
+// ListAliases returns all aliases, newest first
+func (r *ProductAliasRepository) ListAliases(ctx context.Context) ([]*domain.ProductAlias, error) {
+	rows, err := r.db.Query(ctx,
+		`SELECT alias_id, product_id, moved_votes, merged_votes, moved_impressions, created_at
+		FROM product_aliases
+		ORDER BY created_at DESC`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	aliases := []*domain.ProductAlias{}
+	for rows.Next() {
+		var dbAlias models.ProductAliasDB
+		if err := rows.Scan(&dbAlias.AliasID, &dbAlias.ProductID, &dbAlias.MovedVotes, &dbAlias.MergedVotes,
+			&dbAlias.MovedImpressions, &dbAlias.CreatedAt); err != nil {
+			return nil, err
+		}
+		aliases = append(aliases, dbAlias.ToDomain())
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return aliases, nil
+}
+
+// ResolveAlias returns the product an alias stands for, or the ID itself if it is no alias
+func (r *ProductAliasRepository) ResolveAlias(ctx context.Context, id uuid.UUID) (uuid.UUID, error) {
+	var productID uuid.UUID
+	err := r.db.QueryRow(ctx, "SELECT product_id FROM product_aliases WHERE alias_id = $1", id).Scan(&productID)
+	if err == pgx.ErrNoRows {
+		return id, nil
+	}
+	if err != nil {
+		return uuid.Nil, err
+	}
+	return productID, nil
+}
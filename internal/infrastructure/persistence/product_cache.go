@@ -0,0 +1,246 @@
+package persistence
+
+import (
+	"container/list"
+	"context"
+	"encoding/json"
+	"log"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/ArtemSind/food_tinder/internal/domain"
+	"github.com/ArtemSind/food_tinder/internal/infrastructure/external/foodji"
+	"github.com/go-redis/redis/v8"
+	"github.com/google/uuid"
+)
+
+const (
+	// ProductInvalidationChannel carries every snapshot switch, so each replica drops the
+	// products it cached from the earlier snapshot, and every override and alias change, so each
+	// replica drops the curated products it cached
+	ProductInvalidationChannel = "products:invalidate"
+	// DefaultProductCacheSize is how many products each replica keeps in memory
+	DefaultProductCacheSize = 1024
+	// DefaultProductCacheTTL bounds how long a replica serves a cached product; it limits
+	// staleness when an invalidation is missed while the subscription reconnects
+	DefaultProductCacheTTL = 5 * time.Minute
+)
+
+// productInvalidation announces a snapshot switch, or a change of curated products only, on
+// ProductInvalidationChannel
+type productInvalidation struct {
+	Version int64 `json:"version"`
+	// Replica is the replica that switched; it has already dropped its cached products
+	Replica string `json:"replica"`
+	// Curated is set if only overrides or aliases changed, so catalog products stay cached
+	Curated bool `json:"curated,omitempty"`
+}
+
+type productCacheEntry struct {
+	id        uuid.UUID
+	product   foodji.Product
+	expiresAt time.Time
+}
+
+// productCache is an in-process LRU of current snapshot products in front of Redis. It is
+// purged whenever a snapshot is switched to; the generation guards against a read that
+// started before the switch storing a product of the earlier snapshot after the purge.
+type productCache struct {
+	capacity int
+	ttl      time.Duration
+	now      func() time.Time
+
+	mu         sync.Mutex
+	entries    map[uuid.UUID]*list.Element
+	order      *list.List
+	generation uint64
+
+	hits   atomic.Int64
+	misses atomic.Int64
+}
+
+func newProductCache(capacity int, ttl time.Duration) *productCache {
+	return &productCache{
+		capacity: capacity,
+		ttl:      ttl,
+		now:      time.Now,
+		entries:  make(map[uuid.UUID]*list.Element, capacity),
+		order:    list.New(),
+	}
+}
+
+// get returns a copy of a cached product and counts the lookup as a hit or a miss
+func (c *productCache) get(id uuid.UUID) (*foodji.Product, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	element, ok := c.entries[id]
+	if !ok {
+		c.misses.Add(1)
+		return nil, false
+	}
+	entry := element.Value.(*productCacheEntry)
+	if c.now().After(entry.expiresAt) {
+		c.order.Remove(element)
+		delete(c.entries, id)
+		c.misses.Add(1)
+		return nil, false
+	}
+
+	c.order.MoveToFront(element)
+	c.hits.Add(1)
+	product := entry.product
+	return &product, true
+}
+
+// currentGeneration returns the generation to pass to put for a product about to be read
+func (c *productCache) currentGeneration() uint64 {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.generation
+}
+
+// put caches the product read for an ID during the given generation, evicting the least
+// recently used product if the cache is full. Products read before the last purge are not cached.
+func (c *productCache) put(generation uint64, id uuid.UUID, product foodji.Product) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if generation != c.generation {
+		return
+	}
+
+	entry := &productCacheEntry{id: id, product: product, expiresAt: c.now().Add(c.ttl)}
+	if element, ok := c.entries[id]; ok {
+		element.Value = entry
+		c.order.MoveToFront(element)
+		return
+	}
+
+	c.entries[id] = c.order.PushFront(entry)
+	if c.order.Len() > c.capacity {
+		oldest := c.order.Back()
+		c.order.Remove(oldest)
+		delete(c.entries, oldest.Value.(*productCacheEntry).id)
+	}
+}
+
+// purge drops every cached product
+func (c *productCache) purge() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.generation++
+	c.entries = make(map[uuid.UUID]*list.Element, c.capacity)
+	c.order.Init()
+}
+
+func (c *productCache) stats() *domain.ProductCacheStats {
+	c.mu.Lock()
+	entries := c.order.Len()
+	c.mu.Unlock()
+
+	return &domain.ProductCacheStats{
+		Enabled:  true,
+		Capacity: c.capacity,
+		Entries:  entries,
+		Hits:     c.hits.Load(),
+		Misses:   c.misses.Load(),
+	}
+}
+
+// invalidateProducts drops this replica's cached products and tells the other replicas to drop
+// theirs, even if this replica does not cache; failures to publish are logged because the
+// snapshot has already been switched
+func (r *ProductRepository) invalidateProducts(ctx context.Context, version int64) {
+	if r.products != nil {
+		r.products.purge()
+		r.curated.purge()
+	}
+	r.publishInvalidation(ctx, productInvalidation{Version: version, Replica: r.replicaID})
+}
+
+// InvalidateCuratedProducts drops the curated products every replica cached, after an override
+// or alias was written; the catalog products stay cached
+func (r *ProductRepository) InvalidateCuratedProducts(ctx context.Context) {
+	if r.curated != nil {
+		r.curated.purge()
+	}
+
+	// Create a timeout context for the Redis operation
+	redisCtx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	r.publishInvalidation(redisCtx, productInvalidation{Replica: r.replicaID, Curated: true})
+}
+
+// publishInvalidation tells the other replicas to drop their cached products; failures are
+// logged because the change has already been written
+func (r *ProductRepository) publishInvalidation(ctx context.Context, invalidation productInvalidation) {
+	message, err := json.Marshal(invalidation)
+	if err != nil {
+		log.Printf("Failed to marshal product cache invalidation: %v", err)
+		return
+	}
+	if err := r.redisClient.Publish(ctx, ProductInvalidationChannel, message).Err(); err != nil {
+		log.Printf("Failed to publish product cache invalidation: %v", err)
+	}
+}
+
+// watchInvalidations purges the cached products whenever any replica switches the snapshot, and
+// the curated products whenever any replica changes an override or alias
+func (r *ProductRepository) watchInvalidations(ctx context.Context, pubsub *redis.PubSub) {
+	defer r.wg.Done()
+	defer pubsub.Close()
+
+	messages := pubsub.Channel()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case message, ok := <-messages:
+			if !ok {
+				return
+			}
+			// Messages that cannot be read still purge, as a stale product is worse than a miss
+			var invalidation productInvalidation
+			err := json.Unmarshal([]byte(message.Payload), &invalidation)
+			if err == nil && invalidation.Replica == r.replicaID {
+				continue
+			}
+			r.curated.purge()
+			if err != nil || !invalidation.Curated {
+				r.products.purge()
+			}
+		}
+	}
+}
+
+// GetProductCacheStats returns the in-process product cache counters of this replica
+func (r *ProductRepository) GetProductCacheStats() *domain.ProductCacheStats {
+	if r.products == nil {
+		return &domain.ProductCacheStats{}
+	}
+	return r.products.stats()
+}
+
+// GetCuratedProduct returns the curated product of an ID, served from memory if it was curated
+// since the last snapshot switch or override or alias change. Curated products are cached by the
+// ID they were asked for, so an alias is only resolved once.
+func (r *ProductRepository) GetCuratedProduct(ctx context.Context, id uuid.UUID, curate func(ctx context.Context, id uuid.UUID) (*foodji.Product, error)) (*foodji.Product, error) {
+	if r.curated == nil {
+		return curate(ctx, id)
+	}
+	if product, ok := r.curated.get(id); ok {
+		return product, nil
+	}
+
+	generation := r.curated.currentGeneration()
+	product, err := curate(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	r.curated.put(generation, id, *product)
+	return product, nil
+}
@@ -0,0 +1,227 @@
+package persistence_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/ArtemSind/food_tinder/internal/domain"
+	"github.com/ArtemSind/food_tinder/internal/infrastructure/external/foodji"
+	"github.com/ArtemSind/food_tinder/internal/infrastructure/external/foodji/fake"
+	"github.com/ArtemSind/food_tinder/internal/infrastructure/persistence"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var cachedSaladID = uuid.MustParse("3f1c9a4e-5b7d-4c2a-9e8f-1a2b3c4d5e01")
+
+// renamedSalad returns the default fixture with the salad under another name
+func renamedSalad(name string) []foodji.Product {
+	products := append([]foodji.Product(nil), fake.DefaultFixture().Machines[persistence.DefaultMachineID]...)
+	for i := range products {
+		if products[i].ID == cachedSaladID {
+			products[i].Name = name
+		}
+	}
+	return products
+}
+
+// waitForIdle waits until the repository finished its last refresh
+func waitForIdle(t *testing.T, repo *persistence.ProductRepository) {
+	t.Helper()
+	require.Eventually(t, func() bool {
+		status, err := repo.GetRefreshStatus(context.Background())
+		return err == nil && !status.Running && status.LastRun != nil
+	}, time.Second, 5*time.Millisecond)
+}
+
+func TestProductRepository_ProductCache(t *testing.T) {
+	t.Run("repeated lookups are served from memory", func(t *testing.T) {
+		// Arrange
+		f := newProductRepositoryFixture(t)
+		ctx := context.Background()
+		repo := f.newRepository(t)
+		waitForIdle(t, repo)
+		_, err := repo.GetProduct(ctx, cachedSaladID)
+		require.NoError(t, err)
+		before := repo.GetProductCacheStats()
+
+		// Redis no longer answers lookups
+		f.redis.SetError("server unavailable")
+		t.Cleanup(func() { f.redis.SetError("") })
+
+		// Act
+		product, err := repo.GetProduct(ctx, cachedSaladID)
+
+		// Assert
+		require.NoError(t, err)
+		assert.Equal(t, "Chicken Caesar Salad", product.Name)
+
+		stats := repo.GetProductCacheStats()
+		assert.True(t, stats.Enabled)
+		assert.Equal(t, persistence.DefaultProductCacheSize, stats.Capacity)
+		assert.Equal(t, 1, stats.Entries)
+		assert.Equal(t, before.Hits+1, stats.Hits)
+		assert.Equal(t, before.Misses, stats.Misses)
+	})
+
+	t.Run("a refresh drops cached products", func(t *testing.T) {
+		// Arrange
+		f := newProductRepositoryFixture(t)
+		ctx := context.Background()
+		repo := f.newRepository(t)
+		waitForIdle(t, repo)
+		_, err := repo.GetProduct(ctx, cachedSaladID)
+		require.NoError(t, err)
+
+		f.foodji.SetProducts(persistence.DefaultMachineID, renamedSalad("Caesar Salad"))
+
+		// Act
+		require.Eventually(t, repo.TriggerRefresh, time.Second, 5*time.Millisecond, "a refresh starts once the last one is done")
+
+		// Assert
+		require.Eventually(t, func() bool {
+			product, err := repo.GetProduct(ctx, cachedSaladID)
+			return err == nil && product.Name == "Caesar Salad"
+		}, time.Second, 5*time.Millisecond)
+	})
+
+	t.Run("a refresh on another replica drops cached products", func(t *testing.T) {
+		// Arrange
+		f := newProductRepositoryFixture(t)
+		ctx := context.Background()
+		refresher := f.newRepository(t)
+		waitForIdle(t, refresher)
+		replica := f.newRepository(t)
+		waitForIdle(t, replica)
+		_, err := replica.GetProduct(ctx, cachedSaladID)
+		require.NoError(t, err)
+
+		f.foodji.SetProducts(persistence.DefaultMachineID, renamedSalad("Caesar Salad"))
+
+		// Act
+		require.Eventually(t, refresher.TriggerRefresh, time.Second, 5*time.Millisecond, "a refresh starts once the last one is done")
+
+		// Assert
+		require.Eventually(t, func() bool {
+			product, err := replica.GetProduct(ctx, cachedSaladID)
+			return err == nil && product.Name == "Caesar Salad"
+		}, time.Second, 5*time.Millisecond)
+	})
+
+	t.Run("a rollback drops cached products", func(t *testing.T) {
+		// Arrange
+		f := newProductRepositoryFixture(t)
+		ctx := context.Background()
+		repo := f.newRepository(t)
+		waitForIdle(t, repo)
+
+		f.foodji.SetProducts(persistence.DefaultMachineID, renamedSalad("Caesar Salad"))
+		require.Eventually(t, repo.TriggerRefresh, time.Second, 5*time.Millisecond, "a refresh starts once the last one is done")
+		require.Eventually(t, func() bool {
+			product, err := repo.GetProduct(ctx, cachedSaladID)
+			return err == nil && product.Name == "Caesar Salad"
+		}, time.Second, 5*time.Millisecond)
+
+		// Act
+		require.Eventually(t, func() bool {
+			_, err := repo.RollbackToSnapshot(ctx, 1)
+			return err == nil
+		}, time.Second, 5*time.Millisecond, "the rollback takes the lease once the refresh is done")
+
+		// Assert
+		product, err := repo.GetProduct(ctx, cachedSaladID)
+		require.NoError(t, err)
+		assert.Equal(t, "Chicken Caesar Salad", product.Name)
+	})
+
+	t.Run("a size of 0 disables the cache", func(t *testing.T) {
+		// Arrange
+		t.Setenv("PRODUCT_CACHE_SIZE", "0")
+		f := newProductRepositoryFixture(t)
+		ctx := context.Background()
+		repo := f.newRepository(t)
+		waitForIdle(t, repo)
+		_, err := repo.GetProduct(ctx, cachedSaladID)
+		require.NoError(t, err)
+
+		// Act
+		_, err = repo.GetProduct(ctx, cachedSaladID)
+
+		// Assert
+		require.NoError(t, err)
+		assert.Equal(t, &domain.ProductCacheStats{}, repo.GetProductCacheStats())
+	})
+}
+
+func TestProductRepository_CuratedProductCache(t *testing.T) {
+	// curate counts how often a product is curated, returning the catalog product
+	curate := func(repo *persistence.ProductRepository, calls *int) func(ctx context.Context, id uuid.UUID) (*foodji.Product, error) {
+		return func(ctx context.Context, id uuid.UUID) (*foodji.Product, error) {
+			*calls++
+			return repo.GetProduct(ctx, id)
+		}
+	}
+
+	t.Run("curated products are served from memory", func(t *testing.T) {
+		// Arrange
+		f := newProductRepositoryFixture(t)
+		ctx := context.Background()
+		repo := f.newRepository(t)
+		waitForIdle(t, repo)
+		calls := 0
+		_, err := repo.GetCuratedProduct(ctx, cachedSaladID, curate(repo, &calls))
+		require.NoError(t, err)
+
+		// Act
+		product, err := repo.GetCuratedProduct(ctx, cachedSaladID, curate(repo, &calls))
+
+		// Assert
+		require.NoError(t, err)
+		assert.Equal(t, "Chicken Caesar Salad", product.Name)
+		assert.Equal(t, 1, calls)
+	})
+
+	t.Run("an override change on another replica drops curated products only", func(t *testing.T) {
+		// Arrange
+		f := newProductRepositoryFixture(t)
+		ctx := context.Background()
+		admin := f.newRepository(t)
+		waitForIdle(t, admin)
+		replica := f.newRepository(t)
+		waitForIdle(t, replica)
+		calls := 0
+		_, err := replica.GetCuratedProduct(ctx, cachedSaladID, curate(replica, &calls))
+		require.NoError(t, err)
+
+		// Act
+		admin.InvalidateCuratedProducts(ctx)
+
+		// Assert
+		require.Eventually(t, func() bool {
+			_, err := replica.GetCuratedProduct(ctx, cachedSaladID, curate(replica, &calls))
+			return err == nil && calls == 2
+		}, time.Second, 5*time.Millisecond)
+		assert.Equal(t, 1, replica.GetProductCacheStats().Entries, "catalog products stay cached")
+	})
+
+	t.Run("a size of 0 curates every lookup", func(t *testing.T) {
+		// Arrange
+		t.Setenv("PRODUCT_CACHE_SIZE", "0")
+		f := newProductRepositoryFixture(t)
+		ctx := context.Background()
+		repo := f.newRepository(t)
+		waitForIdle(t, repo)
+		calls := 0
+
+		// Act
+		_, err := repo.GetCuratedProduct(ctx, cachedSaladID, curate(repo, &calls))
+		require.NoError(t, err)
+		_, err = repo.GetCuratedProduct(ctx, cachedSaladID, curate(repo, &calls))
+
+		// Assert
+		require.NoError(t, err)
+		assert.Equal(t, 2, calls)
+	})
+}
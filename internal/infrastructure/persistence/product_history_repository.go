@@ -0,0 +1,237 @@
+package persistence
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/ArtemSind/food_tinder/internal/domain"
+	"github.com/ArtemSind/food_tinder/internal/infrastructure/persistence/models"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v4"
+	"github.com/jackc/pgx/v4/pgxpool"
+)
+
+// ProductHistoryRepository keeps every product the catalog ever contained in Postgres,
+// together with the events of it being added, changed and removed
+type ProductHistoryRepository struct {
+	db *pgxpool.Pool
+}
+
+func NewProductHistoryRepository(db *pgxpool.Pool) *ProductHistoryRepository {
+	return &ProductHistoryRepository{db: db}
+}
+
+// RecordCatalog stores the products of a new catalog, marks products that left it as
+// discontinued and records the changes in one transaction. It returns the recorded events.
+func (r *ProductHistoryRepository) RecordCatalog(ctx context.Context, products []*domain.CatalogProduct, seenAt time.Time) ([]*domain.ProductEvent, error) {
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback(ctx)
+
+	// Lock the catalog so concurrent recordings diff against each other's results
+	rows, err := tx.Query(ctx,
+		`SELECT id, content_hash FROM products WHERE discontinued_at IS NULL FOR UPDATE`)
+	if err != nil {
+		return nil, err
+	}
+	previous := make(map[uuid.UUID]string)
+	for rows.Next() {
+		var id uuid.UUID
+		var hash string
+		if err := rows.Scan(&id, &hash); err != nil {
+			rows.Close()
+			return nil, err
+		}
+		previous[id] = hash
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	events := domain.DiffCatalog(previous, products, seenAt)
+
+	for _, product := range products {
+		if _, err := tx.Exec(ctx,
+			`INSERT INTO products (id, content_hash, data, first_seen_at, last_seen_at)
+			VALUES ($1, $2, $3, $4, $4)
+			ON CONFLICT (id) DO UPDATE SET
+				content_hash = EXCLUDED.content_hash,
+				data = EXCLUDED.data,
+				last_seen_at = EXCLUDED.last_seen_at,
+				discontinued_at = NULL`,
+			product.ID, product.ContentHash, []byte(product.Data), seenAt); err != nil {
+			return nil, err
+		}
+
+		// Every refresh records the price it saw, so price changes show without a new version
+		if product.Price != nil {
+			if _, err := tx.Exec(ctx,
+				`INSERT INTO product_prices (product_id, content_hash, price, recorded_at)
+				VALUES ($1, $2, $3, $4)
+				ON CONFLICT (product_id, recorded_at) DO UPDATE SET
+					content_hash = EXCLUDED.content_hash,
+					price = EXCLUDED.price`,
+				product.ID, product.ContentHash, *product.Price, seenAt); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	for _, event := range events {
+		if event.Type == domain.ProductRemoved {
+			if _, err := tx.Exec(ctx,
+				"UPDATE products SET discontinued_at = $2 WHERE id = $1",
+				event.ProductID, seenAt); err != nil {
+				return nil, err
+			}
+		}
+
+		dbEvent := models.ProductEventFromDomain(event)
+		if _, err := tx.Exec(ctx,
+			"INSERT INTO product_events (id, product_id, event_type, content_hash, created_at) VALUES ($1, $2, $3, $4, $5)",
+			dbEvent.ID, dbEvent.ProductID, dbEvent.EventType, dbEvent.ContentHash, dbEvent.CreatedAt); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return nil, err
+	}
+	return events, nil
+}
+
+// MarkSeen records that a refresh found the catalog unchanged, and the unchanged prices it saw
+func (r *ProductHistoryRepository) MarkSeen(ctx context.Context, seenAt time.Time) error {
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback(ctx)
+
+	if _, err := tx.Exec(ctx,
+		"UPDATE products SET last_seen_at = $1 WHERE discontinued_at IS NULL", seenAt); err != nil {
+		return err
+	}
+
+	if _, err := tx.Exec(ctx,
+		`INSERT INTO product_prices (product_id, content_hash, price, recorded_at)
+		SELECT DISTINCT ON (pp.product_id) pp.product_id, p.content_hash, pp.price, $1::timestamp
+		FROM product_prices pp
+		JOIN products p ON p.id = pp.product_id AND p.discontinued_at IS NULL
+		ORDER BY pp.product_id, pp.recorded_at DESC
+		ON CONFLICT (product_id, recorded_at) DO NOTHING`, seenAt); err != nil {
+		return err
+	}
+
+	return tx.Commit(ctx)
+}
+
+// GetProductHistory returns a product as last seen in the catalog and its events, oldest first
+func (r *ProductHistoryRepository) GetProductHistory(ctx context.Context, productID uuid.UUID) (*domain.CatalogProduct, []*domain.ProductEvent, error) {
+	var dbProduct models.ProductDB
+	err := r.db.QueryRow(ctx,
+		`SELECT id, content_hash, data, first_seen_at, last_seen_at, discontinued_at
+		FROM products WHERE id = $1`, productID).
+		Scan(&dbProduct.ID, &dbProduct.ContentHash, &dbProduct.Data,
+			&dbProduct.FirstSeenAt, &dbProduct.LastSeenAt, &dbProduct.DiscontinuedAt)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, nil, domain.ErrProductNotFound
+	}
+	if err != nil {
+		return nil, nil, err
+	}
+
+	rows, err := r.db.Query(ctx,
+		`SELECT id, product_id, event_type, content_hash, created_at
+		FROM product_events
+		WHERE product_id = $1
+		ORDER BY created_at`, productID)
+	if err != nil {
+		return nil, nil, err
+	}
+	defer rows.Close()
+
+	var events []*domain.ProductEvent
+	for rows.Next() {
+		var dbEvent models.ProductEventDB
+		if err := rows.Scan(&dbEvent.ID, &dbEvent.ProductID, &dbEvent.EventType, &dbEvent.ContentHash, &dbEvent.CreatedAt); err != nil {
+			return nil, nil, err
+		}
+		events = append(events, dbEvent.ToDomain())
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, nil, err
+	}
+
+	return dbProduct.ToDomain(), events, nil
+}
+
+// GetPriceHistory returns the prices of a product, oldest first. Refreshes that saw the same
+// price as the refresh before are one price point, which counts the votes cast or changed while
+// the price was in effect.
+func (r *ProductHistoryRepository) GetPriceHistory(ctx context.Context, productID uuid.UUID) ([]*domain.PricePoint, error) {
+	rows, err := r.db.Query(ctx,
+		`WITH changes AS (
+			SELECT product_id, price, content_hash, recorded_at,
+				price IS DISTINCT FROM LAG(price) OVER (ORDER BY recorded_at) AS changed
+			FROM product_prices
+			WHERE product_id = $1
+		), periods AS (
+			SELECT product_id, price, content_hash, recorded_at,
+				LEAD(recorded_at) OVER (ORDER BY recorded_at) AS ended_at
+			FROM changes
+			WHERE changed
+		)
+		SELECT
+			pp.product_id,
+			pp.price::float8,
+			pp.content_hash,
+			pp.recorded_at,
+			(SELECT MAX(c.recorded_at) FROM changes c
+				WHERE c.recorded_at >= pp.recorded_at
+					AND (pp.ended_at IS NULL OR c.recorded_at < pp.ended_at)) AS last_recorded_at,
+			COALESCE(AVG(v.score), 0)::float8 AS avg_score,
+			COUNT(v.id) AS vote_count
+		FROM periods pp
+		LEFT JOIN votes v ON v.product_id = pp.product_id
+			AND v.updated_at >= pp.recorded_at
+			AND (pp.ended_at IS NULL OR v.updated_at < pp.ended_at)
+		GROUP BY pp.product_id, pp.price, pp.content_hash, pp.recorded_at, pp.ended_at
+		ORDER BY pp.recorded_at`, productID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var prices []*domain.PricePoint
+	for rows.Next() {
+		var dbPrice models.PricePointDB
+		if err := rows.Scan(&dbPrice.ProductID, &dbPrice.Price, &dbPrice.ContentHash, &dbPrice.RecordedAt,
+			&dbPrice.LastRecordedAt, &dbPrice.AvgScore, &dbPrice.VoteCount); err != nil {
+			return nil, err
+		}
+		prices = append(prices, dbPrice.ToDomain())
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	if len(prices) == 0 {
+		var exists bool
+		if err := r.db.QueryRow(ctx,
+			"SELECT EXISTS (SELECT 1 FROM products WHERE id = $1)", productID).Scan(&exists); err != nil {
+			return nil, err
+		}
+		if !exists {
+			return nil, domain.ErrProductNotFound
+		}
+	}
+
+	return prices, nil
+}
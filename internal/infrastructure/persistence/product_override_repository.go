@@ -0,0 +1,89 @@
+package persistence
+
+import (
+	"context"
+
+	"github.com/ArtemSind/food_tinder/internal/domain"
+	"github.com/ArtemSind/food_tinder/internal/infrastructure/persistence/models"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v4"
+	"github.com/jackc/pgx/v4/pgxpool"
+)
+
+const productOverrideColumns = "product_id, hidden, pinned, name, category, image_url, created_at, updated_at"
+
+// ProductOverrideRepository stores the admin overrides of catalog products
+type ProductOverrideRepository struct {
+	db *pgxpool.Pool
+}
+
+func NewProductOverrideRepository(db *pgxpool.Pool) *ProductOverrideRepository {
+	return &ProductOverrideRepository{db: db}
+}
+
+// SaveOverride creates or replaces the override of a product. A replaced override keeps its
+// creation time, which is returned in the override.
+func (r *ProductOverrideRepository) SaveOverride(ctx context.Context, override *domain.ProductOverride) error {
+	dbOverride := models.ProductOverrideFromDomain(override)
+	return r.db.QueryRow(ctx,
+		`INSERT INTO product_overrides (`+productOverrideColumns+`)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+		ON CONFLICT (product_id) DO UPDATE
+		SET hidden = EXCLUDED.hidden, pinned = EXCLUDED.pinned, name = EXCLUDED.name,
+			category = EXCLUDED.category, image_url = EXCLUDED.image_url, updated_at = EXCLUDED.updated_at
+		RETURNING created_at`,
+		dbOverride.ProductID, dbOverride.Hidden, dbOverride.Pinned, dbOverride.Name, dbOverride.Category,
+		dbOverride.ImageURL, dbOverride.CreatedAt, dbOverride.UpdatedAt).Scan(&override.CreatedAt)
+}
+
+func (r *ProductOverrideRepository) GetOverride(ctx context.Context, productID uuid.UUID) (*domain.ProductOverride, error) {
+	var dbOverride models.ProductOverrideDB
+	err := r.db.QueryRow(ctx,
+		"SELECT "+productOverrideColumns+" FROM product_overrides WHERE product_id = $1", productID).
+		Scan(&dbOverride.ProductID, &dbOverride.Hidden, &dbOverride.Pinned, &dbOverride.Name,
+			&dbOverride.Category, &dbOverride.ImageURL, &dbOverride.CreatedAt, &dbOverride.UpdatedAt)
+	if err != nil {
+		if err == pgx.ErrNoRows {
+			return nil, domain.ErrOverrideNotFound
+		}
+		return nil, err
+	}
+	return dbOverride.ToDomain(), nil
+}
+
+// ListOverrides returns all overrides in the order they were created
+func (r *ProductOverrideRepository) ListOverrides(ctx context.Context) ([]*domain.ProductOverride, error) {
+	rows, err := r.db.Query(ctx,
+		"SELECT "+productOverrideColumns+" FROM product_overrides ORDER BY created_at, product_id")
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	overrides := []*domain.ProductOverride{}
+	for rows.Next() {
+		var dbOverride models.ProductOverrideDB
+		if err := rows.Scan(&dbOverride.ProductID, &dbOverride.Hidden, &dbOverride.Pinned, &dbOverride.Name,
+			&dbOverride.Category, &dbOverride.ImageURL, &dbOverride.CreatedAt, &dbOverride.UpdatedAt); err != nil {
+			return nil, err
+		}
+		overrides = append(overrides, dbOverride.ToDomain())
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return overrides, nil
+}
+
+func (r *ProductOverrideRepository) DeleteOverride(ctx context.Context, productID uuid.UUID) error {
+	tag, err := r.db.Exec(ctx, "DELETE FROM product_overrides WHERE product_id = $1", productID)
+	if err != nil {
+		return err
+	}
+	if tag.RowsAffected() == 0 {
+		return domain.ErrOverrideNotFound
+	}
+	return nil
+}
@@ -0,0 +1,237 @@
+package persistence
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/ArtemSind/food_tinder/internal/domain"
+	"github.com/go-redis/redis/v8"
+)
+
+const (
+	// RefreshRunningKey holds the start time of the refresh in progress; it expires with the refresh timeout
+	RefreshRunningKey = "products:refresh:running"
+	// RefreshLastRunKey stores the outcome of the last finished refresh
+	RefreshLastRunKey = "products:refresh:last"
+)
+
+// MinRefreshInterval is how recently any replica must have refreshed the catalog
+// for a scheduled refresh to be skipped
+const MinRefreshInterval = UpdateInterval / 2
+
+// PendingRefreshRetryInterval is how often a source change that arrived during a refresh on
+// another replica is retried; refreshes on this replica start it as soon as they finish
+var PendingRefreshRetryInterval = 5 * time.Second
+
+// errRefreshNotDue is returned for a scheduled refresh after another replica refreshed the catalog
+var errRefreshNotDue = errors.New("catalog was refreshed recently")
+
+// TriggerRefresh starts a refresh in the background and reports whether it started;
+// it does nothing if a refresh is already running on any replica
+func (r *ProductRepository) TriggerRefresh() bool {
+	return r.triggerRefresh(domain.RefreshManual)
+}
+
+// ForceRefresh is TriggerRefresh for a refresh that fetches the full catalog and switches to it
+// even if it shrinks by more than CATALOG_MAX_SHRINK; it only applies to the refresh it starts
+func (r *ProductRepository) ForceRefresh() bool {
+	return r.triggerRefresh(domain.RefreshForced)
+}
+
+func (r *ProductRepository) triggerRefresh(trigger domain.RefreshTrigger) bool {
+	if !r.refreshing.CompareAndSwap(false, true) {
+		return false
+	}
+
+	lease, err := r.acquireLease(r.ctx)
+	if err != nil {
+		r.refreshing.Store(false)
+		if !errors.Is(err, domain.ErrRefreshInProgress) {
+			log.Printf("Error in %s product update: %v", trigger, err)
+		}
+		return false
+	}
+
+	r.wg.Add(1)
+	go func() {
+		defer r.wg.Done()
+		defer r.finishRefresh()
+
+		ctx, cancel := context.WithTimeout(r.ctx, UpdateContextTimeout)
+		defer cancel()
+
+		if err := r.runLeased(ctx, lease, trigger); err != nil {
+			log.Printf("Error in %s product update: %v", trigger, err)
+		}
+	}()
+	return true
+}
+
+// finishRefresh marks the refresh of this replica as done and wakes watchSource
+func (r *ProductRepository) finishRefresh() {
+	r.refreshing.Store(false)
+	select {
+	case r.refreshDone <- struct{}{}:
+	default:
+	}
+}
+
+// watchSource refreshes the products whenever the source reports a change. A change that
+// arrives while a refresh is running may not be part of it, so it is kept pending and
+// refreshed once more when that refresh is done.
+func (r *ProductRepository) watchSource(ctx context.Context, changes <-chan struct{}) {
+	defer r.wg.Done()
+
+	retry := time.NewTimer(PendingRefreshRetryInterval)
+	retry.Stop()
+	defer retry.Stop()
+
+	pending := false
+	for {
+		select {
+		case _, ok := <-changes:
+			if !ok {
+				return
+			}
+			pending = true
+		case <-r.refreshDone:
+		case <-retry.C:
+		case <-ctx.Done():
+			return
+		}
+
+		if !pending {
+			continue
+		}
+		if r.triggerRefresh(domain.RefreshSourceChanged) {
+			pending = false
+			retry.Stop()
+		} else {
+			log.Println("Product source changed while a refresh is running, refreshing again once it is done")
+			retry.Reset(PendingRefreshRetryInterval)
+		}
+	}
+}
+
+// refresh updates the products unless a refresh is already running on any replica.
+// Scheduled refreshes are skipped if another replica refreshed the catalog recently.
+func (r *ProductRepository) refresh(ctx context.Context, trigger domain.RefreshTrigger) error {
+	if !r.refreshing.CompareAndSwap(false, true) {
+		return domain.ErrRefreshInProgress
+	}
+
+	lease, err := r.acquireLease(ctx)
+	if err != nil {
+		r.refreshing.Store(false)
+		return err
+	}
+	defer r.finishRefresh()
+
+	// Check under the lease, so a replica that waited for another one sees its result
+	if trigger == domain.RefreshScheduled && r.refreshedRecently(ctx) {
+		lease.release()
+		return errRefreshNotDue
+	}
+
+	return r.runLeased(ctx, lease, trigger)
+}
+
+// runLeased refreshes while renewing the lease and releases it afterwards.
+// The refresh is cancelled if the lease is lost.
+func (r *ProductRepository) runLeased(ctx context.Context, lease *refreshLease, trigger domain.RefreshTrigger) error {
+	defer lease.release()
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	go lease.keepAlive(ctx, cancel)
+
+	return r.runRefresh(ctx, trigger, lease.token)
+}
+
+// refreshedRecently reports whether the last refresh on any replica succeeded within MinRefreshInterval
+func (r *ProductRepository) refreshedRecently(ctx context.Context) bool {
+	status, err := r.GetRefreshStatus(ctx)
+	if err != nil {
+		log.Printf("Failed to check the last refresh: %v", err)
+		return false
+	}
+	if status.LastRun == nil || status.LastRun.Outcome == domain.RefreshFailed {
+		return false
+	}
+	return time.Since(status.LastRun.FinishedAt) < MinRefreshInterval
+}
+
+// runRefresh updates the products and records the run for GetRefreshStatus
+func (r *ProductRepository) runRefresh(ctx context.Context, trigger domain.RefreshTrigger, token int64) error {
+	startedAt := time.Now()
+	r.saveRefreshStatus(func(statusCtx context.Context) error {
+		return r.redisClient.Set(statusCtx, RefreshRunningKey, startedAt.Format(time.RFC3339Nano), UpdateContextTimeout).Err()
+	})
+
+	outcome, productCount, err := r.updateProducts(ctx, token, trigger == domain.RefreshForced)
+	run := domain.NewRefreshRun(trigger, startedAt, outcome, productCount, err)
+
+	r.saveRefreshStatus(func(statusCtx context.Context) error {
+		runJSON, err := json.Marshal(run)
+		if err != nil {
+			return err
+		}
+
+		pipe := r.redisClient.TxPipeline()
+		pipe.Set(statusCtx, RefreshLastRunKey, runJSON, 0)
+		pipe.Del(statusCtx, RefreshRunningKey)
+		_, err = pipe.Exec(statusCtx)
+		return err
+	})
+
+	return err
+}
+
+// saveRefreshStatus writes refresh bookkeeping with its own timeout, so the outcome
+// of a refresh that ran out of time is still recorded; failures are logged
+func (r *ProductRepository) saveRefreshStatus(write func(ctx context.Context) error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	if err := write(ctx); err != nil {
+		log.Printf("Failed to store refresh status: %v", err)
+	}
+}
+
+// GetRefreshStatus reports whether a refresh is running on any replica and how the last one went
+func (r *ProductRepository) GetRefreshStatus(ctx context.Context) (*domain.RefreshStatus, error) {
+	// Create a timeout context for the Redis operation
+	redisCtx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	status := &domain.RefreshStatus{}
+
+	runningSince, err := r.redisClient.Get(redisCtx, RefreshRunningKey).Result()
+	if err != nil && err != redis.Nil {
+		return nil, fmt.Errorf("failed to get refresh status from Redis: %w", err)
+	}
+	if err == nil {
+		status.Running = true
+		if startedAt, err := time.Parse(time.RFC3339Nano, runningSince); err == nil {
+			status.RunningSince = &startedAt
+		}
+	}
+
+	runJSON, err := r.redisClient.Get(redisCtx, RefreshLastRunKey).Bytes()
+	if err != nil && err != redis.Nil {
+		return nil, fmt.Errorf("failed to get last refresh from Redis: %w", err)
+	}
+	if err == nil {
+		var run domain.RefreshRun
+		if err := json.Unmarshal(runJSON, &run); err != nil {
+			return nil, fmt.Errorf("failed to unmarshal last refresh: %w", err)
+		}
+		status.LastRun = &run
+	}
+
+	return status, nil
+}
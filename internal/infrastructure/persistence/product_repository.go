@@ -3,141 +3,494 @@ package persistence
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"log"
+	"os"
+	"slices"
+	"strconv"
+	"sync"
+	"sync/atomic"
 	"time"
 
+	"github.com/ArtemSind/food_tinder/internal/domain"
+	"github.com/ArtemSind/food_tinder/internal/infrastructure/external/catalog"
 	"github.com/ArtemSind/food_tinder/internal/infrastructure/external/foodji"
 	"github.com/go-redis/redis/v8"
 	"github.com/google/uuid"
 )
 
 const (
-	// ProductCacheKeyPrefix is the prefix for product cache keys in Redis
-	ProductCacheKeyPrefix = "product:"
-	// ProductListKey is the key for storing the list of all product IDs
-	ProductListKey = "products:list"
+	// ProductValidationKey stores the validation report of the last Foodji payload
+	ProductValidationKey = "products:validation"
 	// UpdateInterval is how often to update the product data
 	UpdateInterval = 24 * time.Hour
 	// DefaultMachineID is the default machine ID to use for API requests
 	DefaultMachineID = "4bf115ee-303a-4089-a3ea-f6e7aae0ab94"
-	// ProductCacheTimeout is the expiration time for cached products
-	ProductCacheTimeout = 24 * time.Hour
 	// UpdateContextTimeout is the timeout for each update operation
 	UpdateContextTimeout = 5 * time.Minute
 )
 
+// OutboxWriter records events for webhook delivery
+type OutboxWriter interface {
+	Enqueue(ctx context.Context, event *domain.OutboxEvent) error
+}
+
+// CatalogHistory tracks which products the catalog contained over time
+type CatalogHistory interface {
+	RecordCatalog(ctx context.Context, products []*domain.CatalogProduct, seenAt time.Time) ([]*domain.ProductEvent, error)
+	MarkSeen(ctx context.Context, seenAt time.Time) error
+}
+
 // ProductRepository handles product data persistence and caching
 type ProductRepository struct {
-	redisClient  *redis.Client
-	foodjiClient *foodji.Client
-	cancel       context.CancelFunc
+	redisClient       *redis.Client
+	source            catalog.Source
+	outbox            OutboxWriter
+	history           CatalogHistory
+	maxShrink         float64
+	snapshotRetention int
+	replicaID         string
+	products          *productCache // nil if the in-process product cache is disabled
+	curated           *productCache // products with overrides applied; nil with products
+	refreshing        atomic.Bool
+	refreshDone       chan struct{} // signalled whenever a refresh on this replica finishes
+	ctx               context.Context
+	cancel            context.CancelFunc
+	wg                sync.WaitGroup
 }
 
-// NewProductRepository creates a new product repository
-func NewProductRepository(redisClient *redis.Client, foodjiClient *foodji.Client) *ProductRepository {
+// NewProductRepository creates a new product repository.
+// The outbox is optional; when set, every successful refresh records a catalog.updated event.
+// The history is optional too; when set, it records the products of every catalog switched to.
+// CATALOG_MAX_SHRINK overrides the largest share of the catalog a refresh may remove and
+// CATALOG_SNAPSHOT_RETENTION how many earlier snapshots are kept for rollback.
+// PRODUCT_CACHE_SIZE sets how many products, and as many curated products, each replica caches
+// in memory, 0 disabling the caches, and PRODUCT_CACHE_TTL how long a cached product is served at most.
+// STOCK_POLL_INTERVAL sets how often stock is polled between refreshes, 0 disabling the poll.
+func NewProductRepository(redisClient *redis.Client, source catalog.Source, outbox OutboxWriter, history CatalogHistory) *ProductRepository {
+	maxShrink := domain.DefaultMaxCatalogShrink
+	if value, err := strconv.ParseFloat(os.Getenv("CATALOG_MAX_SHRINK"), 64); err == nil && value >= 0 && value <= 1 {
+		maxShrink = value
+	}
+	snapshotRetention := domain.DefaultSnapshotRetention
+	if value, err := strconv.Atoi(os.Getenv("CATALOG_SNAPSHOT_RETENTION")); err == nil && value >= 0 {
+		snapshotRetention = value
+	}
+	cacheSize := DefaultProductCacheSize
+	if value, err := strconv.Atoi(os.Getenv("PRODUCT_CACHE_SIZE")); err == nil && value >= 0 {
+		cacheSize = value
+	}
+	cacheTTL := DefaultProductCacheTTL
+	if value, err := time.ParseDuration(os.Getenv("PRODUCT_CACHE_TTL")); err == nil && value > 0 {
+		cacheTTL = value
+	}
+	stockPollInterval := DefaultStockPollInterval
+	if value, err := time.ParseDuration(os.Getenv("STOCK_POLL_INTERVAL")); err == nil && value >= 0 {
+		stockPollInterval = value
+	}
+
 	ctx, cancel := context.WithCancel(context.Background())
 	repo := &ProductRepository{
-		redisClient:  redisClient,
-		foodjiClient: foodjiClient,
-		cancel:       cancel,
+		redisClient:       redisClient,
+		source:            source,
+		outbox:            outbox,
+		history:           history,
+		maxShrink:         maxShrink,
+		snapshotRetention: snapshotRetention,
+		replicaID:         replicaID(),
+		refreshDone:       make(chan struct{}, 1),
+		ctx:               ctx,
+		cancel:            cancel,
+	}
+
+	// Subscribe before serving products, so no snapshot switch goes unnoticed
+	if cacheSize > 0 {
+		repo.products = newProductCache(cacheSize, cacheTTL)
+		repo.curated = newProductCache(cacheSize, cacheTTL)
+		repo.wg.Add(1)
+		go repo.watchInvalidations(ctx, redisClient.Subscribe(ctx, ProductInvalidationChannel))
 	}
 
 	// Start the periodic update in a goroutine
+	repo.wg.Add(1)
 	go repo.startPeriodicUpdate(ctx)
 
+	if stockPollInterval > 0 {
+		repo.wg.Add(1)
+		go repo.pollStock(ctx, stockPollInterval)
+	}
+
+	// Sources that watch themselves refresh the products as soon as they change
+	if watcher, ok := source.(catalog.Watcher); ok {
+		repo.wg.Add(1)
+		go repo.watchSource(ctx, watcher.Changes())
+	}
+
 	return repo
 }
 
-// Close stops the periodic update goroutine
+// Close stops the periodic update goroutine and waits for a running refresh to release
+// its lease, so another replica can take over
 func (r *ProductRepository) Close() error {
 	if r.cancel != nil {
 		log.Println("Shutting down product repository")
 		r.cancel()
+		r.wg.Wait()
 	}
 	return nil
 }
 
-// startPeriodicUpdate starts a goroutine to update product data periodically
+// RefreshRetrySchedule is how long to wait before retrying after consecutive failed refreshes;
+// the last delay repeats until a refresh succeeds and the regular UpdateInterval resumes
+var RefreshRetrySchedule = []time.Duration{
+	time.Minute,
+	5 * time.Minute,
+	15 * time.Minute,
+	time.Hour,
+}
+
+// nextUpdateDelay returns the delay before the next refresh given the number of consecutive failures
+func nextUpdateDelay(failures int) time.Duration {
+	if failures == 0 {
+		return UpdateInterval
+	}
+	if failures > len(RefreshRetrySchedule) {
+		failures = len(RefreshRetrySchedule)
+	}
+	return RefreshRetrySchedule[failures-1]
+}
+
+// startPeriodicUpdate starts a goroutine to update product data periodically,
+// retrying sooner after a failed update. Only one replica refreshes at a time; the others
+// check back after RefreshLeaseTTL and skip the refresh if it already happened.
 func (r *ProductRepository) startPeriodicUpdate(ctx context.Context) {
-	ticker := time.NewTicker(UpdateInterval)
-	defer ticker.Stop()
+	defer r.wg.Done()
 
 	// Initial update
-	if err := r.updateProducts(ctx); err != nil {
-		log.Printf("Error in initial product update: %v", err)
-	}
+	timer := time.NewTimer(0)
+	defer timer.Stop()
 
+	failures := 0
 	for {
 		select {
-		case <-ticker.C:
+		case <-timer.C:
 			// Create a timeout context for each update operation
 			updateCtx, cancel := context.WithTimeout(ctx, UpdateContextTimeout)
-			if err := r.updateProducts(updateCtx); err != nil {
-				log.Printf("Error in periodic product update: %v", err)
+			delay := UpdateInterval
+			err := r.refresh(updateCtx, domain.RefreshScheduled)
+			switch {
+			case errors.Is(err, domain.ErrRefreshInProgress):
+				log.Printf("A refresh is already running, checking again in %v", RefreshLeaseTTL)
+				delay = RefreshLeaseTTL
+			case errors.Is(err, errRefreshNotDue):
+				log.Println("Skipping periodic product update, another replica refreshed the catalog")
+				failures = 0
+			case err != nil:
+				failures++
+				delay = nextUpdateDelay(failures)
+				log.Printf("Error in periodic product update (failure %d), retrying in %v: %v",
+					failures, delay, err)
+			default:
+				failures = 0
 			}
 			cancel()
+			timer.Reset(delay)
 		case <-ctx.Done():
 			return
 		}
 	}
 }
 
-// updateProducts fetches products from the Foodji API and updates Redis
-func (r *ProductRepository) updateProducts(ctx context.Context) error {
-	log.Println("Starting product update from Foodji API")
+// updateProducts fetches products from the source and writes them to Redis as a new
+// snapshot, which is switched in atomically so readers never see a partial catalog.
+// The fetch is conditional on the source version of the current snapshot; when the
+// source reports no changes the snapshot is kept.
+// The switch is fenced: it fails with domain.ErrStaleRefresh if a refresh holding a newer
+// lease token has already written the catalog.
+// A snapshot pinned by a rollback is kept. A forced update replaces it, fetches unconditionally
+// and is not refused for shrinking the catalog.
+// It returns the outcome and the number of products in the catalog.
+func (r *ProductRepository) updateProducts(ctx context.Context, token int64, force bool) (domain.RefreshOutcome, int, error) {
+	log.Printf("Starting product update from %s", r.source.Name())
+
+	current, err := r.currentSnapshot(ctx)
+	if err != nil {
+		return domain.RefreshFailed, 0, fmt.Errorf("failed to get current snapshot: %w", err)
+	}
 
-	// Get products from the Foodji API
-	products, err := r.foodjiClient.GetMachineProducts(ctx, DefaultMachineID)
+	if !force {
+		pinned, err := r.pinnedVersion(ctx)
+		if err != nil {
+			return domain.RefreshFailed, 0, fmt.Errorf("failed to get pinned snapshot: %w", err)
+		}
+		if pinned != 0 && current != nil {
+			log.Printf("Catalog is pinned to snapshot %d, skipping update", pinned)
+			return domain.RefreshPinned, current.ProductCount, nil
+		}
+	}
+
+	// Only a complete, non-empty catalog is worth revalidating; a snapshot that lost products
+	// in Redis is fetched again in full
+	sourceVersion := ""
+	if current != nil && current.ProductCount > 0 && !force {
+		complete, err := r.snapshotComplete(ctx, current)
+		if err != nil {
+			return domain.RefreshFailed, 0, fmt.Errorf("failed to check snapshot %d: %w", current.Version, err)
+		}
+		if complete {
+			sourceVersion = current.SourceVersion
+		} else {
+			log.Printf("Snapshot %d is missing products, fetching the full catalog", current.Version)
+		}
+	}
+
+	// Get products from the source
+	result, err := r.source.Fetch(ctx, sourceVersion)
+	if errors.Is(err, catalog.ErrNotModified) && sourceVersion != "" {
+		log.Println("Products not modified, kept current snapshot")
+		r.markCatalogSeen(ctx)
+		return domain.RefreshNotModified, current.ProductCount, nil
+	}
+	if errors.Is(err, foodji.ErrInvalidPayload) {
+		var rejections []domain.ProductRejection
+		var validationErr *foodji.ValidationError
+		if errors.As(err, &validationErr) {
+			rejections = productRejections(validationErr.Rejections)
+		}
+		validation := domain.NewCatalogValidation(0, rejections)
+		validation.Refuse(err)
+		r.saveValidation(ctx, validation)
+	}
 	if err != nil {
-		return fmt.Errorf("failed to fetch products from Foodji API: %w", err)
+		return domain.RefreshFailed, 0, fmt.Errorf("failed to fetch products from %s: %w", r.source.Name(), err)
 	}
 
-	log.Printf("Retrieved %d products from API", len(*products))
+	products := result.Products
+	log.Printf("Retrieved %d products from %s, rejected %d", len(products), r.source.Name(), len(result.Rejected))
 
-	// Start a Redis transaction
-	pipe := r.redisClient.TxPipeline()
+	// Refuse payloads that would wipe out a large part of the catalog
+	validation := domain.NewCatalogValidation(len(products), productRejections(result.Rejected))
+	validation.MissingFields = result.MissingFields
+	currentCount := 0
+	if current != nil {
+		currentCount = current.ProductCount
+	}
+	if domain.ShrinksTooMuch(currentCount, len(products), r.maxShrink) {
+		if !force {
+			err := fmt.Errorf("%w: from %d to %d products", domain.ErrCatalogShrink, currentCount, len(products))
+			validation.Refuse(err)
+			r.saveValidation(ctx, validation)
+			return domain.RefreshFailed, currentCount, err
+		}
+		log.Printf("Forced refresh shrinks the catalog from %d to %d products", currentCount, len(products))
+	}
 
-	// Clear existing product list
-	pipe.Del(ctx, ProductListKey)
+	validationJSON, err := json.Marshal(validation)
+	if err != nil {
+		return domain.RefreshFailed, 0, fmt.Errorf("failed to marshal validation: %w", err)
+	}
 
-	// Store each product
-	for _, product := range *products {
-		productID := product.ID.String()
-		productKey := fmt.Sprintf("%s%s", ProductCacheKeyPrefix, productID)
+	snapshot, err := r.writeSnapshot(ctx, products, result.Version)
+	if err != nil {
+		return domain.RefreshFailed, 0, err
+	}
+
+	err = r.switchSnapshot(ctx, token, snapshot, func(pipe redis.Pipeliner) {
+		pipe.Set(ctx, ProductValidationKey, validationJSON, 0)
+		pipe.Del(ctx, ProductPinnedSnapshotKey)
+	})
+	if err != nil {
+		r.redisClient.Del(ctx, snapshotKey(snapshot.Version), soldOutKey(snapshot.Version))
+		return domain.RefreshFailed, 0, fmt.Errorf("failed to switch to snapshot %d: %w", snapshot.Version, err)
+	}
+
+	log.Printf("Product catalog switched to snapshot %d", snapshot.Version)
+	r.pruneSnapshots(ctx)
+	r.recordHistory(ctx, products)
+
+	r.publishCatalogUpdated(ctx, len(products))
+	return domain.RefreshSucceeded, len(products), nil
+}
 
-		// Store product data
-		productJSON, err := json.Marshal(product)
+// saveValidation stores the validation report of a refused payload; failures are logged
+// because the refresh has already failed
+func (r *ProductRepository) saveValidation(ctx context.Context, validation *domain.CatalogValidation) {
+	validationJSON, err := json.Marshal(validation)
+	if err != nil {
+		log.Printf("Failed to marshal catalog validation: %v", err)
+		return
+	}
+
+	if err := r.redisClient.Set(ctx, ProductValidationKey, validationJSON, 0).Err(); err != nil {
+		log.Printf("Failed to store catalog validation: %v", err)
+	}
+}
+
+// GetCatalogValidation returns the validation report of the last Foodji payload
+func (r *ProductRepository) GetCatalogValidation(ctx context.Context) (*domain.CatalogValidation, error) {
+	// Create a timeout context for the Redis operation
+	redisCtx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	validationJSON, err := r.redisClient.Get(redisCtx, ProductValidationKey).Bytes()
+	if err != nil {
+		if err == redis.Nil {
+			return nil, domain.ErrNoCatalogValidation
+		}
+		return nil, fmt.Errorf("failed to get catalog validation from Redis: %w", err)
+	}
+
+	var validation domain.CatalogValidation
+	if err := json.Unmarshal(validationJSON, &validation); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal catalog validation: %w", err)
+	}
+	return &validation, nil
+}
+
+func productRejections(rejections []foodji.Rejection) []domain.ProductRejection {
+	result := make([]domain.ProductRejection, len(rejections))
+	for i, rejection := range rejections {
+		result[i] = domain.ProductRejection{
+			Index:     rejection.Index,
+			ProductID: rejection.ProductID,
+			Reason:    rejection.Reason,
+		}
+	}
+	return result
+}
+
+// productContent is what a product is made of. Its hash is the product's version, so a new
+// price, image, stock, translation or machine does not outdate the votes on the product, and
+// fields Foodji adds later do not either until they are listed here.
+type productContent struct {
+	Name      string   `json:"name"`
+	Category  string   `json:"category"`
+	Allergens []string `json:"allergens"`
+	Diets     []string `json:"diets"`
+}
+
+// newProductContent takes the content of the product, ignoring the order of its labels
+func newProductContent(product foodji.Product) productContent {
+	content := productContent{
+		Name:      product.Name,
+		Category:  product.Category,
+		Allergens: slices.Clone(product.Allergens),
+		Diets:     slices.Clone(product.Diets),
+	}
+	slices.Sort(content.Allergens)
+	slices.Sort(content.Diets)
+	return content
+}
+
+// recordHistory records the products of a catalog that was switched to; failures are logged
+// because the catalog has already been switched
+func (r *ProductRepository) recordHistory(ctx context.Context, products []foodji.Product) {
+	if r.history == nil {
+		return
+	}
+
+	catalogProducts := make([]*domain.CatalogProduct, 0, len(products))
+	for _, product := range products {
+		content, err := json.Marshal(newProductContent(product))
 		if err != nil {
-			return fmt.Errorf("failed to marshal product: %w", err)
+			log.Printf("Failed to marshal product content for history: %v", err)
+			return
+		}
+		// Stock changes with every sale and translations only restate the product, so neither
+		// is kept in the history
+		product.Slots = nil
+		product.Translations = nil
+		data, err := json.Marshal(product)
+		if err != nil {
+			log.Printf("Failed to marshal product for history: %v", err)
+			return
 		}
+		catalogProduct := domain.NewCatalogProduct(product.ID, content, data)
+		if product.Price > 0 {
+			price := product.Price
+			catalogProduct.Price = &price
+		}
+		catalogProducts = append(catalogProducts, catalogProduct)
+	}
+
+	events, err := r.history.RecordCatalog(ctx, catalogProducts, time.Now())
+	if err != nil {
+		log.Printf("Failed to record catalog history: %v", err)
+		return
+	}
+	if len(events) > 0 {
+		log.Printf("Recorded %d product changes", len(events))
+	}
+
+	// Votes keep the version they were cast on, so changed products return to the decks of
+	// sessions that rated an earlier version
+	changed := 0
+	for _, event := range events {
+		if event.Type == domain.ProductChanged {
+			changed++
+		}
+	}
+	if changed > 0 {
+		log.Printf("%d products changed; their earlier ratings are now outdated", changed)
+	}
+}
+
+// markCatalogSeen records that Foodji confirmed the catalog unchanged; failures are logged
+func (r *ProductRepository) markCatalogSeen(ctx context.Context) {
+	if r.history == nil {
+		return
+	}
+	if err := r.history.MarkSeen(ctx, time.Now()); err != nil {
+		log.Printf("Failed to record catalog history: %v", err)
+	}
+}
 
-		pipe.Set(ctx, productKey, productJSON, ProductCacheTimeout)
-		pipe.SAdd(ctx, ProductListKey, productID)
+// publishCatalogUpdated records a catalog.updated event; failures are logged
+// because the cache has already been replaced at this point
+func (r *ProductRepository) publishCatalogUpdated(ctx context.Context, productCount int) {
+	if r.outbox == nil {
+		return
 	}
 
-	// Execute the transaction
-	_, err = pipe.Exec(ctx)
+	event, err := domain.NewOutboxEvent(domain.EventCatalogUpdated, domain.CatalogUpdatedPayload{
+		ProductCount: productCount,
+		UpdatedAt:    time.Now(),
+	})
 	if err != nil {
-		return fmt.Errorf("failed to update Redis: %w", err)
+		log.Printf("Failed to build catalog event: %v", err)
+		return
 	}
 
-	log.Println("Product cache successfully updated")
-	return nil
+	if err := r.outbox.Enqueue(ctx, event); err != nil {
+		log.Printf("Failed to record catalog event: %v", err)
+	}
 }
 
-// GetProduct retrieves a product by ID from Redis
+// GetProduct retrieves a product by ID from the current snapshot, served from memory if it
+// was read since the last snapshot switch
 func (r *ProductRepository) GetProduct(ctx context.Context, id uuid.UUID) (*foodji.Product, error) {
-	productKey := fmt.Sprintf("%s%s", ProductCacheKeyPrefix, id.String())
+	var generation uint64
+	if r.products != nil {
+		if product, ok := r.products.get(id); ok {
+			return product, nil
+		}
+		generation = r.products.currentGeneration()
+	}
 
 	// Create a timeout context for the Redis operation
 	redisCtx, cancel := context.WithTimeout(ctx, 5*time.Second)
 	defer cancel()
 
-	productJSON, err := r.redisClient.Get(redisCtx, productKey).Result()
+	version, err := r.currentVersion(redisCtx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get current snapshot from Redis: %w", err)
+	}
+
+	productJSON, err := r.redisClient.HGet(redisCtx, snapshotKey(version), id.String()).Result()
 	if err != nil {
 		if err == redis.Nil {
-			return nil, fmt.Errorf("product not found")
+			return nil, domain.ErrProductNotFound
 		}
 		return nil, fmt.Errorf("failed to get product from Redis: %w", err)
 	}
@@ -147,18 +500,21 @@ func (r *ProductRepository) GetProduct(ctx context.Context, id uuid.UUID) (*food
 		return nil, fmt.Errorf("failed to unmarshal product: %w", err)
 	}
 
+	if r.products != nil {
+		r.products.put(generation, product.ID, product)
+	}
 	return &product, nil
 }
 
-// ListProducts retrieves all product IDs from Redis
+// ListProducts retrieves all product IDs of the current snapshot
 func (r *ProductRepository) ListProducts(ctx context.Context) ([]uuid.UUID, error) {
 	// Create a timeout context for the Redis operation
 	redisCtx, cancel := context.WithTimeout(ctx, 5*time.Second)
 	defer cancel()
 
-	productIDs, err := r.redisClient.SMembers(redisCtx, ProductListKey).Result()
+	productIDs, err := r.currentProductIDs(redisCtx)
 	if err != nil {
-		return nil, fmt.Errorf("failed to get product list from Redis: %w", err)
+		return nil, err
 	}
 
 	if len(productIDs) == 0 {
@@ -167,15 +523,15 @@ func (r *ProductRepository) ListProducts(ctx context.Context) ([]uuid.UUID, erro
 		updateCtx, updateCancel := context.WithTimeout(ctx, UpdateContextTimeout)
 		defer updateCancel()
 
-		if err := r.updateProducts(updateCtx); err != nil {
+		if err := r.refresh(updateCtx, domain.RefreshEmptyCache); err != nil {
 			log.Printf("Failed to update products: %v", err)
 			return []uuid.UUID{}, nil
 		}
 
 		// Try again after update
-		productIDs, err = r.redisClient.SMembers(redisCtx, ProductListKey).Result()
+		productIDs, err = r.currentProductIDs(redisCtx)
 		if err != nil {
-			return nil, fmt.Errorf("failed to get product list from Redis after update: %w", err)
+			return nil, err
 		}
 	}
 
@@ -191,3 +547,44 @@ func (r *ProductRepository) ListProducts(ctx context.Context) ([]uuid.UUID, erro
 
 	return ids, nil
 }
+
+// SoldOutProducts returns the products of the current snapshot that have no units left
+func (r *ProductRepository) SoldOutProducts(ctx context.Context) ([]uuid.UUID, error) {
+	// Create a timeout context for the Redis operation
+	redisCtx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	version, err := r.currentVersion(redisCtx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get current snapshot from Redis: %w", err)
+	}
+
+	members, err := r.redisClient.SMembers(redisCtx, soldOutKey(version)).Result()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get sold out products from Redis: %w", err)
+	}
+
+	ids := make([]uuid.UUID, 0, len(members))
+	for _, member := range members {
+		id, err := uuid.Parse(member)
+		if err != nil {
+			continue
+		}
+		ids = append(ids, id)
+	}
+	return ids, nil
+}
+
+// currentProductIDs returns the product IDs of the current snapshot
+func (r *ProductRepository) currentProductIDs(ctx context.Context) ([]string, error) {
+	version, err := r.currentVersion(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get current snapshot from Redis: %w", err)
+	}
+
+	productIDs, err := r.redisClient.HKeys(ctx, snapshotKey(version)).Result()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get product list from Redis: %w", err)
+	}
+	return productIDs, nil
+}
@@ -0,0 +1,863 @@
+package persistence_test
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"slices"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/ArtemSind/food_tinder/internal/domain"
+	"github.com/ArtemSind/food_tinder/internal/infrastructure/external/catalog"
+	"github.com/ArtemSind/food_tinder/internal/infrastructure/external/foodji"
+	"github.com/ArtemSind/food_tinder/internal/infrastructure/external/foodji/fake"
+	"github.com/ArtemSind/food_tinder/internal/infrastructure/persistence"
+	"github.com/alicebob/miniredis/v2"
+	"github.com/go-redis/redis/v8"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// FakeOutbox records enqueued events
+type FakeOutbox struct {
+	mu     sync.Mutex
+	events []*domain.OutboxEvent
+}
+
+func (o *FakeOutbox) Enqueue(ctx context.Context, event *domain.OutboxEvent) error {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	o.events = append(o.events, event)
+	return nil
+}
+
+func (o *FakeOutbox) Count() int {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	return len(o.events)
+}
+
+// FakeCatalogHistory keeps the recorded catalogs in memory
+type FakeCatalogHistory struct {
+	mu       sync.Mutex
+	catalogs [][]*domain.CatalogProduct
+	seen     int
+}
+
+func (h *FakeCatalogHistory) RecordCatalog(ctx context.Context, products []*domain.CatalogProduct, seenAt time.Time) ([]*domain.ProductEvent, error) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.catalogs = append(h.catalogs, products)
+	return nil, nil
+}
+
+func (h *FakeCatalogHistory) MarkSeen(ctx context.Context, seenAt time.Time) error {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.seen++
+	return nil
+}
+
+func (h *FakeCatalogHistory) Catalogs() [][]*domain.CatalogProduct {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	return h.catalogs
+}
+
+func (h *FakeCatalogHistory) Seen() int {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	return h.seen
+}
+
+type productRepositoryFixture struct {
+	redis       *miniredis.Miniredis
+	redisClient *redis.Client
+	foodji      *fake.Server
+	client      *foodji.Client
+	source      catalog.Source
+	outbox      *FakeOutbox
+	history     *FakeCatalogHistory
+}
+
+func newProductRepositoryFixture(t *testing.T) *productRepositoryFixture {
+	t.Helper()
+
+	mr := miniredis.RunT(t)
+	redisClient := redis.NewClient(&redis.Options{Addr: mr.Addr()})
+	t.Cleanup(func() { redisClient.Close() })
+
+	fakeServer := fake.NewServer(fake.DefaultFixture())
+	server := httptest.NewServer(fakeServer)
+	t.Cleanup(server.Close)
+
+	client := foodji.NewClient()
+	client.BaseURL = server.URL
+	client.Retry = foodji.RetryPolicy{MaxAttempts: 2, BaseDelay: time.Millisecond, MaxDelay: time.Millisecond}
+	client.Breaker = foodji.NewCircuitBreaker(100, time.Minute)
+
+	return &productRepositoryFixture{
+		redis:       mr,
+		redisClient: redisClient,
+		foodji:      fakeServer,
+		client:      client,
+		source:      catalog.NewFoodjiSource(client, persistence.DefaultMachineID),
+		outbox:      &FakeOutbox{},
+		history:     &FakeCatalogHistory{},
+	}
+}
+
+func (f *productRepositoryFixture) newRepository(t *testing.T) *persistence.ProductRepository {
+	t.Helper()
+
+	repo := persistence.NewProductRepository(f.redisClient, f.source, f.outbox, f.history)
+	t.Cleanup(func() { repo.Close() })
+	return repo
+}
+
+// waitForRequests waits until the initial refresh of a new repository has reached Foodji and finished
+func (f *productRepositoryFixture) waitForRequests(t *testing.T, requests int) {
+	t.Helper()
+	require.Eventually(t, func() bool { return f.foodji.Requests() >= requests }, time.Second, 5*time.Millisecond)
+	time.Sleep(20 * time.Millisecond)
+}
+
+func fixtureProductIDs() []uuid.UUID {
+	var ids []uuid.UUID
+	for _, product := range fake.DefaultFixture().Machines[persistence.DefaultMachineID] {
+		ids = append(ids, product.ID)
+	}
+	return ids
+}
+
+func TestProductRepository_InitialRefresh(t *testing.T) {
+	// Arrange
+	f := newProductRepositoryFixture(t)
+	ctx := context.Background()
+
+	// Act
+	repo := f.newRepository(t)
+	f.waitForRequests(t, 1)
+
+	// Assert
+	ids, err := repo.ListProducts(ctx)
+	require.NoError(t, err)
+	assert.ElementsMatch(t, fixtureProductIDs(), ids)
+
+	product, err := repo.GetProduct(ctx, ids[0])
+	require.NoError(t, err)
+	assert.Equal(t, ids[0], product.ID)
+
+	_, err = repo.GetProduct(ctx, uuid.New())
+	assert.ErrorIs(t, err, domain.ErrProductNotFound)
+
+	assert.Contains(t, f.redis.HGet(persistence.ProductSnapshotsKey, "1"), `"source_version"`)
+	assert.Equal(t, 1, f.outbox.Count())
+
+	version, products, err := repo.GetCatalog(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), version)
+	require.Len(t, products, len(fixtureProductIDs()))
+	assert.NotEmpty(t, products[0].Name)
+	assert.Equal(t, persistence.DefaultMachineID, products[0].MachineID)
+
+	catalogs := f.history.Catalogs()
+	require.Len(t, catalogs, 1, "the catalog is recorded in the product history")
+	assert.Len(t, catalogs[0], len(fixtureProductIDs()))
+	for _, product := range catalogs[0] {
+		assert.NotNil(t, product.Price, "every fixture product records its price")
+	}
+}
+
+func TestProductRepository_Stock(t *testing.T) {
+	// Arrange
+	f := newProductRepositoryFixture(t)
+	ctx := context.Background()
+	brownie := uuid.MustParse("3f1c9a4e-5b7d-4c2a-9e8f-1a2b3c4d5e06")
+	salad := uuid.MustParse("3f1c9a4e-5b7d-4c2a-9e8f-1a2b3c4d5e01")
+
+	// Act
+	repo := f.newRepository(t)
+	f.waitForRequests(t, 1)
+
+	// Assert
+	soldOut, err := repo.SoldOutProducts(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, []uuid.UUID{brownie}, soldOut)
+
+	product, err := repo.GetProduct(ctx, salad)
+	require.NoError(t, err)
+	stock, known := product.Stock()
+	assert.True(t, known)
+	assert.Equal(t, 5, stock, "stock is summed over the product's slots")
+
+	catalogs := f.history.Catalogs()
+	require.Len(t, catalogs, 1)
+	for _, product := range catalogs[0] {
+		assert.NotContains(t, string(product.Data), "slots", "stock is not part of the product's content")
+	}
+}
+
+func TestProductRepository_ContentHash(t *testing.T) {
+	// Arrange
+	f := newProductRepositoryFixture(t)
+	repo := f.newRepository(t)
+	f.waitForRequests(t, 1)
+
+	hashes := func(catalog []*domain.CatalogProduct) map[uuid.UUID]string {
+		result := make(map[uuid.UUID]string)
+		for _, product := range catalog {
+			result[product.ID] = product.ContentHash
+		}
+		return result
+	}
+
+	// Act
+	products := fake.DefaultFixture().Machines[persistence.DefaultMachineID]
+	for i := range products {
+		products[i].Price += 1
+		products[i].ImageURL = "https://images.foodji.invalid/new.jpg"
+		products[i].Origin = "another-source"
+		products[i].Slots = nil
+		products[i].Translations = nil
+		slices.Reverse(products[i].Allergens)
+	}
+	f.foodji.SetProducts(persistence.DefaultMachineID, products)
+	require.Eventually(t, repo.TriggerRefresh, time.Second, 5*time.Millisecond, "the initial refresh should finish")
+	f.waitForRequests(t, 2)
+
+	renamed := slices.Clone(products)
+	renamed[0].Name = "Renamed " + renamed[0].Name
+	f.foodji.SetProducts(persistence.DefaultMachineID, renamed)
+	require.Eventually(t, repo.TriggerRefresh, time.Second, 5*time.Millisecond, "the second refresh should finish")
+	f.waitForRequests(t, 3)
+
+	// Assert
+	catalogs := f.history.Catalogs()
+	require.Len(t, catalogs, 3)
+	initial := hashes(catalogs[0])
+	assert.Equal(t, initial, hashes(catalogs[1]), "price, image, origin, stock, translations and label order are not content")
+	assert.Contains(t, string(catalogs[1][0].Data), "new.jpg", "the history keeps the product as received")
+
+	changed := hashes(catalogs[2])
+	assert.NotEqual(t, initial[renamed[0].ID], changed[renamed[0].ID], "a new name is a new version")
+	assert.Equal(t, initial[renamed[1].ID], changed[renamed[1].ID])
+}
+
+func TestProductRepository_Translations(t *testing.T) {
+	// Arrange
+	f := newProductRepositoryFixture(t)
+	ctx := context.Background()
+	salad := uuid.MustParse("3f1c9a4e-5b7d-4c2a-9e8f-1a2b3c4d5e01")
+
+	// Act
+	repo := f.newRepository(t)
+	f.waitForRequests(t, 1)
+
+	// Assert
+	product, err := repo.GetProduct(ctx, salad)
+	require.NoError(t, err)
+	german := product.Localized("de")
+	assert.Equal(t, "Caesar Salat mit Hähnchen", german.Name)
+	assert.Equal(t, "Salate", german.Category)
+	assert.Equal(t, "Chicken Caesar Salad", product.Localized("fr").Name, "untranslated languages keep the default texts")
+
+	catalogs := f.history.Catalogs()
+	require.Len(t, catalogs, 1)
+	for _, product := range catalogs[0] {
+		assert.NotContains(t, string(product.Data), "translations", "translations are not part of the product's content")
+	}
+}
+
+func TestProductRepository_NotModifiedKeepsCache(t *testing.T) {
+	// Arrange
+	f := newProductRepositoryFixture(t)
+	ctx := context.Background()
+	repo := f.newRepository(t)
+	f.waitForRequests(t, 1)
+
+	// Snapshots do not expire between refreshes
+	f.redis.FastForward(48 * time.Hour)
+
+	// Act: revalidate the cache
+	require.True(t, repo.TriggerRefresh())
+	f.waitForRequests(t, 2)
+
+	// Assert
+	snapshots, err := repo.ListSnapshots(ctx)
+	require.NoError(t, err)
+	require.Len(t, snapshots, 1, "an unchanged catalog does not create a snapshot")
+	assert.True(t, snapshots[0].Current)
+
+	ids, err := repo.ListProducts(ctx)
+	require.NoError(t, err)
+	assert.ElementsMatch(t, fixtureProductIDs(), ids)
+	assert.Equal(t, 1, f.outbox.Count(), "an unchanged catalog is not announced")
+	assert.Len(t, f.history.Catalogs(), 1)
+	assert.Equal(t, 1, f.history.Seen())
+}
+
+func TestProductRepository_NotModifiedWithIncompleteCache(t *testing.T) {
+	// Arrange
+	f := newProductRepositoryFixture(t)
+	ctx := context.Background()
+	repo := f.newRepository(t)
+	f.waitForRequests(t, 1)
+
+	// Redis lost a product of the current snapshot
+	f.redis.HDel(persistence.ProductSnapshotKeyPrefix+"1", cachedSaladID.String())
+
+	// Act
+	require.True(t, repo.TriggerRefresh())
+	f.waitForRequests(t, 2)
+
+	// Assert
+	var status *domain.RefreshStatus
+	require.Eventually(t, func() bool {
+		var err error
+		status, err = repo.GetRefreshStatus(ctx)
+		return err == nil && !status.Running && status.LastRun != nil && status.LastRun.Trigger == domain.RefreshManual
+	}, time.Second, 5*time.Millisecond)
+	assert.Equal(t, domain.RefreshSucceeded, status.LastRun.Outcome, "the full catalog is fetched instead of revalidated")
+
+	snapshots, err := repo.ListSnapshots(ctx)
+	require.NoError(t, err)
+	assert.Len(t, snapshots, 2)
+
+	ids, err := repo.ListProducts(ctx)
+	require.NoError(t, err)
+	assert.ElementsMatch(t, fixtureProductIDs(), ids)
+}
+
+func TestProductRepository_FoodjiFailuresKeepCache(t *testing.T) {
+	tests := []struct {
+		name   string
+		inject func(server *fake.Server)
+	}{
+		{name: "errors", inject: func(server *fake.Server) { server.SetErrorRate(1, http.StatusServiceUnavailable) }},
+		{name: "malformed payload", inject: func(server *fake.Server) { server.SetMalformed(true) }},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Arrange
+			f := newProductRepositoryFixture(t)
+			ctx := context.Background()
+			repo := f.newRepository(t)
+			f.waitForRequests(t, 1)
+
+			// Replace the catalog so a successful refresh would be visible
+			f.foodji.SetProducts(persistence.DefaultMachineID, []foodji.Product{{ID: uuid.New()}})
+			tt.inject(f.foodji)
+
+			// Act
+			require.True(t, repo.TriggerRefresh())
+			f.waitForRequests(t, 2)
+
+			// Assert
+			ids, err := repo.ListProducts(ctx)
+			require.NoError(t, err)
+			assert.ElementsMatch(t, fixtureProductIDs(), ids)
+		})
+	}
+}
+
+func TestProductRepository_ListProductsWhenFoodjiIsDown(t *testing.T) {
+	// Arrange
+	f := newProductRepositoryFixture(t)
+	f.foodji.SetErrorRate(1, http.StatusBadGateway)
+	repo := f.newRepository(t)
+	f.waitForRequests(t, 1)
+
+	// Act
+	ids, err := repo.ListProducts(context.Background())
+
+	// Assert
+	require.NoError(t, err)
+	assert.Empty(t, ids)
+}
+
+func TestProductRepository_Validation(t *testing.T) {
+	t.Run("invalid products are skipped and reported", func(t *testing.T) {
+		// Arrange
+		f := newProductRepositoryFixture(t)
+		ctx := context.Background()
+		valid := uuid.New()
+		f.foodji.SetRawProducts(persistence.DefaultMachineID, []json.RawMessage{
+			json.RawMessage(`{"id":"` + valid.String() + `"}`),
+			json.RawMessage(`{"id":"not-a-uuid"}`),
+		})
+
+		// Act
+		repo := f.newRepository(t)
+		f.waitForRequests(t, 1)
+
+		// Assert
+		ids, err := repo.ListProducts(ctx)
+		require.NoError(t, err)
+		assert.Equal(t, []uuid.UUID{valid}, ids)
+
+		validation, err := repo.GetCatalogValidation(ctx)
+		require.NoError(t, err)
+		assert.Equal(t, 1, validation.Accepted)
+		assert.Equal(t, 1, validation.Rejected)
+		assert.Equal(t, "not-a-uuid", validation.Rejections[0].ProductID)
+		assert.Contains(t, validation.MissingFields, "price", "no product has a price")
+		assert.False(t, validation.Refused)
+	})
+
+	t.Run("a shrinking catalog is refused", func(t *testing.T) {
+		// Arrange
+		f := newProductRepositoryFixture(t)
+		ctx := context.Background()
+		repo := f.newRepository(t)
+		f.waitForRequests(t, 1)
+		f.foodji.SetProducts(persistence.DefaultMachineID, []foodji.Product{{ID: uuid.New()}})
+
+		// Act
+		require.True(t, repo.TriggerRefresh())
+		f.waitForRequests(t, 2)
+
+		// Assert
+		ids, err := repo.ListProducts(ctx)
+		require.NoError(t, err)
+		assert.ElementsMatch(t, fixtureProductIDs(), ids)
+
+		validation, err := repo.GetCatalogValidation(ctx)
+		require.NoError(t, err)
+		assert.True(t, validation.Refused)
+		assert.Contains(t, validation.RefusedReason, "from 8 to 1 products")
+	})
+
+	t.Run("a forced refresh replaces a shrinking catalog once", func(t *testing.T) {
+		// Arrange
+		f := newProductRepositoryFixture(t)
+		ctx := context.Background()
+		repo := f.newRepository(t)
+		f.waitForRequests(t, 1)
+		remaining := uuid.New()
+		f.foodji.SetProducts(persistence.DefaultMachineID, []foodji.Product{{ID: remaining}})
+
+		// Act
+		require.True(t, repo.ForceRefresh())
+		f.waitForRequests(t, 2)
+
+		// Assert
+		ids, err := repo.ListProducts(ctx)
+		require.NoError(t, err)
+		assert.Equal(t, []uuid.UUID{remaining}, ids)
+
+		status, err := repo.GetRefreshStatus(ctx)
+		require.NoError(t, err)
+		require.NotNil(t, status.LastRun)
+		assert.Equal(t, domain.RefreshForced, status.LastRun.Trigger)
+		assert.Equal(t, domain.RefreshSucceeded, status.LastRun.Outcome)
+
+		// The next refresh is guarded again
+		f.foodji.SetProducts(persistence.DefaultMachineID, nil)
+		require.True(t, repo.TriggerRefresh())
+		f.waitForRequests(t, 3)
+
+		ids, err = repo.ListProducts(ctx)
+		require.NoError(t, err)
+		assert.Equal(t, []uuid.UUID{remaining}, ids)
+	})
+
+	t.Run("strict mode refuses payloads with invalid products", func(t *testing.T) {
+		// Arrange
+		f := newProductRepositoryFixture(t)
+		ctx := context.Background()
+		f.client.Validation = foodji.ValidationStrict
+		f.foodji.SetRawProducts(persistence.DefaultMachineID, []json.RawMessage{
+			json.RawMessage(`{"id":"` + uuid.New().String() + `"}`),
+			json.RawMessage(`{}`),
+		})
+
+		// Act
+		repo := f.newRepository(t)
+		f.waitForRequests(t, 1)
+
+		// Assert
+		validation, err := repo.GetCatalogValidation(ctx)
+		require.NoError(t, err)
+		assert.True(t, validation.Refused)
+		assert.Equal(t, 1, validation.Rejected)
+		assert.False(t, f.redis.Exists(persistence.ProductCurrentSnapshotKey))
+	})
+}
+
+func TestProductRepository_TriggerRefresh(t *testing.T) {
+	// Arrange
+	f := newProductRepositoryFixture(t)
+	ctx := context.Background()
+	repo := f.newRepository(t)
+	f.waitForRequests(t, 1)
+
+	f.foodji.SetProducts(persistence.DefaultMachineID, append(fake.DefaultFixture().Machines[persistence.DefaultMachineID], foodji.Product{ID: uuid.New()}))
+	f.foodji.SetLatency(100 * time.Millisecond)
+
+	// Act
+	started := repo.TriggerRefresh()
+	duplicate := repo.TriggerRefresh()
+
+	// Assert
+	assert.True(t, started)
+	assert.False(t, duplicate, "a running refresh is not duplicated")
+
+	require.Eventually(t, func() bool {
+		status, err := repo.GetRefreshStatus(ctx)
+		return err == nil && status.Running
+	}, time.Second, 5*time.Millisecond)
+
+	var status *domain.RefreshStatus
+	require.Eventually(t, func() bool {
+		var err error
+		status, err = repo.GetRefreshStatus(ctx)
+		return err == nil && !status.Running && status.LastRun.Trigger == domain.RefreshManual
+	}, time.Second, 5*time.Millisecond)
+
+	assert.Equal(t, domain.RefreshSucceeded, status.LastRun.Outcome)
+	assert.Equal(t, 9, status.LastRun.ProductCount)
+	assert.GreaterOrEqual(t, status.LastRun.Duration, 100*time.Millisecond)
+	assert.Empty(t, status.LastRun.Error)
+	assert.Equal(t, 2, f.foodji.Requests())
+}
+
+func TestProductRepository_RefreshStatusRecordsFailures(t *testing.T) {
+	// Arrange
+	f := newProductRepositoryFixture(t)
+	ctx := context.Background()
+	f.foodji.SetErrorRate(1, http.StatusServiceUnavailable)
+
+	// Act
+	repo := f.newRepository(t)
+	f.waitForRequests(t, 1)
+
+	// Assert
+	var status *domain.RefreshStatus
+	require.Eventually(t, func() bool {
+		var err error
+		status, err = repo.GetRefreshStatus(ctx)
+		return err == nil && status.LastRun != nil
+	}, time.Second, 5*time.Millisecond)
+
+	assert.Equal(t, domain.RefreshScheduled, status.LastRun.Trigger)
+	assert.Equal(t, domain.RefreshFailed, status.LastRun.Outcome)
+	assert.Contains(t, status.LastRun.Error, "503")
+}
+
+func TestProductRepository_ReplicasShareRefreshes(t *testing.T) {
+	// Arrange
+	f := newProductRepositoryFixture(t)
+	ctx := context.Background()
+	f.newRepository(t)
+	f.waitForRequests(t, 1)
+
+	// Act: a second replica starts after the first one refreshed the catalog
+	replica := f.newRepository(t)
+	time.Sleep(50 * time.Millisecond)
+
+	// Assert
+	assert.Equal(t, 1, f.foodji.Requests(), "the second replica does not call Foodji again")
+
+	ids, err := replica.ListProducts(ctx)
+	require.NoError(t, err)
+	assert.ElementsMatch(t, fixtureProductIDs(), ids)
+}
+
+func TestProductRepository_RefreshLease(t *testing.T) {
+	t.Run("only the lease holder refreshes", func(t *testing.T) {
+		// Arrange
+		f := newProductRepositoryFixture(t)
+		require.NoError(t, f.redis.Set(persistence.RefreshLeaseKey, "other-replica:1"))
+
+		// Act
+		repo := f.newRepository(t)
+		time.Sleep(50 * time.Millisecond)
+		blocked := repo.TriggerRefresh()
+
+		// The other replica shuts down and hands the lease over
+		f.redis.Del(persistence.RefreshLeaseKey)
+		started := repo.TriggerRefresh()
+
+		// Assert
+		assert.False(t, blocked)
+		assert.True(t, started)
+		f.waitForRequests(t, 1)
+		assert.Equal(t, 1, f.foodji.Requests())
+		assert.Equal(t, "1", mustGet(t, f.redis, persistence.ProductFenceKey))
+		assert.False(t, f.redis.Exists(persistence.RefreshLeaseKey), "the lease is released after the refresh")
+	})
+
+	t.Run("a stale holder does not overwrite a newer catalog", func(t *testing.T) {
+		// Arrange: a refresh with a newer fencing token already wrote the catalog
+		f := newProductRepositoryFixture(t)
+		ctx := context.Background()
+		require.NoError(t, f.redis.Set(persistence.ProductFenceKey, "100"))
+
+		// Act
+		repo := f.newRepository(t)
+		f.waitForRequests(t, 1)
+
+		// Assert
+		assert.False(t, f.redis.Exists(persistence.ProductCurrentSnapshotKey))
+
+		status, err := repo.GetRefreshStatus(ctx)
+		require.NoError(t, err)
+		assert.Equal(t, domain.RefreshFailed, status.LastRun.Outcome)
+		assert.Contains(t, status.LastRun.Error, domain.ErrStaleRefresh.Error())
+	})
+
+	t.Run("closing the repository releases the lease", func(t *testing.T) {
+		// Arrange
+		f := newProductRepositoryFixture(t)
+		f.foodji.SetLatency(time.Minute)
+		repo := f.newRepository(t)
+		f.waitForRequests(t, 1)
+		require.True(t, f.redis.Exists(persistence.RefreshLeaseKey))
+
+		// Act
+		require.NoError(t, repo.Close())
+
+		// Assert
+		assert.False(t, f.redis.Exists(persistence.RefreshLeaseKey))
+	})
+}
+
+func mustGet(t *testing.T, mr *miniredis.Miniredis, key string) string {
+	t.Helper()
+	value, err := mr.Get(key)
+	require.NoError(t, err)
+	return value
+}
+
+func TestProductRepository_Snapshots(t *testing.T) {
+	t.Run("each changed catalog is a new snapshot", func(t *testing.T) {
+		// Arrange
+		f := newProductRepositoryFixture(t)
+		ctx := context.Background()
+		repo := f.newRepository(t)
+		f.waitForRequests(t, 1)
+
+		added := foodji.Product{ID: uuid.New()}
+		f.foodji.SetProducts(persistence.DefaultMachineID, append(fake.DefaultFixture().Machines[persistence.DefaultMachineID], added))
+
+		// Act
+		require.True(t, repo.TriggerRefresh())
+		f.waitForRequests(t, 2)
+
+		// Assert
+		snapshots, err := repo.ListSnapshots(ctx)
+		require.NoError(t, err)
+		require.Len(t, snapshots, 2)
+		assert.Equal(t, int64(2), snapshots[0].Version)
+		assert.True(t, snapshots[0].Current)
+		assert.Equal(t, 9, snapshots[0].ProductCount)
+		assert.Equal(t, int64(1), snapshots[1].Version)
+		assert.False(t, snapshots[1].Current)
+		assert.Equal(t, 8, snapshots[1].ProductCount)
+
+		product, err := repo.GetProduct(ctx, added.ID)
+		require.NoError(t, err)
+		assert.Equal(t, added.ID, product.ID)
+	})
+
+	t.Run("old snapshots are pruned", func(t *testing.T) {
+		// Arrange
+		t.Setenv("CATALOG_SNAPSHOT_RETENTION", "1")
+		f := newProductRepositoryFixture(t)
+		ctx := context.Background()
+		repo := f.newRepository(t)
+		f.waitForRequests(t, 1)
+
+		// Act
+		products := fake.DefaultFixture().Machines[persistence.DefaultMachineID]
+		for i := 2; i <= 3; i++ {
+			products = append(products, foodji.Product{ID: uuid.New()})
+			f.foodji.SetProducts(persistence.DefaultMachineID, products)
+			require.True(t, repo.TriggerRefresh())
+			f.waitForRequests(t, i)
+		}
+
+		// Assert
+		snapshots, err := repo.ListSnapshots(ctx)
+		require.NoError(t, err)
+		require.Len(t, snapshots, 2)
+		assert.Equal(t, int64(3), snapshots[0].Version)
+		assert.Equal(t, int64(2), snapshots[1].Version)
+		assert.False(t, f.redis.Exists(persistence.ProductSnapshotKeyPrefix+"1"))
+	})
+
+	t.Run("rollback switches to an earlier snapshot", func(t *testing.T) {
+		// Arrange
+		f := newProductRepositoryFixture(t)
+		ctx := context.Background()
+		repo := f.newRepository(t)
+		f.waitForRequests(t, 1)
+
+		f.foodji.SetProducts(persistence.DefaultMachineID, append(fake.DefaultFixture().Machines[persistence.DefaultMachineID], foodji.Product{ID: uuid.New()}))
+		require.True(t, repo.TriggerRefresh())
+		f.waitForRequests(t, 2)
+
+		// Act
+		snapshot, err := repo.RollbackToSnapshot(ctx, 1)
+
+		// Assert
+		require.NoError(t, err)
+		assert.Equal(t, int64(1), snapshot.Version)
+		assert.True(t, snapshot.Current)
+		assert.True(t, snapshot.Pinned)
+
+		ids, err := repo.ListProducts(ctx)
+		require.NoError(t, err)
+		assert.ElementsMatch(t, fixtureProductIDs(), ids)
+		assert.Equal(t, 3, f.outbox.Count(), "the rollback is announced")
+		catalogs := f.history.Catalogs()
+		require.Len(t, catalogs, 3)
+		assert.Len(t, catalogs[2], len(fixtureProductIDs()), "the rolled back catalog is recorded")
+
+		_, err = repo.RollbackToSnapshot(ctx, 42)
+		assert.ErrorIs(t, err, domain.ErrSnapshotNotFound)
+	})
+
+	t.Run("a rollback is kept until it is unpinned", func(t *testing.T) {
+		// Arrange
+		f := newProductRepositoryFixture(t)
+		ctx := context.Background()
+		repo := f.newRepository(t)
+		f.waitForRequests(t, 1)
+
+		added := foodji.Product{ID: uuid.New()}
+		f.foodji.SetProducts(persistence.DefaultMachineID, append(fake.DefaultFixture().Machines[persistence.DefaultMachineID], added))
+		require.True(t, repo.TriggerRefresh())
+		f.waitForRequests(t, 2)
+		_, err := repo.RollbackToSnapshot(ctx, 1)
+		require.NoError(t, err)
+
+		// Act: a refresh while pinned
+		require.True(t, repo.TriggerRefresh())
+		waitForIdle(t, repo)
+
+		// Assert
+		version, err := repo.GetCatalogVersion(ctx)
+		require.NoError(t, err)
+		assert.Equal(t, int64(1), version, "the pinned snapshot is kept")
+		status, err := repo.GetRefreshStatus(ctx)
+		require.NoError(t, err)
+		assert.Equal(t, domain.RefreshPinned, status.LastRun.Outcome)
+
+		// Act: a refresh after unpinning
+		require.NoError(t, repo.UnpinSnapshot(ctx))
+		require.True(t, repo.TriggerRefresh())
+
+		// Assert
+		require.Eventually(t, func() bool {
+			ids, err := repo.ListProducts(ctx)
+			return err == nil && len(ids) == len(fixtureProductIDs())+1
+		}, time.Second, 5*time.Millisecond)
+		snapshots, err := repo.ListSnapshots(ctx)
+		require.NoError(t, err)
+		for _, snapshot := range snapshots {
+			assert.False(t, snapshot.Pinned)
+		}
+	})
+
+	t.Run("rollback waits for a running refresh", func(t *testing.T) {
+		// Arrange
+		f := newProductRepositoryFixture(t)
+		ctx := context.Background()
+		repo := f.newRepository(t)
+		f.waitForRequests(t, 1)
+		require.NoError(t, f.redis.Set(persistence.RefreshLeaseKey, "other-replica:2"))
+
+		// Act
+		_, err := repo.RollbackToSnapshot(ctx, 1)
+
+		// Assert
+		assert.ErrorIs(t, err, domain.ErrRefreshInProgress)
+	})
+}
+
+func TestProductRepository_FileSourceChanges(t *testing.T) {
+	// Arrange
+	f := newProductRepositoryFixture(t)
+	ctx := context.Background()
+
+	path := filepath.Join(t.TempDir(), "canteen.csv")
+	soup := uuid.New()
+	require.NoError(t, os.WriteFile(path, []byte("id\n"+soup.String()+"\n"), 0o644))
+
+	fileSource := catalog.NewFileSource(path)
+	fileSource.WatchInterval = 5 * time.Millisecond
+	fileSource.Start()
+	t.Cleanup(func() { fileSource.Close() })
+	f.source = catalog.NewCompositeSource(f.source, fileSource)
+
+	repo := f.newRepository(t)
+	f.waitForRequests(t, 1)
+
+	// Act
+	salad := uuid.New()
+	require.NoError(t, os.WriteFile(path, []byte("id\n"+soup.String()+"\n"+salad.String()+"\n"), 0o644))
+
+	// Assert
+	require.Eventually(t, func() bool {
+		status, err := repo.GetRefreshStatus(ctx)
+		return err == nil && status.LastRun != nil && status.LastRun.Trigger == domain.RefreshSourceChanged
+	}, time.Second, 5*time.Millisecond)
+
+	ids, err := repo.ListProducts(ctx)
+	require.NoError(t, err)
+	assert.ElementsMatch(t, append(fixtureProductIDs(), soup, salad), ids)
+
+	product, err := repo.GetProduct(ctx, salad)
+	require.NoError(t, err)
+	assert.Equal(t, "canteen", product.Origin)
+
+	product, err = repo.GetProduct(ctx, fixtureProductIDs()[0])
+	require.NoError(t, err)
+	assert.Equal(t, catalog.FoodjiOrigin, product.Origin)
+}
+
+func TestProductRepository_SourceChangeDuringRefresh(t *testing.T) {
+	// Arrange
+	f := newProductRepositoryFixture(t)
+	ctx := context.Background()
+
+	path := filepath.Join(t.TempDir(), "canteen.csv")
+	soup := uuid.New()
+	require.NoError(t, os.WriteFile(path, []byte("id\n"+soup.String()+"\n"), 0o644))
+
+	fileSource := catalog.NewFileSource(path)
+	fileSource.WatchInterval = 5 * time.Millisecond
+	fileSource.Start()
+	t.Cleanup(func() { fileSource.Close() })
+	f.source = catalog.NewCompositeSource(f.source, fileSource)
+
+	repo := f.newRepository(t)
+	waitForIdle(t, repo)
+
+	// A slow manual refresh is running when the file changes
+	f.foodji.SetLatency(100 * time.Millisecond)
+	require.Eventually(t, repo.TriggerRefresh, time.Second, 5*time.Millisecond, "a refresh starts once the last one is done")
+
+	// Act
+	salad := uuid.New()
+	require.NoError(t, os.WriteFile(path, []byte("id\n"+soup.String()+"\n"+salad.String()+"\n"), 0o644))
+
+	// Assert
+	require.Eventually(t, func() bool {
+		status, err := repo.GetRefreshStatus(ctx)
+		return err == nil && !status.Running && status.LastRun != nil && status.LastRun.Trigger == domain.RefreshSourceChanged
+	}, 2*time.Second, 5*time.Millisecond, "the change is refreshed once the running refresh is done")
+
+	ids, err := repo.ListProducts(ctx)
+	require.NoError(t, err)
+	assert.Contains(t, ids, salad)
+}
@@ -0,0 +1,368 @@
+package persistence
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"sort"
+	"strconv"
+	"time"
+
+	"github.com/ArtemSind/food_tinder/internal/domain"
+	"github.com/ArtemSind/food_tinder/internal/infrastructure/external/foodji"
+	"github.com/go-redis/redis/v8"
+)
+
+const (
+	// ProductSnapshotKeyPrefix is the prefix of the hashes holding each snapshot's products by ID
+	ProductSnapshotKeyPrefix = "products:snapshot:"
+	// ProductSnapshotsKey is the hash describing every retained snapshot by version
+	ProductSnapshotsKey = "products:snapshots"
+	// ProductCurrentSnapshotKey holds the version of the snapshot products are served from
+	ProductCurrentSnapshotKey = "products:current"
+	// ProductVersionKey is the counter snapshot versions are issued from
+	ProductVersionKey = "products:version"
+	// ProductPinnedSnapshotKey holds the version a rollback switched to while refreshes must keep it
+	ProductPinnedSnapshotKey = "products:pinned"
+)
+
+// catalogSnapshot describes a snapshot in Redis, together with the version of the
+// source result it was written from
+type catalogSnapshot struct {
+	Version       int64     `json:"version"`
+	CreatedAt     time.Time `json:"created_at"`
+	ProductCount  int       `json:"product_count"`
+	SourceVersion string    `json:"source_version,omitempty"`
+	// StockSourceVersion is the version of the source result the stock was last updated from
+	StockSourceVersion string     `json:"stock_source_version,omitempty"`
+	StockUpdatedAt     *time.Time `json:"stock_updated_at,omitempty"`
+}
+
+func (s *catalogSnapshot) toDomain(current, pinned int64) *domain.CatalogSnapshot {
+	return &domain.CatalogSnapshot{
+		Version:        s.Version,
+		CreatedAt:      s.CreatedAt,
+		ProductCount:   s.ProductCount,
+		StockUpdatedAt: s.StockUpdatedAt,
+		Current:        s.Version == current,
+		Pinned:         s.Version == pinned,
+	}
+}
+
+func snapshotKey(version int64) string {
+	return ProductSnapshotKeyPrefix + strconv.FormatInt(version, 10)
+}
+
+// soldOutKey is the set of the products of a snapshot that have no units left
+func soldOutKey(version int64) string {
+	return snapshotKey(version) + ":sold_out"
+}
+
+// currentVersion returns the version products are served from, or 0 if there is none yet
+func (r *ProductRepository) currentVersion(ctx context.Context) (int64, error) {
+	version, err := r.redisClient.Get(ctx, ProductCurrentSnapshotKey).Int64()
+	if err == redis.Nil {
+		return 0, nil
+	}
+	return version, err
+}
+
+// pinnedVersion returns the version a rollback pinned, or 0 if refreshes may switch the snapshot
+func (r *ProductRepository) pinnedVersion(ctx context.Context) (int64, error) {
+	version, err := r.redisClient.Get(ctx, ProductPinnedSnapshotKey).Int64()
+	if err == redis.Nil {
+		return 0, nil
+	}
+	return version, err
+}
+
+// loadSnapshot returns the description of a snapshot, or domain.ErrSnapshotNotFound
+func (r *ProductRepository) loadSnapshot(ctx context.Context, version int64) (*catalogSnapshot, error) {
+	snapshotJSON, err := r.redisClient.HGet(ctx, ProductSnapshotsKey, strconv.FormatInt(version, 10)).Bytes()
+	if err == redis.Nil {
+		return nil, domain.ErrSnapshotNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var snapshot catalogSnapshot
+	if err := json.Unmarshal(snapshotJSON, &snapshot); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal snapshot: %w", err)
+	}
+	return &snapshot, nil
+}
+
+// currentSnapshot returns the snapshot products are served from, or nil if there is none yet
+func (r *ProductRepository) currentSnapshot(ctx context.Context) (*catalogSnapshot, error) {
+	version, err := r.currentVersion(ctx)
+	if err != nil || version == 0 {
+		return nil, err
+	}
+
+	snapshot, err := r.loadSnapshot(ctx, version)
+	if err == domain.ErrSnapshotNotFound {
+		return nil, nil
+	}
+	return snapshot, err
+}
+
+// snapshotComplete reports whether a snapshot still holds every product it was written with
+func (r *ProductRepository) snapshotComplete(ctx context.Context, snapshot *catalogSnapshot) (bool, error) {
+	count, err := r.redisClient.HLen(ctx, snapshotKey(snapshot.Version)).Result()
+	if err != nil {
+		return false, err
+	}
+	return int(count) == snapshot.ProductCount, nil
+}
+
+// snapshotProducts returns the products stored in a snapshot
+func (r *ProductRepository) snapshotProducts(ctx context.Context, version int64) ([]foodji.Product, error) {
+	values, err := r.redisClient.HVals(ctx, snapshotKey(version)).Result()
+	if err != nil {
+		return nil, err
+	}
+
+	products := make([]foodji.Product, len(values))
+	for i, value := range values {
+		if err := json.Unmarshal([]byte(value), &products[i]); err != nil {
+			return nil, fmt.Errorf("failed to unmarshal product: %w", err)
+		}
+	}
+	return products, nil
+}
+
+// writeSnapshot stores products under a new snapshot version. Nothing reads the snapshot
+// until switchSnapshot makes it current.
+func (r *ProductRepository) writeSnapshot(ctx context.Context, products []foodji.Product, sourceVersion string) (*catalogSnapshot, error) {
+	version, err := r.redisClient.Incr(ctx, ProductVersionKey).Result()
+	if err != nil {
+		return nil, fmt.Errorf("failed to allocate snapshot version: %w", err)
+	}
+
+	fields := make([]interface{}, 0, 2*len(products))
+	var soldOut []interface{}
+	for _, product := range products {
+		productJSON, err := json.Marshal(product)
+		if err != nil {
+			return nil, fmt.Errorf("failed to marshal product: %w", err)
+		}
+		fields = append(fields, product.ID.String(), productJSON)
+		if product.SoldOut() {
+			soldOut = append(soldOut, product.ID.String())
+		}
+	}
+
+	if len(fields) > 0 {
+		if err := r.redisClient.HSet(ctx, snapshotKey(version), fields...).Err(); err != nil {
+			return nil, fmt.Errorf("failed to write snapshot: %w", err)
+		}
+	}
+	if len(soldOut) > 0 {
+		if err := r.redisClient.SAdd(ctx, soldOutKey(version), soldOut...).Err(); err != nil {
+			return nil, fmt.Errorf("failed to write sold out products: %w", err)
+		}
+	}
+
+	return &catalogSnapshot{
+		Version:       version,
+		CreatedAt:     time.Now(),
+		ProductCount:  len(products),
+		SourceVersion: sourceVersion,
+	}, nil
+}
+
+// switchSnapshot atomically makes a snapshot current, together with any writes of apply.
+// The switch is fenced: it fails with domain.ErrStaleRefresh if a holder of a newer lease
+// token has already written the catalog.
+func (r *ProductRepository) switchSnapshot(ctx context.Context, token int64, snapshot *catalogSnapshot, apply func(pipe redis.Pipeliner)) error {
+	snapshotJSON, err := json.Marshal(snapshot)
+	if err != nil {
+		return fmt.Errorf("failed to marshal snapshot: %w", err)
+	}
+
+	err = r.redisClient.Watch(ctx, func(tx *redis.Tx) error {
+		written, err := tx.Get(ctx, ProductFenceKey).Int64()
+		if err != nil && err != redis.Nil {
+			return err
+		}
+		if written > token {
+			return domain.ErrStaleRefresh
+		}
+
+		_, err = tx.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+			pipe.HSet(ctx, ProductSnapshotsKey, strconv.FormatInt(snapshot.Version, 10), snapshotJSON)
+			pipe.Set(ctx, ProductCurrentSnapshotKey, snapshot.Version, 0)
+			pipe.Set(ctx, ProductFenceKey, token, 0)
+			if apply != nil {
+				apply(pipe)
+			}
+			return nil
+		})
+		return err
+	}, ProductFenceKey)
+	if err == redis.TxFailedErr {
+		return domain.ErrStaleRefresh
+	}
+	if err != nil {
+		return err
+	}
+
+	r.invalidateProducts(ctx, snapshot.Version)
+	return nil
+}
+
+// pruneSnapshots deletes all but the current snapshot and the snapshotRetention newest others;
+// failures are logged because the catalog has already been switched
+func (r *ProductRepository) pruneSnapshots(ctx context.Context) {
+	current, err := r.currentVersion(ctx)
+	if err != nil {
+		log.Printf("Failed to prune snapshots: %v", err)
+		return
+	}
+
+	fields, err := r.redisClient.HKeys(ctx, ProductSnapshotsKey).Result()
+	if err != nil {
+		log.Printf("Failed to prune snapshots: %v", err)
+		return
+	}
+
+	versions := make([]int64, 0, len(fields))
+	for _, field := range fields {
+		version, err := strconv.ParseInt(field, 10, 64)
+		if err != nil || version == current {
+			continue
+		}
+		versions = append(versions, version)
+	}
+	if len(versions) <= r.snapshotRetention {
+		return
+	}
+	sort.Slice(versions, func(i, j int) bool { return versions[i] > versions[j] })
+
+	pipe := r.redisClient.TxPipeline()
+	for _, version := range versions[r.snapshotRetention:] {
+		pipe.Del(ctx, snapshotKey(version), soldOutKey(version))
+		pipe.HDel(ctx, ProductSnapshotsKey, strconv.FormatInt(version, 10))
+	}
+	if _, err := pipe.Exec(ctx); err != nil {
+		log.Printf("Failed to prune snapshots: %v", err)
+	}
+}
+
+// ListSnapshots returns the retained catalog snapshots, newest first
+func (r *ProductRepository) ListSnapshots(ctx context.Context) ([]*domain.CatalogSnapshot, error) {
+	// Create a timeout context for the Redis operation
+	redisCtx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	current, err := r.currentVersion(redisCtx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get current snapshot from Redis: %w", err)
+	}
+	pinned, err := r.pinnedVersion(redisCtx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get pinned snapshot from Redis: %w", err)
+	}
+
+	values, err := r.redisClient.HVals(redisCtx, ProductSnapshotsKey).Result()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get snapshots from Redis: %w", err)
+	}
+
+	snapshots := make([]*domain.CatalogSnapshot, 0, len(values))
+	for _, value := range values {
+		var snapshot catalogSnapshot
+		if err := json.Unmarshal([]byte(value), &snapshot); err != nil {
+			return nil, fmt.Errorf("failed to unmarshal snapshot: %w", err)
+		}
+		snapshots = append(snapshots, snapshot.toDomain(current, pinned))
+	}
+	sort.Slice(snapshots, func(i, j int) bool { return snapshots[i].Version > snapshots[j].Version })
+
+	return snapshots, nil
+}
+
+// RollbackToSnapshot makes an earlier snapshot current and pins it. It takes the refresh lease,
+// so it returns domain.ErrRefreshInProgress while a refresh is running on any replica. Refreshes
+// keep the pinned snapshot until UnpinSnapshot is called or a refresh is forced.
+func (r *ProductRepository) RollbackToSnapshot(ctx context.Context, version int64) (*domain.CatalogSnapshot, error) {
+	lease, err := r.acquireLease(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer lease.release()
+
+	// Create a timeout context for the Redis operations
+	redisCtx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	snapshot, err := r.loadSnapshot(redisCtx, version)
+	if err != nil {
+		return nil, err
+	}
+
+	pin := func(pipe redis.Pipeliner) {
+		pipe.Set(redisCtx, ProductPinnedSnapshotKey, version, 0)
+	}
+	if err := r.switchSnapshot(redisCtx, lease.token, snapshot, pin); err != nil {
+		return nil, fmt.Errorf("failed to switch snapshot: %w", err)
+	}
+	log.Printf("Rolled the catalog back to snapshot %d", version)
+
+	r.publishCatalogUpdated(redisCtx, snapshot.ProductCount)
+	if r.history != nil {
+		products, err := r.snapshotProducts(redisCtx, version)
+		if err != nil {
+			log.Printf("Failed to record catalog history: %v", err)
+		} else {
+			r.recordHistory(redisCtx, products)
+		}
+	}
+	return snapshot.toDomain(version, version), nil
+}
+
+// UnpinSnapshot lets refreshes switch away from the snapshot a rollback pinned again
+func (r *ProductRepository) UnpinSnapshot(ctx context.Context) error {
+	// Create a timeout context for the Redis operation
+	redisCtx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	if err := r.redisClient.Del(redisCtx, ProductPinnedSnapshotKey).Err(); err != nil {
+		return fmt.Errorf("failed to unpin snapshot in Redis: %w", err)
+	}
+	log.Println("Unpinned the catalog snapshot")
+	return nil
+}
+
+// GetCatalogVersion returns the version of the snapshot products are served from, or 0 if there is none yet
+func (r *ProductRepository) GetCatalogVersion(ctx context.Context) (int64, error) {
+	// Create a timeout context for the Redis operation
+	redisCtx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	version, err := r.currentVersion(redisCtx)
+	if err != nil {
+		return 0, fmt.Errorf("failed to get current snapshot from Redis: %w", err)
+	}
+	return version, nil
+}
+
+// GetCatalog returns all products of the current snapshot together with its version
+func (r *ProductRepository) GetCatalog(ctx context.Context) (int64, []foodji.Product, error) {
+	// Create a timeout context for the Redis operations
+	redisCtx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	version, err := r.currentVersion(redisCtx)
+	if err != nil {
+		return 0, nil, fmt.Errorf("failed to get current snapshot from Redis: %w", err)
+	}
+
+	products, err := r.snapshotProducts(redisCtx, version)
+	if err != nil {
+		return 0, nil, fmt.Errorf("failed to get products from Redis: %w", err)
+	}
+	return version, products, nil
+}
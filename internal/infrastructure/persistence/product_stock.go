@@ -0,0 +1,186 @@
+package persistence
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"slices"
+	"strconv"
+	"time"
+
+	"github.com/ArtemSind/food_tinder/internal/infrastructure/external/catalog"
+	"github.com/ArtemSind/food_tinder/internal/infrastructure/external/foodji"
+	"github.com/go-redis/redis/v8"
+)
+
+const (
+	// ProductStockRevisionKey is the counter every stock update of the current snapshot increments
+	ProductStockRevisionKey = "products:stock_revision"
+	// ProductStockPollKey is held by the replica that polls stock in the current interval
+	ProductStockPollKey = "products:stock_poll"
+	// DefaultStockPollInterval is how often stock is polled between catalog refreshes. Every poll
+	// is a request to Foodji, and every stock change empties the product caches, so the default
+	// keeps to 96 requests a day.
+	DefaultStockPollInterval = 15 * time.Minute
+)
+
+// pollStock updates the stock of the current snapshot every interval, so products that sell out
+// leave the deck long before the next catalog refresh
+func (r *ProductRepository) pollStock(ctx context.Context, interval time.Duration) {
+	defer r.wg.Done()
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			pollCtx, cancel := context.WithTimeout(ctx, UpdateContextTimeout)
+			if err := r.updateStock(pollCtx, interval); err != nil {
+				log.Printf("Failed to update stock: %v", err)
+			}
+			cancel()
+		case <-ctx.Done():
+			return
+		}
+	}
+}
+
+// updateStock fetches the source and copies the slots of its products into the current
+// snapshot. Products the snapshot does not have, and any other change to its products, wait for
+// the next refresh. Only one replica polls per interval, and none while this replica refreshes.
+func (r *ProductRepository) updateStock(ctx context.Context, interval time.Duration) error {
+	if r.refreshing.Load() {
+		return nil
+	}
+	polling, err := r.redisClient.SetNX(ctx, ProductStockPollKey, r.replicaID, interval).Result()
+	if err != nil || !polling {
+		return err
+	}
+
+	current, err := r.currentSnapshot(ctx)
+	if err != nil || current == nil {
+		return err
+	}
+
+	// Stock is fetched conditionally on the payload it was last updated from
+	sourceVersion := current.StockSourceVersion
+	if sourceVersion == "" {
+		sourceVersion = current.SourceVersion
+	}
+	result, err := r.source.Fetch(ctx, sourceVersion)
+	if errors.Is(err, catalog.ErrNotModified) && sourceVersion != "" {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("failed to fetch stock from %s: %w", r.source.Name(), err)
+	}
+
+	slots := make(map[string][]foodji.Slot, len(result.Products))
+	for _, product := range result.Products {
+		slots[product.ID.String()] = product.Slots
+	}
+
+	updated, err := r.writeStock(ctx, current, slots, result.Version)
+	if err != nil {
+		return err
+	}
+	if updated > 0 {
+		log.Printf("Updated the stock of %d products in snapshot %d", updated, current.Version)
+		r.invalidateProducts(ctx, current.Version)
+	}
+	return nil
+}
+
+// writeStock replaces the slots of the snapshot's products and its sold out products in one
+// transaction, and returns how many products changed. Nothing is written if another snapshot
+// was switched to meanwhile, as its stock is newer.
+func (r *ProductRepository) writeStock(ctx context.Context, snapshot *catalogSnapshot, slots map[string][]foodji.Slot, sourceVersion string) (int, error) {
+	key := snapshotKey(snapshot.Version)
+	updated := 0
+
+	err := r.redisClient.Watch(ctx, func(tx *redis.Tx) error {
+		version, err := tx.Get(ctx, ProductCurrentSnapshotKey).Int64()
+		if err != nil {
+			return err
+		}
+		if version != snapshot.Version {
+			return nil
+		}
+
+		values, err := tx.HGetAll(ctx, key).Result()
+		if err != nil {
+			return err
+		}
+
+		var fields, soldOut []interface{}
+		for id, value := range values {
+			var product foodji.Product
+			if err := json.Unmarshal([]byte(value), &product); err != nil {
+				return fmt.Errorf("failed to unmarshal product: %w", err)
+			}
+			if productSlots, ok := slots[id]; ok && !slices.Equal(productSlots, product.Slots) {
+				product.Slots = productSlots
+				productJSON, err := json.Marshal(product)
+				if err != nil {
+					return fmt.Errorf("failed to marshal product: %w", err)
+				}
+				fields = append(fields, id, productJSON)
+			}
+			if product.SoldOut() {
+				soldOut = append(soldOut, id)
+			}
+		}
+
+		now := time.Now()
+		snapshot.StockSourceVersion = sourceVersion
+		snapshot.StockUpdatedAt = &now
+		snapshotJSON, err := json.Marshal(snapshot)
+		if err != nil {
+			return fmt.Errorf("failed to marshal snapshot: %w", err)
+		}
+
+		_, err = tx.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+			pipe.HSet(ctx, ProductSnapshotsKey, strconv.FormatInt(snapshot.Version, 10), snapshotJSON)
+			if len(fields) > 0 {
+				pipe.HSet(ctx, key, fields...)
+				pipe.Del(ctx, soldOutKey(snapshot.Version))
+				if len(soldOut) > 0 {
+					pipe.SAdd(ctx, soldOutKey(snapshot.Version), soldOut...)
+				}
+				pipe.Incr(ctx, ProductStockRevisionKey)
+			}
+			return nil
+		})
+		if err == nil {
+			updated = len(fields) / 2
+		}
+		return err
+	}, ProductCurrentSnapshotKey, key)
+	if err == redis.TxFailedErr {
+		return 0, nil
+	}
+	if err != nil {
+		return 0, fmt.Errorf("failed to write stock of snapshot %d: %w", snapshot.Version, err)
+	}
+	return updated, nil
+}
+
+// GetStockRevision returns how often stock was updated between snapshot switches, so readers
+// that keep the catalog notice new stock
+func (r *ProductRepository) GetStockRevision(ctx context.Context) (int64, error) {
+	// Create a timeout context for the Redis operation
+	redisCtx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	revision, err := r.redisClient.Get(redisCtx, ProductStockRevisionKey).Int64()
+	if err == redis.Nil {
+		return 0, nil
+	}
+	if err != nil {
+		return 0, fmt.Errorf("failed to get stock revision from Redis: %w", err)
+	}
+	return revision, nil
+}
@@ -0,0 +1,91 @@
+package persistence_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/ArtemSind/food_tinder/internal/infrastructure/external/foodji"
+	"github.com/ArtemSind/food_tinder/internal/infrastructure/external/foodji/fake"
+	"github.com/ArtemSind/food_tinder/internal/infrastructure/persistence"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestProductRepository_StockPoll(t *testing.T) {
+	t.Run("stock is updated between refreshes", func(t *testing.T) {
+		// Arrange
+		t.Setenv("STOCK_POLL_INTERVAL", "10ms")
+		f := newProductRepositoryFixture(t)
+		ctx := context.Background()
+		repo := f.newRepository(t)
+		f.waitForRequests(t, 1)
+		waitForIdle(t, repo)
+
+		_, err := repo.GetProduct(ctx, cachedSaladID)
+		require.NoError(t, err)
+
+		// The salad sells out and a product is added, which only a refresh brings in
+		products := fake.DefaultFixture().Machines[persistence.DefaultMachineID]
+		for i := range products {
+			if products[i].ID == cachedSaladID {
+				products[i].Slots = []foodji.Slot{{Number: 1, Quantity: 0}}
+			}
+		}
+		added := uuid.New()
+		products = append(products, foodji.Product{ID: added, Name: "Lentil Soup"})
+		f.foodji.SetProducts(persistence.DefaultMachineID, products)
+
+		// Act
+		require.Eventually(t, func() bool {
+			// Another poll may start once the previous one's interval expired
+			f.redis.FastForward(time.Second)
+			product, err := repo.GetProduct(ctx, cachedSaladID)
+			return err == nil && product.SoldOut()
+		}, time.Second, 10*time.Millisecond, "the cached salad should be invalidated and sold out")
+
+		// Assert
+		soldOut, err := repo.SoldOutProducts(ctx)
+		require.NoError(t, err)
+		assert.Contains(t, soldOut, cachedSaladID)
+
+		version, err := repo.GetCatalogVersion(ctx)
+		require.NoError(t, err)
+		assert.Equal(t, int64(1), version, "stock is updated in the current snapshot")
+
+		ids, err := repo.ListProducts(ctx)
+		require.NoError(t, err)
+		assert.NotContains(t, ids, added)
+
+		revision, err := repo.GetStockRevision(ctx)
+		require.NoError(t, err)
+		assert.Positive(t, revision)
+
+		snapshots, err := repo.ListSnapshots(ctx)
+		require.NoError(t, err)
+		require.Len(t, snapshots, 1)
+		assert.NotNil(t, snapshots[0].StockUpdatedAt)
+	})
+
+	t.Run("unchanged stock is not written", func(t *testing.T) {
+		// Arrange
+		t.Setenv("STOCK_POLL_INTERVAL", "10ms")
+		f := newProductRepositoryFixture(t)
+		ctx := context.Background()
+		repo := f.newRepository(t)
+		f.waitForRequests(t, 1)
+		waitForIdle(t, repo)
+
+		// Act
+		require.Eventually(t, func() bool {
+			f.redis.FastForward(time.Second)
+			return f.foodji.Requests() >= 3
+		}, time.Second, 10*time.Millisecond, "stock should be polled")
+
+		// Assert
+		revision, err := repo.GetStockRevision(ctx)
+		require.NoError(t, err)
+		assert.Zero(t, revision)
+	})
+}
@@ -0,0 +1,143 @@
+package persistence
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"os"
+	"strconv"
+	"time"
+
+	"github.com/ArtemSind/food_tinder/internal/domain"
+	"github.com/go-redis/redis/v8"
+	"github.com/google/uuid"
+)
+
+const (
+	// RefreshLeaseKey holds the replica that may refresh the catalog and its fencing token
+	RefreshLeaseKey = "products:refresh:lease"
+	// RefreshTokenKey is the counter fencing tokens are issued from
+	RefreshTokenKey = "products:refresh:token"
+	// ProductFenceKey stores the fencing token of the refresh that last wrote the catalog
+	ProductFenceKey = "products:fence"
+	// RefreshLeaseTTL is how long a lease lasts without renewal; the holder renews it every third of that
+	RefreshLeaseTTL = 30 * time.Second
+)
+
+var (
+	// acquireLeaseScript takes the lease if it is free and returns a new fencing token, or 0
+	acquireLeaseScript = redis.NewScript(`
+if redis.call("EXISTS", KEYS[1]) == 1 then
+	return 0
+end
+local token = redis.call("INCR", KEYS[2])
+redis.call("SET", KEYS[1], ARGV[1] .. ":" .. token, "PX", ARGV[2])
+return token
+`)
+
+	// renewLeaseScript extends the lease if it is still held with the given value
+	renewLeaseScript = redis.NewScript(`
+if redis.call("GET", KEYS[1]) == ARGV[1] then
+	return redis.call("PEXPIRE", KEYS[1], ARGV[2])
+end
+return 0
+`)
+
+	// releaseLeaseScript deletes the lease if it is still held with the given value
+	releaseLeaseScript = redis.NewScript(`
+if redis.call("GET", KEYS[1]) == ARGV[1] then
+	return redis.call("DEL", KEYS[1])
+end
+return 0
+`)
+)
+
+// refreshLease is a Redis lease that lets one replica refresh the catalog at a time.
+// Every lease carries a fencing token that is higher than the tokens of all earlier leases.
+type refreshLease struct {
+	client *redis.Client
+	value  string
+	token  int64
+}
+
+// replicaID identifies this process as a lease holder
+func replicaID() string {
+	hostname, err := os.Hostname()
+	if err != nil {
+		hostname = "unknown"
+	}
+	return fmt.Sprintf("%s-%s", hostname, uuid.NewString()[:8])
+}
+
+// acquireLease takes the refresh lease for this replica.
+// It returns domain.ErrRefreshInProgress if another replica holds it.
+func (r *ProductRepository) acquireLease(ctx context.Context) (*refreshLease, error) {
+	// Create a timeout context for the Redis operation
+	redisCtx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	token, err := acquireLeaseScript.Run(redisCtx, r.redisClient,
+		[]string{RefreshLeaseKey, RefreshTokenKey},
+		r.replicaID, RefreshLeaseTTL.Milliseconds()).Int64()
+	if err != nil {
+		return nil, fmt.Errorf("failed to acquire refresh lease: %w", err)
+	}
+	if token == 0 {
+		return nil, domain.ErrRefreshInProgress
+	}
+
+	return &refreshLease{
+		client: r.redisClient,
+		value:  r.replicaID + ":" + strconv.FormatInt(token, 10),
+		token:  token,
+	}, nil
+}
+
+// renew extends the lease and reports whether it is still held
+func (l *refreshLease) renew(ctx context.Context) (bool, error) {
+	redisCtx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	renewed, err := renewLeaseScript.Run(redisCtx, l.client,
+		[]string{RefreshLeaseKey}, l.value, RefreshLeaseTTL.Milliseconds()).Int64()
+	if err != nil {
+		return false, err
+	}
+	return renewed == 1, nil
+}
+
+// keepAlive renews the lease until ctx is done and calls lost if another replica took it over
+func (l *refreshLease) keepAlive(ctx context.Context, lost func()) {
+	ticker := time.NewTicker(RefreshLeaseTTL / 3)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			held, err := l.renew(ctx)
+			if err != nil {
+				// The fencing token still protects the catalog if the lease expires meanwhile
+				log.Printf("Failed to renew refresh lease: %v", err)
+				continue
+			}
+			if !held {
+				log.Println("Lost the refresh lease, stopping the refresh")
+				lost()
+				return
+			}
+		case <-ctx.Done():
+			return
+		}
+	}
+}
+
+// release hands the lease over to other replicas. It uses its own timeout so the lease
+// is released even when the refresh was cancelled by a shutdown.
+func (l *refreshLease) release() {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	if err := releaseLeaseScript.Run(ctx, l.client, []string{RefreshLeaseKey}, l.value).Err(); err != nil {
+		log.Printf("Failed to release refresh lease: %v", err)
+	}
+}
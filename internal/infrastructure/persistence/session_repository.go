@@ -2,10 +2,12 @@ package persistence
 
 import (
 	"context"
+	"errors"
 
 	"github.com/ArtemSind/food_tinder/internal/domain"
 	"github.com/ArtemSind/food_tinder/internal/infrastructure/persistence/models"
 	"github.com/google/uuid"
+	"github.com/jackc/pgx/v4"
 	"github.com/jackc/pgx/v4/pgxpool"
 )
 
@@ -17,21 +19,89 @@ func NewSessionRepository(db *pgxpool.Pool) *SessionRepository {
 	return &SessionRepository{db: db}
 }
 
+// Create stores the session together with its experiment assignment, if any
 func (r *SessionRepository) Create(ctx context.Context, session *domain.Session) error {
 	dbSession := models.SessionFromDomain(session)
-	_, err := r.db.Exec(ctx,
-		"INSERT INTO sessions (id, created_at) VALUES ($1, $2)",
-		dbSession.ID, dbSession.CreatedAt)
-	return err
+
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback(ctx)
+
+	if _, err := tx.Exec(ctx,
+		"INSERT INTO sessions (id, deck_strategy, excluded_allergens, diets, locale, created_at) VALUES ($1, $2, $3, $4, $5, $6)",
+		dbSession.ID, dbSession.DeckStrategy, dbSession.ExcludedAllergens, dbSession.Diets, dbSession.Locale, dbSession.CreatedAt); err != nil {
+		return err
+	}
+
+	if dbSession.ExperimentID != "" {
+		if _, err := tx.Exec(ctx,
+			"INSERT INTO experiment_assignments (session_id, experiment_id, variant, assigned_at) VALUES ($1, $2, $3, $4)",
+			dbSession.ID, dbSession.ExperimentID, dbSession.Variant, dbSession.CreatedAt); err != nil {
+			return err
+		}
+	}
+
+	return tx.Commit(ctx)
 }
 
 func (r *SessionRepository) GetByID(ctx context.Context, id uuid.UUID) (*domain.Session, error) {
 	var dbSession models.SessionDB
 	err := r.db.QueryRow(ctx,
-		"SELECT id, created_at FROM sessions WHERE id = $1", id).
-		Scan(&dbSession.ID, &dbSession.CreatedAt)
+		`SELECT s.id, s.deck_strategy, s.created_at,
+			COALESCE(a.experiment_id::text, ''), COALESCE(a.variant, ''),
+			s.excluded_allergens, s.diets, s.locale
+		FROM sessions s
+		LEFT JOIN experiment_assignments a ON a.session_id = s.id
+		WHERE s.id = $1`, id).
+		Scan(&dbSession.ID, &dbSession.DeckStrategy, &dbSession.CreatedAt, &dbSession.ExperimentID, &dbSession.Variant,
+			&dbSession.ExcludedAllergens, &dbSession.Diets, &dbSession.Locale)
 	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, domain.ErrSessionNotFound
+		}
 		return nil, err
 	}
 	return dbSession.ToDomain(), nil
 }
+
+func (r *SessionRepository) UpdateDeckStrategy(ctx context.Context, id uuid.UUID, strategy domain.DeckStrategy) error {
+	tag, err := r.db.Exec(ctx,
+		"UPDATE sessions SET deck_strategy = $1 WHERE id = $2",
+		string(strategy), id)
+	if err != nil {
+		return err
+	}
+	if tag.RowsAffected() == 0 {
+		return domain.ErrSessionNotFound
+	}
+	return nil
+}
+
+func (r *SessionRepository) UpdateDietaryPreferences(ctx context.Context, id uuid.UUID, preferences domain.DietaryPreferences) error {
+	dbSession := models.SessionFromDomain(&domain.Session{ID: id, Dietary: preferences})
+	tag, err := r.db.Exec(ctx,
+		"UPDATE sessions SET excluded_allergens = $1, diets = $2 WHERE id = $3",
+		dbSession.ExcludedAllergens, dbSession.Diets, id)
+	if err != nil {
+		return err
+	}
+	if tag.RowsAffected() == 0 {
+		return domain.ErrSessionNotFound
+	}
+	return nil
+}
+
+func (r *SessionRepository) UpdateLocale(ctx context.Context, id uuid.UUID, locale domain.Locale) error {
+	tag, err := r.db.Exec(ctx,
+		"UPDATE sessions SET locale = $1 WHERE id = $2",
+		string(locale), id)
+	if err != nil {
+		return err
+	}
+	if tag.RowsAffected() == 0 {
+		return domain.ErrSessionNotFound
+	}
+	return nil
+}
@@ -0,0 +1,100 @@
+package persistence
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/ArtemSind/food_tinder/internal/domain"
+	"github.com/go-redis/redis/v8"
+	"github.com/google/uuid"
+)
+
+const (
+	// SimilarProductsKeyPrefix is the prefix for similar product lists in Redis
+	SimilarProductsKeyPrefix = "similar:"
+	// SimilarProductsIndexKey is the set of products that have a cached list
+	SimilarProductsIndexKey = "similar_products"
+	// SimilarProductsPerProduct is the number of neighbours cached per product; it is well above
+	// the lists requested, so lists still fill up once neighbours outside the catalog are dropped
+	SimilarProductsPerProduct = 100
+	// SimilarProductsCacheTimeout lets cached lists expire if recomputation stops
+	SimilarProductsCacheTimeout = 2 * time.Hour
+)
+
+// SimilarityCache stores the nearest neighbours of every product in Redis
+// so that all replicas serve the same lists between recomputations
+type SimilarityCache struct {
+	redisClient *redis.Client
+}
+
+func NewSimilarityCache(redisClient *redis.Client) *SimilarityCache {
+	return &SimilarityCache{redisClient: redisClient}
+}
+
+// Store writes the top neighbours of the products that have any in the model, and removes the
+// lists of every other product cached before, such as products that left the catalog
+func (c *SimilarityCache) Store(ctx context.Context, model *domain.SimilarityModel, products []uuid.UUID) error {
+	cached, err := c.redisClient.SMembers(ctx, SimilarProductsIndexKey).Result()
+	if err != nil {
+		return fmt.Errorf("failed to list cached similar products: %w", err)
+	}
+
+	pipe := c.redisClient.TxPipeline()
+	stored := make(map[string]bool, len(products))
+	index := make([]interface{}, 0, len(products))
+	for _, productID := range products {
+		neighbours := model.Neighbours(productID)
+		if len(neighbours) == 0 {
+			continue
+		}
+		if len(neighbours) > SimilarProductsPerProduct {
+			neighbours = neighbours[:SimilarProductsPerProduct]
+		}
+
+		data, err := json.Marshal(neighbours)
+		if err != nil {
+			return fmt.Errorf("failed to marshal similar products: %w", err)
+		}
+		pipe.Set(ctx, SimilarProductsKeyPrefix+productID.String(), data, SimilarProductsCacheTimeout)
+		stored[productID.String()] = true
+		index = append(index, productID.String())
+	}
+
+	for _, id := range cached {
+		if !stored[id] {
+			pipe.Del(ctx, SimilarProductsKeyPrefix+id)
+		}
+	}
+	pipe.Del(ctx, SimilarProductsIndexKey)
+	if len(index) > 0 {
+		pipe.SAdd(ctx, SimilarProductsIndexKey, index...)
+		pipe.Expire(ctx, SimilarProductsIndexKey, SimilarProductsCacheTimeout)
+	}
+
+	if _, err := pipe.Exec(ctx); err != nil {
+		return fmt.Errorf("failed to store similar products in Redis: %w", err)
+	}
+	return nil
+}
+
+// Get returns the cached neighbours of a product; found is false on a cache miss
+func (c *SimilarityCache) Get(ctx context.Context, productID uuid.UUID) ([]domain.SimilarProduct, bool, error) {
+	redisCtx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	data, err := c.redisClient.Get(redisCtx, SimilarProductsKeyPrefix+productID.String()).Bytes()
+	if err != nil {
+		if err == redis.Nil {
+			return nil, false, nil
+		}
+		return nil, false, fmt.Errorf("failed to get similar products from Redis: %w", err)
+	}
+
+	var neighbours []domain.SimilarProduct
+	if err := json.Unmarshal(data, &neighbours); err != nil {
+		return nil, false, fmt.Errorf("failed to unmarshal similar products: %w", err)
+	}
+	return neighbours, true, nil
+}
@@ -0,0 +1,49 @@
+package persistence_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/ArtemSind/food_tinder/internal/domain"
+	"github.com/ArtemSind/food_tinder/internal/infrastructure/persistence"
+	"github.com/alicebob/miniredis/v2"
+	"github.com/go-redis/redis/v8"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSimilarityCache_Store(t *testing.T) {
+	// Arrange
+	ctx := context.Background()
+	mr := miniredis.RunT(t)
+	redisClient := redis.NewClient(&redis.Options{Addr: mr.Addr()})
+	t.Cleanup(func() { redisClient.Close() })
+	cache := persistence.NewSimilarityCache(redisClient)
+
+	pizza, pasta, salad := uuid.New(), uuid.New(), uuid.New()
+	newVote := func(sessionID, productID uuid.UUID, score int) *domain.Vote {
+		vote, _ := domain.NewVote(sessionID, productID, score)
+		return vote
+	}
+	s1, s2 := uuid.New(), uuid.New()
+	model := domain.ComputeItemSimilarities([]*domain.Vote{
+		newVote(s1, pizza, 5), newVote(s1, pasta, 5), newVote(s1, salad, 1),
+		newVote(s2, pizza, 4), newVote(s2, pasta, 5), newVote(s2, salad, 2),
+	}, domain.MinCoRatings)
+	require.NoError(t, cache.Store(ctx, model, []uuid.UUID{pizza, pasta, salad}))
+
+	// Act: the salad left the catalog
+	err := cache.Store(ctx, model, []uuid.UUID{pizza, pasta})
+
+	// Assert
+	require.NoError(t, err)
+	_, found, err := cache.Get(ctx, pizza)
+	require.NoError(t, err)
+	assert.True(t, found)
+
+	_, found, err = cache.Get(ctx, salad)
+	require.NoError(t, err)
+	assert.False(t, found, "the list of a product that left the catalog is removed")
+	assert.False(t, mr.Exists(persistence.SimilarProductsKeyPrefix+salad.String()))
+}
@@ -2,7 +2,9 @@ package persistence
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
+	"time"
 
 	"github.com/ArtemSind/food_tinder/internal/domain"
 	"github.com/ArtemSind/food_tinder/internal/infrastructure/persistence/models"
@@ -18,26 +20,87 @@ func NewVoteRepository(db *pgxpool.Pool) *VoteRepository {
 	return &VoteRepository{db: db}
 }
 
+// voteColumns selects a vote and whether the product changed since it was rated
+const voteColumns = `v.id, v.session_id, v.product_id, v.score, v.created_at, v.updated_at, v.product_version,
+	COALESCE(v.product_version <> p.content_hash, FALSE) AS outdated`
+
+// currentProductVersion is the content hash of the product as last seen in the catalog
+const currentProductVersion = "(SELECT content_hash FROM products WHERE id = $3)"
+
+// Create stores the vote, on the current version of the product, and its vote.created
+// outbox event in one transaction
 func (r *VoteRepository) Create(ctx context.Context, vote *domain.Vote) error {
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback(ctx)
+
 	dbVote := models.VoteFromDomain(vote)
-	_, err := r.db.Exec(ctx,
-		"INSERT INTO votes (id, session_id, product_id, score, created_at) VALUES ($1, $2, $3, $4, $5)",
-		dbVote.ID, dbVote.SessionID, dbVote.ProductID, dbVote.Score, dbVote.CreatedAt)
-	return err
+	if err := tx.QueryRow(ctx,
+		`INSERT INTO votes (id, session_id, product_id, score, created_at, updated_at, product_version)
+		VALUES ($1, $2, $3, $4, $5, $6, `+currentProductVersion+`)
+		RETURNING product_version`,
+		dbVote.ID, dbVote.SessionID, dbVote.ProductID, dbVote.Score, dbVote.CreatedAt, dbVote.UpdatedAt).Scan(&dbVote.ProductVersion); err != nil {
+		return err
+	}
+	setProductVersion(vote, dbVote)
+
+	event, err := domain.NewOutboxEvent(domain.EventVoteCreated, vote)
+	if err != nil {
+		return err
+	}
+	if err := insertOutboxEvent(ctx, tx, event); err != nil {
+		return err
+	}
+
+	return tx.Commit(ctx)
 }
 
+// Update changes the vote score and when it changed, and records a vote.updated outbox event in one transaction.
+// The vote moves to the current version of the product, so it is no longer outdated.
 func (r *VoteRepository) Update(ctx context.Context, vote *domain.Vote) error {
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback(ctx)
+
 	dbVote := models.VoteFromDomain(vote)
-	_, err := r.db.Exec(ctx,
-		"UPDATE votes SET score = $1 WHERE session_id = $2 AND product_id = $3",
-		dbVote.Score, dbVote.SessionID, dbVote.ProductID)
-	return err
+	if err := tx.QueryRow(ctx,
+		`UPDATE votes SET score = $1, updated_at = $4, product_version = `+currentProductVersion+`
+		WHERE session_id = $2 AND product_id = $3
+		RETURNING product_version`,
+		dbVote.Score, dbVote.SessionID, dbVote.ProductID, dbVote.UpdatedAt).Scan(&dbVote.ProductVersion); err != nil {
+		return err
+	}
+	setProductVersion(vote, dbVote)
+
+	event, err := domain.NewOutboxEvent(domain.EventVoteUpdated, vote)
+	if err != nil {
+		return err
+	}
+	if err := insertOutboxEvent(ctx, tx, event); err != nil {
+		return err
+	}
+
+	return tx.Commit(ctx)
+}
+
+// setProductVersion copies the product version a vote was stored with to the domain vote
+func setProductVersion(vote *domain.Vote, dbVote *models.VoteDB) {
+	vote.ProductVersion = ""
+	if dbVote.ProductVersion != nil {
+		vote.ProductVersion = *dbVote.ProductVersion
+	}
+	vote.Outdated = false
 }
 
 func (r *VoteRepository) GetBySessionID(ctx context.Context, sessionID uuid.UUID) ([]*domain.Vote, error) {
 	rows, err := r.db.Query(ctx,
-		`SELECT v.id, v.session_id, v.product_id, v.score, v.created_at
+		`SELECT `+voteColumns+`
 		FROM votes v
+		LEFT JOIN products p ON p.id = v.product_id
 		WHERE v.session_id = $1`, sessionID)
 	if err != nil {
 		fmt.Println(err)
@@ -48,7 +111,8 @@ func (r *VoteRepository) GetBySessionID(ctx context.Context, sessionID uuid.UUID
 	var dbVotes []*models.VoteDB
 	for rows.Next() {
 		var dbVote models.VoteDB
-		if err := rows.Scan(&dbVote.ID, &dbVote.SessionID, &dbVote.ProductID, &dbVote.Score, &dbVote.CreatedAt); err != nil {
+		if err := rows.Scan(&dbVote.ID, &dbVote.SessionID, &dbVote.ProductID, &dbVote.Score, &dbVote.CreatedAt,
+			&dbVote.UpdatedAt, &dbVote.ProductVersion, &dbVote.Outdated); err != nil {
 			return nil, err
 		}
 		dbVotes = append(dbVotes, &dbVote)
@@ -61,15 +125,102 @@ func (r *VoteRepository) GetBySessionID(ctx context.Context, sessionID uuid.UUID
 	return models.VotesToDomain(dbVotes), nil
 }
 
+// GetAll returns every vote, i.e. the full session×product rating matrix
+func (r *VoteRepository) GetAll(ctx context.Context) ([]*domain.Vote, error) {
+	rows, err := r.db.Query(ctx,
+		`SELECT `+voteColumns+`
+		FROM votes v
+		LEFT JOIN products p ON p.id = v.product_id`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var dbVotes []*models.VoteDB
+	for rows.Next() {
+		var dbVote models.VoteDB
+		if err := rows.Scan(&dbVote.ID, &dbVote.SessionID, &dbVote.ProductID, &dbVote.Score, &dbVote.CreatedAt,
+			&dbVote.UpdatedAt, &dbVote.ProductVersion, &dbVote.Outdated); err != nil {
+			return nil, err
+		}
+		dbVotes = append(dbVotes, &dbVote)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return models.VotesToDomain(dbVotes), nil
+}
+
+// GetAggregatedScores returns the vote totals of every product that was voted on, together
+// with the number of distinct sessions that saw and skipped it and the product
+// as last seen in the catalog, which may since have been discontinued. Votes are also split by
+// the product version they were cast on, and every score carries the last recorded price of
+// its product. Votes and impressions of product aliases count for the product they stand for.
 func (r *VoteRepository) GetAggregatedScores(ctx context.Context) ([]*domain.ProductScore, error) {
 	rows, err := r.db.Query(ctx,
-		`SELECT 
-			product_id, 
-			AVG(score) as avg_score, 
-			COUNT(id) as vote_count
-		FROM votes
-		GROUP BY product_id
-		ORDER BY avg_score DESC`)
+		`WITH scores AS (
+			SELECT 
+				COALESCE(a.product_id, votes.product_id) AS product_id, 
+				AVG(score) as avg_score, 
+				COUNT(id) as vote_count
+			FROM votes
+			LEFT JOIN product_aliases a ON a.alias_id = votes.product_id
+			GROUP BY 1
+		), version_scores AS (
+			SELECT
+				product_id,
+				json_agg(json_build_object(
+					'version', product_version,
+					'avg_score', avg_score,
+					'vote_count', vote_count
+				) ORDER BY first_vote_at) AS versions
+			FROM (
+				SELECT
+					COALESCE(a.product_id, votes.product_id) AS product_id,
+					COALESCE(product_version, '') AS product_version,
+					AVG(score)::float8 AS avg_score,
+					COUNT(id) AS vote_count,
+					MIN(votes.created_at) AS first_vote_at
+				FROM votes
+				LEFT JOIN product_aliases a ON a.alias_id = votes.product_id
+				GROUP BY 1, 2
+			) versions
+			GROUP BY product_id
+		), views AS (
+			SELECT
+				COALESCE(a.product_id, impressions.product_id) AS product_id,
+				COUNT(DISTINCT session_id) AS views,
+				COUNT(DISTINCT session_id) FILTER (WHERE action = 'skip') AS skips
+			FROM impressions
+			LEFT JOIN product_aliases a ON a.alias_id = impressions.product_id
+			GROUP BY 1
+		)
+		SELECT
+			s.product_id,
+			s.avg_score::float8,
+			s.vote_count,
+			COALESCE(v.views, 0) AS views,
+			COALESCE(v.skips, 0) AS skips,
+			vs.versions,
+			p.content_hash,
+			p.data,
+			p.first_seen_at,
+			p.last_seen_at,
+			p.discontinued_at,
+			price.price::float8
+		FROM scores s
+		LEFT JOIN views v ON v.product_id = s.product_id
+		LEFT JOIN version_scores vs ON vs.product_id = s.product_id
+		LEFT JOIN products p ON p.id = s.product_id
+		LEFT JOIN LATERAL (
+			SELECT pp.price FROM product_prices pp
+			WHERE pp.product_id = p.id
+			ORDER BY pp.recorded_at DESC
+			LIMIT 1
+		) price ON true
+		ORDER BY s.avg_score DESC`)
 	if err != nil {
 		return nil, err
 	}
@@ -78,9 +229,29 @@ func (r *VoteRepository) GetAggregatedScores(ctx context.Context) ([]*domain.Pro
 	var scores []*domain.ProductScore
 	for rows.Next() {
 		score := &domain.ProductScore{}
-		if err := rows.Scan(&score.ProductID, &score.AvgScore, &score.VoteCount); err != nil {
+		var contentHash *string
+		var dbProduct models.ProductDB
+		var firstSeenAt, lastSeenAt *time.Time
+		var versions []byte
+		if err := rows.Scan(&score.ProductID, &score.AvgScore, &score.VoteCount, &score.Views, &score.Skips,
+			&versions, &contentHash, &dbProduct.Data, &firstSeenAt, &lastSeenAt, &dbProduct.DiscontinuedAt, &score.Price); err != nil {
 			return nil, err
 		}
+		if versions != nil {
+			if err := json.Unmarshal(versions, &score.Versions); err != nil {
+				return nil, fmt.Errorf("failed to unmarshal version scores: %w", err)
+			}
+		}
+		for i := range score.Versions {
+			score.Versions[i].Current = contentHash != nil && score.Versions[i].Version == *contentHash
+		}
+		if contentHash != nil {
+			dbProduct.ID = score.ProductID
+			dbProduct.ContentHash = *contentHash
+			dbProduct.FirstSeenAt = *firstSeenAt
+			dbProduct.LastSeenAt = *lastSeenAt
+			score.Product = dbProduct.ToDomain()
+		}
 		scores = append(scores, score)
 	}
 
@@ -90,3 +261,42 @@ func (r *VoteRepository) GetAggregatedScores(ctx context.Context) ([]*domain.Pro
 
 	return scores, nil
 }
+
+// GetUnvotedViews returns the number of distinct sessions that saw and skipped each product
+// that was shown but never voted on, most viewed first. Impressions of product aliases count
+// for the product they stand for.
+func (r *VoteRepository) GetUnvotedViews(ctx context.Context) ([]*domain.ProductViews, error) {
+	rows, err := r.db.Query(ctx,
+		`SELECT
+			COALESCE(a.product_id, i.product_id) AS product_id,
+			COUNT(DISTINCT i.session_id) AS views,
+			COUNT(DISTINCT i.session_id) FILTER (WHERE i.action = 'skip') AS skips
+		FROM impressions i
+		LEFT JOIN product_aliases a ON a.alias_id = i.product_id
+		WHERE NOT EXISTS (
+			SELECT 1 FROM votes
+			LEFT JOIN product_aliases va ON va.alias_id = votes.product_id
+			WHERE COALESCE(va.product_id, votes.product_id) = COALESCE(a.product_id, i.product_id)
+		)
+		GROUP BY 1
+		ORDER BY views DESC, product_id`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var views []*domain.ProductViews
+	for rows.Next() {
+		v := &domain.ProductViews{}
+		if err := rows.Scan(&v.ProductID, &v.Views, &v.Skips); err != nil {
+			return nil, err
+		}
+		views = append(views, v)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return views, nil
+}
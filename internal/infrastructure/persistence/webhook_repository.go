@@ -0,0 +1,186 @@
+package persistence
+
+import (
+	"context"
+	"time"
+
+	"github.com/ArtemSind/food_tinder/internal/domain"
+	"github.com/ArtemSind/food_tinder/internal/infrastructure/persistence/models"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v4"
+	"github.com/jackc/pgx/v4/pgxpool"
+)
+
+const webhookDeliveryColumns = `d.id, d.subscription_id, d.event_id, e.event_type, e.payload, d.status,
+	d.attempts, d.next_attempt_at, d.last_error, d.created_at, s.url, s.secret`
+
+// WebhookRepository stores webhook subscriptions and their deliveries
+type WebhookRepository struct {
+	db *pgxpool.Pool
+}
+
+func NewWebhookRepository(db *pgxpool.Pool) *WebhookRepository {
+	return &WebhookRepository{db: db}
+}
+
+func (r *WebhookRepository) CreateSubscription(ctx context.Context, subscription *domain.WebhookSubscription) error {
+	dbSubscription := models.WebhookSubscriptionFromDomain(subscription)
+	_, err := r.db.Exec(ctx,
+		"INSERT INTO webhook_subscriptions (id, url, secret, event_types, active, created_at) VALUES ($1, $2, $3, $4, $5, $6)",
+		dbSubscription.ID, dbSubscription.URL, dbSubscription.Secret, dbSubscription.EventTypes,
+		dbSubscription.Active, dbSubscription.CreatedAt)
+	return err
+}
+
+func (r *WebhookRepository) ListSubscriptions(ctx context.Context) ([]*domain.WebhookSubscription, error) {
+	rows, err := r.db.Query(ctx,
+		`SELECT id, url, secret, event_types, active, created_at
+		FROM webhook_subscriptions
+		ORDER BY created_at`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var dbSubscriptions []*models.WebhookSubscriptionDB
+	for rows.Next() {
+		var dbSubscription models.WebhookSubscriptionDB
+		if err := rows.Scan(&dbSubscription.ID, &dbSubscription.URL, &dbSubscription.Secret,
+			&dbSubscription.EventTypes, &dbSubscription.Active, &dbSubscription.CreatedAt); err != nil {
+			return nil, err
+		}
+		dbSubscriptions = append(dbSubscriptions, &dbSubscription)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return models.WebhookSubscriptionsToDomain(dbSubscriptions), nil
+}
+
+func (r *WebhookRepository) DeleteSubscription(ctx context.Context, id uuid.UUID) error {
+	tag, err := r.db.Exec(ctx, "DELETE FROM webhook_subscriptions WHERE id = $1", id)
+	if err != nil {
+		return err
+	}
+	if tag.RowsAffected() == 0 {
+		return domain.ErrWebhookNotFound
+	}
+	return nil
+}
+
+// EnqueueDeliveries stores the deliveries of an outbox event and marks the event as processed.
+// Deliveries already created by another replica are left untouched.
+func (r *WebhookRepository) EnqueueDeliveries(ctx context.Context, eventID uuid.UUID, deliveries []*domain.WebhookDelivery) error {
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback(ctx)
+
+	for _, delivery := range deliveries {
+		if _, err := tx.Exec(ctx,
+			`INSERT INTO webhook_deliveries (id, subscription_id, event_id, status, attempts, next_attempt_at, created_at)
+			VALUES ($1, $2, $3, $4, $5, $6, $7)
+			ON CONFLICT (subscription_id, event_id) DO NOTHING`,
+			delivery.ID, delivery.SubscriptionID, delivery.EventID, string(delivery.Status),
+			delivery.Attempts, delivery.NextAttemptAt, delivery.CreatedAt); err != nil {
+			return err
+		}
+	}
+
+	if _, err := tx.Exec(ctx,
+		"UPDATE outbox_events SET processed_at = NOW() WHERE id = $1", eventID); err != nil {
+		return err
+	}
+
+	return tx.Commit(ctx)
+}
+
+// ClaimDueDeliveries returns pending deliveries whose next attempt is due and pushes their
+// next attempt out by the lease, so that other replicas do not send them concurrently
+func (r *WebhookRepository) ClaimDueDeliveries(ctx context.Context, limit int, lease time.Duration) ([]*domain.WebhookDelivery, error) {
+	rows, err := r.db.Query(ctx,
+		`UPDATE webhook_deliveries d
+		SET next_attempt_at = $2
+		FROM outbox_events e, webhook_subscriptions s
+		WHERE d.event_id = e.id
+			AND d.subscription_id = s.id
+			AND d.id IN (
+				SELECT id FROM webhook_deliveries
+				WHERE status = 'pending' AND next_attempt_at <= NOW()
+				ORDER BY next_attempt_at
+				LIMIT $1
+				FOR UPDATE SKIP LOCKED
+			)
+		RETURNING `+webhookDeliveryColumns,
+		limit, time.Now().Add(lease))
+	if err != nil {
+		return nil, err
+	}
+
+	return scanWebhookDeliveries(rows)
+}
+
+// SaveDeliveryResult stores the outcome of a delivery attempt
+func (r *WebhookRepository) SaveDeliveryResult(ctx context.Context, delivery *domain.WebhookDelivery) error {
+	_, err := r.db.Exec(ctx,
+		`UPDATE webhook_deliveries
+		SET status = $1, attempts = $2, next_attempt_at = $3, last_error = $4
+		WHERE id = $5`,
+		string(delivery.Status), delivery.Attempts, delivery.NextAttemptAt, delivery.LastError, delivery.ID)
+	return err
+}
+
+// ListDeadLetters returns the most recent deliveries that exhausted their attempts
+func (r *WebhookRepository) ListDeadLetters(ctx context.Context, limit int) ([]*domain.WebhookDelivery, error) {
+	rows, err := r.db.Query(ctx,
+		`SELECT `+webhookDeliveryColumns+`
+		FROM webhook_deliveries d
+		JOIN outbox_events e ON e.id = d.event_id
+		JOIN webhook_subscriptions s ON s.id = d.subscription_id
+		WHERE d.status = 'dead'
+		ORDER BY d.created_at DESC
+		LIMIT $1`, limit)
+	if err != nil {
+		return nil, err
+	}
+
+	return scanWebhookDeliveries(rows)
+}
+
+// RequeueDelivery moves a dead-lettered delivery back to the pending queue with a fresh attempt budget
+func (r *WebhookRepository) RequeueDelivery(ctx context.Context, id uuid.UUID) error {
+	tag, err := r.db.Exec(ctx,
+		`UPDATE webhook_deliveries
+		SET status = 'pending', attempts = 0, next_attempt_at = NOW(), last_error = ''
+		WHERE id = $1 AND status = 'dead'`, id)
+	if err != nil {
+		return err
+	}
+	if tag.RowsAffected() == 0 {
+		return domain.ErrDeliveryNotFound
+	}
+	return nil
+}
+
+func scanWebhookDeliveries(rows pgx.Rows) ([]*domain.WebhookDelivery, error) {
+	defer rows.Close()
+
+	var dbDeliveries []*models.WebhookDeliveryDB
+	for rows.Next() {
+		var d models.WebhookDeliveryDB
+		if err := rows.Scan(&d.ID, &d.SubscriptionID, &d.EventID, &d.EventType, &d.Payload, &d.Status,
+			&d.Attempts, &d.NextAttemptAt, &d.LastError, &d.CreatedAt, &d.URL, &d.Secret); err != nil {
+			return nil, err
+		}
+		dbDeliveries = append(dbDeliveries, &d)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return models.WebhookDeliveriesToDomain(dbDeliveries), nil
+}
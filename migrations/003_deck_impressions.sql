@@ -0,0 +1,14 @@
+ALTER TABLE sessions ADD COLUMN deck_strategy TEXT NOT NULL DEFAULT 'random';
+
+
+CREATE TABLE impressions (
+    id UUID PRIMARY KEY,
+    session_id UUID NOT NULL REFERENCES sessions(id),
+    product_id UUID NOT NULL,
+    strategy TEXT NOT NULL,
+    position INT NOT NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX impressions_session_id_idx ON impressions(session_id);
+CREATE INDEX impressions_product_id_idx ON impressions(product_id);
@@ -0,0 +1,22 @@
+CREATE TABLE experiments (
+    id UUID PRIMARY KEY,
+    name TEXT NOT NULL,
+    description TEXT NOT NULL DEFAULT '',
+    status TEXT NOT NULL CHECK (status IN ('running', 'stopped')),
+    variants JSONB NOT NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    stopped_at TIMESTAMP
+);
+
+-- Sessions get a single deck strategy, so only one experiment may run at a time
+CREATE UNIQUE INDEX experiments_single_running_idx ON experiments(status) WHERE status = 'running';
+
+
+CREATE TABLE experiment_assignments (
+    session_id UUID PRIMARY KEY REFERENCES sessions(id),
+    experiment_id UUID NOT NULL REFERENCES experiments(id),
+    variant TEXT NOT NULL,
+    assigned_at TIMESTAMP NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX experiment_assignments_experiment_id_idx ON experiment_assignments(experiment_id, variant);
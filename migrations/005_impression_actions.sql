@@ -0,0 +1,3 @@
+ALTER TABLE impressions ADD COLUMN action TEXT NOT NULL DEFAULT 'impression';
+
+CREATE INDEX impressions_product_id_action_idx ON impressions(product_id, action);
@@ -0,0 +1,20 @@
+CREATE TABLE products (
+    id UUID PRIMARY KEY,
+    content_hash TEXT NOT NULL,
+    data JSONB NOT NULL,
+    first_seen_at TIMESTAMP NOT NULL,
+    last_seen_at TIMESTAMP NOT NULL,
+    discontinued_at TIMESTAMP
+);
+
+CREATE INDEX products_discontinued_at_idx ON products(discontinued_at);
+
+CREATE TABLE product_events (
+    id UUID PRIMARY KEY,
+    product_id UUID NOT NULL REFERENCES products(id),
+    event_type TEXT NOT NULL,
+    content_hash TEXT NOT NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX product_events_product_id_idx ON product_events(product_id, created_at);
@@ -0,0 +1,10 @@
+CREATE TABLE product_overrides (
+    product_id UUID PRIMARY KEY,
+    hidden BOOLEAN NOT NULL DEFAULT FALSE,
+    pinned BOOLEAN NOT NULL DEFAULT FALSE,
+    name TEXT,
+    category TEXT,
+    image_url TEXT,
+    created_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMP NOT NULL DEFAULT NOW()
+);
@@ -0,0 +1,11 @@
+CREATE TABLE product_aliases (
+    alias_id UUID PRIMARY KEY,
+    product_id UUID NOT NULL,
+    moved_votes INT NOT NULL DEFAULT 0,
+    merged_votes INT NOT NULL DEFAULT 0,
+    moved_impressions INT NOT NULL DEFAULT 0,
+    created_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    CHECK (alias_id <> product_id)
+);
+
+CREATE INDEX product_aliases_product_id_idx ON product_aliases(product_id);
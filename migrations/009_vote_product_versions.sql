@@ -0,0 +1,4 @@
+-- The content hash of the product a vote was cast on; NULL for votes from before products were tracked
+ALTER TABLE votes ADD COLUMN product_version TEXT;
+
+CREATE INDEX votes_product_id_version_idx ON votes(product_id, product_version);
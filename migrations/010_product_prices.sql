@@ -0,0 +1,8 @@
+-- The price of every product, recorded by every refresh that saw it
+CREATE TABLE product_prices (
+    product_id UUID NOT NULL REFERENCES products(id),
+    content_hash TEXT NOT NULL,
+    price NUMERIC(10, 2) NOT NULL,
+    recorded_at TIMESTAMP NOT NULL,
+    PRIMARY KEY (product_id, recorded_at)
+);
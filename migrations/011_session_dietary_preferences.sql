@@ -0,0 +1,4 @@
+-- Allergens and diets a session declared; products they rule out are left out of its deck
+ALTER TABLE sessions
+    ADD COLUMN excluded_allergens TEXT[] NOT NULL DEFAULT '{}',
+    ADD COLUMN diets TEXT[] NOT NULL DEFAULT '{}';
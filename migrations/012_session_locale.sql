@@ -0,0 +1,3 @@
+-- Language a session chose for product texts and messages; empty follows the request's Accept-Language
+ALTER TABLE sessions
+    ADD COLUMN locale TEXT NOT NULL DEFAULT '';
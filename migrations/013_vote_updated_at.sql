@@ -0,0 +1,4 @@
+-- When a vote's score last changed; votes from before this column keep the time they were cast
+ALTER TABLE votes ADD COLUMN updated_at TIMESTAMP NOT NULL DEFAULT NOW();
+
+UPDATE votes SET updated_at = created_at;